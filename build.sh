@@ -6,7 +6,7 @@
 set -e
 
 BINARY_NAME="odata-mcp"
-MAIN_PATH="cmd/odata-mcp/main.go"
+MAIN_PATH="./cmd/odata-mcp"
 VERSION=${VERSION:-"1.0.0"}
 BUILD_DIR="build"
 
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odata-mcp/go/internal/bench"
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/startup"
+)
+
+var (
+	benchEntity      string
+	benchConcurrency int
+	benchRequests    int
+	benchTop         int
+	benchJSON        bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [service-url]",
+	Short: "Measure the latency of list, count and get tools against a service",
+	Long: `Call the filter, count and get tools of an entity set repeatedly, with a number of
+calls in flight at the same time, and report the latency percentiles and throughput of
+each tool. The calls take the same path as tool calls of an MCP client, including the
+bridge's response processing, so the report shows what agents will experience.
+
+Without --entity, the first entity set with a filter tool is used. The get tool is called
+with the key of the first entity the filter tool returns. Counts are not cached.
+Takes the same service and authentication options as the bridge.
+
+Examples:
+  odata-mcp bench https://services.odata.org/V2/Northwind/Northwind.svc/
+  odata-mcp bench --entity Orders --concurrency 8 --requests 100 --user DEVELOPER --password secret https://my-service.com/odata/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBench,
+}
+
+// registerBench adds the bench command with the service and authentication flags of the bridge
+func registerBench() {
+	addServiceFlags(benchCmd)
+	benchCmd.Flags().StringVar(&benchEntity, "entity", "", "Entity set to benchmark (default: the first entity set with a filter tool)")
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", constants.DefaultMaxConcurrentCalls, "Calls of a tool in flight at the same time")
+	benchCmd.Flags().IntVar(&benchRequests, "requests", 20, "Calls per tool")
+	benchCmd.Flags().IntVar(&benchTop, "top", 10, "$top of the filter tool calls")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "Write the results as JSON")
+	rootCmd.AddCommand(benchCmd)
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if err := resolveService(cmd, args); err != nil {
+		return startup.Wrap(startup.CategoryConfig, err)
+	}
+	// Every count call must reach the service
+	cfg.CountCacheTTL = 0
+
+	b, err := bridge.NewODataMCPBridge(cfg)
+	if err != nil {
+		return startup.Wrap(startup.CategoryMetadata, fmt.Errorf("failed to create OData MCP bridge: %w", err))
+	}
+	entitySet, targets, err := benchTargets(cmd.Context(), b, benchEntity, benchTop)
+	if err != nil {
+		return startup.Wrap(startup.CategoryConfig, err)
+	}
+
+	results := bench.Run(cmd.Context(), b.CallTool, targets, bench.Options{
+		Concurrency: benchConcurrency,
+		Requests:    benchRequests,
+	})
+
+	out := cmd.OutOrStdout()
+	if benchJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]interface{}{
+			"service_url": cfg.ServiceURL,
+			"entity_set":  entitySet,
+			"concurrency": benchConcurrency,
+			"requests":    benchRequests,
+			"results":     results,
+		}); err != nil {
+			return err
+		}
+	} else {
+		printBenchResults(out, entitySet, results)
+	}
+
+	failed, calls := 0, 0
+	for _, result := range results {
+		failed += result.Errors
+		calls += result.Calls
+	}
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d calls failed", failed, calls)
+	}
+	return nil
+}
+
+// printBenchResults writes the results as a table with latencies in milliseconds
+func printBenchResults(out io.Writer, entitySet string, results []bench.Result) {
+	fmt.Fprintf(out, "OData MCP bench: %s\nEntity set %s, %d calls per tool, concurrency %d\n\n", cfg.ServiceURL, entitySet, benchRequests, benchConcurrency)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TOOL\tCALLS\tERRORS\tP50 MS\tP90 MS\tP99 MS\tMAX MS\tCALLS/S\t")
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%.1f", float64(d)/float64(time.Millisecond))
+	}
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%.1f\t\n", r.Tool, r.Calls, r.Errors, ms(r.P50), ms(r.P90), ms(r.P99), ms(r.Max), r.Throughput())
+	}
+	w.Flush()
+	for _, r := range results {
+		if r.FirstError != "" {
+			fmt.Fprintf(out, "\n%s: %s\n", r.Tool, r.FirstError)
+		}
+	}
+}
+
+// benchTargets returns the filter, count and get tool calls of an entity set; without a
+// name, the first entity set with a filter tool is used
+func benchTargets(ctx context.Context, b *bridge.ODataMCPBridge, entitySet string, top int) (string, []bench.Target, error) {
+	info, err := b.GetTraceInfo()
+	if err != nil {
+		return "", nil, err
+	}
+	tools := make(map[string]map[string]string) // entity set -> operation -> tool
+	for _, tool := range info.RegisteredTools {
+		if tool.EntitySet == "" {
+			continue
+		}
+		if tools[tool.EntitySet] == nil {
+			tools[tool.EntitySet] = make(map[string]string)
+		}
+		tools[tool.EntitySet][tool.Operation] = tool.Name
+	}
+	if entitySet == "" {
+		var names []string
+		for name, operations := range tools {
+			if operations[constants.OpFilter] != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return "", nil, fmt.Errorf("no entity set has a filter tool to benchmark")
+		}
+		sort.Strings(names)
+		entitySet = names[0]
+	}
+	operations := tools[entitySet]
+	if operations[constants.OpFilter] == "" {
+		return "", nil, fmt.Errorf("entity set %s has no filter tool to benchmark", entitySet)
+	}
+
+	filterTool := operations[constants.OpFilter]
+	targets := []bench.Target{{Tool: filterTool, Args: map[string]interface{}{
+		"$top": float64(top), "output_format": constants.OutputFormatJSON,
+	}}}
+	if countTool := operations[constants.OpCount]; countTool != "" {
+		targets = append(targets, bench.Target{Tool: countTool, Args: map[string]interface{}{}})
+	}
+	if getTool := operations[constants.OpGet]; getTool != "" {
+		key, err := benchKey(ctx, b, filterTool, getTool)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", getTool, err)
+		} else {
+			targets = append(targets, bench.Target{Tool: getTool, Args: key})
+		}
+	}
+	return entitySet, targets, nil
+}
+
+// benchKey returns the key arguments of the get tool for the first entity of the
+// entity set
+func benchKey(ctx context.Context, b *bridge.ODataMCPBridge, filterTool, getTool string) (map[string]interface{}, error) {
+	tools, err := b.GetTools()
+	if err != nil {
+		return nil, err
+	}
+	var keyNames []string
+	for _, tool := range tools {
+		if tool.Name == getTool {
+			keyNames, _ = tool.InputSchema["required"].([]string)
+		}
+	}
+
+	result, err := b.CallTool(ctx, filterTool, map[string]interface{}{"$top": float64(1), "output_format": constants.OutputFormatJSON})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read an entity: %w", err)
+	}
+	text, _ := result.(string)
+	entity := firstEntity(text)
+	if entity == nil {
+		return nil, fmt.Errorf("the entity set has no entities")
+	}
+	key := make(map[string]interface{}, len(keyNames))
+	for _, name := range keyNames {
+		value, ok := entity[name]
+		if !ok {
+			return nil, fmt.Errorf("the entities lack the key property %s", name)
+		}
+		key[name] = value
+	}
+	return key, nil
+}
+
+// firstEntity returns the first entity of a filter tool result: a response of the bridge
+// ({"value": [...]}) or, with --raw-responses, the body of an OData v2 ({"d": {"results":
+// [...]}}) or v4 service
+func firstEntity(text string) map[string]interface{} {
+	var body struct {
+		Value []map[string]interface{} `json:"value"`
+		D     *struct {
+			Results []map[string]interface{} `json:"results"`
+		} `json:"d"`
+	}
+	if err := json.Unmarshal([]byte(text), &body); err != nil {
+		return nil
+	}
+	entities := body.Value
+	if body.D != nil {
+		entities = body.D.Results
+	}
+	if len(entities) == 0 {
+		return nil
+	}
+	return entities[0]
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/logging"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate the shell completion script for odata-mcp",
+	Long: `Generate the shell completion script for odata-mcp, completing all flags and
+their values (transports, output formats, files).
+
+Examples:
+  source <(odata-mcp completion bash)
+  odata-mcp completion zsh > "${fpath[1]}/_odata-mcp"
+  odata-mcp completion fish > ~/.config/fish/completions/odata-mcp.fish
+  odata-mcp completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		return fmt.Errorf("unsupported shell %q: must be bash, zsh, fish or powershell", args[0])
+	},
+}
+
+// registerCompletions adds the completion command and completions of flag values; it
+// runs after the flags are defined
+func registerCompletions() {
+	rootCmd.AddCommand(completionCmd)
+
+	// Complete the values of flags with a fixed set of choices or a file argument
+	fixedValues := map[string][]string{
+		"transport":     {constants.TransportStdio, constants.TransportHTTP},
+		"format-param":  {constants.FormatParamAuto, constants.FormatParamAlways, constants.FormatParamNever},
+		"output-format": {constants.OutputFormatJSON, constants.OutputFormatColumns, constants.OutputFormatCSV, constants.OutputFormatMarkdown},
+		"log-level":     logging.Levels,
+		"log-format":    logging.Formats,
+		"error-format":  {errorFormatText, errorFormatJSON},
+		"verbose":       logging.Subsystems,
+	}
+	for name, values := range fixedValues {
+		values := values
+		rootCmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return values, cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+	rootCmd.MarkFlagFilename("config", "yaml", "yml", "json")
+	rootCmd.MarkFlagFilename("cookie-file")
+	rootCmd.MarkFlagFilename("env-file")
+	rootCmd.MarkFlagFilename("entity-ops-file")
+	rootCmd.MarkFlagFilename("metadata-file", "xml", "json")
+	rootCmd.MarkFlagFilename("log-file")
+	rootCmd.MarkFlagFilename("audit-log")
+}
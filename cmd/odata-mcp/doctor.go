@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odata-mcp/go/internal/doctor"
+	"github.com/odata-mcp/go/internal/startup"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [service-url]",
+	Short: "Check connectivity, authentication, $metadata, CSRF and JSON support of a service",
+	Long: `Check DNS resolution, the TCP/TLS connection, authentication, $metadata retrieval,
+CSRF token handling and JSON support of an OData service and print a report.
+Takes the same service and authentication options as the bridge.
+
+Examples:
+  odata-mcp doctor https://my-sap-system.com/sap/opu/odata/sap/API_SALES_ORDER_SRV/
+  odata-mcp doctor --user DEVELOPER --password secret https://my-service.com/odata/
+  odata-mcp doctor --config odata-mcp.yaml --profile qa`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctor,
+}
+
+// registerDoctor adds the doctor command with the service and authentication flags of the bridge
+func registerDoctor() {
+	addServiceFlags(doctorCmd)
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if err := resolveService(cmd, args); err != nil {
+		return startup.Wrap(startup.CategoryConfig, err)
+	}
+
+	report := doctor.Run(cmd.Context(), cfg)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "OData MCP doctor: %s\n\n", report.ServiceURL)
+	for _, check := range report.Checks {
+		fmt.Fprintf(out, "  %-6s %-15s %s\n", "["+strings.ToUpper(check.Status)+"]", check.Name, check.Detail)
+	}
+	fmt.Fprintln(out)
+
+	if report.Failed() {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("the service is not usable; see the failed checks above")
+	}
+	fmt.Fprintln(out, "The service is reachable and can be used with odata-mcp.")
+	return nil
+}
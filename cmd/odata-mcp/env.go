@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/odata-mcp/go/internal/config"
+)
+
+// dotEnvFile is loaded when no --env-file is given
+const dotEnvFile = ".env"
+
+var (
+	// envFiles are the env files loaded into the environment, in order
+	envFiles []string
+
+	// processEnv are the variables set before the env files were loaded; the env files do
+	// not override them
+	processEnv = make(map[string]bool)
+)
+
+// recordProcessEnv remembers the variables of the process environment
+func recordProcessEnv() {
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		processEnv[name] = true
+	}
+}
+
+// loadEnvFiles loads the --env-file files (ODATA_ENV_FILE, or ./.env if it exists) into
+// the environment. Later files override earlier ones, so credentials can be layered on
+// top of shared settings; variables of the process environment override all files.
+func loadEnvFiles() error {
+	files := cfg.EnvFiles
+	if len(files) == 0 {
+		files = parseCommaSeparated(os.Getenv("ODATA_ENV_FILE"))
+	}
+	if len(files) == 0 {
+		if _, err := os.Stat(dotEnvFile); err == nil {
+			files = []string{dotEnvFile}
+		}
+	}
+
+	values, err := config.ReadEnvFiles(files, false)
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		if !processEnv[name] {
+			os.Setenv(name, value)
+		}
+	}
+
+	envFiles = files
+	if len(files) > 0 {
+		logger.Debug("Loaded env files", "variables", len(values), "files", files)
+	}
+	return nil
+}
+
+// envHandledFlags are read from the environment where they are processed, under their
+// established names (ODATA_URL, ODATA_USERNAME, ODATA_COOKIE_FILE, ...)
+var envHandledFlags = map[string]bool{
+	"config":        true,
+	"profile":       true,
+	"env-file":      true,
+	"service":       true,
+	"user":          true,
+	"password":      true,
+	"pass":          true,
+	"cookie-file":   true,
+	"cookie-string": true,
+}
+
+// applyEnvironment sets the flags given neither on the command line nor in the config
+// file from their ODATA_* environment variables (ODATA_MAX_ITEMS for --max-items), so
+// deployments can be configured without arguments
+func applyEnvironment(cmd *cobra.Command) error {
+	var names []string
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed && !envHandledFlags[flag.Name] {
+			names = append(names, flag.Name)
+		}
+	})
+
+	values := config.LoadEnv(names)
+	applied := make([]string, 0, len(values))
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", config.EnvName(name), err)
+		}
+		applied = append(applied, config.EnvName(name))
+	}
+
+	if len(applied) > 0 {
+		logger.Debug("Using options from environment", "variables", applied)
+	}
+	return nil
+}
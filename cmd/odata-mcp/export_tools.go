@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/startup"
+)
+
+var (
+	exportFormat string
+	exportOut    string
+)
+
+// serverOnlyFlags are root flags that control how the bridge serves MCP rather than
+// which tools it generates; export-tools does not take them
+var serverOnlyFlags = map[string]bool{
+	"trace":                     true,
+	"validate-metadata":         true,
+	"transport":                 true,
+	"http-addr":                 true,
+	"max-sessions":              true,
+	"pass-through-auth":         true,
+	"keepalive":                 true,
+	"idle-timeout":              true,
+	"tool-timeout":              true,
+	"dry-run":                   true,
+	"otlp-endpoint":             true,
+	"audit-log":                 true,
+	"slow-query-threshold":      true,
+	"sap-statistics":            true,
+	"debug-requests":            true,
+	"max-concurrent-calls":      true,
+	"max-message-size":          true,
+	"page-size":                 true,
+	"page-workers":              true,
+	"bulk-workers":              true,
+	"aggregate-workers":         true,
+	"tcp-keepalive":             true,
+	"idle-conn-timeout":         true,
+	"max-idle-conns":            true,
+	"max-idle-conns-per-host":   true,
+	"tls-session-cache":         true,
+	"disable-http2":             true,
+	"count-cache-ttl":           true,
+	"max-unfiltered-rows":       true,
+	"metadata-refresh-interval": true,
+	"watch-config":              true,
+	"max-tools":                 true,
+	"tools-page-size":           true,
+}
+
+var exportToolsCmd = &cobra.Command{
+	Use:   "export-tools [service-url]",
+	Short: "Export the generated tools as an OpenAPI document or tool manifest",
+	Long: `Generate the tools for an OData service as the bridge would and write them as an
+OpenAPI 3.1 document (one POST /tools/{name} operation per tool, with the tool's input
+and output schemas as request and response bodies) or as a JSON tool manifest in the
+tools/list format, for consumers that do not speak MCP.
+
+Takes the same service, authentication, naming and filtering options as the bridge.
+
+Examples:
+  odata-mcp export-tools --format openapi --out tools.openapi.json https://services.odata.org/V2/Northwind/Northwind.svc/
+  odata-mcp export-tools --format manifest --entities 'Products,Orders' --metadata-file parsed.json https://my-service.com/odata/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExportTools,
+}
+
+// registerExportTools adds the export-tools command with the tool generation flags of the
+// bridge; it must run after the root flags are defined
+func registerExportTools() {
+	rootCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !serverOnlyFlags[flag.Name] {
+			exportToolsCmd.Flags().AddFlag(flag)
+		}
+	})
+	exportToolsCmd.Flags().StringVar(&exportFormat, "format", "openapi", "Export format: 'openapi' (OpenAPI 3.1 document) or 'manifest' (tools/list JSON)")
+	exportToolsCmd.Flags().StringVarP(&exportOut, "out", "o", "", "File to write the export to (default: stdout)")
+	exportToolsCmd.MarkFlagFilename("out", "json")
+	exportToolsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"openapi", "manifest"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.AddCommand(exportToolsCmd)
+}
+
+func runExportTools(cmd *cobra.Command, args []string) error {
+	if exportFormat != "openapi" && exportFormat != "manifest" {
+		return startup.Wrap(startup.CategoryConfig, fmt.Errorf("invalid --format value %q: must be 'openapi' or 'manifest'", exportFormat))
+	}
+	if err := prepareConfig(cmd, args); err != nil {
+		return startup.Wrap(startup.CategoryConfig, err)
+	}
+
+	b, err := bridge.NewODataMCPBridge(cfg)
+	if err != nil {
+		return startup.Wrap(startup.CategoryMetadata, fmt.Errorf("failed to create OData MCP bridge: %w", err))
+	}
+
+	exportDoc := b.ExportOpenAPI
+	if exportFormat == "manifest" {
+		exportDoc = b.ExportManifest
+	}
+	export, err := exportDoc()
+	if err != nil {
+		return fmt.Errorf("failed to generate tools: %w", err)
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tools: %w", err)
+	}
+	data = append(data, '\n')
+
+	if exportOut == "" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(exportOut, data, 0644); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	tools, _ := b.GetTools()
+	fmt.Fprintf(os.Stderr, "Wrote %d tools to %s\n", len(tools), exportOut)
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/redact"
+	"github.com/odata-mcp/go/internal/startup"
+)
+
+// Values of --error-format
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// reportFatalError prints the error that ended the command, as text or, with
+// --error-format json, as a JSON object for supervising MCP hosts, and returns the exit
+// code of its category
+func reportFatalError(w io.Writer, err error) int {
+	category := startup.Classify(err)
+	if fatalErrorFormat() == errorFormatJSON {
+		startup.WriteJSON(w, err)
+		return startup.ExitCode(category)
+	}
+
+	fmt.Fprintf(w, "\n--- FATAL ERROR ---\n")
+	fmt.Fprintf(w, "An unexpected error occurred: %s\n", redact.String(err.Error()))
+	if category == startup.CategoryConfig {
+		fmt.Fprintf(w, "Run 'odata-mcp --help' for usage.\n")
+	}
+	fmt.Fprintf(w, "-------------------\n")
+	return startup.ExitCode(category)
+}
+
+// fatalErrorFormat returns --error-format, falling back to ODATA_ERROR_FORMAT when the
+// command failed before the environment was applied
+func fatalErrorFormat() string {
+	if flag := rootCmd.Flags().Lookup("error-format"); flag != nil && flag.Changed {
+		return cfg.ErrorFormat
+	}
+	if format := os.Getenv(config.EnvName("error-format")); format != "" {
+		return format
+	}
+	return cfg.ErrorFormat
+}
@@ -2,19 +2,30 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/odata-mcp/go/internal/audit"
 	"github.com/odata-mcp/go/internal/bridge"
 	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/logging"
+	"github.com/odata-mcp/go/internal/startup"
+	"github.com/odata-mcp/go/internal/tracing"
+	"github.com/odata-mcp/go/internal/transport"
+	"github.com/odata-mcp/go/internal/utils"
 )
 
 var cfg *config.Config
@@ -31,20 +42,30 @@ Examples:
   odata-mcp https://services.odata.org/V2/Northwind/Northwind.svc/
   odata-mcp --service https://my-sap-service.com/sap/opu/odata/sap/SERVICE_NAME/
   odata-mcp --user admin --password secret https://my-service.com/odata/
-  odata-mcp --cookie-file cookies.txt https://my-service.com/odata/`,
+  odata-mcp --cookie-file cookies.txt https://my-service.com/odata/
+  odata-mcp --config odata-mcp.yaml
+  odata-mcp --config odata-mcp.yaml --profile qa`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runBridge,
 }
 
 func init() {
-	// Load .env file if it exists
-	godotenv.Load()
+	// Env files are loaded once the flags are parsed; they do not override these variables
+	recordProcessEnv()
 
 	// Initialize config
 	cfg = &config.Config{}
 
+	// Configuration file
+	rootCmd.Flags().StringVar(&cfg.ConfigFile, "config", "", "YAML or JSON file setting any of these flags by name (e.g. 'service: https://...', 'entities: [Products, Orders]'); command-line flags take precedence (overrides ODATA_CONFIG env var)")
+	rootCmd.Flags().StringVar(&cfg.Profile, "profile", "", "Named profile of the --config file to use, e.g. 'dev', 'qa' or 'prod' (overrides the file's 'profile' key and ODATA_PROFILE env var)")
+	rootCmd.Flags().StringArrayVar(&cfg.EnvFiles, "env-file", nil, "Load environment variables from this file instead of ./.env; repeat to layer files, later ones overriding earlier ones (e.g., --env-file .env --env-file .env.qa)")
+
 	// Service URL
 	rootCmd.Flags().StringVar(&cfg.ServiceURL, "service", "", "URL of the OData service (overrides positional argument and ODATA_SERVICE_URL env var)")
+	rootCmd.Flags().StringVar(&cfg.MetadataFile, "metadata-file", "", "Read the service metadata from this file instead of $metadata: a $metadata XML document or the JSON written by 'odata-mcp metadata'")
+	rootCmd.Flags().StringVar(&cfg.MetadataCacheDir, "metadata-cache-dir", "", "Keep the parsed metadata in this directory and revalidate it with the ETag/Last-Modified of $metadata, parsing the document again only when it changed")
+	rootCmd.Flags().IntVar(&cfg.MaxMetadataSize, "max-metadata-size", 0, "Fail startup with guidance when the $metadata document exceeds this many bytes (0 = unlimited)")
 
 	// Authentication flags (mutually exclusive handled in validation)
 	rootCmd.Flags().StringVarP(&cfg.Username, "user", "u", "", "Username for basic authentication (overrides ODATA_USERNAME env var)")
@@ -59,65 +80,244 @@ func init() {
 	rootCmd.Flags().BoolVar(&cfg.NoPostfix, "no-postfix", false, "Use prefix instead of postfix for tool naming")
 	rootCmd.Flags().BoolVar(&cfg.ToolShrink, "tool-shrink", false, "Use shortened tool names (create_, get_, upd_, del_, search_, filter_)")
 
+	rootCmd.Flags().IntVar(&cfg.ToolNameMaxLength, "tool-name-max-length", constants.DefaultToolNameMaxLength, "Maximum length of generated tool names; longer names are shortened with a hash suffix")
+	rootCmd.Flags().StringVar(&cfg.ToolNameCollisions, "tool-name-collisions", constants.ToolNameCollisionsSuffix, "How tools whose names collide are renamed: 'suffix' appends _2, _3, ...; 'namespace' prefixes the schema namespace of the entity set or function import first")
+
 	// Entity and function filtering
-	rootCmd.Flags().StringVar(&cfg.Entities, "entities", "", "Comma-separated list of entities to generate tools for (e.g., 'Products,Categories,Orders'). Supports wildcards: 'Product*,Order*'")
-	rootCmd.Flags().StringVar(&cfg.Functions, "functions", "", "Comma-separated list of function imports to generate tools for (e.g., 'GetProducts,CreateOrder'). Supports wildcards: 'Get*,Create*'")
+	rootCmd.Flags().StringVar(&cfg.Entities, "entities", "", "Comma-separated list of entities to generate tools for (e.g., 'Products,Categories,Orders'). Supports wildcards ('Product*,*Order*') and regular expressions ('^A_(Product|Plant).*')")
+	rootCmd.Flags().StringVar(&cfg.Functions, "functions", "", "Comma-separated list of function imports to generate tools for (e.g., 'GetProducts,CreateOrder'). Supports wildcards ('Get*,Create*') and regular expressions ('^Get.*Set$')")
+
+	// Operation filtering
+	rootCmd.Flags().StringVar(&cfg.Operations, "operations", "", "Operation classes to generate tools for: c=create, r=read (filter/count/search/get), u=update, d=delete, a=functions; finer codes f=filter, s=search, g=get; codes are combined in the order crudfsga (e.g., 'cr' or 'read,create')")
+	rootCmd.Flags().StringVar(&cfg.DisableOperations, "disable", "", "Operations to exclude from tool generation, as codes or names (e.g., 'ud' or 'delete,update')")
+
+	rootCmd.Flags().StringVar(&cfg.EntityOperations, "entity-ops", "", "Per-entity operation rules (e.g., 'Products:read, Orders:read+create'). Supports wildcards in entity names")
+	rootCmd.Flags().StringVar(&cfg.EntityOperationsFile, "entity-ops-file", "", "Path to a file with per-entity operation rules, one '<entity>:<operations>' rule per line")
+
+	rootCmd.Flags().StringVar(&cfg.ConfirmOperations, "confirm", "", "Operations that ask the user for confirmation via MCP elicitation before running (e.g., 'delete' or 'delete,update'); they fail if the client cannot ask")
+	rootCmd.Flags().BoolVar(&cfg.DryRun, "dry-run", false, "Do not call the OData service from tools; return the method, URL, headers and body of the request each tool call would send")
+	rootCmd.Flags().StringVar(&cfg.AuditLog, "audit-log", "", "Append a JSON record (time, tool, entity, key, user, session, payload hash, result) of every create, update, delete and non-GET function call to this file")
+
+	rootCmd.Flags().IntVar(&cfg.MaxTools, "max-tools", 0, "Maximum number of tools to register up front; above it only service info plus list/enable tools are registered and entity tools are added on demand (0 = no limit)")
+
+	rootCmd.Flags().BoolVar(&cfg.CompactTools, "compact-tools", false, "Expose a handful of generic tools (query_entity, get_entity, create_entity, ...) taking the entity set name as a parameter instead of per-entity tools")
+
+	rootCmd.Flags().IntVar(&cfg.PageSize, "page-size", 0, "Split queries whose $top exceeds this many entities into pages fetched concurrently and merged in order (0 = one request)")
+	rootCmd.Flags().IntVar(&cfg.PageWorkers, "page-workers", constants.DefaultPageWorkers, "Number of pages of a split query (--page-size) fetched in parallel")
+	rootCmd.Flags().BoolVar(&cfg.BulkCreate, "bulk-create", false, "Generate a bulk create tool per creatable entity set that takes an array of entities and creates them in $batch changesets, reporting success or failure per entity")
+	rootCmd.Flags().IntVar(&cfg.BulkChangesetSize, "bulk-changeset-size", constants.DefaultBulkChangesetSize, "Entities per $batch changeset of bulk create tools; a changeset is created completely or not at all")
+	rootCmd.Flags().IntVar(&cfg.BulkWorkers, "bulk-workers", constants.DefaultBulkWorkers, "Changesets of a bulk create (--bulk-create) sent in parallel")
+	rootCmd.Flags().BoolVar(&cfg.AggregateAcross, "aggregate-across", false, "Generate the aggregate_across tool that runs the same query across several entity sets concurrently and returns all results in one response")
+	rootCmd.Flags().IntVar(&cfg.AggregateWorkers, "aggregate-workers", constants.DefaultAggregateWorkers, "Entity sets of an aggregate_across call queried in parallel")
+	rootCmd.Flags().BoolVar(&cfg.ValueHelp, "value-help", false, "Generate a value help tool per property with an SAP value help that lists the allowed values from its value help entity set (which --entities must include)")
+	rootCmd.Flags().BoolVar(&cfg.ChangesetTool, "changeset-tool", false, "Generate the execute_changeset tool that runs several create, update and delete operations in one $batch changeset, applied all or nothing with a single CSRF token fetch")
+	rootCmd.Flags().IntVar(&cfg.MaxConcurrentCalls, "max-concurrent-calls", constants.DefaultMaxConcurrentCalls, "Maximum number of tool calls executed in parallel; further calls wait for a free slot (0 = unbounded)")
+	rootCmd.Flags().IntVar(&cfg.MaxMessageSize, "max-message-size", 0, "Maximum size in bytes of JSON-RPC messages; larger requests and responses are answered with a JSON-RPC error (0 = 10MB for requests, responses unlimited)")
+	rootCmd.Flags().DurationVar(&cfg.ToolTimeout, "tool-timeout", 0, "Abort tool calls that run longer than this, including their backend requests (e.g., '2m'; 0 = no limit)")
+	rootCmd.Flags().DurationVar(&cfg.SlowQueryThreshold, "slow-query-threshold", 0, "Log entity set queries that take at least this long as warnings, with their query options and row count (e.g., '2s'; 0 = off)")
+	rootCmd.Flags().BoolVar(&cfg.SAPStatistics, "sap-statistics", false, "Request SAP Gateway performance statistics with every request and report the gateway/backend timings in debug output and bridge_stats")
+	rootCmd.Flags().IntVar(&cfg.DebugRequests, "debug-requests", 0, "Keep the last N requests and responses (credentials redacted, bodies cut to 4 KB) in memory and expose them through the debug_recent_requests tool (0 = off)")
+	rootCmd.Flags().DurationVar(&cfg.KeepAliveInterval, "keepalive", 0, "With --transport http, send a ping to each session's client at this interval and close the session if it does not answer (e.g., '30s'; 0 = disabled)")
+	rootCmd.Flags().IntVar(&cfg.MaxUnfilteredRows, "max-unfiltered-rows", 0, "Reject filter tool calls with neither $filter nor $top on entity sets with more entities than this, instead of letting the backend time out; has no effect while --default-top, --max-items (default 100) or --hard-top limits such calls (0 = off)")
+	rootCmd.Flags().DurationVar(&cfg.CountCacheTTL, "count-cache-ttl", constants.DefaultCountCacheTTL*time.Second, "Reuse entity counts per entity set and filter for this long; writes through the bridge drop them (0 = always query)")
+	rootCmd.Flags().DurationVar(&cfg.TCPKeepAlive, "tcp-keepalive", constants.DefaultTCPKeepAlive*time.Second, "Interval of TCP keep-alive probes on connections to the OData service, keeping them open through firewalls and load balancers")
+	rootCmd.Flags().DurationVar(&cfg.IdleConnTimeout, "idle-conn-timeout", constants.DefaultIdleConnTimeout*time.Second, "How long an idle connection to the OData service is kept for reuse")
+	rootCmd.Flags().IntVar(&cfg.MaxIdleConns, "max-idle-conns", constants.DefaultMaxIdleConns, "Idle connections kept for reuse across all hosts")
+	rootCmd.Flags().IntVar(&cfg.MaxIdleConnsPerHost, "max-idle-conns-per-host", constants.DefaultIdleConnsPerHost, "Idle connections kept for reuse per host; raise it with --max-concurrent-calls so parallel tool calls do not open new connections")
+	rootCmd.Flags().IntVar(&cfg.TLSSessionCache, "tls-session-cache", 0, "TLS sessions kept for resumption, so new connections to the service skip the full TLS handshake (0 = off)")
+	rootCmd.Flags().BoolVar(&cfg.DisableHTTP2, "disable-http2", false, "Use HTTP/1.1 even if the OData service offers HTTP/2")
+	rootCmd.Flags().BoolVar(&cfg.StartupProbes, "startup-probes", false, "At startup, fetch the service document and a CSRF token (opening the SAP session) concurrently with $metadata, saving round trips against slow services")
+	rootCmd.Flags().DurationVar(&cfg.StartupTimeout, "startup-timeout", 0, "Retry loading the metadata while the service is unreachable or failing for up to this long, then exit with an error (e.g., '2m'; 0 = one attempt)")
+	rootCmd.Flags().DurationVar(&cfg.MetadataRefreshInterval, "metadata-refresh-interval", 0, "Re-fetch the service metadata at this interval and regenerate the tools when it changed, notifying the client and register the refresh_metadata tool, which refreshes on demand (e.g., '10m'; 0 = off)")
+	rootCmd.Flags().BoolVar(&cfg.WatchConfig, "watch-config", false, "Watch the --config and .env files and apply changed filters, limits and verbosity without a restart, notifying the client when the tools change")
+	rootCmd.Flags().DurationVar(&cfg.IdleTimeout, "idle-timeout", 0, "With --transport http, close a session after this long without client requests (e.g., '30m'; 0 = disabled)")
+
+	rootCmd.Flags().StringVar(&cfg.FormatParam, "format-param", constants.FormatParamAuto, "When to add $format=json to queries: 'auto' (SAP OData v2 services only), 'always' or 'never'; otherwise the Accept header selects JSON")
+	rootCmd.Flags().StringVar(&cfg.Transport, "transport", constants.TransportStdio, "MCP transport: 'stdio' or 'http' (Streamable HTTP for multiple clients, each with an isolated session)")
+	rootCmd.Flags().StringVar(&cfg.HTTPAddr, "http-addr", constants.DefaultHTTPAddr, "Listen address for --transport http")
+	rootCmd.Flags().IntVar(&cfg.MaxSessions, "max-sessions", constants.DefaultMaxSessions, "With --transport http, the maximum number of open sessions; a new session closes the longest idle one, or is refused if all are busy (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&cfg.PassThroughAuth, "pass-through-auth", false, "With --transport http, forward each client's Authorization header to the OData service instead of the configured credentials")
+	rootCmd.Flags().IntVar(&cfg.ToolsPageSize, "tools-page-size", 0, "Maximum number of tools per tools/list response; clients fetch further pages with the returned cursor (0 = no pagination)")
 
 	// Output and debugging options
-	rootCmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output to stderr")
+	addVerboseFlag(rootCmd.Flags(), cfg, "v", "Enable verbose output (same as --log-level debug); --verbose=client,metadata logs debug messages of these subsystems only")
 	rootCmd.Flags().BoolVar(&cfg.Debug, "debug", false, "Alias for --verbose")
+	rootCmd.Flags().StringVar(&cfg.LogLevel, "log-level", "", "Minimum level of log messages: 'debug', 'info', 'warn' or 'error' (default 'warn', 'debug' with --verbose)")
+	rootCmd.Flags().StringVar(&cfg.LogFormat, "log-format", logging.FormatText, "Format of log messages: 'text' (key=value) or 'json' (one object per line, for Splunk/ELK)")
+	rootCmd.Flags().StringVar(&cfg.LogFile, "log-file", "", "Append log messages to this file instead of writing them to stderr")
+	rootCmd.Flags().StringVar(&cfg.OTLPEndpoint, "otlp-endpoint", "", "Export spans of tool calls and OData requests to this OpenTelemetry collector via OTLP/HTTP (e.g., 'http://localhost:4318'; default: $OTEL_EXPORTER_OTLP_ENDPOINT)")
+	rootCmd.Flags().StringVar(&cfg.ErrorFormat, "error-format", errorFormatText, "Format of fatal startup errors on stderr: 'text' or 'json' (a JSON object with category auth, network, metadata or config for supervising MCP hosts)")
 	rootCmd.Flags().BoolVar(&cfg.SortTools, "sort-tools", true, "Sort tools alphabetically in the output")
 	rootCmd.Flags().BoolVar(&cfg.Trace, "trace", false, "Initialize MCP service and print all tools and parameters, then exit (useful for debugging)")
+	rootCmd.Flags().BoolVar(&cfg.ValidateMetadata, "validate-metadata", false, "Check the service metadata for unresolved types, entity sets without keys, unsupported constructs and tool name collisions, print a report and exit (non-zero on errors)")
 	
 	// Response enhancement options
 	rootCmd.Flags().BoolVar(&cfg.PaginationHints, "pagination-hints", false, "Add pagination support with suggested_next_call and has_more indicators")
 	rootCmd.Flags().BoolVar(&cfg.LegacyDates, "legacy-dates", true, "Support epoch timestamp format (/Date(1234567890000)/) - enabled by default for SAP")
 	rootCmd.Flags().BoolVar(&cfg.NoLegacyDates, "no-legacy-dates", false, "Disable legacy date format conversion")
 	rootCmd.Flags().BoolVar(&cfg.VerboseErrors, "verbose-errors", false, "Provide detailed error context and debugging information")
-	rootCmd.Flags().BoolVar(&cfg.ResponseMetadata, "response-metadata", false, "Include detailed __metadata blocks in entity responses")
+	rootCmd.Flags().BoolVar(&cfg.ResponseMetadata, "response-metadata", false, "Return entities as sent by the service; by default __metadata blocks, __deferred navigation stubs, nulls and empty strings are removed")
+	rootCmd.Flags().BoolVar(&cfg.RawResponses, "raw-responses", false, "Return the JSON body of filter, search and get requests verbatim, without decoding and re-encoding it, when it fits --max-response-size (no lean responses, date conversion or truncation notes)")
 	
 	// Response size limits
-	rootCmd.Flags().IntVar(&cfg.MaxResponseSize, "max-response-size", 5*1024*1024, "Maximum response size in bytes (default: 5MB)")
-	rootCmd.Flags().IntVar(&cfg.MaxItems, "max-items", 100, "Maximum number of items in response (default: 100)")
-
-	// Bind flags to viper for environment variable support
-	viper.BindPFlag("service", rootCmd.Flags().Lookup("service"))
-	viper.BindPFlag("username", rootCmd.Flags().Lookup("user"))
-	viper.BindPFlag("password", rootCmd.Flags().Lookup("password"))
-	viper.BindPFlag("verbose", rootCmd.Flags().Lookup("verbose"))
-
-	// Set up environment variable mapping
+	rootCmd.Flags().IntVar(&cfg.MaxResponseSize, "max-response-size", 5*1024*1024, "Maximum tool output size in bytes; larger lists are truncated and marked \"truncated\": true, backend bodies over 4x this size are rejected (default: 5MB)")
+	rootCmd.Flags().IntVar(&cfg.MaxItems, "max-items", 100, "Maximum number of items in response; queries without $top are limited to this many entities (default: 100)")
+	rootCmd.Flags().IntVar(&cfg.DefaultTop, "default-top", 0, "$top of filter and search queries without one (0 = --max-items)")
+	rootCmd.Flags().IntVar(&cfg.HardTop, "hard-top", 0, "Lower a larger $top of filter and search queries to this and mark the result truncated (0 = no cap)")
+
+	// Automatic $select
+	rootCmd.Flags().BoolVar(&cfg.AutoSelect, "auto-select", false, "When the agent passes no $select, query only key properties, their sap:text descriptions, properties with sap:semantics and --auto-select-fields")
+	rootCmd.Flags().StringVar(&cfg.AutoSelectFields, "auto-select-fields", constants.DefaultAutoSelectFields, "Comma-separated property names or sap:labels selected by --auto-select. Supports wildcards and regular expressions")
+
+	// List rendering options
+	rootCmd.Flags().StringVar(&cfg.OutputFormat, "output-format", constants.OutputFormatJSON, "Default result format of filter and search tools: 'json', 'columns', 'csv' or 'markdown' (agents can override it per call with output_format)")
+	rootCmd.Flags().IntVar(&cfg.TableMaxColumns, "table-max-columns", constants.DefaultTableMaxColumns, "Maximum columns of Markdown tables; further properties are omitted with a note (0 = unlimited)")
+	rootCmd.Flags().IntVar(&cfg.TableMaxRows, "table-max-rows", constants.DefaultTableMaxRows, "Maximum rows of Markdown tables; further entities are omitted with a note (0 = unlimited)")
+
+	// Set up environment variable mapping for the service and authentication variables;
+	// all other flags are read from ODATA_<FLAG> by applyEnvironment
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("ODATA")
+
+	logging.Init()
+
+	// Fatal errors are printed by main with their category and exit code
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return startup.Wrap(startup.CategoryConfig, err)
+	})
+
+	registerCompletions()
+	rootCmd.AddCommand(versionCmd)
+	registerDoctor()
+	registerBench()
+	registerMetadataExport()
+	registerExportTools()
+	rootCmd.Version = Version
 }
 
 func runBridge(cmd *cobra.Command, args []string) error {
+	if err := prepareConfig(cmd, args); err != nil {
+		return startup.Wrap(startup.CategoryConfig, err)
+	}
+
+	stopTracing, err := startTracing(cfg)
+	if err != nil {
+		return startup.Wrap(startup.CategoryConfig, err)
+	}
+	defer stopTracing()
+
+	if cfg.AuditLog != "" {
+		closeAudit, err := audit.Open(cfg.AuditLog)
+		if err != nil {
+			return startup.Wrap(startup.CategoryConfig, err)
+		}
+		defer closeAudit()
+		logger.Debug("Writing audit log", "file", cfg.AuditLog)
+	}
+
+	// Set up signal handling
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	// The HTTP transport answers probes while the metadata loads
+	if cfg.Transport == constants.TransportHTTP && !cfg.Trace && !cfg.ValidateMetadata {
+		return runHTTPServer(cmd, sigChan)
+	}
+
+	// Create and initialize bridge
+	bridge, err := createBridge()
+	if err != nil {
+		return err
+	}
+
+	// Handle trace mode
+	if cfg.Trace {
+		return printTraceInfo(bridge)
+	}
+
+	if cfg.ValidateMetadata {
+		return printValidationReport(bridge)
+	}
+
+	if cfg.WatchConfig {
+		if err := startConfigWatch(bridge, cmd.Flags()); err != nil {
+			return err
+		}
+	}
+
+	// Start bridge in a goroutine
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- bridge.Run()
+	}()
+
+	// Wait for signal or error
+	select {
+	case sig := <-sigChan:
+		slog.Info("Shutting down server", "signal", sig.String())
+		bridge.Stop()
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// prepareConfig applies the config file and environment to cfg and validates it, for
+// the bridge and the subcommands that generate its tools
+func prepareConfig(cmd *cobra.Command, args []string) error {
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		commandLineFlags[flag.Name] = true
+	})
+	// Log the env and config file handling at the level given on the command line
+	logging.SetLevel(logLevel(cfg))
+	logging.SetScopes(logScopes(cfg))
+	if err := loadEnvFiles(); err != nil {
+		return err
+	}
+
+	if cfg.ConfigFile == "" {
+		cfg.ConfigFile = viper.GetString("CONFIG")
+	}
+	if cfg.Profile == "" {
+		cfg.Profile = viper.GetString("PROFILE")
+	}
+	if cfg.Profile != "" && cfg.ConfigFile == "" {
+		return fmt.Errorf("--profile %s requires a --config file", cfg.Profile)
+	}
+	if cfg.ConfigFile != "" {
+		if err := applyConfigFile(cmd, cfg.ConfigFile); err != nil {
+			return err
+		}
+	}
+	if err := applyEnvironment(cmd); err != nil {
+		return err
+	}
+
 	// Handle --debug as alias for --verbose
 	if cfg.Debug {
 		cfg.Verbose = true
 	}
+	if err := configureLogging(cfg); err != nil {
+		return err
+	}
 	
 	// Handle legacy dates flags
 	if cfg.NoLegacyDates {
 		cfg.LegacyDates = false
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Legacy date format conversion disabled.\n")
-		}
+		logger.Debug("Legacy date format conversion disabled")
 	} else if !cmd.Flags().Changed("legacy-dates") {
 		// Default to legacy dates for SAP compatibility
 		cfg.LegacyDates = true
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Legacy date format enabled by default for SAP compatibility. Use --no-legacy-dates to disable.\n")
-		}
+		logger.Debug("Legacy date format enabled by default for SAP compatibility. Use --no-legacy-dates to disable")
 	}
 
 	// Determine service URL with priority: --service flag > positional arg > env vars
 	if cfg.ServiceURL == "" && len(args) > 0 {
 		cfg.ServiceURL = args[0]
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Using OData service URL from positional argument.\n")
-		}
+		logger.Debug("Using OData service URL from positional argument")
 	}
 
 	if cfg.ServiceURL == "" {
@@ -125,8 +325,8 @@ func runBridge(cmd *cobra.Command, args []string) error {
 		if cfg.ServiceURL == "" {
 			cfg.ServiceURL = viper.GetString("SERVICE_URL")
 		}
-		if cfg.ServiceURL != "" && cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Using ODATA_URL from environment.\n")
+		if cfg.ServiceURL != "" {
+			logger.Debug("Using ODATA_URL from environment")
 		}
 	}
 
@@ -139,51 +339,226 @@ func runBridge(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	return parseOptions(cfg)
+}
+
+// parseOptions parses and validates the filter, operation and output options of a
+// configuration, at startup and when --watch-config reloads it
+func parseOptions(cfg *config.Config) error {
+	cfg.AllowedEntities, cfg.AllowedFunctions, cfg.ImportantFields = nil, nil, nil
+
+	if _, err := logging.ParseLevel(logLevel(cfg)); err != nil {
+		return err
+	}
+	if cfg.ErrorFormat != errorFormatText && cfg.ErrorFormat != errorFormatJSON {
+		return fmt.Errorf("invalid --error-format value %q: must be '%s' or '%s'", cfg.ErrorFormat, errorFormatText, errorFormatJSON)
+	}
+
 	// Parse entity and function filters
 	if cfg.Entities != "" {
-		cfg.AllowedEntities = parseCommaSeparated(cfg.Entities)
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Filtering tools to only these entities: %v\n", cfg.AllowedEntities)
-		}
+		cfg.AllowedEntities = utils.SplitPatterns(cfg.Entities)
+		logger.Debug("Filtering tools to only these entities", "entities", cfg.AllowedEntities)
 	}
 
 	if cfg.Functions != "" {
-		cfg.AllowedFunctions = parseCommaSeparated(cfg.Functions)
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Filtering tools to only these functions: %v\n", cfg.AllowedFunctions)
+		cfg.AllowedFunctions = utils.SplitPatterns(cfg.Functions)
+		logger.Debug("Filtering tools to only these functions", "functions", cfg.AllowedFunctions)
+	}
+
+	if cfg.AutoSelect {
+		cfg.ImportantFields = utils.SplitPatterns(cfg.AutoSelectFields)
+	}
+
+	if err := cfg.ParseOperationFilters(); err != nil {
+		return err
+	}
+	if cfg.EnabledOperations != nil {
+		logger.Debug("Generating tools only for these operations", "operations", cfg.EnabledOperationNames())
+	}
+
+	if err := cfg.ParseConfirmOperations(); err != nil {
+		return err
+	}
+
+	if err := loadEntityOperationRules(cfg); err != nil {
+		return err
+	}
+
+	if err := cfg.CompilePatterns(); err != nil {
+		return err
+	}
+
+	switch cfg.FormatParam {
+	case constants.FormatParamAuto, constants.FormatParamAlways, constants.FormatParamNever:
+	default:
+		return fmt.Errorf("invalid --format-param value %q: must be 'auto', 'always' or 'never'", cfg.FormatParam)
+	}
+
+	switch cfg.ToolNameCollisions {
+	case constants.ToolNameCollisionsSuffix, constants.ToolNameCollisionsNamespace:
+	default:
+		return fmt.Errorf("invalid --tool-name-collisions value %q: must be 'suffix' or 'namespace'", cfg.ToolNameCollisions)
+	}
+
+	switch cfg.OutputFormat {
+	case constants.OutputFormatJSON, constants.OutputFormatColumns, constants.OutputFormatCSV, constants.OutputFormatMarkdown:
+	default:
+		return fmt.Errorf("invalid --output-format value %q: must be 'json', 'columns', 'csv' or 'markdown'", cfg.OutputFormat)
+	}
+
+	if cfg.Transport != constants.TransportStdio && cfg.Transport != constants.TransportHTTP {
+		return fmt.Errorf("invalid --transport value %q: must be 'stdio' or 'http'", cfg.Transport)
+	}
+	if cfg.MaxSessions < 0 {
+		return fmt.Errorf("invalid --max-sessions value %d: must be 0 (unlimited) or more", cfg.MaxSessions)
+	}
+	// A stdio session lives as long as the client process that started the bridge
+	if cfg.Transport != constants.TransportHTTP && (cfg.KeepAliveInterval > 0 || cfg.IdleTimeout > 0) {
+		return fmt.Errorf("--keepalive and --idle-timeout apply only to --transport http")
+	}
+
+	return nil
+}
+
+// applyConfigFile sets the flags not given on the command line from a --config file.
+// Options of the bridge that a subcommand does not take are ignored.
+func applyConfigFile(cmd *cobra.Command, path string) error {
+	values, err := config.LoadFile(path, cfg.Profile)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if cmd.Root().Flags().Lookup(name) == nil || name == "config" || name == "profile" || name == "env-file" {
+			return fmt.Errorf("unknown option %q in config file %s", name, path)
+		}
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(name, values[name]); err != nil {
+			return fmt.Errorf("invalid value for %s in config file %s: %w", name, path, err)
 		}
 	}
 
-	// Set up signal handling
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	if cfg.Profile != "" {
+		logger.Debug("Loaded config file", "options", len(names), "file", path, "profile", cfg.Profile)
+	} else {
+		logger.Debug("Loaded config file", "options", len(names), "file", path)
+	}
+	return nil
+}
 
-	// Create and initialize bridge
-	bridge, err := bridge.NewODataMCPBridge(cfg)
+// createBridge creates the bridge and loads the metadata, retrying within --startup-timeout
+func createBridge() (*bridge.ODataMCPBridge, error) {
+	b, err := bridge.NewODataMCPBridge(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create OData MCP bridge: %w", err)
+		return nil, startup.Wrap(startup.CategoryMetadata, fmt.Errorf("failed to create OData MCP bridge: %w", err))
 	}
+	return b, nil
+}
 
-	// Handle trace mode
-	if cfg.Trace {
-		return printTraceInfo(bridge)
+// runHTTPServer serves MCP over HTTP. The server listens before the metadata is loaded,
+// so the liveness probe succeeds and the readiness probe fails until the bridge is ready;
+// if the metadata cannot be loaded, the server stops and the process exits with an error.
+func runHTTPServer(cmd *cobra.Command, sigChan <-chan os.Signal) error {
+	server := transport.NewHTTPServer(nil, cfg)
+	shutdown := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(ctx)
 	}
 
-	// Start bridge in a goroutine
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 2)
 	go func() {
-		errChan <- bridge.Run()
+		errChan <- server.ListenAndServe()
 	}()
 
-	// Wait for signal or error
-	select {
-	case sig := <-sigChan:
-		fmt.Fprintf(os.Stderr, "\n%s received, shutting down server...\n", sig)
-		bridge.Stop()
-		return nil
-	case err := <-errChan:
+	bridgeChan := make(chan *bridge.ODataMCPBridge, 1)
+	go func() {
+		b, err := createBridge()
+		if err != nil {
+			errChan <- err
+			return
+		}
+		bridgeChan <- b
+	}()
+
+	for {
+		select {
+		case b := <-bridgeChan:
+			if cfg.WatchConfig {
+				if err := startConfigWatch(b, cmd.Flags()); err != nil {
+					shutdown()
+					return err
+				}
+			}
+			server.SetBridge(b)
+		case sig := <-sigChan:
+			slog.Info("Shutting down server", "signal", sig.String())
+			return shutdown()
+		case err := <-errChan:
+			shutdown()
+			return err
+		}
+	}
+}
+
+// logLevel returns the --log-level of a configuration; --verbose and --debug lower the
+// default to debug
+func logLevel(cfg *config.Config) string {
+	switch {
+	case cfg.LogLevel != "":
+		return cfg.LogLevel
+	case cfg.Verbose || cfg.Debug:
+		return "debug"
+	default:
+		return logging.DefaultLevel
+	}
+}
+
+// startTracing starts exporting spans to --otlp-endpoint or $OTEL_EXPORTER_OTLP_ENDPOINT,
+// under the service name $OTEL_SERVICE_NAME. The returned function flushes the spans.
+func startTracing(cfg *config.Config) (func(), error) {
+	endpoint := cfg.OTLPEndpoint
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		return func() {}, nil
+	}
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = constants.MCPServerName
+	}
+
+	shutdown, err := tracing.Setup(endpoint, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("Exporting traces", "endpoint", endpoint, "service", serviceName)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			slog.Warn("Failed to flush traces", "error", err)
+		}
+	}, nil
+}
+
+// configureLogging applies --log-level, --log-format and --log-file. The log file stays
+// open for the lifetime of the process.
+func configureLogging(cfg *config.Config) error {
+	if _, err := logging.Configure(logLevel(cfg), cfg.LogFormat, cfg.LogFile); err != nil {
 		return err
 	}
+	return logging.SetScopes(logScopes(cfg))
 }
 
 func processAuthentication(cfg *config.Config) error {
@@ -215,9 +590,7 @@ func processAuthentication(cfg *config.Config) error {
 		}
 
 		cfg.Cookies = cookies
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Loaded %d cookies from file: %s\n", len(cookies), cfg.CookieFile)
-		}
+		logger.Debug("Loaded cookies from file", "cookies", len(cookies), "file", cfg.CookieFile)
 	} else if cfg.CookieString != "" {
 		// Process cookie string authentication
 		cookies := parseCookieString(cfg.CookieString)
@@ -226,9 +599,7 @@ func processAuthentication(cfg *config.Config) error {
 		}
 
 		cfg.Cookies = cookies
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Parsed %d cookies from string\n", len(cookies))
-		}
+		logger.Debug("Parsed cookies from string", "cookies", len(cookies))
 	} else {
 		// Handle basic authentication from environment if not provided via flags
 		if cfg.Username == "" {
@@ -255,35 +626,52 @@ func processAuthentication(cfg *config.Config) error {
 					cookies, err := loadCookiesFromFile(envCookieFile)
 					if err == nil {
 						cfg.Cookies = cookies
-						if cfg.Verbose {
-							fmt.Fprintf(os.Stderr, "[VERBOSE] Loaded %d cookies from environment ODATA_COOKIE_FILE\n", len(cookies))
-						}
+						logger.Debug("Loaded cookies from environment ODATA_COOKIE_FILE", "cookies", len(cookies))
 					}
 				}
 			} else if envCookieString != "" {
 				cookies := parseCookieString(envCookieString)
 				if len(cookies) > 0 {
 					cfg.Cookies = cookies
-					if cfg.Verbose {
-						fmt.Fprintf(os.Stderr, "[VERBOSE] Parsed %d cookies from environment ODATA_COOKIE_STRING\n", len(cookies))
-					}
+					logger.Debug("Parsed cookies from environment ODATA_COOKIE_STRING", "cookies", len(cookies))
 				}
 			}
 		}
 
 		// Set up basic auth if credentials are available
 		if cfg.Username != "" && cfg.Password != "" {
-			if cfg.Verbose {
-				fmt.Fprintf(os.Stderr, "[VERBOSE] Using basic authentication for user: %s\n", cfg.Username)
-			}
-		} else if cfg.Verbose && len(cfg.Cookies) == 0 {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] No authentication provided or configured. Attempting anonymous access.\n")
+			logger.Debug("Using basic authentication", "user", cfg.Username)
+		} else if len(cfg.Cookies) == 0 {
+			logger.Debug("No authentication provided or configured. Attempting anonymous access")
 		}
 	}
 
 	return nil
 }
 
+func loadEntityOperationRules(cfg *config.Config) error {
+	spec := cfg.EntityOperations
+	if cfg.EntityOperationsFile != "" {
+		data, err := os.ReadFile(cfg.EntityOperationsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read entity operations file: %w", err)
+		}
+		// Inline rules take precedence, so they are placed first
+		spec = spec + "\n" + string(data)
+	}
+
+	rules, err := config.ParseEntityOperationRules(spec)
+	if err != nil {
+		return err
+	}
+
+	cfg.EntityOperationRules = rules
+	if len(rules) > 0 {
+		logger.Debug("Loaded per-entity operation rules", "rules", len(rules))
+	}
+	return nil
+}
+
 func loadCookiesFromFile(cookieFile string) (map[string]string, error) {
 	cookies := make(map[string]string)
 
@@ -371,11 +759,35 @@ func printTraceInfo(bridge *bridge.ODataMCPBridge) error {
 	return nil
 }
 
+func printValidationReport(bridge *bridge.ODataMCPBridge) error {
+	report := bridge.ValidateMetadata()
+
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println("OData Metadata Validation")
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Printf("Service:   %s\n", report.ServiceURL)
+	fmt.Printf("Metadata:  %d entity types, %d entity sets, %d function imports\n",
+		report.MetadataSummary.EntityTypes, report.MetadataSummary.EntitySets, report.MetadataSummary.FunctionImports)
+	fmt.Printf("Tools:     %d\n\n", report.TotalTools)
+
+	for _, issue := range report.Issues {
+		fmt.Printf("%-8s %-24s %s\n", strings.ToUpper(issue.Severity), issue.Kind, issue.Message)
+	}
+	if len(report.Issues) == 0 {
+		fmt.Println("No issues found")
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 80))
+	fmt.Printf("%d errors, %d warnings\n", report.Errors, report.Warnings)
+
+	if report.Errors > 0 {
+		return fmt.Errorf("metadata validation found %d errors", report.Errors)
+	}
+	return nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "\n--- FATAL ERROR ---\n")
-		fmt.Fprintf(os.Stderr, "An unexpected error occurred: %v\n", err)
-		fmt.Fprintf(os.Stderr, "-------------------\n")
-		os.Exit(1)
+		os.Exit(reportFatalError(os.Stderr, err))
 	}
 }
\ No newline at end of file
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/startup"
+)
+
+var metadataOut string
+
+var metadataCmd = &cobra.Command{
+	Use:   "metadata [service-url]",
+	Short: "Export the parsed service metadata as JSON",
+	Long: `Fetch and parse the $metadata document of an OData service and write the parsed
+entity sets, entity and complex types, function imports and capabilities as JSON.
+The file can be inspected offline, trimmed by hand and passed back to the bridge
+with --metadata-file.
+
+Examples:
+  odata-mcp metadata --out parsed.json https://services.odata.org/V2/Northwind/Northwind.svc/
+  odata-mcp --metadata-file parsed.json https://services.odata.org/V2/Northwind/Northwind.svc/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMetadataExport,
+}
+
+// registerMetadataExport adds the metadata command with the service and authentication flags of the bridge
+func registerMetadataExport() {
+	addServiceFlags(metadataCmd)
+	metadataCmd.Flags().StringVarP(&metadataOut, "out", "o", "", "File to write the parsed metadata to (default: stdout)")
+	metadataCmd.MarkFlagFilename("out", "json")
+	rootCmd.AddCommand(metadataCmd)
+}
+
+func runMetadataExport(cmd *cobra.Command, args []string) error {
+	if err := resolveService(cmd, args); err != nil {
+		return startup.Wrap(startup.CategoryConfig, err)
+	}
+
+	odataClient := client.NewODataClient(cfg.ServiceURL, cfg.Verbose)
+	if cfg.HasBasicAuth() {
+		odataClient.SetBasicAuth(cfg.Username, cfg.Password)
+	} else if cfg.HasCookieAuth() {
+		odataClient.SetCookies(cfg.Cookies)
+	}
+
+	meta, err := odataClient.GetMetadata(cmd.Context())
+	if err != nil {
+		return startup.Wrap(startup.CategoryMetadata, fmt.Errorf("failed to fetch metadata: %w", err))
+	}
+	if meta.ParseError != "" {
+		slog.Warn("$metadata could not be parsed, exporting the entity sets of the service document", "error", meta.ParseError)
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	data = append(data, '\n')
+
+	if metadataOut == "" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(metadataOut, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d entity sets, %d entity types and %d function imports to %s\n",
+		len(meta.EntitySets), len(meta.EntityTypes), len(meta.FunctionImports), metadataOut)
+	return nil
+}
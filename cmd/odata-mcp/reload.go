@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/logging"
+)
+
+// commandLineFlags are the flags given on the command line, which reloads never override
+var commandLineFlags = make(map[string]bool)
+
+// reloadableFlags binds the options --watch-config applies at runtime to a configuration;
+// changes of other options need a restart
+func reloadableFlags(c *config.Config) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("reload", pflag.ContinueOnError)
+	flags.StringVar(&c.Entities, "entities", c.Entities, "")
+	flags.StringVar(&c.Functions, "functions", c.Functions, "")
+	flags.StringVar(&c.Operations, "operations", c.Operations, "")
+	flags.StringVar(&c.DisableOperations, "disable", c.DisableOperations, "")
+	flags.StringVar(&c.EntityOperations, "entity-ops", c.EntityOperations, "")
+	flags.StringVar(&c.EntityOperationsFile, "entity-ops-file", c.EntityOperationsFile, "")
+	flags.StringVar(&c.ConfirmOperations, "confirm", c.ConfirmOperations, "")
+	flags.IntVar(&c.MaxItems, "max-items", c.MaxItems, "")
+	flags.IntVar(&c.DefaultTop, "default-top", c.DefaultTop, "")
+	flags.IntVar(&c.HardTop, "hard-top", c.HardTop, "")
+	flags.IntVar(&c.MaxResponseSize, "max-response-size", c.MaxResponseSize, "")
+	flags.StringVar(&c.OutputFormat, "output-format", c.OutputFormat, "")
+	flags.IntVar(&c.TableMaxColumns, "table-max-columns", c.TableMaxColumns, "")
+	flags.IntVar(&c.TableMaxRows, "table-max-rows", c.TableMaxRows, "")
+	flags.BoolVar(&c.PaginationHints, "pagination-hints", c.PaginationHints, "")
+	flags.BoolVar(&c.ResponseMetadata, "response-metadata", c.ResponseMetadata, "")
+	flags.BoolVar(&c.RawResponses, "raw-responses", c.RawResponses, "")
+	flags.BoolVar(&c.AutoSelect, "auto-select", c.AutoSelect, "")
+	flags.StringVar(&c.AutoSelectFields, "auto-select-fields", c.AutoSelectFields, "")
+	addVerboseFlag(flags, c, "", "")
+	flags.BoolVar(&c.Debug, "debug", c.Debug, "")
+	flags.StringVar(&c.LogLevel, "log-level", c.LogLevel, "")
+	flags.BoolVar(&c.VerboseErrors, "verbose-errors", c.VerboseErrors, "")
+	flags.DurationVar(&c.SlowQueryThreshold, "slow-query-threshold", c.SlowQueryThreshold, "")
+	flags.BoolVar(&c.SAPStatistics, "sap-statistics", c.SAPStatistics, "")
+	flags.DurationVar(&c.CountCacheTTL, "count-cache-ttl", c.CountCacheTTL, "")
+	flags.IntVar(&c.MaxUnfilteredRows, "max-unfiltered-rows", c.MaxUnfilteredRows, "")
+	return flags
+}
+
+// loadSources reads the option values of the config file and the environment, with the
+// env files read afresh. Values of the config file take precedence.
+func loadSources(options *pflag.FlagSet) (map[string]string, error) {
+	values := make(map[string]string)
+
+	dotEnv, err := config.ReadEnvFiles(envFiles, true)
+	if err != nil {
+		return nil, err
+	}
+	options.VisitAll(func(flag *pflag.Flag) {
+		name := config.EnvName(flag.Name)
+		value := dotEnv[name]
+		if processEnv[name] {
+			value = os.Getenv(name)
+		}
+		if value != "" {
+			values[flag.Name] = value
+		}
+	})
+
+	if cfg.ConfigFile != "" {
+		fileValues, err := config.LoadFile(cfg.ConfigFile, cfg.Profile)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range fileValues {
+			values[name] = value
+		}
+	}
+	return values, nil
+}
+
+// reloadConfig derives a new configuration from the current one and the option values
+// of the config file and environment; options without a value are reset to the default
+// of the bridge flag. Options given on the command line are kept.
+func reloadConfig(current *config.Config, values map[string]string, options *pflag.FlagSet) (*config.Config, error) {
+	next := *current
+	flags := reloadableFlags(&next)
+
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || commandLineFlags[flag.Name] {
+			return
+		}
+		value, ok := values[flag.Name]
+		if !ok {
+			value = options.Lookup(flag.Name).DefValue
+		}
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value for %s: %w", flag.Name, setErr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if next.Debug {
+		next.Verbose = true
+	}
+	if err := parseOptions(&next); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// fileStamp identifies a version of a file; missing files have the zero stamp
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{info.ModTime(), info.Size()}
+}
+
+// startConfigWatch polls the config and env files for changes and applies them to the
+// bridge (--watch-config). options are the flags of the bridge.
+func startConfigWatch(b *bridge.ODataMCPBridge, options *pflag.FlagSet) error {
+	paths := append([]string{}, envFiles...)
+	if cfg.ConfigFile != "" {
+		paths = append(paths, cfg.ConfigFile)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("--watch-config requires a --config file or an env file")
+	}
+	values, err := loadSources(options)
+	if err != nil {
+		return err
+	}
+
+	stamps := make([]fileStamp, len(paths))
+	for i, path := range paths {
+		stamps[i] = statFile(path)
+	}
+	logger.Debug("Watching for configuration changes", "files", paths)
+
+	go func() {
+		current := cfg
+		ticker := time.NewTicker(constants.ConfigWatchInterval * time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			changed := false
+			for i, path := range paths {
+				if stamp := statFile(path); stamp != stamps[i] {
+					stamps[i] = stamp
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+
+			next, nextValues, err := applyReload(b, current, values, options)
+			if err != nil {
+				slog.Warn("Configuration not reloaded", "error", err)
+				continue
+			}
+			current, values = next, nextValues
+		}
+	}()
+	return nil
+}
+
+// applyReload reloads the configuration and applies it to the bridge, warning about
+// changed options that only take effect after a restart
+func applyReload(b *bridge.ODataMCPBridge, current *config.Config, previous map[string]string, options *pflag.FlagSet) (*config.Config, map[string]string, error) {
+	values, err := loadSources(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err := reloadConfig(current, values, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reloadable := reloadableFlags(&config.Config{})
+	options.VisitAll(func(flag *pflag.Flag) {
+		if values[flag.Name] != previous[flag.Name] && reloadable.Lookup(flag.Name) == nil && !commandLineFlags[flag.Name] {
+			slog.Warn("Option changed; restart odata-mcp to apply it", "option", flag.Name)
+		}
+	})
+
+	if err := logging.SetLevel(logLevel(next)); err != nil {
+		return nil, nil, err
+	}
+	if err := logging.SetScopes(logScopes(next)); err != nil {
+		return nil, nil, err
+	}
+	toolsChanged, err := b.ApplyConfig(next)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("Configuration reloaded", "tools_changed", toolsChanged)
+	return next, values, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/odata-mcp/go/internal/logging"
+)
+
+// addServiceFlags adds the service and authentication flags of the bridge to a subcommand
+// that talks to the service without serving MCP
+func addServiceFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&cfg.ConfigFile, "config", "", "YAML or JSON file with the service and authentication options")
+	flags.StringVar(&cfg.Profile, "profile", "", "Named profile of the --config file to use")
+	flags.StringArrayVar(&cfg.EnvFiles, "env-file", nil, "Load environment variables from this file instead of ./.env; repeat to layer files")
+	flags.StringVar(&cfg.ServiceURL, "service", "", "URL of the OData service (overrides positional argument)")
+	flags.StringVarP(&cfg.Username, "user", "u", "", "Username for basic authentication")
+	flags.StringVarP(&cfg.Password, "password", "p", "", "Password for basic authentication")
+	flags.StringVar(&cfg.Password, "pass", "", "Password for basic authentication (alias for --password)")
+	flags.StringVar(&cfg.CookieFile, "cookie-file", "", "Path to cookie file in Netscape format")
+	flags.StringVar(&cfg.CookieString, "cookie-string", "", "Cookie string (key1=val1; key2=val2)")
+	addVerboseFlag(flags, cfg, "v", "Enable verbose output (same as --log-level debug); --verbose=client,metadata logs debug messages of these subsystems only")
+	flags.StringVar(&cfg.LogLevel, "log-level", "", "Minimum level of log messages: 'debug', 'info', 'warn' or 'error' (default 'warn', 'debug' with --verbose)")
+	flags.StringVar(&cfg.LogFormat, "log-format", logging.FormatText, "Format of log messages: 'text' (key=value) or 'json' (one object per line, for Splunk/ELK)")
+	flags.StringVar(&cfg.LogFile, "log-file", "", "Append log messages to this file instead of writing them to stderr")
+	cmd.MarkFlagFilename("config", "yaml", "yml", "json")
+	cmd.MarkFlagFilename("cookie-file")
+	cmd.MarkFlagFilename("log-file")
+}
+
+// resolveService applies the config file and determines the service URL and credentials
+// of a subcommand the same way the bridge does
+func resolveService(cmd *cobra.Command, args []string) error {
+	logging.SetLevel(logLevel(cfg))
+	logging.SetScopes(logScopes(cfg))
+	if err := loadEnvFiles(); err != nil {
+		return err
+	}
+	if cfg.ConfigFile == "" {
+		cfg.ConfigFile = viper.GetString("CONFIG")
+	}
+	if cfg.Profile == "" {
+		cfg.Profile = viper.GetString("PROFILE")
+	}
+	if cfg.ConfigFile != "" {
+		if err := applyConfigFile(cmd, cfg.ConfigFile); err != nil {
+			return err
+		}
+	}
+	if err := applyEnvironment(cmd); err != nil {
+		return err
+	}
+	if err := configureLogging(cfg); err != nil {
+		return err
+	}
+
+	if cfg.ServiceURL == "" && len(args) > 0 {
+		cfg.ServiceURL = args[0]
+	}
+	if cfg.ServiceURL == "" {
+		cfg.ServiceURL = viper.GetString("URL")
+		if cfg.ServiceURL == "" {
+			cfg.ServiceURL = viper.GetString("SERVICE_URL")
+		}
+	}
+	if cfg.ServiceURL == "" {
+		return fmt.Errorf("OData service URL not provided. Use --service flag, positional argument, or ODATA_URL environment variable")
+	}
+
+	return processAuthentication(cfg)
+}
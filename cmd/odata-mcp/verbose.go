@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/pflag"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/logging"
+)
+
+// logger logs the handling of flags, config and env files and authentication
+var logger = logging.Subsystem(logging.SubsystemConfig)
+
+// verboseValue is the value of --verbose: a bool, or a comma-separated list of the
+// subsystems whose debug messages are logged (--verbose=client,metadata)
+type verboseValue struct {
+	cfg *config.Config
+}
+
+func (v verboseValue) Set(value string) error {
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		v.cfg.Verbose, v.cfg.VerboseScopes = enabled, ""
+		return nil
+	}
+	if _, err := logging.ParseScopes(value); err != nil {
+		return err
+	}
+	v.cfg.Verbose, v.cfg.VerboseScopes = true, value
+	return nil
+}
+
+func (v verboseValue) String() string {
+	if v.cfg == nil || !v.cfg.Verbose {
+		return "false"
+	}
+	if v.cfg.VerboseScopes != "" {
+		return v.cfg.VerboseScopes
+	}
+	return "true"
+}
+
+// Type is "bool" so that help shows --verbose like the other switches
+func (v verboseValue) Type() string {
+	return "bool"
+}
+
+// addVerboseFlag adds --verbose to a flag set; without a value it logs the debug
+// messages of all subsystems
+func addVerboseFlag(flags *pflag.FlagSet, c *config.Config, shorthand, usage string) {
+	flag := flags.VarPF(verboseValue{cfg: c}, "verbose", shorthand, usage)
+	flag.NoOptDefVal = "true"
+	flag.DefValue = "false"
+}
+
+// logScopes returns the subsystems whose debug messages a configuration logs; --debug
+// and a --log-level of debug without --verbose=<subsystems> log all of them
+func logScopes(cfg *config.Config) string {
+	if cfg.Debug {
+		return ""
+	}
+	return cfg.VerboseScopes
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/odata-mcp/go/internal/constants"
+)
+
+// Build metadata, set with -ldflags "-X main.Version=... -X main.Commit=... -X main.BuildTime=..."
+// (see Makefile and build.sh)
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version, build metadata and supported protocol versions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		commit, buildTime := buildMetadata()
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "odata-mcp %s\n", Version)
+		fmt.Fprintf(out, "  Commit:         %s\n", commit)
+		fmt.Fprintf(out, "  Built:          %s\n", buildTime)
+		fmt.Fprintf(out, "  Go:             %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+		fmt.Fprintf(out, "  MCP protocols:  %s\n", strings.Join(constants.MCPSupportedProtocolVersions, ", "))
+		fmt.Fprintf(out, "  OData versions: v2, v4\n")
+	},
+}
+
+// buildMetadata returns the commit and build time given by -ldflags, falling back to the
+// VCS information Go embeds in binaries built with go build or go install
+func buildMetadata() (string, string) {
+	commit, buildTime := Commit, BuildTime
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return commit, buildTime
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if commit == "unknown" && len(setting.Value) >= 7 {
+				commit = setting.Value[:7]
+			}
+		case "vcs.time":
+			if buildTime == "unknown" {
+				buildTime = setting.Value + " (commit time)"
+			}
+		case "vcs.modified":
+			if setting.Value == "true" && Commit == "unknown" && commit != "unknown" {
+				commit += "-dirty"
+			}
+		}
+	}
+	return commit, buildTime
+}
@@ -0,0 +1,104 @@
+// Package audit appends a record of every modifying operation (create, update, delete and
+// non-GET function imports) to a JSON lines file set with --audit-log. Records are only
+// ever appended; payloads are stored as SHA-256 hashes, not in clear text.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Result statuses of a record
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// Record is one audited operation
+type Record struct {
+	Time        time.Time              `json:"time"`
+	Tool        string                 `json:"tool"`
+	Operation   string                 `json:"operation"`      // create, update, delete or function
+	Entity      string                 `json:"entity"`         // Entity set or function import
+	Key         map[string]interface{} `json:"key,omitempty"`  // Key of the updated or deleted entity
+	User        string                 `json:"user,omitempty"` // User of the OData service, if known
+	Session     string                 `json:"session,omitempty"`
+	RequestID   string                 `json:"request_id,omitempty"` // X-Request-ID of the tool call
+	PayloadHash string                 `json:"payload_sha256,omitempty"`
+	Status      string                 `json:"status"`
+	HTTPStatus  int                    `json:"http_status,omitempty"` // Status code of a rejected request
+	Error       string                 `json:"error,omitempty"`
+}
+
+// file is the open audit log; nil when auditing is off
+var (
+	mu   sync.Mutex
+	file *os.File
+)
+
+// Open starts appending records to a file, creating it if needed. The returned function
+// closes the file.
+func Open(path string) (func() error, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	mu.Lock()
+	file = f
+	mu.Unlock()
+
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if file == f {
+			file = nil
+		}
+		return f.Close()
+	}, nil
+}
+
+// Enabled reports whether an audit log is open
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return file != nil
+}
+
+// Write appends a record as one JSON line and syncs it to disk. Without an open audit log
+// it does nothing.
+func Write(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return nil
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return file.Sync()
+}
+
+// HashPayload returns the hex SHA-256 hash of the JSON encoding of a payload (map keys
+// sorted), or "" for an empty payload
+func HashPayload(payload map[string]interface{}) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+// Package bench measures the latency of tool calls against a live service (odata-mcp
+// bench), to size deployments and find slow backends before agents hit them
+package bench
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Caller invokes a tool, such as ODataMCPBridge.CallTool
+type Caller func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error)
+
+// Target is a tool call to measure
+type Target struct {
+	Tool string
+	Args map[string]interface{}
+}
+
+// Options control a benchmark run
+type Options struct {
+	Concurrency int // Calls of a tool in flight at the same time
+	Requests    int // Calls per tool
+}
+
+// Result holds the latencies of the calls of one tool
+type Result struct {
+	Tool       string        `json:"tool"`
+	Calls      int           `json:"calls"`
+	Errors     int           `json:"errors"`
+	FirstError string        `json:"first_error,omitempty"`
+	Duration   time.Duration `json:"duration_ns"`
+	P50        time.Duration `json:"p50_ns"`
+	P90        time.Duration `json:"p90_ns"`
+	P99        time.Duration `json:"p99_ns"`
+	Max        time.Duration `json:"max_ns"`
+}
+
+// Throughput returns the completed calls per second
+func (r *Result) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Calls) / r.Duration.Seconds()
+}
+
+// Run calls each target opts.Requests times, opts.Concurrency calls at a time. The
+// targets run one after another, so that their latencies do not affect each other.
+func Run(ctx context.Context, call Caller, targets []Target, opts Options) []Result {
+	results := make([]Result, 0, len(targets))
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, runTarget(ctx, call, target, opts))
+	}
+	return results
+}
+
+// runTarget measures the calls of one target
+func runTarget(ctx context.Context, call Caller, target Target, opts Options) Result {
+	requests := max(opts.Requests, 1)
+	workers := min(max(opts.Concurrency, 1), requests)
+
+	var mu sync.Mutex
+	result := Result{Tool: target.Tool}
+	latencies := make([]time.Duration, 0, requests)
+
+	start := time.Now()
+	var called sync.WaitGroup
+	next := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		called.Add(1)
+		go func() {
+			defer called.Done()
+			for range next {
+				args := make(map[string]interface{}, len(target.Args))
+				for k, v := range target.Args {
+					args[k] = v
+				}
+				callStart := time.Now()
+				_, err := call(ctx, target.Tool, args)
+				latency := time.Since(callStart)
+
+				mu.Lock()
+				result.Calls++
+				latencies = append(latencies, latency)
+				if err != nil {
+					if result.Errors == 0 {
+						result.FirstError = err.Error()
+					}
+					result.Errors++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < requests && ctx.Err() == nil; i++ {
+		select {
+		case next <- struct{}{}:
+		case <-ctx.Done():
+		}
+	}
+	close(next)
+	called.Wait()
+	result.Duration = time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50 = Percentile(latencies, 50)
+	result.P90 = Percentile(latencies, 90)
+	result.P99 = Percentile(latencies, 99)
+	if len(latencies) > 0 {
+		result.Max = latencies[len(latencies)-1]
+	}
+	return result
+}
+
+// Percentile returns the nearest-rank percentile p (0-100) of sorted latencies
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
@@ -0,0 +1,263 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// aggregateSetResult is the result of one entity set of an aggregate_across call: the
+// filter tool result of the entity set or its error
+type aggregateSetResult struct {
+	EntitySet string          `json:"entity_set"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// aggregateEntityNames returns the sorted included entity sets that support filtering
+func (b *ODataMCPBridge) aggregateEntityNames() []string {
+	var names []string
+	for _, name := range b.includedEntityNames() {
+		for _, operation := range b.entitySetOperations(name, b.metadata.EntitySets[name]) {
+			if operation == constants.OpFilter {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// hasAggregateTool reports whether the aggregate_across tool is generated
+func (b *ODataMCPBridge) hasAggregateTool() bool {
+	return b.config.AggregateAcross && len(b.aggregateEntityNames()) >= 2
+}
+
+// generateAggregateTool creates the tool that runs the same query across several entity
+// sets concurrently, e.g. open items of the entity sets of several company codes. With
+// --aggregate-across, it is generated if at least two entity sets support filtering.
+func (b *ODataMCPBridge) generateAggregateTool() {
+	if !b.hasAggregateTool() {
+		return
+	}
+	entityNames := b.aggregateEntityNames()
+	toolName := b.formatToolName("aggregate_across", "")
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: "Run the same query across several entity sets concurrently and return the results of all of them in one response, instead of one filter call per entity set",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"entity_sets": map[string]interface{}{
+					"type":        "array",
+					"description": "Names of the entity sets to query (see odata_service_info)",
+					"items":       map[string]interface{}{"type": "string", "enum": entityNames},
+					"minItems":    1,
+				},
+				"$filter":  map[string]interface{}{"type": "string", "description": "OData filter expression applied to every entity set"},
+				"$select":  map[string]interface{}{"type": "string", "description": "Comma-separated list of properties to select"},
+				"$orderby": map[string]interface{}{"type": "string", "description": "Properties to order by"},
+				"$top":     map[string]interface{}{"type": "integer", "description": "Maximum number of entities to return per entity set"},
+			},
+			"required": []string{"entity_sets"},
+		},
+	}
+	if !b.config.DryRun {
+		tool.OutputSchema = map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"entity_sets": map[string]interface{}{"type": "integer"},
+				"failed":      map[string]interface{}{"type": "integer"},
+				"truncated":   map[string]interface{}{"type": "boolean"},
+				"results": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"entity_set": map[string]interface{}{"type": "string"},
+							"result":     map[string]interface{}{"type": "object"},
+							"error":      map[string]interface{}{"type": "string"},
+						},
+						"required": []string{"entity_set"},
+					},
+				},
+			},
+			"required": []string{"entity_sets", "failed", "results"},
+		}
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleAggregateAcross(ctx, args)
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: tool.Description,
+		Operation:   constants.OpFilter,
+	})
+}
+
+// handleAggregateAcross runs the filter tool of each requested entity set with the same
+// query options, --aggregate-workers entity sets at a time. A failing entity set does
+// not fail the others; its error is reported in its result. The entity sets share
+// --max-response-size: each result is truncated to its share, and results that still do
+// not fit are omitted from the end.
+func (b *ODataMCPBridge) handleAggregateAcross(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	supported := make(map[string]bool)
+	for _, name := range b.aggregateEntityNames() {
+		supported[name] = true
+	}
+	requested, _ := args["entity_sets"].([]interface{})
+	var entitySets []string
+	seen := make(map[string]bool)
+	for _, item := range requested {
+		name, _ := item.(string)
+		if !supported[name] {
+			return nil, fmt.Errorf("invalid entity: entity set not found: %s", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			entitySets = append(entitySets, name)
+		}
+	}
+	if len(entitySets) == 0 {
+		return nil, fmt.Errorf("%w: entity_sets must name at least one entity set", mcp.ErrInvalidArguments)
+	}
+
+	// Every entity set is queried with the same options, always as JSON
+	queryArgs := map[string]interface{}{"output_format": constants.OutputFormatJSON}
+	for _, option := range []string{"$filter", "$select", "$orderby", "$top"} {
+		if value, ok := args[option]; ok {
+			queryArgs[option] = value
+		}
+	}
+
+	setView := b
+	if limit := b.config.MaxResponseSize; limit > 0 {
+		cfg := *b.config
+		cfg.MaxResponseSize = max(limit/len(entitySets), 1)
+		view := *b
+		view.config = &cfg
+		setView = &view
+	}
+
+	results := make([]aggregateSetResult, len(entitySets))
+	workers := b.config.AggregateWorkers
+	if workers <= 0 || workers > len(entitySets) {
+		workers = len(entitySets)
+	}
+	// The queries must not interleave their own progress with the progress per entity set
+	queryCtx := mcp.WithoutProgress(ctx)
+
+	var mu sync.Mutex
+	completed := 0
+	var queried sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		queried.Add(1)
+		go func() {
+			defer queried.Done()
+			for i := range next {
+				err := mcp.Recovered(entitySets[i], func() error {
+					results[i] = setView.aggregateEntitySet(queryCtx, entitySets[i], queryArgs)
+					return nil
+				})
+				if err != nil {
+					results[i] = aggregateSetResult{EntitySet: entitySets[i], Error: err.Error()}
+				}
+
+				mu.Lock()
+				completed++
+				mcp.ReportProgress(ctx, float64(completed), float64(len(entitySets)), fmt.Sprintf("Queried %s", entitySets[i]))
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range entitySets {
+		next <- i
+	}
+	close(next)
+	queried.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := aggregateResponse(results, false)
+	if err != nil {
+		return nil, err
+	}
+	// Results with entities larger than their share can still exceed the limit together
+	limit := b.config.MaxResponseSize
+	for i := len(results) - 1; i > 0 && limit > 0 && len(data) > limit; i-- {
+		if results[i].Result == nil {
+			continue
+		}
+		results[i].Result = nil
+		results[i].Error = fmt.Sprintf("result omitted: the results of all entity sets exceed the response size limit (%d bytes); query this entity set on its own or use $select and $top", limit)
+		if data, err = aggregateResponse(results, true); err != nil {
+			return nil, err
+		}
+	}
+	return string(data), nil
+}
+
+// aggregateResponse encodes the results of an aggregate_across call
+func aggregateResponse(results []aggregateSetResult, truncated bool) ([]byte, error) {
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	response := map[string]interface{}{
+		"entity_sets": len(results),
+		"failed":      failed,
+		"results":     results,
+	}
+	if truncated {
+		response["truncated"] = true
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	return data, nil
+}
+
+// aggregateEntitySet runs the filter tool of one entity set of an aggregate_across call.
+// With --dry-run, the result is the request the query would have sent.
+func (b *ODataMCPBridge) aggregateEntitySet(ctx context.Context, entitySetName string, args map[string]interface{}) aggregateSetResult {
+	queryArgs := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		queryArgs[k] = v
+	}
+	result := aggregateSetResult{EntitySet: entitySetName}
+	response, err := b.handleEntityFilter(ctx, entitySetName, queryArgs)
+	var request *client.DryRunRequest
+	if errors.As(err, &request) {
+		response, err = request, nil
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if text, ok := response.(string); ok {
+		result.Result = json.RawMessage(text)
+		return result
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to format response: %v", err)
+		return result
+	}
+	result.Result = data
+	return result
+}
@@ -0,0 +1,62 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/odata-mcp/go/internal/audit"
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// toolNameKey carries the name of the called tool to the operation handlers
+type toolNameKey struct{}
+
+func withToolName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolNameKey{}, name)
+}
+
+func toolNameFrom(ctx context.Context) string {
+	name, _ := ctx.Value(toolNameKey{}).(string)
+	return name
+}
+
+// SetSessionID names the MCP session recorded in audit records of this bridge
+func (b *ODataMCPBridge) SetSessionID(id string) {
+	b.sessionID = id
+}
+
+// auditOperation appends a record of a modifying request sent to the service to the
+// audit log (--audit-log). Dry runs modify nothing and are not recorded. The operation
+// has already run, so a failure to write the record is logged, not returned.
+func (b *ODataMCPBridge) auditOperation(ctx context.Context, operation, target string, key, payload map[string]interface{}, err error) {
+	if b.config.DryRun || !audit.Enabled() {
+		return
+	}
+
+	record := audit.Record{
+		Time:        time.Now().UTC(),
+		Tool:        toolNameFrom(ctx),
+		Operation:   operation,
+		Entity:      target,
+		Key:         key,
+		User:        b.client.User(),
+		Session:     b.sessionID,
+		RequestID:   client.RequestIDFrom(ctx),
+		PayloadHash: audit.HashPayload(payload),
+		Status:      audit.StatusSuccess,
+	}
+	if err != nil {
+		record.Status = audit.StatusFailed
+		record.Error = redact.String(err.Error())
+		var statusErr *client.StatusError
+		if errors.As(err, &statusErr) {
+			record.HTTPStatus = statusErr.StatusCode
+		}
+	}
+
+	if err := audit.Write(record); err != nil {
+		b.logger.Error("Failed to write audit record", "tool", record.Tool, "error", err)
+	}
+}
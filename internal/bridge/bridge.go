@@ -3,88 +3,347 @@ package bridge
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/odata-mcp/go/internal/client"
 	"github.com/odata-mcp/go/internal/config"
 	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/logging"
 	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/metrics"
 	"github.com/odata-mcp/go/internal/models"
+	"github.com/odata-mcp/go/internal/redact"
+	"github.com/odata-mcp/go/internal/tracing"
 	"github.com/odata-mcp/go/internal/utils"
 )
 
-// ODataMCPBridge connects OData services to MCP
+// ODataMCPBridge connects OData services to MCP. A tool call works with a view of the
+// bridge pinned to the metadata snapshot and configuration current when it started (see
+// pin); the view shares all other state with the bridge.
 type ODataMCPBridge struct {
-	config     *config.Config
-	client     *client.ODataClient
-	server     *mcp.Server
-	metadata   *models.ODataMetadata
-	tools      map[string]*models.ToolInfo
-	mu         sync.RWMutex
-	running    bool
-	stopChan   chan struct{}
+	*bridgeState
+	config   *config.Config        // Configuration this view is pinned to
+	metadata *models.ODataMetadata // Metadata of the snapshot this view is pinned to
+	dialect  *client.Dialect       // Client dialect of the same snapshot
+}
+
+// snapshot is the metadata the bridge serves together with the client dialect derived
+// from it. A metadata refresh publishes a new snapshot instead of changing the current one.
+type snapshot struct {
+	metadata *models.ODataMetadata
+	dialect  *client.Dialect
+}
+
+// bridgeState is the state a bridge shares with the views of its tool calls
+type bridgeState struct {
+	settings    atomic.Pointer[config.Config] // Current configuration, replaced by ApplyConfig
+	client      *client.ODataClient
+	server      *mcp.Server
+	current     atomic.Pointer[snapshot] // Published metadata snapshot (nil = none loaded yet)
+	refreshMu   sync.Mutex               // Serializes metadata refreshes and configuration reloads
+	tools       map[string]*models.ToolInfo
+	lazyTools   bool            // Entity tools are registered on demand (--max-tools)
+	enabled     map[string]bool // Entity sets ("entity:" keys) and functions ("function:" keys) whose tools are registered
+	enableMu    sync.Mutex      // Serializes on-demand registration, so each reports only its own tools
+	collisions  []string        // Tool names that had to be disambiguated
+	toolSet     []sharedTool    // Registered tools in order, which HTTP sessions share
+	shared      *ODataMCPBridge // Bridge whose tools an HTTP session shares (nil = generates its own)
+	logger      *slog.Logger
+	sessionID   string     // HTTP session recorded in audit records
+	counts      countCache // Entity counts reused for --count-cache-ttl
+	splitExpand sync.Map   // Entity sets whose backend rejects expanding several navigation properties at once
+	mu          sync.RWMutex
+	running     bool
+	stopChan    chan struct{}
+}
+
+// toolHandler handles a tool call on the bridge it is called for, which for a shared
+// tool is the HTTP session's bridge rather than the one that generated the tool
+type toolHandler func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// sharedTool is a generated tool with the handler it is registered with
+type sharedTool struct {
+	tool    *mcp.Tool
+	info    *models.ToolInfo
+	handler toolHandler
 }
 
 // NewODataMCPBridge creates a new bridge instance
 func NewODataMCPBridge(cfg *config.Config) (*ODataMCPBridge, error) {
-	// Create OData client
-	odataClient := client.NewODataClient(cfg.ServiceURL, cfg.Verbose)
+	bridge := newBridge(cfg)
 
-	// Configure authentication
-	if cfg.HasBasicAuth() {
-		odataClient.SetBasicAuth(cfg.Username, cfg.Password)
-	} else if cfg.HasCookieAuth() {
-		odataClient.SetCookies(cfg.Cookies)
+	// Initialize metadata and tools
+	if err := bridge.initialize(); err != nil {
+		return nil, fmt.Errorf("failed to initialize bridge: %w", err)
 	}
 
+	return bridge, nil
+}
+
+// newBridge creates a bridge with its own OData client and MCP server but no tools yet
+func newBridge(cfg *config.Config) *ODataMCPBridge {
+	odataClient := NewClient(cfg)
+
 	// Create MCP server
 	mcpServer := mcp.NewServer(constants.MCPServerName, constants.MCPServerVersion)
+	mcpServer.SetToolsPageSize(cfg.ToolsPageSize)
+	if cfg.Transport == constants.TransportHTTP {
+		mcpServer.SetKeepAlive(cfg.KeepAliveInterval, cfg.IdleTimeout)
+	}
+	mcpServer.SetToolTimeout(cfg.ToolTimeout)
+	mcpServer.SetVerbose(cfg.Verbose || cfg.VerboseErrors)
+	mcpServer.SetMaxConcurrentCalls(cfg.MaxConcurrentCalls)
+	mcpServer.SetMaxMessageSize(cfg.MaxMessageSize)
 
-	bridge := &ODataMCPBridge{
-		config:   cfg,
+	// Stream client diagnostics to the MCP client as log notifications
+	odataClient.SetLogHook(func(level, message string) {
+		mcpServer.Log(level, "odata-client", message)
+	})
+
+	b := &ODataMCPBridge{config: cfg, bridgeState: &bridgeState{
 		client:   odataClient,
 		server:   mcpServer,
 		tools:    make(map[string]*models.ToolInfo),
+		enabled:  make(map[string]bool),
+		logger:   logging.Subsystem(logging.SubsystemTools),
 		stopChan: make(chan struct{}),
+	}}
+	b.settings.Store(cfg)
+	return b
+}
+
+// NewClient creates an OData client for the configured service with the transport,
+// limits and credentials of the configuration
+func NewClient(cfg *config.Config) *client.ODataClient {
+	odataClient := client.NewODataClient(cfg.ServiceURL, cfg.Verbose)
+	odataClient.SetFormatParam(cfg.FormatParam)
+	odataClient.SetSlowQueryThreshold(cfg.SlowQueryThreshold)
+	odataClient.SetSAPStatistics(cfg.SAPStatistics)
+	odataClient.SetRequestLog(cfg.DebugRequests)
+	odataClient.SetMetadataCache(cfg.MetadataCacheDir)
+	odataClient.SetMaxMetadataSize(int64(cfg.MaxMetadataSize))
+	odataClient.SetTransport(client.TransportOptions{
+		TCPKeepAlive:        cfg.TCPKeepAlive,
+		IdleConnTimeout:     cfg.IdleConnTimeout,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		TLSSessionCache:     cfg.TLSSessionCache,
+		DisableHTTP2:        cfg.DisableHTTP2,
+	})
+	if cfg.MaxResponseSize > 0 {
+		odataClient.SetMaxBodySize(int64(cfg.MaxResponseSize) * constants.ResponseBodySizeFactor)
 	}
 
-	// Initialize metadata and tools
-	if err := bridge.initialize(); err != nil {
-		return nil, fmt.Errorf("failed to initialize bridge: %w", err)
+	// Configure authentication
+	if cfg.HasBasicAuth() {
+		odataClient.SetBasicAuth(cfg.Username, cfg.Password)
+	} else if cfg.HasCookieAuth() {
+		odataClient.SetCookies(cfg.Cookies)
 	}
+	return odataClient
+}
 
-	return bridge, nil
+// publish makes metadata the snapshot that tool calls starting from now on work with
+func (b *ODataMCPBridge) publish(metadata *models.ODataMetadata) {
+	b.current.Store(&snapshot{metadata: metadata, dialect: client.NewDialect(metadata)})
+}
+
+// pin returns a view of the bridge that works with the current configuration and
+// metadata snapshot throughout, however often they are replaced meanwhile
+func (b *ODataMCPBridge) pin() *ODataMCPBridge {
+	view := *b
+	view.config = b.settings.Load()
+	if s := b.current.Load(); s != nil {
+		view.metadata, view.dialect = s.metadata, s.dialect
+	}
+	return &view
+}
+
+// pinCall pins a view for a call and makes the call's OData requests use its dialect
+func (b *ODataMCPBridge) pinCall(ctx context.Context) (*ODataMCPBridge, context.Context) {
+	view := b.pin()
+	if view.dialect != nil {
+		ctx = client.WithDialect(ctx, view.dialect)
+	}
+	return view, ctx
+}
+
+// NewSession creates a bridge for one network client. It reuses the parsed
+// metadata and the generated tools but has its own OData client (CSRF token,
+// cookies, credentials), MCP server and enabled tools, so no state leaks between
+// sessions. A non-empty authorization header replaces the configured credentials
+// for this session.
+func (b *ODataMCPBridge) NewSession(authorization string) (*ODataMCPBridge, error) {
+	current := b.current.Load()
+	session := newBridge(b.settings.Load())
+	session.client.ApplyMetadata(current.metadata)
+	if authorization != "" {
+		session.client.SetAuthorization(authorization)
+	}
+	session.current.Store(current)
+	session.metadata, session.dialect = current.metadata, current.dialect
+	session.shared = b
+
+	if err := session.setup(); err != nil {
+		return nil, err
+	}
+	return session, nil
 }
 
 // initialize loads metadata and generates tools
 func (b *ODataMCPBridge) initialize() error {
-	ctx := context.Background()
-
 	// Fetch metadata
-	metadata, err := b.client.GetMetadata(ctx)
+	metadata, err := b.loadStartupMetadata()
 	if err != nil {
-		return fmt.Errorf("failed to fetch metadata: %w", err)
+		return err
+	}
+
+	b.publish(metadata)
+	b.metadata, b.dialect = metadata, b.current.Load().dialect
+	if err := b.setup(); err != nil {
+		return err
 	}
 
-	b.metadata = metadata
+	// Sessions reuse this metadata, so only the bridge that loaded it reports the startup
+	b.logStartupSummary()
+	b.warnInactiveUnfilteredGuard()
+	return nil
+}
+
+// loadMetadata fetches the service metadata, or reads it from --metadata-file
+func (b *ODataMCPBridge) loadMetadata(ctx context.Context) (*models.ODataMetadata, error) {
+	logger := logging.Subsystem(logging.SubsystemMetadata)
+	if b.config.MetadataFile == "" {
+		first := b.current.Load() == nil
 
-	// Generate tools
-	if err := b.generateTools(); err != nil {
-		return fmt.Errorf("failed to generate tools: %w", err)
+		// With --startup-probes, the first fetch probes the service concurrently
+		fetch := b.client.GetMetadata
+		if first && b.config.StartupProbes {
+			fetch = b.client.GetMetadataWithProbes
+		}
+		metadata, err := fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+		}
+		logger.Debug("Fetched metadata", "version", metadata.Version, "entity_sets", len(metadata.EntitySets), "functions", len(metadata.FunctionImports), "sap", metadata.IsSAP)
+		return metadata, nil
 	}
 
+	metadata, err := b.client.LoadMetadataFile(b.config.MetadataFile)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("Loaded metadata from file", "file", b.config.MetadataFile, "version", metadata.Version, "entity_sets", len(metadata.EntitySets), "functions", len(metadata.FunctionImports))
+	return metadata, nil
+}
+
+// setup registers resource templates and completions for loaded metadata. The tools are
+// generated when a client first lists or calls them, so sessions and commands that never
+// list the tools do not pay for generating them.
+func (b *ODataMCPBridge) setup() error {
+	b.server.SetToolsLoader(func() error {
+		started := time.Now()
+		view := b.pin()
+		if view.shareTools() {
+			return nil
+		}
+		if err := view.generateTools(); err != nil {
+			return fmt.Errorf("failed to generate tools: %w", err)
+		}
+		view.mu.RLock()
+		tools := len(view.tools)
+		view.mu.RUnlock()
+		view.logger.Debug("Generated tools", "tools", tools, "ms", time.Since(started).Milliseconds())
+		return nil
+	})
+
+	// Expose entities as resources for clients that prefer resources over tools
+	b.generateResourceTemplates()
+
+	b.server.SetCompletionHandler(func(ctx context.Context, req *mcp.CompletionRequest) ([]string, error) {
+		view, ctx := b.pinCall(ctx)
+		return view.handleCompletion(ctx, req)
+	})
+
 	return nil
 }
 
 // generateTools creates MCP tools based on metadata
 func (b *ODataMCPBridge) generateTools() error {
-	// 1. Generate service info tool first
+	// 1. Generate service info, metadata refresh and statistics tools first
 	b.generateServiceInfoTool()
+	if b.config.MetadataRefreshInterval > 0 {
+		b.generateRefreshMetadataTool()
+	}
+	b.generateStatsTool()
+	if b.config.DebugRequests > 0 {
+		b.generateDebugRequestsTool()
+	}
+	b.generateAggregateTool()
+	b.generateChangesetTool()
+
+	if b.config.CompactTools {
+		b.generateCompactTools()
+		return nil
+	}
+
+	entityNames := b.includedEntityNames()
+	functionNames := b.includedFunctionNames()
+
+	// Fall back to on-demand registration if the full tool set exceeds --max-tools
+	if b.config.MaxTools > 0 {
+		total := 2 + len(functionNames)
+		if b.config.MetadataRefreshInterval > 0 {
+			total++
+		}
+		if b.config.DebugRequests > 0 {
+			total++
+		}
+		if b.hasAggregateTool() {
+			total++
+		}
+		if b.hasChangesetTool() {
+			total++
+		}
+		for _, name := range entityNames {
+			entitySet := b.metadata.EntitySets[name]
+			total += len(b.entitySetOperations(name, entitySet))
+			if n := len(b.valueHelpProperties(entitySet)); n > 0 {
+				// One value help tool per property, counted once as an operation above
+				total += n - 1
+			}
+		}
+		if total > b.config.MaxTools {
+			b.logger.Debug("Tools exceed --max-tools, entity tools will be registered on demand", "tools", total, "max_tools", b.config.MaxTools)
+			b.lazyTools = true
+			b.generateLazyTools()
+			return nil
+		}
+	}
 
 	// 2. Generate entity set tools in alphabetical order
+	for _, name := range entityNames {
+		b.enableEntitySet(name)
+	}
+
+	// 3. Generate function import tools in alphabetical order
+	for _, name := range functionNames {
+		b.enableFunction(name)
+	}
+
+	return nil
+}
+
+// includedEntityNames returns the sorted entity set names that pass the entity filter
+func (b *ODataMCPBridge) includedEntityNames() []string {
 	entityNames := make([]string, 0, len(b.metadata.EntitySets))
 	for name := range b.metadata.EntitySets {
 		if b.shouldIncludeEntity(name) {
@@ -92,13 +351,14 @@ func (b *ODataMCPBridge) generateTools() error {
 		}
 	}
 	sort.Strings(entityNames)
-	
-	for _, name := range entityNames {
-		entitySet := b.metadata.EntitySets[name]
-		b.generateEntitySetTools(name, entitySet)
-	}
+	return entityNames
+}
 
-	// 3. Generate function import tools in alphabetical order
+// includedFunctionNames returns the sorted function import names that pass the function and operation filters
+func (b *ODataMCPBridge) includedFunctionNames() []string {
+	if !b.config.IsOperationEnabled(constants.OpFunction) {
+		return nil
+	}
 	functionNames := make([]string, 0, len(b.metadata.FunctionImports))
 	for name := range b.metadata.FunctionImports {
 		if b.shouldIncludeFunction(name) {
@@ -106,13 +366,312 @@ func (b *ODataMCPBridge) generateTools() error {
 		}
 	}
 	sort.Strings(functionNames)
-	
-	for _, name := range functionNames {
-		function := b.metadata.FunctionImports[name]
-		b.generateFunctionTool(name, function)
+	return functionNames
+}
+
+// enableEntitySet registers the tools of an entity set, returning the new tool names
+func (b *ODataMCPBridge) enableEntitySet(name string) []string {
+	b.enableMu.Lock()
+	defer b.enableMu.Unlock()
+	before, ok := b.markEnabled(entityEnabledKey(name))
+	if !ok {
+		return nil
 	}
+	b.generateEntitySetTools(name, b.metadata.EntitySets[name])
+	return b.newToolNames(before)
+}
 
-	return nil
+// enableFunction registers the tool of a function import, returning the new tool names
+func (b *ODataMCPBridge) enableFunction(name string) []string {
+	b.enableMu.Lock()
+	defer b.enableMu.Unlock()
+	before, ok := b.markEnabled(functionEnabledKey(name))
+	if !ok {
+		return nil
+	}
+	b.generateFunctionTool(name, b.metadata.FunctionImports[name])
+	return b.newToolNames(before)
+}
+
+// restoreEnabled registers again, in lazy mode (--max-tools), the tools of the entity sets
+// and functions of a previous enabled set that are still available
+func (b *ODataMCPBridge) restoreEnabled(enabled map[string]bool) {
+	b.mu.RLock()
+	lazyTools := b.lazyTools
+	b.mu.RUnlock()
+	if !lazyTools {
+		return
+	}
+	keys := make([]string, 0, len(enabled))
+	for key := range enabled {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if name, ok := strings.CutPrefix(key, entityEnabledKey("")); ok {
+			if _, exists := b.metadata.EntitySets[name]; exists && b.shouldIncludeEntity(name) {
+				b.enableEntitySet(name)
+			}
+		} else if name, ok := strings.CutPrefix(key, functionEnabledKey("")); ok {
+			if _, exists := b.metadata.FunctionImports[name]; exists && b.shouldIncludeFunction(name) && b.config.IsOperationEnabled(constants.OpFunction) {
+				b.enableFunction(name)
+			}
+		}
+	}
+}
+
+// entityEnabledKey and functionEnabledKey key b.enabled, so an entity set and a function
+// import with the same name are enabled separately
+func entityEnabledKey(name string) string { return "entity:" + name }
+func functionEnabledKey(name string) string { return "function:" + name }
+
+// markEnabled marks an entity set or function as enabled and returns the tool names
+// registered before, or false if it was already enabled
+func (b *ODataMCPBridge) markEnabled(key string) (map[string]bool, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.enabled[key] {
+		return nil, false
+	}
+	b.enabled[key] = true
+	names := make(map[string]bool, len(b.tools))
+	for name := range b.tools {
+		names[name] = true
+	}
+	return names, true
+}
+
+// newToolNames returns the sorted tool names registered since the given snapshot
+func (b *ODataMCPBridge) newToolNames(before map[string]bool) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	added := make([]string, 0)
+	for name := range b.tools {
+		if !before[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	return added
+}
+
+// registerTool adds a tool to the MCP server and tracks its info. If another
+// entity set, function or operation already uses the tool name, the new tool
+// gets a numeric suffix instead of silently replacing the existing one.
+func (b *ODataMCPBridge) registerTool(tool *mcp.Tool, handler toolHandler, info *models.ToolInfo) {
+	b.mu.Lock()
+	if existing, exists := b.tools[tool.Name]; exists && !sameToolTarget(existing, info) {
+		original := tool.Name
+		tool.Name = b.disambiguatedToolName(original, info)
+		info.Name = tool.Name
+		b.collisions = append(b.collisions, fmt.Sprintf("%s -> %s", original, tool.Name))
+		b.logger.Debug("Tool name collision", "tool", original, "renamed", tool.Name)
+	}
+	b.tools[tool.Name] = info
+	b.mu.Unlock()
+
+	if tool.Annotations == nil {
+		tool.Annotations = b.toolAnnotations(info)
+	}
+	if tool.OutputSchema == nil && !b.config.DryRun {
+		// Dry run results are request descriptions, not the declared output
+		tool.OutputSchema = b.toolOutputSchema(info)
+	}
+
+	b.mu.Lock()
+	b.toolSet = append(b.toolSet, sharedTool{tool: tool, info: info, handler: handler})
+	b.mu.Unlock()
+	b.server.AddTool(tool, b.boundHandler(tool.Name, handler))
+}
+
+// boundHandler returns the MCP handler running a tool handler on a view of this bridge
+// pinned to the metadata snapshot current when the call starts
+func (b *ODataMCPBridge) boundHandler(name string, handler toolHandler) mcp.ToolHandler {
+	bound := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		view, ctx := b.pinCall(ctx)
+		return handler(view, ctx, args)
+	}
+	if b.config.DryRun {
+		bound = dryRunHandler(bound)
+	}
+	return instrumentedHandler(name, bound)
+}
+
+// shareTools registers the tools the bridge an HTTP session was created from has
+// generated, bound to the session, instead of generating them again. The tool
+// definitions are shared read-only. It returns false if the bridge has to generate
+// its own tools, e.g. because its metadata was refreshed.
+func (b *ODataMCPBridge) shareTools() bool {
+	parent := b.shared
+	if parent == nil || parent.server.LoadTools() != nil {
+		return false
+	}
+
+	parent.mu.RLock()
+	b.mu.RLock()
+	same := parent.current.Load() == b.current.Load() && parent.settings.Load() == b.config
+	b.mu.RUnlock()
+	if !same {
+		parent.mu.RUnlock()
+		return false
+	}
+	toolSet := parent.toolSet
+	tools := make(map[string]*models.ToolInfo, len(parent.tools))
+	for name, info := range parent.tools {
+		tools[name] = info
+	}
+	enabled := make(map[string]bool, len(parent.enabled))
+	for key := range parent.enabled {
+		enabled[key] = true
+	}
+	collisions := append([]string(nil), parent.collisions...)
+	lazyTools := parent.lazyTools
+	parent.mu.RUnlock()
+
+	b.mu.Lock()
+	b.toolSet = toolSet[:len(toolSet):len(toolSet)]
+	b.tools = tools
+	b.enabled = enabled
+	b.collisions = collisions
+	b.lazyTools = lazyTools
+	b.mu.Unlock()
+
+	for _, shared := range toolSet {
+		b.server.AddTool(shared.tool, b.boundHandler(shared.tool.Name, shared.handler))
+	}
+	return true
+}
+
+// instrumentedHandler runs a tool call in a span, the parent of the spans of its OData
+// requests, and records its calls, errors, duration and result size in the metrics. Each
+// call gets a correlation ID, sent as X-Request-ID with its OData requests and appended
+// to its error, so failures can be found in the gateway error log (/IWFND/ERROR_LOG).
+func instrumentedHandler(name string, handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		requestID := client.NewRequestID()
+		ctx = client.WithRequestID(withToolName(ctx, name), requestID)
+		ctx, span := tracing.Start(ctx, "tools/call "+name, tracing.KindServer)
+		span.SetAttribute("mcp.tool.name", name)
+		span.SetAttribute("odata.request_id", requestID)
+		started := time.Now()
+
+		result, err := handler(ctx, args)
+		metrics.Default.ObserveToolCall(name, time.Since(started), resultSize(result), err != nil)
+		span.SetError(err)
+		span.End()
+		if err != nil {
+			// Errors reach the MCP client, so they must not contain credentials
+			err = fmt.Errorf("%w (request ID: %s)", redact.Error(err), requestID)
+		}
+		return result, err
+	}
+}
+
+// resultSize returns the size of a tool result as sent in its text content
+func resultSize(result interface{}) int {
+	switch r := result.(type) {
+	case nil:
+		return 0
+	case string:
+		return len(r)
+	case *mcp.TextResult:
+		return len(r.Text)
+	default:
+		data, _ := json.Marshal(r)
+		return len(data)
+	}
+}
+
+// dryRunHandler runs a tool handler with --dry-run: the OData client does not send the
+// request, and the method, URL and body it would have sent become the tool result
+func dryRunHandler(handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		result, err := handler(client.WithDryRun(ctx), args)
+		var request *client.DryRunRequest
+		if errors.As(err, &request) {
+			data, err := json.Marshal(request)
+			if err != nil {
+				return nil, fmt.Errorf("failed to format response: %w", err)
+			}
+			return string(data), nil
+		}
+		return result, err
+	}
+}
+
+// toolAnnotations derives MCP behavior hints from the operation a tool performs
+func (b *ODataMCPBridge) toolAnnotations(info *models.ToolInfo) *mcp.ToolAnnotations {
+	hint := func(v bool) *bool { return &v }
+
+	operation := info.Operation
+	if info.Function != "" {
+		// Function imports invoked with GET must not have side effects
+		if function, exists := b.metadata.FunctionImports[info.Function]; exists && strings.EqualFold(function.HTTPMethod, constants.GET) {
+			operation = constants.OpGet
+		}
+	}
+
+	switch operation {
+	case constants.OpInfo, constants.OpFilter, constants.OpCount, constants.OpSearch, constants.OpGet, constants.OpValueHelp:
+		return &mcp.ToolAnnotations{ReadOnlyHint: hint(true)}
+	case constants.OpCreate, constants.OpBulkCreate:
+		return &mcp.ToolAnnotations{ReadOnlyHint: hint(false), DestructiveHint: hint(false), IdempotentHint: hint(false)}
+	case constants.OpUpdate:
+		return &mcp.ToolAnnotations{ReadOnlyHint: hint(false), DestructiveHint: hint(false), IdempotentHint: hint(true)}
+	case constants.OpDelete:
+		return &mcp.ToolAnnotations{ReadOnlyHint: hint(false), DestructiveHint: hint(true), IdempotentHint: hint(true)}
+	default:
+		// Non-GET function imports may do anything, so the protocol defaults apply
+		return &mcp.ToolAnnotations{ReadOnlyHint: hint(false)}
+	}
+}
+
+// disambiguatedToolName returns a free name for a tool whose name is taken. With
+// --tool-name-collisions namespace, the schema namespace of its entity set or function
+// import is tried first. Callers must hold b.mu.
+func (b *ODataMCPBridge) disambiguatedToolName(name string, info *models.ToolInfo) string {
+	if b.config.ToolNameCollisions == constants.ToolNameCollisionsNamespace {
+		namespace := ""
+		if entitySet, exists := b.metadata.EntitySets[info.EntitySet]; exists {
+			namespace = entitySet.Namespace
+		} else if function, exists := b.metadata.FunctionImports[info.Function]; exists {
+			namespace = function.Namespace
+		}
+		if namespace != "" {
+			maxLength := b.config.ToolNameMaxLength
+			if maxLength <= 0 {
+				maxLength = constants.DefaultToolNameMaxLength
+			}
+			candidate := utils.ShortenToolName(utils.SanitizeToolNamePart(namespace)+"_"+name, maxLength)
+			if _, exists := b.tools[candidate]; !exists {
+				return candidate
+			}
+			name = candidate
+		}
+	}
+	return b.uniqueToolName(name)
+}
+
+// uniqueToolName returns the first free "<name>_<n>" variant of a tool name
+// within the length budget. Callers must hold b.mu.
+func (b *ODataMCPBridge) uniqueToolName(name string) string {
+	maxLength := b.config.ToolNameMaxLength
+	if maxLength <= 0 {
+		maxLength = constants.DefaultToolNameMaxLength
+	}
+
+	for i := 2; ; i++ {
+		suffix := fmt.Sprintf("_%d", i)
+		candidate := utils.ShortenToolName(name, maxLength-len(suffix)) + suffix
+		if _, exists := b.tools[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+// sameToolTarget reports whether two tool infos describe the same operation on the same target
+func sameToolTarget(a, b *models.ToolInfo) bool {
+	return a.EntitySet == b.EntitySet && a.Function == b.Function && a.Operation == b.Operation
 }
 
 // shouldIncludeEntity checks if an entity should be included based on filters
@@ -145,24 +704,73 @@ func (b *ODataMCPBridge) shouldIncludeFunction(functionName string) bool {
 	return false
 }
 
-// matchesPattern checks if a name matches a pattern (supports wildcards)
-func (b *ODataMCPBridge) matchesPattern(name, pattern string) bool {
-	if pattern == name {
-		return true
+// isEntityOperationEnabled checks the global operation filter and the first
+// per-entity operation rule matching the entity set
+func (b *ODataMCPBridge) isEntityOperationEnabled(entitySetName, operation string) bool {
+	if !b.config.IsOperationEnabled(operation) {
+		return false
 	}
 
-	// Simple wildcard support
-	if strings.HasSuffix(pattern, "*") {
-		prefix := strings.TrimSuffix(pattern, "*")
-		return strings.HasPrefix(name, prefix)
+	for _, rule := range b.config.EntityOperationRules {
+		if b.matchesPattern(entitySetName, rule.Pattern) {
+			return rule.Operations[operation]
+		}
 	}
 
-	if strings.HasPrefix(pattern, "*") {
-		suffix := strings.TrimPrefix(pattern, "*")
-		return strings.HasSuffix(name, suffix)
+	return true
+}
+
+// matchesPattern checks if a name matches a pattern (supports wildcards and regular expressions)
+func (b *ODataMCPBridge) matchesPattern(name, pattern string) bool {
+	return b.config.Patterns.Match(name, pattern)
+}
+
+// generateLazyTools registers the tools used to discover and enable entity tools on demand
+func (b *ODataMCPBridge) generateLazyTools() {
+	listName := b.formatToolName("list_entities", "")
+	listTool := &mcp.Tool{
+		Name:        listName,
+		Description: "List the entity sets and function imports of the OData service. Their tools are not registered up front; use the enable tool to register them",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"filter": map[string]interface{}{
+					"type":        "string",
+					"description": "Only list names matching this pattern (supports wildcards, e.g. 'Sales*')",
+				},
+			},
+		},
 	}
+	b.registerTool(listTool, func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleListEntities(ctx, args)
+	}, &models.ToolInfo{
+		Name:        listName,
+		Description: listTool.Description,
+		Operation:   constants.OpInfo,
+	})
 
-	return false
+	enableName := b.formatToolName("enable_entity", "")
+	enableTool := &mcp.Tool{
+		Name:        enableName,
+		Description: fmt.Sprintf("Register the tools of an entity set or function import listed by %s so they can be called", listName),
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Entity set or function import name",
+				},
+			},
+			"required": []string{"name"},
+		},
+	}
+	b.registerTool(enableTool, func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleEnableEntity(ctx, args)
+	}, &models.ToolInfo{
+		Name:        enableName,
+		Description: enableTool.Description,
+		Operation:   constants.OpInfo,
+	})
 }
 
 // generateServiceInfoTool creates a tool to get service information
@@ -184,18 +792,15 @@ func (b *ODataMCPBridge) generateServiceInfoTool() {
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleServiceInfo(ctx, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: tool.Description,
 		Operation:   constants.OpInfo,
-	}
+	})
 }
 
 // generateEntitySetTools creates tools for an entity set
@@ -203,40 +808,65 @@ func (b *ODataMCPBridge) generateEntitySetTools(entitySetName string, entitySet
 	// Get entity type
 	entityType, exists := b.metadata.EntityTypes[entitySet.EntityType]
 	if !exists {
-		if b.config.Verbose {
-			fmt.Printf("[VERBOSE] Entity type not found for entity set %s: %s\n", entitySetName, entitySet.EntityType)
-		}
+		b.logger.Debug("Entity type not found", "entity_set", entitySetName, "entity_type", entitySet.EntityType)
 		return
 	}
 
-	// Generate filter/list tool
-	b.generateFilterTool(entitySetName, entitySet, entityType)
-
-	// Generate count tool  
-	b.generateCountTool(entitySetName, entitySet, entityType)
-
-	// Generate search tool if supported
-	if entitySet.Searchable {
-		b.generateSearchTool(entitySetName, entitySet, entityType)
-	}
-
-	// Generate get tool
-	b.generateGetTool(entitySetName, entitySet, entityType)
-
-	// Generate create tool if allowed
-	if entitySet.Creatable {
-		b.generateCreateTool(entitySetName, entitySet, entityType)
-	}
-
-	// Generate update tool if allowed
-	if entitySet.Updatable {
-		b.generateUpdateTool(entitySetName, entitySet, entityType)
+	for _, operation := range b.entitySetOperations(entitySetName, entitySet) {
+		switch operation {
+		case constants.OpFilter:
+			b.generateFilterTool(entitySetName, entitySet, entityType)
+		case constants.OpCount:
+			b.generateCountTool(entitySetName, entitySet, entityType)
+		case constants.OpSearch:
+			b.generateSearchTool(entitySetName, entitySet, entityType)
+		case constants.OpGet:
+			b.generateGetTool(entitySetName, entitySet, entityType)
+		case constants.OpCreate:
+			b.generateCreateTool(entitySetName, entitySet, entityType)
+		case constants.OpUpdate:
+			b.generateUpdateTool(entitySetName, entitySet, entityType)
+		case constants.OpDelete:
+			b.generateDeleteTool(entitySetName, entitySet, entityType)
+		case constants.OpBulkCreate:
+			b.generateBulkCreateTool(entitySetName, entitySet, entityType)
+		case constants.OpValueHelp:
+			b.generateValueHelpTools(entitySetName, entitySet)
+		}
 	}
+}
 
-	// Generate delete tool if allowed
-	if entitySet.Deletable {
-		b.generateDeleteTool(entitySetName, entitySet, entityType)
+// entitySetOperations returns the operations that get tools for an entity set,
+// based on its capabilities and the configured operation filters
+func (b *ODataMCPBridge) entitySetOperations(entitySetName string, entitySet *models.EntitySet) []string {
+	candidates := []struct {
+		operation string
+		supported bool
+	}{
+		{constants.OpFilter, true},
+		{constants.OpCount, true},
+		{constants.OpSearch, entitySet.Searchable},
+		{constants.OpGet, true},
+		{constants.OpCreate, entitySet.Creatable},
+		{constants.OpUpdate, entitySet.Updatable},
+		{constants.OpDelete, entitySet.Deletable},
+	}
+
+	operations := make([]string, 0, len(candidates)+1)
+	for _, c := range candidates {
+		if c.supported && b.isEntityOperationEnabled(entitySetName, c.operation) {
+			operations = append(operations, c.operation)
+			// Bulk create tools follow the filters of create tools
+			if c.operation == constants.OpCreate && b.config.BulkCreate {
+				operations = append(operations, constants.OpBulkCreate)
+			}
+			// Value help tools read other entity sets, so they follow the filters of filter tools
+			if c.operation == constants.OpFilter && len(b.valueHelpProperties(entitySet)) > 0 {
+				operations = append(operations, constants.OpValueHelp)
+			}
+		}
 	}
+	return operations
 }
 
 // generateFilterTool creates a filter/list tool for an entity set
@@ -244,14 +874,10 @@ func (b *ODataMCPBridge) generateFilterTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpFilter, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("List/filter %s entities with OData query options", entitySetName)
+	description := fmt.Sprintf("List/filter %s entities with OData query options", b.entitySetTitle(entitySetName))
 
 	// Build input schema with standard OData parameters
 	properties := map[string]interface{}{
-		"$filter": map[string]interface{}{
-			"type":        "string",
-			"description": "OData filter expression",
-		},
 		"$select": map[string]interface{}{
 			"type":        "string", 
 			"description": "Comma-separated list of properties to select",
@@ -272,6 +898,13 @@ func (b *ODataMCPBridge) generateFilterTool(entitySetName string, entitySet *mod
 			"type":        "integer", 
 			"description": "Number of entities to skip",
 		},
+		"output_format": b.outputFormatParam(),
+	}
+	if filter := b.filterParam(entitySetName); filter != nil {
+		properties["$filter"] = filter
+	}
+	if entityType.Hierarchy != nil {
+		description += b.addHierarchyParams(properties, entityType)
 	}
 
 	tool := &mcp.Tool{
@@ -283,19 +916,19 @@ func (b *ODataMCPBridge) generateFilterTool(entitySetName string, entitySet *mod
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		if entityType.Hierarchy != nil {
+			return b.handleHierarchyFilter(ctx, entitySetName, entityType, args)
+		}
 		return b.handleEntityFilter(ctx, entitySetName, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		EntitySet:   entitySetName,
 		Operation:   constants.OpFilter,
-	}
+	})
 }
 
 // generateCountTool creates a count tool for an entity set
@@ -303,35 +936,32 @@ func (b *ODataMCPBridge) generateCountTool(entitySetName string, entitySet *mode
 	opName := constants.GetToolOperationName(constants.OpCount, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Get count of %s entities with optional filter", entitySetName)
+	description := fmt.Sprintf("Get count of %s entities with optional filter", b.entitySetTitle(entitySetName))
+
+	properties := map[string]interface{}{}
+	if filter := b.filterParam(entitySetName); filter != nil {
+		properties["$filter"] = filter
+	}
 
 	tool := &mcp.Tool{
 		Name:        toolName,
 		Description: description,
 		InputSchema: map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"$filter": map[string]interface{}{
-					"type":        "string",
-					"description": "OData filter expression",
-				},
-			},
+			"type":       "object",
+			"properties": properties,
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityCount(ctx, entitySetName, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		EntitySet:   entitySetName,
 		Operation:   constants.OpCount,
-	}
+	})
 }
 
 // generateSearchTool creates a search tool for an entity set
@@ -339,7 +969,7 @@ func (b *ODataMCPBridge) generateSearchTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpSearch, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Full-text search %s entities", entitySetName)
+	description := fmt.Sprintf("Full-text search %s entities", b.entitySetTitle(entitySetName))
 
 	tool := &mcp.Tool{
 		Name:        toolName,
@@ -359,24 +989,22 @@ func (b *ODataMCPBridge) generateSearchTool(entitySetName string, entitySet *mod
 					"type":        "integer",
 					"description": "Maximum number of entities to return",
 				},
+				"output_format": b.outputFormatParam(),
 			},
 			"required": []string{"search"},
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntitySearch(ctx, entitySetName, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		EntitySet:   entitySetName,
 		Operation:   constants.OpSearch,
-	}
+	})
 }
 
 // generateGetTool creates a get tool for an entity set
@@ -384,7 +1012,7 @@ func (b *ODataMCPBridge) generateGetTool(entitySetName string, entitySet *models
 	opName := constants.GetToolOperationName(constants.OpGet, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Get a single %s entity by key", entitySetName)
+	description := fmt.Sprintf("Get a single %s entity by key", b.entitySetTitle(entitySetName))
 
 	// Build key properties for input schema
 	properties := make(map[string]interface{})
@@ -393,10 +1021,7 @@ func (b *ODataMCPBridge) generateGetTool(entitySetName string, entitySet *models
 	for _, keyProp := range entityType.KeyProperties {
 		for _, prop := range entityType.Properties {
 			if prop.Name == keyProp {
-				properties[keyProp] = map[string]interface{}{
-					"type":        b.getJSONSchemaType(prop.Type),
-					"description": fmt.Sprintf("Key property: %s", keyProp),
-				}
+				properties[keyProp] = b.keyPropertySchema(prop)
 				required = append(required, keyProp)
 				break
 			}
@@ -427,19 +1052,16 @@ func (b *ODataMCPBridge) generateGetTool(entitySetName string, entitySet *models
 		InputSchema: inputSchema,
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityGet(ctx, entitySetName, entityType, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		EntitySet:   entitySetName,
 		Operation:   constants.OpGet,
-	}
+	})
 }
 
 // generateCreateTool creates a create tool for an entity set
@@ -447,7 +1069,7 @@ func (b *ODataMCPBridge) generateCreateTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpCreate, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Create a new %s entity", entitySetName)
+	description := fmt.Sprintf("Create a new %s entity", b.entitySetTitle(entitySetName))
 
 	// Build properties for input schema based on entity type
 	properties := make(map[string]interface{})
@@ -459,16 +1081,37 @@ func (b *ODataMCPBridge) generateCreateTool(entitySetName string, entitySet *mod
 			continue
 		}
 
-		properties[prop.Name] = map[string]interface{}{
-			"type":        b.getJSONSchemaType(prop.Type),
-			"description": fmt.Sprintf("Property: %s", prop.Name),
-		}
+		properties[prop.Name] = b.createPropertySchema(prop)
 
 		if !prop.Nullable {
 			required = append(required, prop.Name)
 		}
 	}
 
+	// Navigation properties take related entities to create in the same request (deep insert)
+	for _, nav := range entityType.NavigationProps {
+		if nav.TargetEntityType == "" {
+			continue
+		}
+		target := nav.TargetEntitySet
+		if target == "" {
+			target = nav.TargetEntityType
+		}
+		schema := map[string]interface{}{"type": "object"}
+		if targetType := b.metadata.EntityTypes[nav.TargetEntityType]; targetType != nil {
+			targetProperties := make(map[string]interface{})
+			for _, prop := range targetType.Properties {
+				targetProperties[prop.Name] = b.createPropertySchema(prop)
+			}
+			schema["properties"] = targetProperties
+		}
+		if nav.IsCollection {
+			schema = map[string]interface{}{"type": "array", "items": schema}
+		}
+		schema["description"] = fmt.Sprintf("Deep insert: related %s entities to create together with this one", target)
+		properties[nav.Name] = schema
+	}
+
 	inputSchema := map[string]interface{}{
 		"type":       "object",
 		"properties": properties,
@@ -484,19 +1127,16 @@ func (b *ODataMCPBridge) generateCreateTool(entitySetName string, entitySet *mod
 		InputSchema: inputSchema,
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityCreate(ctx, entitySetName, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		EntitySet:   entitySetName,
 		Operation:   constants.OpCreate,
-	}
+	})
 }
 
 // generateUpdateTool creates an update tool for an entity set
@@ -504,7 +1144,7 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpUpdate, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Update an existing %s entity", entitySetName)
+	description := fmt.Sprintf("Update an existing %s entity", b.entitySetTitle(entitySetName))
 
 	// Build properties for input schema
 	properties := make(map[string]interface{})
@@ -514,10 +1154,7 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 	for _, keyProp := range entityType.KeyProperties {
 		for _, prop := range entityType.Properties {
 			if prop.Name == keyProp {
-				properties[keyProp] = map[string]interface{}{
-					"type":        b.getJSONSchemaType(prop.Type),
-					"description": fmt.Sprintf("Key property: %s", keyProp),
-				}
+				properties[keyProp] = b.keyPropertySchema(prop)
 				required = append(required, keyProp)
 				break
 			}
@@ -527,10 +1164,7 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 	// Add updatable properties (optional)
 	for _, prop := range entityType.Properties {
 		if !prop.IsKey {
-			properties[prop.Name] = map[string]interface{}{
-				"type":        b.getJSONSchemaType(prop.Type),
-				"description": fmt.Sprintf("Property: %s", prop.Name),
-			}
+			properties[prop.Name] = b.propertySchema(prop, propertyDescription(prop))
 		}
 	}
 
@@ -552,19 +1186,16 @@ func (b *ODataMCPBridge) generateUpdateTool(entitySetName string, entitySet *mod
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityUpdate(ctx, entitySetName, entityType, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		EntitySet:   entitySetName,
 		Operation:   constants.OpUpdate,
-	}
+	})
 }
 
 // generateDeleteTool creates a delete tool for an entity set
@@ -572,7 +1203,7 @@ func (b *ODataMCPBridge) generateDeleteTool(entitySetName string, entitySet *mod
 	opName := constants.GetToolOperationName(constants.OpDelete, b.config.ToolShrink)
 	toolName := b.formatToolName(opName, entitySetName)
 
-	description := fmt.Sprintf("Delete a %s entity", entitySetName)
+	description := fmt.Sprintf("Delete a %s entity", b.entitySetTitle(entitySetName))
 
 	// Build key properties for input schema
 	properties := make(map[string]interface{})
@@ -581,10 +1212,7 @@ func (b *ODataMCPBridge) generateDeleteTool(entitySetName string, entitySet *mod
 	for _, keyProp := range entityType.KeyProperties {
 		for _, prop := range entityType.Properties {
 			if prop.Name == keyProp {
-				properties[keyProp] = map[string]interface{}{
-					"type":        b.getJSONSchemaType(prop.Type),
-					"description": fmt.Sprintf("Key property: %s", keyProp),
-				}
+				properties[keyProp] = b.keyPropertySchema(prop)
 				required = append(required, keyProp)
 				break
 			}
@@ -601,19 +1229,16 @@ func (b *ODataMCPBridge) generateDeleteTool(entitySetName string, entitySet *mod
 		},
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleEntityDelete(ctx, entitySetName, entityType, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		EntitySet:   entitySetName,
 		Operation:   constants.OpDelete,
-	}
+	})
 }
 
 // generateFunctionTool creates a tool for a function import
@@ -621,6 +1246,9 @@ func (b *ODataMCPBridge) generateFunctionTool(functionName string, function *mod
 	toolName := b.formatToolName(functionName, "")
 
 	description := fmt.Sprintf("Call function: %s", functionName)
+	if returns := b.functionResultDescription(function); returns != "" {
+		description += ". " + returns
+	}
 
 	// Build properties for input schema based on function parameters
 	properties := make(map[string]interface{})
@@ -654,48 +1282,57 @@ func (b *ODataMCPBridge) generateFunctionTool(functionName string, function *mod
 		InputSchema: inputSchema,
 	}
 
-	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
 		return b.handleFunctionCall(ctx, functionName, function, args)
 	}
 
-	b.server.AddTool(tool, handler)
-
-	// Track tool info
-	b.tools[toolName] = &models.ToolInfo{
+	b.registerTool(tool, handler, &models.ToolInfo{
 		Name:        toolName,
 		Description: description,
 		Function:    functionName,
-	}
+	})
 }
 
-// formatToolName formats a tool name with prefix/postfix
+// formatToolName formats a tool name with prefix/postfix. Names are transliterated
+// to the characters allowed in MCP tool names and shortened to the configured
+// length budget, keeping the prefix/postfix intact.
 func (b *ODataMCPBridge) formatToolName(operation, entityName string) string {
-	var name string
+	name := utils.SanitizeToolNamePart(operation)
 
 	if entityName != "" {
+		entityName = utils.SanitizeToolNamePart(entityName)
 		if b.config.UsePostfix() {
-			name = fmt.Sprintf("%s_%s", operation, entityName)
+			name = fmt.Sprintf("%s_%s", name, entityName)
 		} else {
-			name = fmt.Sprintf("%s_%s", entityName, operation)
+			name = fmt.Sprintf("%s_%s", entityName, name)
 		}
-	} else {
-		name = operation
 	}
 
-	// Apply prefix/postfix
+	// Determine prefix/postfix
+	prefix, suffix := "", ""
 	if b.config.UsePostfix() && b.config.ToolPostfix != "" {
-		name = fmt.Sprintf("%s_%s", name, b.config.ToolPostfix)
+		suffix = "_" + utils.SanitizeToolNamePart(b.config.ToolPostfix)
 	} else if !b.config.UsePostfix() && b.config.ToolPrefix != "" {
-		name = fmt.Sprintf("%s_%s", b.config.ToolPrefix, name)
+		prefix = utils.SanitizeToolNamePart(b.config.ToolPrefix) + "_"
 	}
 
 	// Apply default postfix if none specified
 	if b.config.UsePostfix() && b.config.ToolPostfix == "" {
 		serviceID := constants.FormatServiceID(b.config.ServiceURL)
-		name = fmt.Sprintf("%s_for_%s", name, serviceID)
+		suffix = "_for_" + utils.SanitizeToolNamePart(serviceID)
 	}
 
-	return name
+	// Apply the length budget to the operation/entity part first
+	maxLength := b.config.ToolNameMaxLength
+	if maxLength <= 0 {
+		maxLength = constants.DefaultToolNameMaxLength
+	}
+	budget := maxLength - len(prefix) - len(suffix)
+	if budget < constants.MinToolNameCoreLength {
+		return utils.ShortenToolName(prefix+name+suffix, maxLength)
+	}
+
+	return prefix + utils.ShortenToolName(name, budget) + suffix
 }
 
 // getJSONSchemaType converts OData type to JSON schema type
@@ -709,8 +1346,59 @@ func (b *ODataMCPBridge) getJSONSchemaType(odataType string) string {
 		return "number"
 	case "Edm.Boolean":
 		return "boolean"
-	default:
-		return "string"
+	}
+	if strings.HasPrefix(odataType, "Collection(") {
+		return "array"
+	}
+	if b.metadata != nil && b.metadata.ComplexTypes[odataType[strings.LastIndex(odataType, ".")+1:]] != nil {
+		return "object"
+	}
+	return "string"
+}
+
+// propertySchema returns the input schema of an entity property. Complex types become
+// nested objects (or arrays of them) instead of strings.
+func (b *ODataMCPBridge) propertySchema(prop *models.EntityProperty, description string) map[string]interface{} {
+	itemType := strings.TrimSuffix(strings.TrimPrefix(prop.Type, "Collection("), ")")
+
+	var schema map[string]interface{}
+	if complexType := b.metadata.ComplexTypes[prop.ComplexType]; complexType != nil {
+		schema = b.complexTypeSchema(complexType, 0)
+	} else {
+		schema = map[string]interface{}{"type": b.getJSONSchemaType(itemType)}
+	}
+	if prop.IsCollection {
+		schema = map[string]interface{}{"type": "array", "items": schema}
+	}
+	schema["description"] = description
+	applyFacets(schema, prop)
+	return schema
+}
+
+// complexTypeSchema describes a complex type as a JSON object schema, nesting complex
+// properties up to a fixed depth
+func (b *ODataMCPBridge) complexTypeSchema(complexType *models.ComplexType, depth int) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, prop := range complexType.Properties {
+		if nested := b.metadata.ComplexTypes[prop.ComplexType]; nested != nil && depth < 5 {
+			schema := b.complexTypeSchema(nested, depth+1)
+			if prop.IsCollection {
+				schema = map[string]interface{}{"type": "array", "items": schema}
+			}
+			properties[prop.Name] = schema
+			continue
+		}
+		schema := map[string]interface{}{
+			"type":        b.getJSONSchemaType(prop.Type),
+			"description": prop.Type,
+		}
+		applyFacets(schema, prop)
+		properties[prop.Name] = schema
+	}
+	return map[string]interface{}{
+		"type":        "object",
+		"description": complexType.Name,
+		"properties":  properties,
 	}
 }
 
@@ -724,10 +1412,20 @@ func (b *ODataMCPBridge) Run() error {
 	b.running = true
 	b.mu.Unlock()
 
+	if b.config.MetadataRefreshInterval > 0 {
+		go b.refreshMetadataPeriodically(b.config.MetadataRefreshInterval)
+	}
+
 	// Start MCP server
 	return b.server.Run()
 }
 
+// Serve runs the MCP server over the given streams instead of stdio
+func (b *ODataMCPBridge) Serve(input io.Reader, output io.Writer) error {
+	b.server.SetIO(input, output)
+	return b.Run()
+}
+
 // Stop stops the MCP bridge
 func (b *ODataMCPBridge) Stop() {
 	b.mu.Lock()
@@ -742,8 +1440,35 @@ func (b *ODataMCPBridge) Stop() {
 	b.server.Stop()
 }
 
+// CallTool invokes a registered tool by name
+func (b *ODataMCPBridge) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	return b.server.CallTool(ctx, name, args)
+}
+
+// GetTools returns the MCP tool definitions as served by tools/list, or the error that
+// kept the tools from being generated
+func (b *ODataMCPBridge) GetTools() ([]*mcp.Tool, error) {
+	return b.server.GetTools()
+}
+
+// Complete returns completion candidates for a tool or resource template argument
+func (b *ODataMCPBridge) Complete(ctx context.Context, req *mcp.CompletionRequest) ([]string, error) {
+	view, ctx := b.pinCall(ctx)
+	return view.handleCompletion(ctx, req)
+}
+
+// ReadResource reads a resource by URI
+func (b *ODataMCPBridge) ReadResource(ctx context.Context, uri string) ([]mcp.ResourceContent, error) {
+	return b.server.ReadResource(ctx, uri)
+}
+
 // GetTraceInfo returns comprehensive trace information
 func (b *ODataMCPBridge) GetTraceInfo() (*models.TraceInfo, error) {
+	if err := b.server.LoadTools(); err != nil {
+		return nil, err
+	}
+	b = b.pin()
+
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -774,6 +1499,7 @@ func (b *ODataMCPBridge) GetTraceInfo() (*models.TraceInfo, error) {
 		SortTools:       b.config.SortTools,
 		EntityFilter:    b.config.AllowedEntities,
 		FunctionFilter:  b.config.AllowedFunctions,
+		OperationFilter: b.config.EnabledOperationNames(),
 		Authentication:  authType,
 		MetadataSummary: models.MetadataSummary{
 			EntityTypes:     len(b.metadata.EntityTypes),
@@ -781,6 +1507,7 @@ func (b *ODataMCPBridge) GetTraceInfo() (*models.TraceInfo, error) {
 			FunctionImports: len(b.metadata.FunctionImports),
 		},
 		RegisteredTools: tools,
+		NameCollisions:  b.collisions,
 		TotalTools:      len(tools),
 	}, nil
 }
@@ -795,6 +1522,9 @@ func (b *ODataMCPBridge) handleServiceInfo(ctx context.Context, args map[string]
 		includeMetadata = val
 	}
 
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	info := map[string]interface{}{
 		"service_url": b.config.ServiceURL,
 		"entity_sets": len(b.metadata.EntitySets),
@@ -804,6 +1534,7 @@ func (b *ODataMCPBridge) handleServiceInfo(ctx context.Context, args map[string]
 		"container_name": b.metadata.ContainerName,
 		"version": b.metadata.Version,
 		"parsed_at": b.metadata.ParsedAt.Format("2006-01-02T15:04:05Z"),
+		"entity_catalog": b.entityCatalog(),
 	}
 
 	if includeMetadata {
@@ -820,12 +1551,137 @@ func (b *ODataMCPBridge) handleServiceInfo(ctx context.Context, args map[string]
 	return string(response), nil
 }
 
+// entityCatalog summarizes each exposed entity set with its key fields, capabilities and
+// navigation properties, so that multi-entity queries ($expand, follow-up gets) can be
+// planned without the raw metadata
+func (b *ODataMCPBridge) entityCatalog() []map[string]interface{} {
+	catalog := make([]map[string]interface{}, 0)
+	for _, name := range b.includedEntityNames() {
+		entitySet := b.metadata.EntitySets[name]
+		entry := map[string]interface{}{
+			"name":        name,
+			"entity_type": entitySet.EntityType,
+			"capabilities": map[string]bool{
+				"creatable":  entitySet.Creatable,
+				"updatable":  entitySet.Updatable,
+				"deletable":  entitySet.Deletable,
+				"searchable": entitySet.Searchable,
+				"pageable":   entitySet.Pageable,
+			},
+		}
+		if entitySet.Label != "" {
+			entry["label"] = entitySet.Label
+		}
+
+		keys := make([]string, 0)
+		navigation := make([]map[string]interface{}, 0)
+		if entityType := b.entityTypeOf(name); entityType != nil {
+			keys = append(keys, entityType.KeyProperties...)
+			for _, nav := range entityType.NavigationProps {
+				navEntry := map[string]interface{}{
+					"name":        nav.Name,
+					"target_type": nav.TargetEntityType,
+					"collection":  nav.IsCollection,
+				}
+				if nav.TargetEntitySet != "" {
+					navEntry["target_entity_set"] = nav.TargetEntitySet
+				}
+				navigation = append(navigation, navEntry)
+			}
+		}
+		entry["keys"] = keys
+		entry["navigation"] = navigation
+
+		catalog = append(catalog, entry)
+	}
+	return catalog
+}
+
+func (b *ODataMCPBridge) handleListEntities(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	pattern, _ := args["filter"].(string)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entities := make([]map[string]interface{}, 0)
+	for _, name := range b.includedEntityNames() {
+		if pattern != "" && !b.matchesPattern(name, pattern) {
+			continue
+		}
+		entitySet := b.metadata.EntitySets[name]
+		entities = append(entities, map[string]interface{}{
+			"name":        name,
+			"entity_type": entitySet.EntityType,
+			"operations":  b.entitySetOperations(name, entitySet),
+			"enabled":     b.enabled[entityEnabledKey(name)],
+		})
+	}
+
+	functions := make([]map[string]interface{}, 0)
+	for _, name := range b.includedFunctionNames() {
+		if pattern != "" && !b.matchesPattern(name, pattern) {
+			continue
+		}
+		functions = append(functions, map[string]interface{}{
+			"name":        name,
+			"http_method": b.metadata.FunctionImports[name].HTTPMethod,
+			"enabled":     b.enabled[functionEnabledKey(name)],
+		})
+	}
+
+	result, err := json.Marshal(map[string]interface{}{
+		"entity_sets":      entities,
+		"function_imports": functions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
+func (b *ODataMCPBridge) handleEnableEntity(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("missing required parameter: name")
+	}
+
+	// An entity set and a function import of the same name are both enabled
+	added := make([]string, 0)
+	found := false
+	if _, exists := b.metadata.EntitySets[name]; exists && b.shouldIncludeEntity(name) {
+		added = append(added, b.enableEntitySet(name)...)
+		found = true
+	}
+	if _, exists := b.metadata.FunctionImports[name]; exists && b.shouldIncludeFunction(name) && b.config.IsOperationEnabled(constants.OpFunction) {
+		added = append(added, b.enableFunction(name)...)
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid entity: %s is not an available entity set or function import", name)
+	}
+
+	// The server notifies the client of the new tools via notifications/tools/list_changed
+	result, err := json.Marshal(map[string]interface{}{
+		"name":  name,
+		"tools": added,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+
+	return string(result), nil
+}
+
 func (b *ODataMCPBridge) handleEntityFilter(ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
 	// Build query options from arguments using standard OData parameters
 	options := make(map[string]string)
 	
 	// Handle each OData parameter
 	if filter, ok := args["$filter"].(string); ok && filter != "" {
+		if err := b.checkFilter(entitySetName, filter); err != nil {
+			return nil, err
+		}
 		options[constants.QueryFilter] = filter
 	}
 	if selectParam, ok := args["$select"].(string); ok && selectParam != "" {
@@ -843,26 +1699,53 @@ func (b *ODataMCPBridge) handleEntityFilter(ctx context.Context, entitySetName s
 	if skip, ok := args["$skip"].(float64); ok {
 		options[constants.QuerySkip] = fmt.Sprintf("%d", int(skip))
 	}
+	if err := b.checkUnfilteredQuery(ctx, entitySetName, options); err != nil {
+		return nil, err
+	}
+	// Raw bodies are returned as the service sent them, so they cannot drop an extra entity
+	raw := b.rawResponses(b.outputFormat(args))
+	topLimit := b.applyDefaultTop(options, !raw)
+	autoSelect := b.applyAutoSelect(entitySetName, options)
+	b.applyInlineCount(options)
 	
 	// Call OData client to get entity set
-	response, err := b.client.GetEntitySet(ctx, entitySetName, options)
+	var response *models.ODataResponse
+	var err error
+	if raw {
+		var raw string
+		raw, response, err = b.rawResult(b.client.GetEntitySetRaw(ctx, entitySetName, options))
+		if err == nil && response == nil {
+			return raw, nil
+		}
+	} else {
+		response, err = withProgressHeartbeat(ctx, "Query of "+entitySetName, func() (*models.ODataResponse, error) {
+			return b.queryWithExpand(ctx, entitySetName, options, true, func(ctx context.Context, options map[string]string) (*models.ODataResponse, error) {
+				return b.getEntitySetPages(ctx, entitySetName, options)
+			})
+		})
+	}
 	if err != nil {
 		if b.config.VerboseErrors {
 			return nil, fmt.Errorf("failed to filter entities from %s with options %v: %w", entitySetName, options, err)
 		}
 		return nil, fmt.Errorf("failed to filter entities: %w", err)
 	}
+	if response.Count != nil && options[constants.QuerySearch] == "" {
+		// The inline count of a query is the count of its filter
+		b.counts.put(entitySetName, options[constants.QueryFilter], *response.Count, b.config.CountCacheTTL)
+	}
+	topLimit.trim(response, options)
 	
 	// Enhance response based on configuration
 	enhancedResponse := b.enhanceResponse(response, options)
-	
-	// Format response as JSON string
-	result, err := json.Marshal(enhancedResponse)
-	if err != nil {
-		return nil, fmt.Errorf("failed to format response: %w", err)
+	if topLimit != nil {
+		b.markDefaultTopTruncation(enhancedResponse, topLimit)
+	}
+	if autoSelect {
+		markAutoSelect(enhancedResponse, options)
 	}
 	
-	return string(result), nil
+	return b.formatListResult(entitySetName, enhancedResponse, args, options)
 }
 
 // enhanceResponse enhances OData response based on configuration options
@@ -874,9 +1757,11 @@ func (b *ODataMCPBridge) enhanceResponse(response *models.ODataResponse, options
 		Value:    response.Value,
 		Error:    response.Error,
 		Metadata: response.Metadata,
+		Messages: response.Messages,
 	}
 	
-	// Apply size limits first to prevent large responses
+	// Slim entities before applying size limits so that more of them fit
+	enhanced.Value = b.slimEntities(enhanced.Value)
 	enhanced = b.applySizeLimits(enhanced)
 	
 	// Add pagination hints if enabled
@@ -928,11 +1813,6 @@ func (b *ODataMCPBridge) enhanceResponse(response *models.ODataResponse, options
 		enhanced.Value = b.convertLegacyDates(enhanced.Value)
 	}
 	
-	// Strip metadata if not requested
-	if !b.config.ResponseMetadata {
-		enhanced.Value = b.stripMetadata(enhanced.Value)
-	}
-	
 	return enhanced
 }
 
@@ -957,6 +1837,7 @@ func (b *ODataMCPBridge) applySizeLimits(response *models.ODataResponse) *models
 					Value:    truncated,
 					Error:    response.Error,
 					Metadata: response.Metadata,
+					Messages: response.Messages,
 				}
 				
 				// Add truncation warning
@@ -966,7 +1847,8 @@ func (b *ODataMCPBridge) applySizeLimits(response *models.ODataResponse) *models
 				newResponse.Metadata["truncated"] = true
 				newResponse.Metadata["original_count"] = len(resultArray)
 				newResponse.Metadata["max_items"] = b.config.MaxItems
-				newResponse.Metadata["warning"] = fmt.Sprintf("Response truncated from %d to %d items due to size limits", len(resultArray), b.config.MaxItems)
+				newResponse.Metadata["warning"] = fmt.Sprintf("Response truncated from %d to %d items due to size limits. %s", len(resultArray), b.config.MaxItems, truncationGuidance)
+				newResponse.Truncated = true
 				
 				return newResponse
 			}
@@ -975,36 +1857,21 @@ func (b *ODataMCPBridge) applySizeLimits(response *models.ODataResponse) *models
 	
 	// Apply response size limit
 	if b.config.MaxResponseSize > 0 {
-		// Estimate response size by marshaling to JSON
-		jsonData, err := json.Marshal(response.Value)
-		if err == nil && len(jsonData) > b.config.MaxResponseSize {
-			// If it's an array, try to reduce items
-                       if resultArray, ok := response.Value.([]interface{}); ok {
-                               if len(resultArray) == 0 {
-                                       return response
-                               }
-
-                               // Calculate how many items we can fit
-                               avgItemSize := len(jsonData) / len(resultArray)
-                               if avgItemSize == 0 {
-                                       return response
-                               }
-                               maxItems := b.config.MaxResponseSize / avgItemSize
-                               if maxItems < 1 {
-                                       maxItems = 1
-                               }
-				
-				// Truncate to fit size limit
-				truncated := resultArray[:maxItems]
+		// Only collections are truncated; their items are measured one at a time
+		if resultArray, ok := response.Value.([]interface{}); ok && len(resultArray) > 0 {
+			if kept := fitItemsToSize(resultArray, b.config.MaxResponseSize); kept < len(resultArray) {
+				truncated := resultArray[:kept]
 				
 				// Update response
 				newResponse := &models.ODataResponse{
-					Context:  response.Context,
-					Count:    response.Count,
-					NextLink: response.NextLink,
-					Value:    truncated,
-					Error:    response.Error,
-					Metadata: response.Metadata,
+					Context:   response.Context,
+					Count:     response.Count,
+					NextLink:  response.NextLink,
+					Value:     truncated,
+					Error:     response.Error,
+					Metadata:  response.Metadata,
+					Truncated: true,
+					Messages:  response.Messages,
 				}
 				
 				// Add truncation warning
@@ -1015,7 +1882,7 @@ func (b *ODataMCPBridge) applySizeLimits(response *models.ODataResponse) *models
 				newResponse.Metadata["original_count"] = len(resultArray)
 				newResponse.Metadata["truncated_count"] = len(truncated)
 				newResponse.Metadata["max_response_size"] = b.config.MaxResponseSize
-				newResponse.Metadata["warning"] = fmt.Sprintf("Response truncated from %d to %d items due to response size limit (%d bytes)", len(resultArray), len(truncated), b.config.MaxResponseSize)
+				newResponse.Metadata["warning"] = fmt.Sprintf("Response truncated from %d to %d items due to response size limit (%d bytes). %s", len(resultArray), len(truncated), b.config.MaxResponseSize, truncationGuidance)
 				
 				return newResponse
 			}
@@ -1025,7 +1892,32 @@ func (b *ODataMCPBridge) applySizeLimits(response *models.ODataResponse) *models
 	return response
 }
 
-// convertLegacyDates converts date fields to epoch timestamp format (/Date(1234567890000)/)
+// truncationGuidance tells the agent how to avoid truncated responses
+const truncationGuidance = "Use $select to request fewer properties, or $top/$skip to page through the results."
+
+// fitItemsToSize returns how many leading items fit into maxSize bytes of JSON (at least
+// one). The items are encoded one at a time and only counted, never buffered together.
+func fitItemsToSize(items []interface{}, maxSize int) int {
+	var counter countingWriter
+	enc := json.NewEncoder(&counter)
+	size := 1 // Opening bracket
+	for i, item := range items {
+		before := counter.n
+		if err := enc.Encode(item); err != nil {
+			continue
+		}
+		size += counter.n - before // The encoder's newline stands for the comma or closing bracket
+		if size > maxSize {
+			if i == 0 {
+				return 1
+			}
+			return i
+		}
+	}
+	return len(items)
+}
+
+// convertLegacyDates converts /Date(...)/ values in responses to ISO 8601
 func (b *ODataMCPBridge) convertLegacyDates(data interface{}) interface{} {
 	if !b.config.LegacyDates {
 		return data
@@ -1035,23 +1927,34 @@ func (b *ODataMCPBridge) convertLegacyDates(data interface{}) interface{} {
 	return utils.ConvertDatesInResponse(data, true)
 }
 
-// stripMetadata removes __metadata blocks from entities unless specifically requested
-func (b *ODataMCPBridge) stripMetadata(data interface{}) interface{} {
+// slimEntities removes OData noise from entities unless --response-metadata is set:
+// __metadata blocks, __deferred navigation stubs, nulls and empty strings
+func (b *ODataMCPBridge) slimEntities(data interface{}) interface{} {
+	if b.config.ResponseMetadata {
+		return data
+	}
+	
 	switch v := data.(type) {
 	case []interface{}:
 		// Handle array of entities
 		result := make([]interface{}, len(v))
 		for i, item := range v {
-			result[i] = b.stripMetadata(item)
+			result[i] = b.slimEntities(item)
 		}
 		return result
 	case map[string]interface{}:
 		// Handle single entity
 		result := make(map[string]interface{})
 		for key, value := range v {
-			if key != "__metadata" {
-				result[key] = b.stripMetadata(value)
+			if key == "__metadata" || value == nil || value == "" {
+				continue
 			}
+			if nested, ok := value.(map[string]interface{}); ok {
+				if _, deferred := nested["__deferred"]; deferred {
+					continue
+				}
+			}
+			result[key] = b.slimEntities(value)
 		}
 		return result
 	default:
@@ -1060,31 +1963,51 @@ func (b *ODataMCPBridge) stripMetadata(data interface{}) interface{} {
 }
 
 func (b *ODataMCPBridge) handleEntityCount(ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+	filter, _ := args["$filter"].(string)
+	if err := b.checkFilter(entitySetName, filter); err != nil {
+		return nil, err
+	}
+
+	count, err := b.entityCount(ctx, entitySetName, filter)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Return count as formatted string
+	return fmt.Sprintf(`{"count": %d}`, count), nil
+}
+
+// entityCount returns the number of entities of an entity set that match a filter,
+// reusing counts for --count-cache-ttl
+func (b *ODataMCPBridge) entityCount(ctx context.Context, entitySetName, filter string) (int64, error) {
 	// Build query options - for count we typically only need filter
 	options := make(map[string]string)
-	
-	if filter, ok := args["$filter"].(string); ok && filter != "" {
+	if filter != "" {
 		options[constants.QueryFilter] = filter
 	}
+	if b.config.CountCacheTTL > 0 {
+		if count, ok := b.counts.get(entitySetName, filter); ok {
+			return count, nil
+		}
+	}
 	
-	// Add $inlinecount=allpages to get inline count (OData v2 syntax)
+	// Add $inlinecount=allpages to get inline count ($count=true for OData v4)
 	options[constants.QueryInlineCount] = "allpages"
 	options[constants.QueryTop] = "0" // We only want the count, not the data
 	
 	// Call OData client to get count
 	response, err := b.client.GetEntitySet(ctx, entitySetName, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get entity count: %w", err)
+		return 0, fmt.Errorf("failed to get entity count: %w", err)
 	}
 	
 	// Extract count from response
 	count := int64(0)
 	if response.Count != nil {
 		count = *response.Count
+		b.counts.put(entitySetName, filter, count, b.config.CountCacheTTL)
 	}
-	
-	// Return count as formatted string
-	return fmt.Sprintf(`{"count": %d}`, count), nil
+	return count, nil
 }
 
 func (b *ODataMCPBridge) handleEntitySearch(ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
@@ -1102,26 +2025,46 @@ func (b *ODataMCPBridge) handleEntitySearch(ctx context.Context, entitySetName s
 	options[constants.QuerySearch] = searchTerm
 	
 	// Handle optional parameters
+	if selectParam, ok := args["$select"].(string); ok && selectParam != "" {
+		options[constants.QuerySelect] = selectParam
+	}
 	if top, ok := args["$top"].(float64); ok {
 		options[constants.QueryTop] = fmt.Sprintf("%d", int(top))
 	}
 	if skip, ok := args["$skip"].(float64); ok {
 		options[constants.QuerySkip] = fmt.Sprintf("%d", int(skip))
 	}
+	raw := b.rawResponses(b.outputFormat(args))
+	topLimit := b.applyDefaultTop(options, !raw)
+	autoSelect := b.applyAutoSelect(entitySetName, options)
+	b.applyInlineCount(options)
 	
 	// Call OData client to search entities
-	response, err := b.client.GetEntitySet(ctx, entitySetName, options)
+	var response *models.ODataResponse
+	var err error
+	if raw {
+		var raw string
+		raw, response, err = b.rawResult(b.client.GetEntitySetRaw(ctx, entitySetName, options))
+		if err == nil && response == nil {
+			return raw, nil
+		}
+	} else {
+		response, err = b.client.GetEntitySet(ctx, entitySetName, options)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to search entities: %w", err)
 	}
-	
-	// Format response as JSON string
-	result, err := json.Marshal(response)
-	if err != nil {
-		return nil, fmt.Errorf("failed to format response: %w", err)
+	topLimit.trim(response, options)
+	response.Value = b.slimEntities(b.convertLegacyDates(response.Value))
+	response = b.applySizeLimits(response)
+	if topLimit != nil {
+		b.markDefaultTopTruncation(response, topLimit)
+	}
+	if autoSelect {
+		markAutoSelect(response, options)
 	}
 	
-	return string(result), nil
+	return b.formatListResult(entitySetName, response, args, options)
 }
 
 func (b *ODataMCPBridge) handleEntityGet(ctx context.Context, entitySetName string, entityType *models.EntityType, args map[string]interface{}) (interface{}, error) {
@@ -1145,10 +2088,23 @@ func (b *ODataMCPBridge) handleEntityGet(ctx context.Context, entitySetName stri
 	}
 	
 	// Call OData client to get entity
-	response, err := b.client.GetEntity(ctx, entitySetName, key, options)
+	var response *models.ODataResponse
+	var err error
+	if b.rawResponses(constants.OutputFormatJSON) {
+		var raw string
+		raw, response, err = b.rawResult(b.client.GetEntityRaw(ctx, entitySetName, key, options))
+		if err == nil && response == nil {
+			return raw, nil
+		}
+	} else {
+		response, err = b.queryWithExpand(ctx, entitySetName, options, false, func(ctx context.Context, options map[string]string) (*models.ODataResponse, error) {
+			return b.client.GetEntity(ctx, entitySetName, key, options)
+		})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get entity: %w", err)
 	}
+	response.Value = b.slimEntities(b.convertLegacyDates(response.Value))
 	
 	// Format response as JSON string
 	result, err := json.Marshal(response)
@@ -1169,20 +2125,24 @@ func (b *ODataMCPBridge) handleEntityCreate(ctx context.Context, entitySetName s
 		}
 	}
 	
-	// Convert numeric fields to strings for SAP OData v2 compatibility
+	// Send Edm.Decimal and Edm.Int64 fields as strings for OData v2 compatibility
 	// This prevents "Failed to read property 'Quantity' at offset" errors
-	entityData = utils.ConvertNumericsInMap(entityData)
+	entityData = b.convertNumericsForBackend(entitySetName, entityData)
 	
-	// Convert date fields to OData legacy format if needed
-	if b.config.LegacyDates {
-		entityData = utils.ConvertDatesInMap(entityData, false) // false = convert ISO to legacy
+	// Convert Edm.DateTime fields to OData legacy format if needed
+	entityData = b.convertDatesForBackend(entitySetName, entityData)
+	
+	if err := b.confirmOperation(ctx, constants.OpCreate, entitySetName, nil); err != nil {
+		return nil, err
 	}
 	
 	// Call OData client to create entity
 	response, err := b.client.CreateEntity(ctx, entitySetName, entityData)
+	b.auditOperation(ctx, constants.OpCreate, entitySetName, nil, entityData, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create entity: %w", err)
 	}
+	b.counts.invalidate(entitySetName)
 	
 	// Enhance response (includes date conversion if enabled)
 	response = b.enhanceResponse(response, make(map[string]string))
@@ -1233,20 +2193,24 @@ func (b *ODataMCPBridge) handleEntityUpdate(ctx context.Context, entitySetName s
 		}
 	}
 	
-	// Convert numeric fields to strings for SAP OData v2 compatibility
+	if err := b.confirmOperation(ctx, constants.OpUpdate, entitySetName, key); err != nil {
+		return nil, err
+	}
+	
+	// Send Edm.Decimal and Edm.Int64 fields as strings for OData v2 compatibility
 	// This prevents "Failed to read property 'Quantity' at offset" errors
-	updateData = utils.ConvertNumericsInMap(updateData)
+	updateData = b.convertNumericsForBackend(entitySetName, updateData)
 	
-	// Convert date fields to OData legacy format if needed
-	if b.config.LegacyDates {
-		updateData = utils.ConvertDatesInMap(updateData, false) // false = convert ISO to legacy
-	}
+	// Convert Edm.DateTime fields to OData legacy format if needed
+	updateData = b.convertDatesForBackend(entitySetName, updateData)
 	
 	// Call OData client to update entity
 	response, err := b.client.UpdateEntity(ctx, entitySetName, key, updateData, method)
+	b.auditOperation(ctx, constants.OpUpdate, entitySetName, key, updateData, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update entity: %w", err)
 	}
+	b.counts.invalidate(entitySetName)
 	
 	// Enhance response (includes date conversion if enabled)
 	response = b.enhanceResponse(response, make(map[string]string))
@@ -1271,13 +2235,30 @@ func (b *ODataMCPBridge) handleEntityDelete(ctx context.Context, entitySetName s
 		}
 	}
 	
+	if err := b.confirmOperation(ctx, constants.OpDelete, entitySetName, key); err != nil {
+		return nil, err
+	}
+	
 	// Call OData client to delete entity
-	_, err := b.client.DeleteEntity(ctx, entitySetName, key)
+	response, err := b.client.DeleteEntity(ctx, entitySetName, key)
+	b.auditOperation(ctx, constants.OpDelete, entitySetName, key, nil, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete entity: %w", err)
 	}
+	b.counts.invalidate(entitySetName)
 	
 	// For successful deletes, return a simple success message
+	if len(response.Messages) > 0 {
+		result, err := json.Marshal(map[string]interface{}{
+			"status":   "success",
+			"message":  "Entity deleted successfully",
+			"messages": response.Messages,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(result), nil
+	}
 	return `{"status": "success", "message": "Entity deleted successfully"}`, nil
 }
 
@@ -1300,17 +2281,33 @@ func (b *ODataMCPBridge) handleFunctionCall(ctx context.Context, functionName st
 		method = constants.GET
 	}
 	
+	if err := b.confirmOperation(ctx, constants.OpFunction, functionName, parameters); err != nil {
+		return nil, err
+	}
+	
 	// Call OData client to execute function
-	response, err := b.client.CallFunction(ctx, functionName, parameters, method)
+	response, err := withProgressHeartbeat(ctx, "Function "+functionName, func() (*models.ODataResponse, error) {
+		return b.client.CallFunction(ctx, functionName, parameters, method)
+	})
+	if method != constants.GET {
+		b.auditOperation(ctx, constants.OpFunction, functionName, nil, parameters, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to call function: %w", err)
 	}
+	if method != constants.GET {
+		// Actions may change any entity set
+		b.counts.invalidate("")
+	}
+	response.Value = unwrapFunctionResult(functionName, function, response.Value)
+	response.Value = b.slimEntities(b.convertLegacyDates(response.Value))
+	response = b.applySizeLimits(response)
 	
 	// Format response as JSON string
-	result, err := json.Marshal(response)
+	result, err := encodeResponse(response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to format response: %w", err)
 	}
 	
-	return string(result), nil
+	return result, nil
 }
\ No newline at end of file
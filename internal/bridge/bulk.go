@@ -0,0 +1,213 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// bulkItemResult is the outcome of one entity of a bulk create
+type bulkItemResult struct {
+	Index  int         `json:"index"`
+	Status string      `json:"status"` // "created" or "failed"
+	Entity interface{} `json:"entity,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// generateBulkCreateTool creates a tool that creates many entities of an entity set in
+// $batch changesets
+func (b *ODataMCPBridge) generateBulkCreateTool(entitySetName string, entitySet *models.EntitySet, entityType *models.EntityType) {
+	opName := constants.GetToolOperationName(constants.OpBulkCreate, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, entitySetName)
+
+	description := fmt.Sprintf("Create many %s entities at once. They are sent in $batch changesets of up to %d entities; "+
+		"a changeset is created completely or not at all, and the result lists the outcome of every entity", b.entitySetTitle(entitySetName), b.bulkChangesetSize())
+
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+	for _, prop := range entityType.Properties {
+		// Skip key properties that are auto-generated
+		if prop.IsKey {
+			continue
+		}
+		properties[prop.Name] = b.createPropertySchema(prop)
+		if !prop.Nullable {
+			required = append(required, prop.Name)
+		}
+	}
+	entity := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		entity["required"] = required
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"entities": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("The %s entities to create", b.entitySetTitle(entitySetName)),
+					"items":       entity,
+					"minItems":    1,
+				},
+			},
+			"required": []string{"entities"},
+		},
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleBulkCreate(ctx, entitySetName, args)
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   entitySetName,
+		Operation:   constants.OpBulkCreate,
+	})
+}
+
+// bulkChangesetSize returns the number of entities sent per changeset
+func (b *ODataMCPBridge) bulkChangesetSize() int {
+	if b.config.BulkChangesetSize > 0 {
+		return b.config.BulkChangesetSize
+	}
+	return constants.DefaultBulkChangesetSize
+}
+
+// handleBulkCreate creates entities in changesets of --bulk-changeset-size entities, sent
+// by --bulk-workers concurrent $batch requests. A failed changeset does not stop the
+// others; the result reports every entity as created or failed.
+func (b *ODataMCPBridge) handleBulkCreate(ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+	list, ok := args["entities"].([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("entities must be a non-empty array of objects")
+	}
+	entities := make([]map[string]interface{}, len(list))
+	for i, item := range list {
+		data, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("entities[%d] is not an object", i)
+		}
+		entityData := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			// Skip any system parameters (starting with $)
+			if !strings.HasPrefix(k, "$") {
+				entityData[k] = v
+			}
+		}
+		entityData = b.convertNumericsForBackend(entitySetName, entityData)
+		entities[i] = b.convertDatesForBackend(entitySetName, entityData)
+	}
+
+	if err := b.confirmOperation(ctx, constants.OpCreate, entitySetName, nil); err != nil {
+		return nil, err
+	}
+
+	size := b.bulkChangesetSize()
+	changesets := (len(entities) + size - 1) / size
+	workers := b.config.BulkWorkers
+	if workers <= 0 || workers > changesets {
+		workers = changesets
+	}
+
+	results := make([]bulkItemResult, len(entities))
+	var dryRun *client.DryRunRequest
+	var mu sync.Mutex
+	done := 0
+
+	var sent sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		sent.Add(1)
+		go func() {
+			defer sent.Done()
+			for changeset := range next {
+				start := changeset * size
+				end := min(start+size, len(entities))
+
+				requests := make([]client.ChangesetRequest, 0, end-start)
+				for _, entity := range entities[start:end] {
+					requests = append(requests, client.ChangesetRequest{Method: constants.POST, Path: entitySetName, Body: entity})
+				}
+				// Changesets not yet sent when the call is cancelled fail with its error
+				err := ctx.Err()
+				var created []interface{}
+				if err == nil {
+					err = mcp.Recovered(entitySetName, func() error {
+						responses, err := b.client.ExecuteChangeset(ctx, requests)
+						if err != nil {
+							return err
+						}
+						for _, response := range responses {
+							created = append(created, b.enhanceResponse(response, make(map[string]string)).Value)
+						}
+						return nil
+					})
+				}
+
+				for i := start; i < end; i++ {
+					b.auditOperation(ctx, constants.OpCreate, entitySetName, nil, entities[i], err)
+					if err != nil {
+						results[i] = bulkItemResult{Index: i, Status: "failed", Error: err.Error()}
+						continue
+					}
+					results[i] = bulkItemResult{Index: i, Status: "created", Entity: created[i-start]}
+				}
+
+				mu.Lock()
+				// With --dry-run, the request of a changeset is the result
+				var request *client.DryRunRequest
+				if errors.As(err, &request) {
+					dryRun = request
+				}
+				done++
+				mcp.ReportProgress(ctx, float64(done), float64(changesets), fmt.Sprintf("%d of %d changesets sent", done, changesets))
+				mu.Unlock()
+			}
+		}()
+	}
+	for changeset := 0; changeset < changesets; changeset++ {
+		next <- changeset
+	}
+	close(next)
+	sent.Wait()
+
+	if dryRun != nil {
+		return nil, dryRun
+	}
+
+	created := 0
+	for _, r := range results {
+		if r.Status == "created" {
+			created++
+		}
+	}
+	if created > 0 {
+		b.counts.invalidate(entitySetName)
+	}
+
+	result, err := json.Marshal(map[string]interface{}{
+		"created":    created,
+		"failed":     len(results) - created,
+		"changesets": changesets,
+		"results":    results,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	return string(result), nil
+}
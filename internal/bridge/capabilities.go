@@ -0,0 +1,45 @@
+package bridge
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// filterParam is the input schema of the $filter argument of an entity set's filter and
+// count tools, naming its non-filterable properties. It is nil when the entity set does
+// not support $filter (Capabilities.FilterRestrictions).
+func (b *ODataMCPBridge) filterParam(entitySetName string) map[string]interface{} {
+	entitySet, ok := b.metadata.EntitySets[entitySetName]
+	if ok && !entitySet.Filterable {
+		return nil
+	}
+
+	description := "OData filter expression"
+	if ok && len(entitySet.NonFilterableProperties) > 0 {
+		description += fmt.Sprintf(". These properties cannot be filtered on: %s", strings.Join(entitySet.NonFilterableProperties, ", "))
+	}
+	return map[string]interface{}{
+		"type":        "string",
+		"description": description,
+	}
+}
+
+// checkFilter rejects a $filter on an entity set that does not support filtering and
+// warns when it references properties the service declares non-filterable
+func (b *ODataMCPBridge) checkFilter(entitySetName, filter string) error {
+	entitySet, ok := b.metadata.EntitySets[entitySetName]
+	if !ok || filter == "" {
+		return nil
+	}
+	if !entitySet.Filterable {
+		return fmt.Errorf("entity set %s does not support $filter", entitySetName)
+	}
+
+	for _, name := range entitySet.NonFilterableProperties {
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`).MatchString(filter) {
+			b.server.Log("warning", "odata-bridge", fmt.Sprintf("$filter on %s references %s, which the service declares non-filterable", entitySetName, name))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// changesetOperations are the operations an execute_changeset call can run, in the
+// order of the operation enum of the tool
+var changesetOperations = []string{constants.OpCreate, constants.OpUpdate, constants.OpDelete}
+
+// changesetItem is one validated operation of an execute_changeset call
+type changesetItem struct {
+	operation string
+	entitySet string
+	request   client.ChangesetRequest
+}
+
+// changesetItemResult is the outcome of one operation of an execute_changeset call
+type changesetItemResult struct {
+	Index     int                 `json:"index"`
+	Operation string              `json:"operation"`
+	EntitySet string              `json:"entity_set"`
+	Entity    interface{}         `json:"entity,omitempty"`
+	Messages  []models.SAPMessage `json:"messages,omitempty"`
+}
+
+// changesetEntityOperations returns the create, update and delete operations with tools
+// per included entity set, leaving out entity sets without any
+func (b *ODataMCPBridge) changesetEntityOperations() map[string]map[string]bool {
+	allowed := make(map[string]map[string]bool)
+	for _, name := range b.includedEntityNames() {
+		for _, operation := range b.entitySetOperations(name, b.metadata.EntitySets[name]) {
+			for _, candidate := range changesetOperations {
+				if operation == candidate {
+					if allowed[name] == nil {
+						allowed[name] = make(map[string]bool)
+					}
+					allowed[name][operation] = true
+				}
+			}
+		}
+	}
+	return allowed
+}
+
+// hasChangesetTool reports whether the execute_changeset tool is generated
+func (b *ODataMCPBridge) hasChangesetTool() bool {
+	return b.config.ChangesetTool && len(b.changesetEntityOperations()) > 0
+}
+
+// generateChangesetTool creates the tool that runs several create, update and delete
+// operations in one $batch changeset. With --changeset-tool, it is generated if at least
+// one entity set can be modified.
+func (b *ODataMCPBridge) generateChangesetTool() {
+	if !b.hasChangesetTool() {
+		return
+	}
+	allowed := b.changesetEntityOperations()
+	entityNames := make([]string, 0, len(allowed))
+	for _, name := range b.includedEntityNames() {
+		if allowed[name] != nil {
+			entityNames = append(entityNames, name)
+		}
+	}
+	toolName := b.formatToolName("execute_changeset", "")
+
+	tool := &mcp.Tool{
+		Name: toolName,
+		Description: "Run several create, update and delete operations in one $batch changeset. The service applies all of them or none, " +
+			"and the CSRF token is fetched once for the whole changeset instead of once per operation. Use it for changes that belong together, " +
+			"e.g. a header with its items",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"operations": map[string]interface{}{
+					"type":        "array",
+					"description": "The operations, applied in this order",
+					"minItems":    1,
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"operation": map[string]interface{}{
+								"type": "string",
+								"enum": changesetOperations,
+							},
+							"entity_set": map[string]interface{}{
+								"type":        "string",
+								"description": "Name of the entity set (see odata_service_info)",
+								"enum":        entityNames,
+							},
+							"key": map[string]interface{}{
+								"type":        "object",
+								"description": "Key properties of the entity to update or delete",
+							},
+							"data": map[string]interface{}{
+								"type":        "object",
+								"description": "Properties of the entity to create, or the properties to update",
+							},
+							"method": map[string]interface{}{
+								"type":        "string",
+								"description": "HTTP method of an update (PUT, PATCH, or MERGE)",
+								"enum":        []string{"PUT", "PATCH", "MERGE"},
+								"default":     "PUT",
+							},
+						},
+						"required": []string{"operation", "entity_set"},
+					},
+				},
+			},
+			"required": []string{"operations"},
+		},
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleChangeset(ctx, args)
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: tool.Description,
+		Operation:   constants.OpChangeset,
+	})
+}
+
+// handleChangeset validates all operations, asks for the confirmations --confirm requires
+// and sends the operations as one changeset. Nothing is sent if an operation is invalid,
+// and a changeset the service rejects applies none of its operations.
+func (b *ODataMCPBridge) handleChangeset(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	list, ok := args["operations"].([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("%w: operations must be a non-empty array of objects", mcp.ErrInvalidArguments)
+	}
+	allowed := b.changesetEntityOperations()
+	items := make([]changesetItem, len(list))
+	for i, entry := range list {
+		item, err := b.changesetItem(entry, allowed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: operations[%d]: %v", mcp.ErrInvalidArguments, i, err)
+		}
+		items[i] = item
+	}
+
+	for _, item := range items {
+		if err := b.confirmOperation(ctx, item.operation, item.entitySet, item.request.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	requests := make([]client.ChangesetRequest, len(items))
+	for i, item := range items {
+		requests[i] = item.request
+	}
+	responses, err := b.client.ExecuteChangeset(ctx, requests)
+	for _, item := range items {
+		b.auditOperation(ctx, item.operation, item.entitySet, item.request.Key, item.request.Body, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("changeset failed, no operation was applied: %w", err)
+	}
+
+	results := make([]changesetItemResult, len(items))
+	for i, item := range items {
+		b.counts.invalidate(item.entitySet)
+		results[i] = changesetItemResult{Index: i, Operation: item.operation, EntitySet: item.entitySet}
+		if item.operation == constants.OpDelete {
+			results[i].Messages = responses[i].Messages
+			continue
+		}
+		response := b.enhanceResponse(responses[i], make(map[string]string))
+		results[i].Entity = response.Value
+		results[i].Messages = response.Messages
+	}
+
+	result, err := json.Marshal(map[string]interface{}{
+		"status":     "success",
+		"operations": len(results),
+		"results":    results,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	return string(result), nil
+}
+
+// changesetItem validates one operation of an execute_changeset call against the
+// operations allowed per entity set and builds its changeset request
+func (b *ODataMCPBridge) changesetItem(entry interface{}, allowed map[string]map[string]bool) (changesetItem, error) {
+	args, ok := entry.(map[string]interface{})
+	if !ok {
+		return changesetItem{}, fmt.Errorf("not an object")
+	}
+	operation, _ := args["operation"].(string)
+	entitySetName, _ := args["entity_set"].(string)
+	if allowed[entitySetName] == nil {
+		return changesetItem{}, fmt.Errorf("entity set %q cannot be modified", entitySetName)
+	}
+	if !allowed[entitySetName][operation] {
+		return changesetItem{}, fmt.Errorf("%q is not allowed on %s", operation, entitySetName)
+	}
+	item := changesetItem{operation: operation, entitySet: entitySetName}
+
+	if operation != constants.OpDelete {
+		data, _ := args["data"].(map[string]interface{})
+		if len(data) == 0 {
+			return changesetItem{}, fmt.Errorf("%s requires data", operation)
+		}
+		body := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			// Skip any system parameters (starting with $)
+			if !strings.HasPrefix(k, "$") {
+				body[k] = v
+			}
+		}
+		body = b.convertNumericsForBackend(entitySetName, body)
+		item.request.Body = b.convertDatesForBackend(entitySetName, body)
+	}
+
+	if operation == constants.OpCreate {
+		item.request.Method = constants.POST
+		item.request.Path = entitySetName
+		return item, nil
+	}
+
+	entityType := b.metadata.EntityTypes[b.metadata.EntitySets[entitySetName].EntityType]
+	if entityType == nil {
+		return changesetItem{}, fmt.Errorf("%s: %s", constants.ErrEntityTypeNotFound, entitySetName)
+	}
+	keyArgs, _ := args["key"].(map[string]interface{})
+	key := make(map[string]interface{}, len(entityType.KeyProperties))
+	for _, keyProp := range entityType.KeyProperties {
+		value, exists := keyArgs[keyProp]
+		if !exists {
+			return changesetItem{}, fmt.Errorf("missing required key property: %s", keyProp)
+		}
+		key[keyProp] = value
+	}
+	item.request.Path = entitySetName
+	item.request.Key = key
+
+	item.request.Method = constants.DELETE
+	if operation == constants.OpUpdate {
+		item.request.Method = constants.PUT
+		if method, ok := args["method"].(string); ok && method != "" {
+			switch method {
+			case constants.PUT, constants.PATCH, constants.MERGE:
+				item.request.Method = method
+			default:
+				return changesetItem{}, fmt.Errorf("method must be PUT, PATCH or MERGE, not %q", method)
+			}
+		}
+	}
+	return item, nil
+}
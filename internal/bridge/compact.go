@@ -0,0 +1,251 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// generateCompactTools registers a fixed set of generic tools that take the
+// entity set name as a parameter, instead of one tool per entity set and operation
+func (b *ODataMCPBridge) generateCompactTools() {
+	entityNames := b.includedEntityNames()
+
+	// Collect the entity sets supporting each operation
+	supported := make(map[string][]string)
+	for _, name := range entityNames {
+		for _, operation := range b.entitySetOperations(name, b.metadata.EntitySets[name]) {
+			supported[operation] = append(supported[operation], name)
+		}
+	}
+
+	entitySetParam := func(operation string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "string",
+			"description": "Name of the entity set (see odata_service_info)",
+			"enum":        supported[operation],
+		}
+	}
+	keyParam := map[string]interface{}{
+		"type":        "object",
+		"description": "Key properties of the entity as name/value pairs (e.g. {\"ProductID\": 1})",
+	}
+
+	if len(supported[constants.OpFilter]) > 0 {
+		description := "List/filter entities of an entity set with OData query options"
+		properties := map[string]interface{}{
+			"entity_set":    entitySetParam(constants.OpFilter),
+			"$filter":       map[string]interface{}{"type": "string", "description": "OData filter expression"},
+			"$select":       map[string]interface{}{"type": "string", "description": "Comma-separated list of properties to select"},
+			"$expand":       map[string]interface{}{"type": "string", "description": "Navigation properties to expand"},
+			"$orderby":      map[string]interface{}{"type": "string", "description": "Properties to order by"},
+			"$top":          map[string]interface{}{"type": "integer", "description": "Maximum number of entities to return"},
+			"$skip":         map[string]interface{}{"type": "integer", "description": "Number of entities to skip"},
+			"output_format": b.outputFormatParam(),
+		}
+		if hierarchies := b.hierarchyEntityNames(supported[constants.OpFilter]); len(hierarchies) > 0 {
+			description += b.addCompactHierarchyParams(properties, hierarchies)
+		}
+		b.registerCompactTool("query_entity", constants.OpFilter, description, properties, []string{"entity_set"}, func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+			if entityType := b.entityTypeOf(entitySetName); entityType != nil && entityType.Hierarchy != nil {
+				return b.handleHierarchyFilter(ctx, entitySetName, entityType, args)
+			}
+			for _, param := range []string{"node", "parent", "level", "subtree"} {
+				if _, ok := args[param]; ok {
+					return nil, fmt.Errorf("%w: %s applies only to hierarchy entity sets, and %s is none", mcp.ErrInvalidArguments, param, entitySetName)
+				}
+			}
+			return b.handleEntityFilter(ctx, entitySetName, args)
+		})
+	}
+
+	if len(supported[constants.OpCount]) > 0 {
+		b.registerCompactTool("count_entity", constants.OpCount, "Get count of entities of an entity set with optional filter", map[string]interface{}{
+			"entity_set": entitySetParam(constants.OpCount),
+			"$filter":    map[string]interface{}{"type": "string", "description": "OData filter expression"},
+		}, []string{"entity_set"}, func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+			return b.handleEntityCount(ctx, entitySetName, args)
+		})
+	}
+
+	if len(supported[constants.OpSearch]) > 0 {
+		b.registerCompactTool("search_entity", constants.OpSearch, "Full-text search entities of an entity set", map[string]interface{}{
+			"entity_set":    entitySetParam(constants.OpSearch),
+			"search":        map[string]interface{}{"type": "string", "description": "Search query string"},
+			"$select":       map[string]interface{}{"type": "string", "description": "Comma-separated list of properties to select"},
+			"$top":          map[string]interface{}{"type": "integer", "description": "Maximum number of entities to return"},
+			"output_format": b.outputFormatParam(),
+		}, []string{"entity_set", "search"}, func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+			return b.handleEntitySearch(ctx, entitySetName, args)
+		})
+	}
+
+	if len(supported[constants.OpGet]) > 0 {
+		b.registerCompactTool("get_entity", constants.OpGet, "Get a single entity of an entity set by key", map[string]interface{}{
+			"entity_set": entitySetParam(constants.OpGet),
+			"key":        keyParam,
+			"$select":    map[string]interface{}{"type": "string", "description": "Comma-separated list of properties to select"},
+			"$expand":    map[string]interface{}{"type": "string", "description": "Navigation properties to expand"},
+		}, []string{"entity_set", "key"}, func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+			return b.handleEntityGet(ctx, entitySetName, b.entityTypeOf(entitySetName), mergeCompactArgs(args, "key"))
+		})
+	}
+
+	if len(supported[constants.OpCreate]) > 0 {
+		b.registerCompactTool("create_entity", constants.OpCreate, "Create a new entity in an entity set", map[string]interface{}{
+			"entity_set": entitySetParam(constants.OpCreate),
+			"data":       map[string]interface{}{"type": "object", "description": "Property values of the new entity"},
+		}, []string{"entity_set", "data"}, func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+			data, _ := args["data"].(map[string]interface{})
+			return b.handleEntityCreate(ctx, entitySetName, data)
+		})
+	}
+
+	if len(supported[constants.OpUpdate]) > 0 {
+		b.registerCompactTool("update_entity", constants.OpUpdate, "Update an existing entity of an entity set", map[string]interface{}{
+			"entity_set": entitySetParam(constants.OpUpdate),
+			"key":        keyParam,
+			"data":       map[string]interface{}{"type": "object", "description": "Property values to update"},
+			"_method": map[string]interface{}{
+				"type":        "string",
+				"description": "HTTP method to use (PUT, PATCH, or MERGE)",
+				"enum":        []string{"PUT", "PATCH", "MERGE"},
+				"default":     "PUT",
+			},
+		}, []string{"entity_set", "key", "data"}, func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+			return b.handleEntityUpdate(ctx, entitySetName, b.entityTypeOf(entitySetName), mergeCompactArgs(args, "key", "data"))
+		})
+	}
+
+	if len(supported[constants.OpDelete]) > 0 {
+		b.registerCompactTool("delete_entity", constants.OpDelete, "Delete an entity of an entity set", map[string]interface{}{
+			"entity_set": entitySetParam(constants.OpDelete),
+			"key":        keyParam,
+		}, []string{"entity_set", "key"}, func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error) {
+			return b.handleEntityDelete(ctx, entitySetName, b.entityTypeOf(entitySetName), mergeCompactArgs(args, "key"))
+		})
+	}
+
+	functionNames := b.includedFunctionNames()
+	if len(functionNames) > 0 {
+		toolName := b.formatToolName("call_function", "")
+		tool := &mcp.Tool{
+			Name:        toolName,
+			Description: "Call a function import of the OData service",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"function": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the function import",
+						"enum":        functionNames,
+					},
+					"parameters": map[string]interface{}{
+						"type":        "object",
+						"description": "Function parameters as name/value pairs",
+					},
+				},
+				"required": []string{"function"},
+			},
+		}
+
+		handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			functionName, _ := args["function"].(string)
+			function, exists := b.metadata.FunctionImports[functionName]
+			if !exists || !b.shouldIncludeFunction(functionName) {
+				return nil, fmt.Errorf("invalid entity: function import not found: %s", functionName)
+			}
+			parameters, _ := args["parameters"].(map[string]interface{})
+			if parameters == nil {
+				parameters = make(map[string]interface{})
+			}
+			return b.handleFunctionCall(ctx, functionName, function, parameters)
+		}
+
+		b.registerTool(tool, handler, &models.ToolInfo{
+			Name:        toolName,
+			Description: tool.Description,
+			Operation:   constants.OpFunction,
+		})
+	}
+}
+
+// registerCompactTool registers a generic tool that resolves and validates the
+// entity_set argument before delegating to the operation handler
+func (b *ODataMCPBridge) registerCompactTool(name, operation, description string, properties map[string]interface{}, required []string, handle func(b *ODataMCPBridge, ctx context.Context, entitySetName string, args map[string]interface{}) (interface{}, error)) {
+	toolName := b.formatToolName(name, "")
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		},
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		entitySetName, _ := args["entity_set"].(string)
+		entitySet, exists := b.metadata.EntitySets[entitySetName]
+		if !exists || !b.shouldIncludeEntity(entitySetName) || b.entityTypeOf(entitySetName) == nil {
+			return nil, fmt.Errorf("invalid entity: entity set not found: %s", entitySetName)
+		}
+
+		allowed := false
+		for _, op := range b.entitySetOperations(entitySetName, entitySet) {
+			if op == operation {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("operation %s is not available for entity set %s", operation, entitySetName)
+		}
+
+		// The caller's arguments are left untouched
+		entityArgs := make(map[string]interface{}, len(args))
+		for k, v := range args {
+			if k != "entity_set" {
+				entityArgs[k] = v
+			}
+		}
+		return handle(b, ctx, entitySetName, entityArgs)
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		Operation:   operation,
+	})
+}
+
+// entityTypeOf returns the entity type of an entity set, or nil if it cannot be resolved
+func (b *ODataMCPBridge) entityTypeOf(entitySetName string) *models.EntityType {
+	entitySet, exists := b.metadata.EntitySets[entitySetName]
+	if !exists {
+		return nil
+	}
+	return b.metadata.EntityTypes[entitySet.EntityType]
+}
+
+// mergeCompactArgs flattens the given object arguments (e.g. "key", "data") into
+// the top-level arguments expected by the per-entity handlers
+func mergeCompactArgs(args map[string]interface{}, objectArgs ...string) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for k, v := range args {
+		merged[k] = v
+	}
+	for _, name := range objectArgs {
+		delete(merged, name)
+		if values, ok := args[name].(map[string]interface{}); ok {
+			for k, v := range values {
+				merged[k] = v
+			}
+		}
+	}
+	return merged
+}
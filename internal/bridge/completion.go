@@ -0,0 +1,99 @@
+package bridge
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// handleCompletion completes entity set names for the entity resource template
+// and entity set, function and property names in tool arguments
+func (b *ODataMCPBridge) handleCompletion(ctx context.Context, req *mcp.CompletionRequest) ([]string, error) {
+	switch req.Ref["type"] {
+	case "ref/resource":
+		if req.Argument == "entitySet" {
+			return mcp.FilterCompletions(b.includedEntityNames(), req.Value), nil
+		}
+	case "ref/tool":
+		name, _ := req.Ref["name"].(string)
+		if err := b.server.LoadTools(); err != nil {
+			return nil, err
+		}
+		b.mu.RLock()
+		info := b.tools[name]
+		b.mu.RUnlock()
+		if info == nil {
+			return nil, nil
+		}
+
+		switch req.Argument {
+		case "entity_set":
+			return mcp.FilterCompletions(b.includedEntityNames(), req.Value), nil
+		case "function":
+			return mcp.FilterCompletions(b.includedFunctionNames(), req.Value), nil
+		case "$select", "$orderby", "$expand":
+			entitySetName := info.EntitySet
+			if entitySetName == "" {
+				// Compact tools take the entity set as an argument
+				entitySetName = req.Arguments["entity_set"]
+			}
+			entityType := b.entityTypeOf(entitySetName)
+			if entityType == nil {
+				return nil, nil
+			}
+			return completeNameList(propertyCandidates(entityType, req.Argument), req.Value), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// propertyCandidates returns the names that may appear in a $select, $orderby or $expand list
+func propertyCandidates(entityType *models.EntityType, argument string) []string {
+	var names []string
+	if argument == "$expand" {
+		for _, nav := range entityType.NavigationProps {
+			names = append(names, nav.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	for _, prop := range entityType.Properties {
+		names = append(names, prop.Name)
+		if argument == "$orderby" {
+			names = append(names, prop.Name+" desc")
+		}
+	}
+	return names
+}
+
+// completeNameList completes the last entry of a comma-separated list,
+// keeping the entries typed so far and skipping names already listed
+func completeNameList(candidates []string, value string) []string {
+	prefix := ""
+	current := value
+	if comma := strings.LastIndex(value, ","); comma >= 0 {
+		prefix = value[:comma+1]
+		current = strings.TrimLeft(value[comma+1:], " ")
+	}
+
+	used := make(map[string]bool)
+	for _, entry := range strings.Split(prefix, ",") {
+		if fields := strings.Fields(entry); len(fields) > 0 {
+			used[fields[0]] = true
+		}
+	}
+
+	values := []string{}
+	for _, candidate := range mcp.FilterCompletions(candidates, current) {
+		if used[strings.Fields(candidate)[0]] {
+			continue
+		}
+		values = append(values, prefix+candidate)
+	}
+	return values
+}
@@ -0,0 +1,62 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/mcp"
+)
+
+// confirmationSchema is the elicitation form shown for --confirm operations
+var confirmationSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"confirm": map[string]interface{}{
+			"type":        "boolean",
+			"title":       "Confirm",
+			"description": "Run this operation against the OData service",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// confirmOperation asks the user to confirm an operation listed in --confirm.
+// It fails closed: without an answer from the user the operation is not run. Dry runs
+// send nothing to the service and are not confirmed.
+func (b *ODataMCPBridge) confirmOperation(ctx context.Context, operation, target string, key map[string]interface{}) error {
+	if !b.config.RequiresConfirmation(operation) || b.config.DryRun {
+		return nil
+	}
+
+	message := fmt.Sprintf("Confirm %s on %s", operation, target)
+	if len(key) > 0 {
+		message += fmt.Sprintf(" (%s)", formatKeyForDisplay(key))
+	}
+	message += "?"
+
+	result, err := b.server.Elicit(ctx, message, confirmationSchema)
+	if err != nil {
+		if errors.Is(err, mcp.ErrElicitationUnsupported) {
+			return fmt.Errorf("%s on %s requires user confirmation, but the MCP client does not support elicitation", operation, target)
+		}
+		return fmt.Errorf("failed to request confirmation: %w", err)
+	}
+
+	if result.Action != "accept" || result.Content["confirm"] != true {
+		return fmt.Errorf("%s on %s was not confirmed by the user", operation, target)
+	}
+	return nil
+}
+
+// formatKeyForDisplay renders key values as sorted "Name=value" pairs
+func formatKeyForDisplay(key map[string]interface{}) string {
+	parts := make([]string, 0, len(key))
+	for name, value := range key {
+		parts = append(parts, fmt.Sprintf("%s=%v", name, value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,82 @@
+package bridge
+
+import (
+	"sync"
+	"time"
+
+	"github.com/odata-mcp/go/internal/metrics"
+)
+
+// maxCountCacheEntries bounds the counts kept for distinct filters; expired counts are
+// dropped first, and all counts if that is not enough
+const maxCountCacheEntries = 1000
+
+// countCache holds the entity counts of entity sets per filter for --count-cache-ttl, so
+// that agents asking "how many" repeatedly in a conversation are answered without a
+// request. Writes through the bridge drop the counts they may change.
+type countCache struct {
+	mu      sync.Mutex
+	entries map[countKey]countEntry
+}
+
+type countKey struct {
+	entitySet string
+	filter    string
+}
+
+type countEntry struct {
+	count   int64
+	expires time.Time
+}
+
+// get returns the cached count of an entity set and filter, if it has not expired
+func (c *countCache) get(entitySet, filter string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := countKey{entitySet, filter}
+	entry, ok := c.entries[key]
+	if ok && time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	metrics.Default.ObserveCache("entity_counts", ok)
+	return entry.count, ok
+}
+
+// put caches the count of an entity set and filter for ttl
+func (c *countCache) put(entitySet, filter string, count int64, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[countKey]countEntry)
+	}
+	if len(c.entries) >= maxCountCacheEntries {
+		now := time.Now()
+		for key, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= maxCountCacheEntries {
+			c.entries = make(map[countKey]countEntry)
+		}
+	}
+	c.entries[countKey{entitySet, filter}] = countEntry{count: count, expires: time.Now().Add(ttl)}
+}
+
+// invalidate drops the counts of an entity set, or of all entity sets if it is empty
+func (c *countCache) invalidate(entitySet string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if entitySet == "" || key.entitySet == entitySet {
+			delete(c.entries, key)
+		}
+	}
+}
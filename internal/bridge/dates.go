@@ -0,0 +1,43 @@
+package bridge
+
+import (
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/odata-mcp/go/internal/utils"
+)
+
+// convertDatesForBackend converts ISO 8601 input for Edm.DateTime and
+// Edm.DateTimeOffset properties of the entity set to the /Date(...)/ JSON
+// format of OData v2. Property types come from metadata, so string fields that
+// merely look like dates are left alone.
+func (b *ODataMCPBridge) convertDatesForBackend(entitySetName string, data map[string]interface{}) map[string]interface{} {
+	if !b.config.LegacyDates || b.dialect.IsV4() {
+		return data
+	}
+	entityType := b.entityTypeOf(entitySetName)
+	if entityType == nil {
+		return data
+	}
+	return b.convertDates(entityType, data)
+}
+
+// convertDates converts the Edm.DateTime and Edm.DateTimeOffset values of an entity and
+// of the related entities of a deep insert to the /Date(...)/ format
+func (b *ODataMCPBridge) convertDates(entityType *models.EntityType, data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for name, value := range data {
+		result[name] = value
+	}
+	for _, prop := range entityType.Properties {
+		if prop.Type != "Edm.DateTime" && prop.Type != "Edm.DateTimeOffset" {
+			continue
+		}
+		value, ok := data[prop.Name].(string)
+		if !ok || utils.IsODataLegacyDate(value) {
+			continue
+		}
+		if t, ok := utils.ParseISODateTime(value); ok {
+			result[prop.Name] = utils.FormatDateForOData(t, prop.Type, true)
+		}
+	}
+	return b.convertRelatedEntities(entityType, result, b.convertDates)
+}
@@ -0,0 +1,53 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// generateDebugRequestsTool creates the tool that returns the most recent requests sent
+// to the service and their responses (--debug-requests)
+func (b *ODataMCPBridge) generateDebugRequestsTool() {
+	toolName := b.formatToolName("debug_recent_requests", "")
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: "Show the most recent HTTP requests sent to the OData service and their responses (method, URL, headers, bodies cut to 4 KB, status, duration), with credentials redacted. Use it to see exactly what was sent to the service",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of requests to return, most recent first",
+					"default":     5,
+					"minimum":     1,
+				},
+			},
+		},
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		limit := 5
+		if value, ok := args["limit"].(float64); ok && value > 0 {
+			limit = int(value)
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			"requests": b.client.RecentRequests(limit),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(data), nil
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: tool.Description,
+		Operation:   constants.OpInfo,
+	})
+}
@@ -0,0 +1,64 @@
+package bridge
+
+import (
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/odata-mcp/go/internal/utils"
+)
+
+// convertNumericsForBackend serializes Edm.Decimal and Edm.Int64 values of the
+// entity set as strings, as the OData v2 JSON format requires. Property types
+// come from metadata; other numeric properties are sent as JSON numbers.
+func (b *ODataMCPBridge) convertNumericsForBackend(entitySetName string, data map[string]interface{}) map[string]interface{} {
+	if b.dialect.IsV4() {
+		return data
+	}
+	entityType := b.entityTypeOf(entitySetName)
+	if entityType == nil {
+		return data
+	}
+	return b.convertNumerics(entityType, data)
+}
+
+// convertNumerics serializes the Edm.Decimal and Edm.Int64 values of an entity and of
+// the related entities of a deep insert as strings
+func (b *ODataMCPBridge) convertNumerics(entityType *models.EntityType, data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for name, value := range data {
+		result[name] = value
+	}
+	for _, prop := range entityType.Properties {
+		if prop.Type != "Edm.Decimal" && prop.Type != "Edm.Int64" {
+			continue
+		}
+		if value, exists := data[prop.Name]; exists {
+			result[prop.Name] = utils.ConvertNumericToString(value)
+		}
+	}
+	return b.convertRelatedEntities(entityType, result, b.convertNumerics)
+}
+
+// convertRelatedEntities applies convert to the related entities of a deep insert in the
+// navigation properties of an entity, with the target entity type of each
+func (b *ODataMCPBridge) convertRelatedEntities(entityType *models.EntityType, data map[string]interface{}, convert func(*models.EntityType, map[string]interface{}) map[string]interface{}) map[string]interface{} {
+	for _, nav := range entityType.NavigationProps {
+		target := b.metadata.EntityTypes[nav.TargetEntityType]
+		if target == nil {
+			continue
+		}
+		switch related := data[nav.Name].(type) {
+		case map[string]interface{}:
+			data[nav.Name] = convert(target, related)
+		case []interface{}:
+			items := make([]interface{}, len(related))
+			for i, item := range related {
+				if entity, ok := item.(map[string]interface{}); ok {
+					items[i] = convert(target, entity)
+				} else {
+					items[i] = item
+				}
+			}
+			data[nav.Name] = items
+		}
+	}
+	return data
+}
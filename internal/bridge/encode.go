@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// valueField is the value of a collection in the JSON envelope encodeResponse streams into
+var valueField = []byte(`"value":[]`)
+
+// encodeResponse renders a response as JSON, exactly as json.Marshal does, but encodes
+// the entities of a collection one at a time into the result. A large collection is then
+// held once, as the result, instead of also as the marshaled buffer it is copied from.
+func encodeResponse(response *models.ODataResponse) (string, error) {
+	items, ok := response.Value.([]interface{})
+	if !ok || len(items) == 0 {
+		data, err := json.Marshal(response)
+		return string(data), err
+	}
+
+	// The fields before "value" are strings and numbers, so its first occurrence is
+	// the field and not part of a string or a nested object
+	envelope := *response
+	envelope.Value = json.RawMessage(`[]`)
+	data, err := json.Marshal(&envelope)
+	if err != nil {
+		return "", err
+	}
+	split := bytes.Index(data, valueField)
+	if split < 0 {
+		return "", fmt.Errorf("failed to encode response: no value field")
+	}
+	split += len(valueField) - 1 // Between the brackets
+
+	var out strings.Builder
+	out.Write(data[:split])
+	var item bytes.Buffer
+	enc := json.NewEncoder(&item)
+	for i, value := range items {
+		item.Reset()
+		if err := enc.Encode(value); err != nil {
+			return "", fmt.Errorf("failed to encode entity %d: %w", i, err)
+		}
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		out.Write(bytes.TrimSuffix(item.Bytes(), []byte("\n")))
+	}
+	out.Write(data[split:])
+	return out.String(), nil
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
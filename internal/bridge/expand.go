@@ -0,0 +1,212 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// expandQuery sends a query with the given options
+type expandQuery func(ctx context.Context, options map[string]string) (*models.ODataResponse, error)
+
+// queryWithExpand sends a query that may expand several navigation properties. Some
+// backends reject expanding more than one in a request; then the query is sent once per
+// navigation property, concurrently, and the expanded properties are merged into the
+// entities of the first response. Entity sets whose backend rejected the combined
+// request are split right away afterwards. collection tells whether the query reads an
+// entity set rather than one entity.
+func (b *ODataMCPBridge) queryWithExpand(ctx context.Context, entitySetName string, options map[string]string, collection bool, query expandQuery) (*models.ODataResponse, error) {
+	groups := expandGroups(options[constants.QueryExpand])
+	if len(groups) < 2 {
+		return query(ctx, options)
+	}
+	if _, split := b.splitExpand.Load(entitySetName); split {
+		return b.querySplitExpand(ctx, entitySetName, groups, options, collection, query)
+	}
+
+	response, err := query(ctx, options)
+	var statusErr *client.StatusError
+	if err == nil || !errors.As(err, &statusErr) || (statusErr.StatusCode != http.StatusBadRequest && statusErr.StatusCode != http.StatusNotImplemented) {
+		return response, err
+	}
+
+	b.logger.Debug("Combined $expand rejected, expanding navigation properties in separate requests", "entity_set", entitySetName, "expand", options[constants.QueryExpand], "status", statusErr.StatusCode)
+	response, splitErr := b.querySplitExpand(ctx, entitySetName, groups, options, collection, query)
+	if splitErr != nil {
+		// The expansions fail on their own as well, so the original error is the cause
+		return nil, err
+	}
+	b.splitExpand.Store(entitySetName, true)
+	return response, nil
+}
+
+// querySplitExpand sends a query once per group of expand paths (see expandGroups),
+// concurrently, and merges the responses; the first failure cancels the other requests.
+// Without $orderby, the entity set queries are ordered by the key, so that each returns
+// the same entities even if $top or server-side paging cuts them off.
+func (b *ODataMCPBridge) querySplitExpand(ctx context.Context, entitySetName string, groups []string, options map[string]string, collection bool, query expandQuery) (*models.ODataResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	orderby := options[constants.QueryOrderBy]
+	if orderby == "" && collection {
+		orderby = b.keyOrderBy(entitySetName)
+	}
+
+	responses := make([]*models.ODataResponse, len(groups))
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i, group := range groups {
+		pathOptions := make(map[string]string, len(options)+1)
+		for k, v := range options {
+			pathOptions[k] = v
+		}
+		pathOptions[constants.QueryExpand] = group
+		if orderby != "" {
+			pathOptions[constants.QueryOrderBy] = orderby
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var response *models.ODataResponse
+			err := mcp.Recovered(entitySetName, func() (err error) {
+				response, err = query(ctx, pathOptions)
+				return err
+			})
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			responses[i] = response
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	merged := responses[0]
+	var keys []string
+	if entityType := b.entityTypeOf(entitySetName); entityType != nil {
+		keys = entityType.KeyProperties
+	}
+	for i, response := range responses[1:] {
+		if err := mergeExpanded(merged.Value, response.Value, expandProperty(groups[i+1]), keys); err != nil {
+			return nil, err
+		}
+		merged.Messages = append(merged.Messages, response.Messages...)
+	}
+	return merged, nil
+}
+
+// mergeExpanded copies an expanded navigation property from the entities of one response
+// into the matching entities of another: by key, or by position if the entity type
+// declares no key
+func mergeExpanded(target, source interface{}, property string, keys []string) error {
+	if entity, ok := target.(map[string]interface{}); ok {
+		if from, ok := source.(map[string]interface{}); ok {
+			entity[property] = from[property]
+		}
+		return nil
+	}
+
+	targets, ok := target.([]interface{})
+	sources, ok2 := source.([]interface{})
+	if !ok || !ok2 {
+		return fmt.Errorf("failed to merge $expand of %s: unexpected response", property)
+	}
+	byKey := make(map[string]map[string]interface{}, len(sources))
+	for i, item := range sources {
+		if entity, ok := item.(map[string]interface{}); ok {
+			byKey[entityKey(entity, keys, i)] = entity
+		}
+	}
+	for i, item := range targets {
+		entity, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if from, found := byKey[entityKey(entity, keys, i)]; found {
+			entity[property] = from[property]
+		}
+	}
+	return nil
+}
+
+// entityKey identifies an entity of a response by its key properties, or by its position
+func entityKey(entity map[string]interface{}, keys []string, position int) string {
+	if len(keys) == 0 {
+		return fmt.Sprint(position)
+	}
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = fmt.Sprint(entity[key])
+	}
+	return strings.Join(values, "\x00")
+}
+
+// expandPaths splits a $expand list into its navigation paths; commas within the
+// parentheses of v4 expand options do not separate paths
+func expandPaths(expand string) []string {
+	var paths []string
+	depth, start := 0, 0
+	for i, r := range expand {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				if path := strings.TrimSpace(expand[start:i]); path != "" {
+					paths = append(paths, path)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if path := strings.TrimSpace(expand[start:]); path != "" {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// expandGroups splits a $expand list into groups of the paths that start with the same
+// navigation property, each a $expand list of its own. A split request expands whole
+// groups, as merging Items/Product and Items/Supplier from separate requests would keep
+// only one of them.
+func expandGroups(expand string) []string {
+	var groups []string
+	index := make(map[string]int)
+	for _, path := range expandPaths(expand) {
+		property := expandProperty(path)
+		if i, ok := index[property]; ok {
+			groups[i] += "," + path
+			continue
+		}
+		index[property] = len(groups)
+		groups = append(groups, path)
+	}
+	return groups
+}
+
+// expandProperty returns the navigation property of the entity set that a path expands
+func expandProperty(path string) string {
+	if i := strings.IndexAny(path, "/("); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
@@ -0,0 +1,90 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+)
+
+// ExportManifest describes the generated tools as tools/list returns them, for
+// consumers that call tools without speaking MCP
+func (b *ODataMCPBridge) ExportManifest() (map[string]interface{}, error) {
+	tools, err := b.server.GetTools()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"service_url": b.config.ServiceURL,
+		"tools":       tools,
+	}, nil
+}
+
+// ExportOpenAPI describes the generated tools as an OpenAPI 3.1 document with one
+// POST /tools/{name} operation per tool. Input and output schemas are used unchanged as
+// request and response body schemas; tool annotations become x-mcp-annotations.
+func (b *ODataMCPBridge) ExportOpenAPI() (map[string]interface{}, error) {
+	// GetTools generates deferred tools, which takes the bridge lock
+	tools, err := b.server.GetTools()
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	paths := make(map[string]interface{})
+	for _, tool := range tools {
+		operation := map[string]interface{}{
+			"operationId": tool.Name,
+			"summary":     tool.Name,
+			"description": tool.Description,
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					constants.ContentTypeJSON: map[string]interface{}{"schema": tool.InputSchema},
+				},
+			},
+			"responses": openAPIResponses(tool),
+		}
+		if info, ok := b.tools[tool.Name]; ok {
+			switch {
+			case info.EntitySet != "":
+				operation["tags"] = []string{info.EntitySet}
+			case info.Function != "":
+				operation["tags"] = []string{"Functions"}
+			default:
+				operation["tags"] = []string{"Service"}
+			}
+		}
+		if tool.Annotations != nil {
+			operation["x-mcp-annotations"] = tool.Annotations
+		}
+		paths["/tools/"+tool.Name] = map[string]interface{}{"post": operation}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       fmt.Sprintf("OData MCP tools for %s", b.config.ServiceURL),
+			"description": "Tools generated by the OData MCP bridge. Each operation takes the tool arguments as its JSON request body and returns the tool result.",
+			"version":     constants.MCPServerVersion,
+		},
+		"paths": paths,
+	}, nil
+}
+
+// openAPIResponses describes the result of a tool call, typed by its output schema when
+// the tool declares one
+func openAPIResponses(tool *mcp.Tool) map[string]interface{} {
+	success := map[string]interface{}{"description": "Tool result"}
+	if tool.OutputSchema != nil {
+		success["content"] = map[string]interface{}{
+			constants.ContentTypeJSON: map[string]interface{}{"schema": tool.OutputSchema},
+		}
+	}
+	return map[string]interface{}{
+		"200":     success,
+		"default": map[string]interface{}{"description": "Invalid arguments or an error returned by the OData service"},
+	}
+}
@@ -0,0 +1,104 @@
+package bridge
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// applyFacets adds the MaxLength, Precision/Scale and sap:display-format facets of a
+// primitive property to its input schema, so that clients can validate values (e.g. a
+// 10-digit SalesOrder with leading zeros) before the service rejects them
+func applyFacets(schema map[string]interface{}, prop *models.EntityProperty) {
+	if prop.ComplexType != "" || prop.IsCollection {
+		return
+	}
+	jsonType, _ := schema["type"].(string)
+
+	var hints []string
+	if jsonType == "string" && prop.MaxLength > 0 {
+		schema["maxLength"] = prop.MaxLength
+		hints = append(hints, fmt.Sprintf("max %d characters", prop.MaxLength))
+	}
+	switch prop.DisplayFormat {
+	case "NonNegative":
+		if jsonType == "string" {
+			schema["pattern"] = "^[0-9]*$"
+			if prop.MaxLength > 1 {
+				hints = append(hints, fmt.Sprintf("digits only, padded with leading zeros to %d digits, e.g. %s1", prop.MaxLength, strings.Repeat("0", prop.MaxLength-1)))
+			} else {
+				hints = append(hints, "digits only")
+			}
+		}
+	case "UpperCase":
+		hints = append(hints, "upper case")
+	}
+	if prop.Type == "Edm.Decimal" && prop.Precision > 0 {
+		scale := 0
+		if prop.Scale != nil {
+			scale = *prop.Scale
+		}
+		hints = append(hints, fmt.Sprintf("up to %d digits, %d after the decimal point", prop.Precision, scale))
+	}
+	if len(hints) == 0 {
+		return
+	}
+	description, _ := schema["description"].(string)
+	if strings.HasSuffix(description, ")") {
+		schema["description"] = strings.TrimSuffix(description, ")") + ", " + strings.Join(hints, ", ") + ")"
+	} else {
+		schema["description"] = description + " (" + strings.Join(hints, ", ") + ")"
+	}
+}
+
+// createPropertySchema returns the input schema of a property of a create tool. Unlike
+// update tools, where an omitted property keeps its value, it announces the DefaultValue
+// facet the service applies to an omitted property.
+func (b *ODataMCPBridge) createPropertySchema(prop *models.EntityProperty) map[string]interface{} {
+	schema := b.propertySchema(prop, propertyDescription(prop))
+	if prop.ComplexType == "" && !prop.IsCollection {
+		jsonType, _ := schema["type"].(string)
+		if value := defaultValue(prop.DefaultValue, jsonType); value != nil {
+			schema["default"] = value
+		}
+	}
+	return schema
+}
+
+// defaultValue converts a DefaultValue facet to the JSON type of the property's schema,
+// or returns nil if there is none or it does not convert
+func defaultValue(value, jsonType string) interface{} {
+	if value == "" {
+		return nil
+	}
+	switch jsonType {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+		return nil
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+		return nil
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		return nil
+	}
+	return value
+}
+
+// keyPropertySchema returns the input schema of a key property
+func (b *ODataMCPBridge) keyPropertySchema(prop *models.EntityProperty) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":        b.getJSONSchemaType(prop.Type),
+		"description": fmt.Sprintf("Key property: %s", prop.Name),
+	}
+	applyFacets(schema, prop)
+	return schema
+}
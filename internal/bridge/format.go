@@ -0,0 +1,282 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// outputFormatParam is the input schema of the output_format argument of list tools
+func (b *ODataMCPBridge) outputFormatParam() map[string]interface{} {
+	defaultFormat := b.config.OutputFormat
+	if defaultFormat == "" {
+		defaultFormat = constants.OutputFormatJSON
+	}
+	return map[string]interface{}{
+		"type":        "string",
+		"enum":        []string{constants.OutputFormatJSON, constants.OutputFormatColumns, constants.OutputFormatCSV, constants.OutputFormatMarkdown},
+		"default":     defaultFormat,
+		"description": "Result format: json, columns (JSON with the property names listed once in columns and one array per entity in rows, for wide entities), csv (compact table of the selected properties for summarizing large lists) or markdown (table for display)",
+	}
+}
+
+// outputFormat returns the output format of a list tool call: json, columns, csv or markdown
+func (b *ODataMCPBridge) outputFormat(args map[string]interface{}) string {
+	format, _ := args["output_format"].(string)
+	if format == "" {
+		format = b.config.OutputFormat
+	}
+	switch format {
+	case constants.OutputFormatColumns, constants.OutputFormatCSV, constants.OutputFormatMarkdown:
+		return format
+	}
+	return constants.OutputFormatJSON
+}
+
+// formatListResult renders a list response in the output format requested by the tool
+// arguments or configured with --output-format. Non-JSON formats keep the JSON response
+// as structured content; their columns follow the $select of the query options.
+func (b *ODataMCPBridge) formatListResult(entitySetName string, response *models.ODataResponse, args map[string]interface{}, options map[string]string) (interface{}, error) {
+	format := b.outputFormat(args)
+	items, isList := response.Value.([]interface{})
+	if format == constants.OutputFormatColumns && isList {
+		columns := b.tableColumns(entitySetName, items, options[constants.QuerySelect])
+		data, err := json.Marshal(columnarResponse(response, items, columns))
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response as columns: %w", err)
+		}
+		return string(data), nil
+	}
+
+	result, err := encodeResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format response: %w", err)
+	}
+	if format == constants.OutputFormatJSON || format == constants.OutputFormatColumns {
+		return result, nil
+	}
+
+	columns := b.tableColumns(entitySetName, items, options[constants.QuerySelect])
+
+	units := unitProperties(b.entityTypeOf(entitySetName))
+
+	var text string
+	if format == constants.OutputFormatCSV {
+		text, err = renderCSV(response, items, columns, units)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response as CSV: %w", err)
+		}
+	} else {
+		text = renderMarkdown(response, items, columns, units, b.config.TableMaxColumns, b.config.TableMaxRows)
+	}
+	return &mcp.TextResult{Text: text, Structured: result}, nil
+}
+
+// tableColumns returns the columns of a tabular rendering: the $select order if given,
+// otherwise the entity type's property order followed by any other returned properties.
+// OData bookkeeping fields (__metadata, __deferred, ...) are never columns.
+func (b *ODataMCPBridge) tableColumns(entitySetName string, items []interface{}, selectParam string) []string {
+	var columns []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] && !strings.HasPrefix(name, "__") {
+			seen[name] = true
+			columns = append(columns, name)
+		}
+	}
+
+	if selectParam != "" {
+		for _, name := range strings.Split(selectParam, ",") {
+			add(strings.TrimSpace(name))
+		}
+		return columns
+	}
+
+	present := make(map[string]bool)
+	for _, item := range items {
+		if entity, ok := item.(map[string]interface{}); ok {
+			for name := range entity {
+				present[name] = true
+			}
+		}
+	}
+	if entityType := b.entityTypeOf(entitySetName); entityType != nil {
+		for _, prop := range entityType.Properties {
+			if present[prop.Name] {
+				add(prop.Name)
+			}
+		}
+	}
+	var rest []string
+	for name := range present {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		add(name)
+	}
+	return columns
+}
+
+// columnarResult is a list response whose entities are rows of values in the order of
+// columns, so that property names are not repeated for every entity
+type columnarResult struct {
+	*models.ODataResponse
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// columnarResponse converts the entities of a list response to rows; properties an
+// entity lacks are null
+func columnarResponse(response *models.ODataResponse, items []interface{}, columns []string) *columnarResult {
+	envelope := *response
+	envelope.Value = nil
+	rows := make([][]interface{}, len(items))
+	for i, item := range items {
+		entity, _ := item.(map[string]interface{})
+		row := make([]interface{}, len(columns))
+		for j, column := range columns {
+			row[j] = entity[column]
+		}
+		rows[i] = row
+	}
+	if columns == nil {
+		columns = []string{}
+	}
+	return &columnarResult{ODataResponse: &envelope, Columns: columns, Rows: rows}
+}
+
+// renderCSV writes entities as CSV with a header row. Counts and truncation warnings
+// are prepended as '#' comment lines so they are not lost. Amounts and quantities are
+// paired with their currency or unit as in renderMarkdown.
+func renderCSV(response *models.ODataResponse, items []interface{}, columns []string, units map[string]string) (string, error) {
+	var buf bytes.Buffer
+	for _, line := range tableNotes(response) {
+		fmt.Fprintf(&buf, "# %s\n", line)
+	}
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		entity, _ := item.(map[string]interface{})
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = unitCell(entity, column, units)
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// renderMarkdown writes entities as a Markdown table limited to maxColumns columns and
+// maxRows rows (0 = unlimited). Omitted columns and rows are reported above the table.
+// Amounts and quantities are shown with the currency or unit named in units ("9.50 EUR").
+func renderMarkdown(response *models.ODataResponse, items []interface{}, columns []string, units map[string]string, maxColumns, maxRows int) string {
+	if len(columns) == 0 {
+		return "No entities found.\n"
+	}
+
+	notes := tableNotes(response)
+	if maxColumns > 0 && len(columns) > maxColumns {
+		notes = append(notes, fmt.Sprintf("%d more columns omitted (%s), use $select to choose the columns", len(columns)-maxColumns, strings.Join(columns[maxColumns:], ", ")))
+		columns = columns[:maxColumns]
+	}
+	rows := items
+	if maxRows > 0 && len(items) > maxRows {
+		notes = append(notes, fmt.Sprintf("showing %d of %d entities, use $top and $skip to page", maxRows, len(items)))
+		rows = items[:maxRows]
+	}
+
+	var buf strings.Builder
+	for _, note := range notes {
+		fmt.Fprintf(&buf, "_%s_\n\n", note)
+	}
+
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = markdownCell(column)
+	}
+	fmt.Fprintf(&buf, "| %s |\n", strings.Join(cells, " | "))
+	buf.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for _, item := range rows {
+		entity, _ := item.(map[string]interface{})
+		for i, column := range columns {
+			cells[i] = markdownCell(unitCell(entity, column, units))
+		}
+		fmt.Fprintf(&buf, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return buf.String()
+}
+
+// unitCell returns the table cell of a property, followed by the currency or unit that
+// units names for it ("9.50 EUR")
+func unitCell(entity map[string]interface{}, column string, units map[string]string) string {
+	cell := tableCell(entity[column])
+	if unit := tableCell(entity[units[column]]); cell != "" && unit != "" {
+		cell += " " + unit
+	}
+	return cell
+}
+
+// markdownCell escapes pipes and line breaks, which would end a Markdown table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	text = strings.ReplaceAll(text, "\r\n", "<br>")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// tableNotes returns the response details a table cannot carry
+func tableNotes(response *models.ODataResponse) []string {
+	var notes []string
+	if response.Count != nil {
+		notes = append(notes, fmt.Sprintf("total_count: %d", *response.Count))
+	}
+	if warning, ok := response.Metadata["warning"].(string); ok && warning != "" {
+		notes = append(notes, "warning: "+warning)
+	}
+	if response.NextLink != "" {
+		notes = append(notes, "more results available, use $skip to page")
+	}
+	for _, message := range response.Messages {
+		if message.Severity != "" {
+			notes = append(notes, message.Severity+": "+message.Message)
+		} else {
+			notes = append(notes, message.Message)
+		}
+	}
+	return notes
+}
+
+// tableCell renders a property value as a table cell; nested values are written as JSON
+func tableCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
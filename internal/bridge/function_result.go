@@ -0,0 +1,77 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// splitReturnType splits a function import return type such as Collection(NS.Product)
+// into whether it is a collection and its element type without namespace (Edm types
+// keep theirs)
+func splitReturnType(returnType string) (bool, string) {
+	isCollection := strings.HasPrefix(returnType, "Collection(") && strings.HasSuffix(returnType, ")")
+	if isCollection {
+		returnType = returnType[len("Collection(") : len(returnType)-1]
+	}
+	if !strings.HasPrefix(returnType, "Edm.") {
+		returnType = returnType[strings.LastIndex(returnType, ".")+1:]
+	}
+	return isCollection, returnType
+}
+
+// functionResultDescription documents the shape of a function import's result for its
+// tool description, e.g. "Returns an array of Product entities in value"
+func (b *ODataMCPBridge) functionResultDescription(function *models.FunctionImport) string {
+	if function.ReturnType == "" {
+		return ""
+	}
+	isCollection, elementType := splitReturnType(function.ReturnType)
+
+	element := elementType
+	switch {
+	case b.metadata.EntityTypes[elementType] != nil:
+		element = elementType + " entities"
+		if !isCollection {
+			element = "a " + elementType + " entity"
+		}
+	case b.metadata.ComplexTypes[elementType] != nil:
+		element = elementType + " objects"
+		if !isCollection {
+			element = "a " + elementType + " object"
+		}
+	case isCollection:
+		element = elementType + " values"
+	}
+
+	if isCollection {
+		return fmt.Sprintf("Returns an array of %s in value", element)
+	}
+	return fmt.Sprintf("Returns %s in value", element)
+}
+
+// unwrapFunctionResult removes the wrappers OData v2 puts around function import results:
+// {"FunctionName": ...} around primitive and complex values and {"results": [...]} around
+// collections, so that value holds the declared return type
+func unwrapFunctionResult(functionName string, function *models.FunctionImport, value interface{}) interface{} {
+	if m, ok := value.(map[string]interface{}); ok && len(m) == 1 {
+		if inner, ok := m[functionName]; ok {
+			value = inner
+		}
+	}
+
+	isCollection, _ := splitReturnType(function.ReturnType)
+	if !isCollection {
+		return value
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		if results, ok := m["results"]; ok {
+			value = results
+		}
+	}
+	if value == nil {
+		return []interface{}{}
+	}
+	return value
+}
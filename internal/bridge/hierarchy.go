@@ -0,0 +1,242 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/odata-mcp/go/internal/utils"
+)
+
+// subtreeBatchSize is the number of parent nodes whose children a subtree query reads
+// with one request, keeping the $filter within URL length limits
+const subtreeBatchSize = 20
+
+// addHierarchyParams adds the tree navigation parameters of an SAP hierarchy entity type
+// to the input schema of its filter tool and returns the sentence describing them
+func (b *ODataMCPBridge) addHierarchyParams(properties map[string]interface{}, entityType *models.EntityType) string {
+	hierarchy := entityType.Hierarchy
+	nodeType := b.getJSONSchemaType(propertyType(entityType, hierarchy.NodeProperty))
+
+	properties["node"] = map[string]interface{}{
+		"type":        nodeType,
+		"description": fmt.Sprintf("Return the hierarchy node with this %s", hierarchy.NodeProperty),
+	}
+	properties["parent"] = map[string]interface{}{
+		"type":        nodeType,
+		"description": fmt.Sprintf("Return the child nodes of the node with this %s", hierarchy.NodeProperty),
+	}
+	if hierarchy.LevelProperty != "" {
+		properties["level"] = map[string]interface{}{
+			"type":        b.getJSONSchemaType(propertyType(entityType, hierarchy.LevelProperty)),
+			"description": fmt.Sprintf("Return the nodes at this hierarchy level (%s, 0 for root nodes)", hierarchy.LevelProperty),
+		}
+	}
+	properties["subtree"] = map[string]interface{}{
+		"type":        nodeType,
+		"description": fmt.Sprintf("Return the node with this %s and all its descendants, parents before children; cannot be combined with $filter, $skip, node, parent or level", hierarchy.NodeProperty),
+	}
+	return fmt.Sprintf(". The entities form a hierarchy (node %s, parent %s): use node, parent, level or subtree to navigate the tree", hierarchy.NodeProperty, hierarchy.ParentProperty)
+}
+
+// hierarchyEntityNames returns the entity sets among names whose entity type is an SAP hierarchy
+func (b *ODataMCPBridge) hierarchyEntityNames(names []string) []string {
+	var hierarchies []string
+	for _, name := range names {
+		if entityType := b.entityTypeOf(name); entityType != nil && entityType.Hierarchy != nil {
+			hierarchies = append(hierarchies, name)
+		}
+	}
+	return hierarchies
+}
+
+// addCompactHierarchyParams adds the tree navigation parameters to the input schema of
+// the compact query tool (--compact-tools) and returns the sentence naming the hierarchy
+// entity sets they apply to. Node IDs are strings, which are converted to the type of
+// the node property of the entity set.
+func (b *ODataMCPBridge) addCompactHierarchyParams(properties map[string]interface{}, hierarchies []string) string {
+	properties["node"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Hierarchy entity sets only: return the node with this ID",
+	}
+	properties["parent"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Hierarchy entity sets only: return the child nodes of the node with this ID",
+	}
+	properties["level"] = map[string]interface{}{
+		"type":        "integer",
+		"description": "Hierarchy entity sets only: return the nodes at this hierarchy level (0 for root nodes)",
+	}
+	properties["subtree"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Hierarchy entity sets only: return the node with this ID and all its descendants, parents before children; cannot be combined with $filter, $skip, node, parent or level",
+	}
+	return fmt.Sprintf(". The entities of %s form hierarchies: use node, parent, level or subtree to navigate the tree", strings.Join(hierarchies, ", "))
+}
+
+// handleHierarchyFilter runs the filter tool of an SAP hierarchy entity set. The node,
+// parent and level parameters become conditions of the $filter; subtree reads a branch
+// of the tree.
+func (b *ODataMCPBridge) handleHierarchyFilter(ctx context.Context, entitySetName string, entityType *models.EntityType, args map[string]interface{}) (interface{}, error) {
+	if _, ok := args["subtree"]; ok {
+		return b.handleHierarchySubtree(ctx, entitySetName, entityType, args)
+	}
+
+	hierarchy := entityType.Hierarchy
+	queryArgs := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		queryArgs[k] = v
+	}
+	var conditions []string
+	if filter, ok := args["$filter"].(string); ok && filter != "" {
+		conditions = append(conditions, "("+filter+")")
+	}
+	for _, param := range []struct{ name, property string }{
+		{"node", hierarchy.NodeProperty},
+		{"parent", hierarchy.ParentProperty},
+		{"level", hierarchy.LevelProperty},
+	} {
+		value, ok := args[param.name]
+		delete(queryArgs, param.name)
+		if !ok || param.property == "" {
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("%s eq %s", param.property, hierarchyLiteral(entityType, param.property, value)))
+	}
+	if len(conditions) > 0 {
+		queryArgs["$filter"] = strings.Join(conditions, " and ")
+	}
+	return b.handleEntityFilter(ctx, entitySetName, queryArgs)
+}
+
+// handleHierarchySubtree returns a node and its descendants. Services cannot filter by
+// ancestry, so the tree is read level by level: the children of the nodes of one level
+// are queried together, until a level has no children or $top nodes were read (without
+// $top, constants.DefaultSubtreeMaxNodes or --default-top). Leaf nodes, by their drill
+// state, are not queried for children.
+func (b *ODataMCPBridge) handleHierarchySubtree(ctx context.Context, entitySetName string, entityType *models.EntityType, args map[string]interface{}) (interface{}, error) {
+	for _, param := range []string{"$filter", "$skip", "node", "parent", "level"} {
+		if _, ok := args[param]; ok {
+			return nil, fmt.Errorf("%w: subtree cannot be combined with %s", mcp.ErrInvalidArguments, param)
+		}
+	}
+	hierarchy := entityType.Hierarchy
+
+	options := make(map[string]string)
+	if selectParam, ok := args["$select"].(string); ok && selectParam != "" {
+		// The node IDs are needed to read the next level
+		options[constants.QuerySelect] = selectParam
+		if !utils.ContainsName(strings.Split(selectParam, ","), hierarchy.NodeProperty) {
+			options[constants.QuerySelect] += "," + hierarchy.NodeProperty
+		}
+	}
+	if expand, ok := args["$expand"].(string); ok && expand != "" {
+		options[constants.QueryExpand] = expand
+	}
+	if orderby, ok := args["$orderby"].(string); ok && orderby != "" {
+		options[constants.QueryOrderBy] = orderby
+	}
+	if top, ok := args["$top"].(float64); ok {
+		options[constants.QueryTop] = strconv.Itoa(int(top))
+	}
+	b.applyDefaultTop(options, false)
+	limit := constants.DefaultSubtreeMaxNodes
+	if top, err := strconv.Atoi(options[constants.QueryTop]); err == nil && top > 0 {
+		limit = top
+	}
+	delete(options, constants.QueryTop)
+
+	var nodes []interface{}
+	truncated := false
+	seen := make(map[string]bool)
+	level := []string{hierarchyLiteral(entityType, hierarchy.NodeProperty, args["subtree"])}
+	property := hierarchy.NodeProperty
+	for len(level) > 0 && !truncated {
+		var next []string
+		for start := 0; start < len(level) && !truncated; start += subtreeBatchSize {
+			batch := level[start:min(start+subtreeBatchSize, len(level))]
+			conditions := make([]string, len(batch))
+			for i, literal := range batch {
+				conditions[i] = fmt.Sprintf("%s eq %s", property, literal)
+			}
+			queryOptions := make(map[string]string, len(options)+2)
+			for k, v := range options {
+				queryOptions[k] = v
+			}
+			queryOptions[constants.QueryFilter] = strings.Join(conditions, " or ")
+			// One node more than fits shows that the subtree was cut
+			queryOptions[constants.QueryTop] = strconv.Itoa(limit - len(nodes) + 1)
+
+			response, err := b.client.GetEntitySet(ctx, entitySetName, queryOptions)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the subtree: %w", err)
+			}
+			items, _ := response.Value.([]interface{})
+			for _, item := range items {
+				if len(nodes) == limit {
+					truncated = true
+					break
+				}
+				nodes = append(nodes, item)
+				entity, _ := item.(map[string]interface{})
+				id, ok := entity[hierarchy.NodeProperty]
+				if !ok || (hierarchy.DrillStateProperty != "" && entity[hierarchy.DrillStateProperty] == "leaf") {
+					continue
+				}
+				if literal := hierarchyLiteral(entityType, hierarchy.NodeProperty, id); !seen[literal] {
+					seen[literal] = true
+					next = append(next, literal)
+				}
+			}
+		}
+		level = next
+		property = hierarchy.ParentProperty
+		mcp.ReportProgress(ctx, float64(len(nodes)), float64(limit), fmt.Sprintf("Read %d nodes", len(nodes)))
+	}
+
+	response := b.enhanceResponse(&models.ODataResponse{Value: nodes}, options)
+	if truncated {
+		response.Truncated = true
+		if response.Metadata == nil {
+			response.Metadata = make(map[string]interface{})
+		}
+		response.Metadata["truncated"] = true
+		response.Metadata["warning"] = fmt.Sprintf("Only the first %d nodes of the subtree were returned. Pass a larger $top or read a lower node's subtree.", limit)
+	}
+	return b.formatListResult(entitySetName, response, args, options)
+}
+
+// hierarchyLiteral formats a node ID or level as a literal of its property type
+func hierarchyLiteral(entityType *models.EntityType, property string, value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		if propertyType(entityType, property) == "Edm.String" {
+			return utils.QuoteODataString(strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		switch propertyType(entityType, property) {
+		case "Edm.Int16", "Edm.Int32", "Edm.Int64", "Edm.Byte", "Edm.SByte":
+			if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return v
+			}
+		}
+		return utils.QuoteODataString(v)
+	default:
+		return utils.QuoteODataString(fmt.Sprint(v))
+	}
+}
+
+// propertyType returns the type of a property of an entity type, or "" if it has none
+func propertyType(entityType *models.EntityType, name string) string {
+	for _, prop := range entityType.Properties {
+		if prop.Name == name {
+			return prop.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// topLimit is a $top the bridge set on a query: the default of a query without $top, or
+// the --hard-top cap of a larger $top
+type topLimit struct {
+	top       int
+	capped    bool // The agent's $top exceeded --hard-top
+	probe     bool // $top asks for one more entity, which tells whether the query was cut off
+	truncated bool // The service had more entities than top (see trim)
+}
+
+// applyDefaultTop limits queries without $top to --default-top (--max-items if unset)
+// and lowers a $top above --hard-top to it. It returns the $top it set, or nil if the
+// query keeps the agent's $top or stays unlimited. With probe, one more entity is
+// requested, which trim removes again after recording that there were more.
+func (b *ODataMCPBridge) applyDefaultTop(options map[string]string, probe bool) *topLimit {
+	hardTop := b.config.HardTop
+	if top, exists := options[constants.QueryTop]; exists {
+		if requested, err := strconv.Atoi(top); err == nil && hardTop > 0 && requested > hardTop {
+			return setTopLimit(options, &topLimit{top: hardTop, capped: true, probe: probe})
+		}
+		return nil
+	}
+
+	limit := b.defaultTop()
+	if limit <= 0 {
+		return nil
+	}
+	return setTopLimit(options, &topLimit{top: limit, probe: probe})
+}
+
+// defaultTop returns the $top applyDefaultTop sets on queries without one: --default-top,
+// --max-items if unset, at most --hard-top; 0 if they stay unlimited
+func (b *ODataMCPBridge) defaultTop() int {
+	limit := b.config.DefaultTop
+	if limit <= 0 {
+		limit = b.config.MaxItems
+	}
+	if hardTop := b.config.HardTop; hardTop > 0 && (limit <= 0 || limit > hardTop) {
+		limit = hardTop
+	}
+	return max(limit, 0)
+}
+
+// setTopLimit sets the $top of a limit in the query options
+func setTopLimit(options map[string]string, limit *topLimit) *topLimit {
+	top := limit.top
+	if limit.probe {
+		top++
+	}
+	options[constants.QueryTop] = strconv.Itoa(top)
+	return limit
+}
+
+// trim removes the extra entity a probing limit requested and records whether there
+// was one. The options get back the $top of the limit, which pagination hints report.
+func (limit *topLimit) trim(response *models.ODataResponse, options map[string]string) {
+	if limit == nil || !limit.probe {
+		return
+	}
+	options[constants.QueryTop] = strconv.Itoa(limit.top)
+	if items, ok := response.Value.([]interface{}); ok && len(items) > limit.top {
+		response.Value = items[:limit.top]
+		limit.truncated = true
+	}
+}
+
+// applyInlineCount requests the total count of a query for --pagination-hints, which
+// need it for has_more; other queries skip it, as counting is expensive on large SAP
+// tables
+func (b *ODataMCPBridge) applyInlineCount(options map[string]string) {
+	if b.config.PaginationHints {
+		options[constants.QueryInlineCount] = "allpages"
+	}
+}
+
+// checkUnfilteredQuery rejects a query with neither $filter nor $top of an entity set
+// with more than --max-unfiltered-rows entities, which large backends often answer only
+// after a timeout. Queries that --default-top, --max-items or --hard-top limit are not
+// full table reads and pass. The size is found with a count query, reused for
+// --count-cache-ttl; if it cannot be counted, the query is sent.
+func (b *ODataMCPBridge) checkUnfilteredQuery(ctx context.Context, entitySetName string, options map[string]string) error {
+	limit := b.config.MaxUnfilteredRows
+	if limit <= 0 || options[constants.QueryFilter] != "" || b.defaultTop() > 0 {
+		return nil
+	}
+	if _, exists := options[constants.QueryTop]; exists {
+		return nil
+	}
+
+	count, err := b.entityCount(ctx, entitySetName, "")
+	if err != nil {
+		b.logger.Debug("Could not count entities of an unfiltered query", "entity_set", entitySetName, "error", err)
+		return nil
+	}
+	if count > int64(limit) {
+		return fmt.Errorf("refusing to query all of %s: it has %d entities, more than --max-unfiltered-rows %d. Add a $filter to narrow the query (the count tool tells how many entities a filter matches) or a $top to take only the first entities", entitySetName, count, limit)
+	}
+	return nil
+}
+
+// warnInactiveUnfilteredGuard warns that --max-unfiltered-rows rejects no query while
+// --default-top, --max-items (100 unless set) or --hard-top limits every query without $top
+func (b *ODataMCPBridge) warnInactiveUnfilteredGuard() {
+	if b.config.MaxUnfilteredRows > 0 && b.defaultTop() > 0 {
+		b.logger.Warn("--max-unfiltered-rows has no effect: queries without $top are limited by --default-top, --max-items or --hard-top; set them to 0 to use it", "default_top", b.defaultTop())
+	}
+}
+
+// markDefaultTopTruncation flags a response that was cut off by a $top the bridge set
+func (b *ODataMCPBridge) markDefaultTopTruncation(response *models.ODataResponse, limit *topLimit) {
+	if !limit.truncated {
+		return
+	}
+
+	response.Truncated = true
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]interface{})
+	}
+	response.Metadata["truncated"] = true
+	response.Metadata["max_items"] = limit.top
+	if limit.capped {
+		response.Metadata["warning"] = fmt.Sprintf("Only the first %d entities were returned because $top is capped at %d. %s", limit.top, limit.top, truncationGuidance)
+	} else {
+		response.Metadata["warning"] = fmt.Sprintf("Only the first %d entities were returned because no $top was given. %s", limit.top, truncationGuidance)
+	}
+}
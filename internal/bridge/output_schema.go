@@ -0,0 +1,130 @@
+package bridge
+
+import (
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// toolOutputSchema returns the JSON schema of a tool's structured result, or
+// nil if the result has no predictable shape
+func (b *ODataMCPBridge) toolOutputSchema(info *models.ToolInfo) map[string]interface{} {
+	if info.Function != "" {
+		function, exists := b.metadata.FunctionImports[info.Function]
+		if !exists || function.ReturnType == "" {
+			return nil
+		}
+		isCollection, elementType := splitReturnType(function.ReturnType)
+
+		var element map[string]interface{}
+		if entityType, exists := b.metadata.EntityTypes[elementType]; exists {
+			element = entityOutputSchema(entityType)
+		} else if complexType, exists := b.metadata.ComplexTypes[elementType]; exists {
+			element = propertiesOutputSchema(complexType.Properties)
+		} else if strings.HasPrefix(elementType, "Edm.") {
+			element = map[string]interface{}{"description": elementType}
+			if jsonType := outputJSONType(elementType); jsonType != "" {
+				element["type"] = jsonType
+			}
+		} else {
+			// Unknown return types are passed through as-is
+			element = map[string]interface{}{}
+		}
+		if isCollection {
+			return responseOutputSchema(map[string]interface{}{"type": "array", "items": element})
+		}
+		return responseOutputSchema(element)
+	}
+
+	var entity map[string]interface{}
+	if entityType := b.entityTypeOf(info.EntitySet); entityType != nil {
+		entity = entityOutputSchema(entityType)
+	} else {
+		// Compact tools serve any entity set
+		entity = map[string]interface{}{"type": "object"}
+	}
+
+	switch info.Operation {
+	case constants.OpFilter, constants.OpSearch:
+		return responseOutputSchema(map[string]interface{}{"type": "array", "items": entity})
+	case constants.OpGet, constants.OpCreate, constants.OpUpdate:
+		return responseOutputSchema(entity)
+	case constants.OpCount:
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"count": map[string]interface{}{"type": "integer"},
+			},
+			"required": []string{"count"},
+		}
+	default:
+		return nil
+	}
+}
+
+// responseOutputSchema wraps a value schema in the shape of models.ODataResponse
+func responseOutputSchema(value map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"value":           value,
+			"@odata.context":  map[string]interface{}{"type": "string"},
+			"@odata.count":    map[string]interface{}{"type": "integer"},
+			"@odata.nextLink": map[string]interface{}{"type": "string"},
+			"@odata.metadata": map[string]interface{}{"type": "object"},
+			"pagination":      map[string]interface{}{"type": "object"},
+		},
+	}
+}
+
+// entityOutputSchema builds the schema of an entity as returned by the service.
+// Only types that are never reformatted get a JSON type; decimals, 64-bit
+// integers and dates may arrive as strings or numbers depending on flags and
+// service version, so they are described by their Edm type only.
+func entityOutputSchema(entityType *models.EntityType) map[string]interface{} {
+	return propertiesOutputSchema(entityType.Properties)
+}
+
+// propertiesOutputSchema builds the object schema of an entity or complex value
+func propertiesOutputSchema(props []*models.EntityProperty) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, prop := range props {
+		schema := map[string]interface{}{
+			"description": prop.Type,
+		}
+		jsonType := outputJSONType(prop.Type)
+		if prop.ComplexType != "" {
+			jsonType = "object"
+			if prop.IsCollection {
+				jsonType = "array"
+			}
+		}
+		if jsonType != "" {
+			if prop.Nullable {
+				schema["type"] = []string{jsonType, "null"}
+			} else {
+				schema["type"] = jsonType
+			}
+		}
+		properties[prop.Name] = schema
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+// outputJSONType returns the JSON type of the Edm types that are never reformatted
+func outputJSONType(edmType string) string {
+	switch edmType {
+	case "Edm.String", "Edm.Guid":
+		return "string"
+	case "Edm.Boolean":
+		return "boolean"
+	case "Edm.Byte", "Edm.SByte", "Edm.Int16", "Edm.Int32":
+		return "integer"
+	}
+	return ""
+}
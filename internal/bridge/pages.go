@@ -0,0 +1,143 @@
+package bridge
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// getEntitySetPages queries an entity set. With --page-size, a $top larger than one page
+// is split into pages of $skip/$top requests that --page-workers workers fetch
+// concurrently; the entities of the pages are merged in order. Without $orderby the pages
+// are ordered by the key, so that they neither overlap nor skip entities, and only the
+// first page asks for the inline count.
+func (b *ODataMCPBridge) getEntitySetPages(ctx context.Context, entitySetName string, options map[string]string) (*models.ODataResponse, error) {
+	pageSize := b.config.PageSize
+	top, err := strconv.Atoi(options[constants.QueryTop])
+	if pageSize <= 0 || err != nil || top <= pageSize {
+		return b.client.GetEntitySet(ctx, entitySetName, options)
+	}
+	skip, _ := strconv.Atoi(options[constants.QuerySkip])
+	orderby := options[constants.QueryOrderBy]
+	if orderby == "" {
+		orderby = b.keyOrderBy(entitySetName)
+	}
+
+	pages := (top + pageSize - 1) / pageSize
+	responses := make([]*models.ODataResponse, pages)
+
+	workers := b.config.PageWorkers
+	if workers <= 0 || workers > pages {
+		workers = pages
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var fetched sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		fetched.Add(1)
+		go func() {
+			defer fetched.Done()
+			for page := range next {
+				pageOptions := make(map[string]string, len(options))
+				for k, v := range options {
+					pageOptions[k] = v
+				}
+				pageOptions[constants.QuerySkip] = strconv.Itoa(skip + page*pageSize)
+				pageOptions[constants.QueryTop] = strconv.Itoa(min(pageSize, top-page*pageSize))
+				if orderby != "" {
+					pageOptions[constants.QueryOrderBy] = orderby
+				}
+				if page > 0 {
+					delete(pageOptions, constants.QueryInlineCount)
+				}
+
+				var response *models.ODataResponse
+				err := mcp.Recovered(entitySetName, func() (err error) {
+					response, err = b.client.GetEntitySet(ctx, entitySetName, pageOptions)
+					return err
+				})
+				if err != nil {
+					// The first failure cancels the other pages and is returned
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				responses[page] = response
+			}
+		}()
+	}
+	for page := 0; page < pages && ctx.Err() == nil; page++ {
+		select {
+		case next <- page:
+		case <-ctx.Done():
+		}
+	}
+	close(next)
+	fetched.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return mergePages(responses, pageSize, top), nil
+}
+
+// keyOrderBy returns an $orderby of the key properties of an entity set, which orders
+// its entities the same way in every request, or "" if the key is unknown
+func (b *ODataMCPBridge) keyOrderBy(entitySetName string) string {
+	entityType := b.entityTypeOf(entitySetName)
+	if entityType == nil {
+		return ""
+	}
+	return strings.Join(entityType.KeyProperties, ",")
+}
+
+// mergePages joins the entities of pages in order. It stops after the first page that
+// has fewer entities than requested, the end of the collection; if the service cut such
+// a page short (it returned a next link), its next link is kept, so the result never
+// skips entities.
+func mergePages(responses []*models.ODataResponse, pageSize, top int) *models.ODataResponse {
+	first := responses[0]
+	merged := &models.ODataResponse{
+		Context:  first.Context,
+		Count:    first.Count,
+		Metadata: first.Metadata,
+	}
+	var items []interface{}
+	for i, response := range responses {
+		pageItems, ok := response.Value.([]interface{})
+		if !ok {
+			return first
+		}
+		items = append(items, pageItems...)
+		merged.Messages = append(merged.Messages, response.Messages...)
+
+		requested := min(pageSize, top-i*pageSize)
+		if len(pageItems) < requested {
+			merged.NextLink = response.NextLink
+			break
+		}
+		if i == len(responses)-1 {
+			merged.NextLink = response.NextLink
+		}
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+	merged.Value = items
+	return merged
+}
@@ -0,0 +1,49 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odata-mcp/go/internal/mcp"
+)
+
+// progressHeartbeatInterval is how often progress is reported while waiting on a slow request
+const progressHeartbeatInterval = 5 * time.Second
+
+// withProgressHeartbeat runs fn and, if the client asked for progress, reports
+// the elapsed time every progressHeartbeatInterval until fn returns so that
+// slow requests do not look hung
+func withProgressHeartbeat[T any](ctx context.Context, operation string, fn func() (T, error)) (T, error) {
+	if !mcp.HasProgress(ctx) {
+		return fn()
+	}
+
+	start := time.Now()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	mcp.ReportProgress(ctx, 0, 0, fmt.Sprintf("%s started", operation))
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(progressHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				elapsed := time.Since(start).Seconds()
+				mcp.ReportProgress(ctx, elapsed, 0, fmt.Sprintf("%s still running (%.0fs elapsed)", operation, elapsed))
+			}
+		}
+	}()
+
+	result, err := fn()
+	close(done)
+	<-stopped
+
+	return result, err
+}
@@ -0,0 +1,27 @@
+package bridge
+
+import (
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// rawResponses reports whether a read tool returns the JSON body of the service as is
+// (--raw-responses). Pagination hints and CSV or Markdown output need the decoded
+// entities, so they turn it off.
+func (b *ODataMCPBridge) rawResponses(format string) bool {
+	return b.config.RawResponses && !b.config.PaginationHints && format == constants.OutputFormatJSON
+}
+
+// rawResult passes on the body of a raw query. A body within --max-response-size is
+// returned as the result; a larger one is decoded into a response, which the caller
+// trims like any other.
+func (b *ODataMCPBridge) rawResult(body []byte, err error) (string, *models.ODataResponse, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	if b.config.MaxResponseSize <= 0 || len(body) <= b.config.MaxResponseSize {
+		return string(body), nil, nil
+	}
+	response, err := b.client.DecodeResponse(body)
+	return "", response, err
+}
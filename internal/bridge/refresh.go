@@ -0,0 +1,159 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// generateRefreshMetadataTool creates the tool that reloads metadata after the service was redeployed
+func (b *ODataMCPBridge) generateRefreshMetadataTool() {
+	toolName := b.formatToolName("refresh_metadata", "")
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: "Re-fetch the OData service metadata and regenerate the tools if it changed (e.g. after the service was redeployed)",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		changed, err := b.RefreshMetadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// Report the refreshed metadata, not the snapshot this call started with
+		current := b.pin()
+		current.mu.RLock()
+		result := map[string]interface{}{
+			"changed":          changed,
+			"entity_sets":      len(current.metadata.EntitySets),
+			"function_imports": len(current.metadata.FunctionImports),
+			"tools":            len(current.tools),
+		}
+		current.mu.RUnlock()
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(data), nil
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: tool.Description,
+		Operation:   constants.OpInfo,
+	})
+}
+
+// RefreshMetadata re-fetches the service metadata and, if it changed, publishes it and
+// regenerates the tools and resource templates. The client is notified through
+// tools/list_changed. Calls under way finish with the metadata they started with.
+func (b *ODataMCPBridge) RefreshMetadata(ctx context.Context) (bool, error) {
+	b.refreshMu.Lock()
+	defer b.refreshMu.Unlock()
+	b = b.pin()
+
+	metadata, err := b.loadMetadata(ctx)
+	if err != nil {
+		return false, err
+	}
+	if sameMetadata(b.current.Load().metadata, metadata) {
+		return false, nil
+	}
+
+	b.publish(metadata)
+	tools, err := b.regenerateTools()
+	if err != nil {
+		return false, err
+	}
+
+	b.logger.Info("Metadata changed, regenerated tools", "tools", tools)
+	return true, nil
+}
+
+// regenerateTools replaces the tools and resource templates with those generated for the
+// current metadata snapshot and configuration, and returns the number of tools. Entity
+// sets and functions enabled on demand (--max-tools) stay enabled. Callers must hold
+// b.refreshMu.
+func (b *ODataMCPBridge) regenerateTools() (int, error) {
+	// Register the new tools before removing stale ones, so that tools which
+	// still exist stay callable throughout. Tools that were never listed stay deferred.
+	loaded := b.server.ToolsLoaded()
+	b.mu.Lock()
+	stale := make(map[string]bool, len(b.tools))
+	for name := range b.tools {
+		stale[name] = true
+	}
+	enabled := b.enabled
+	b.tools = make(map[string]*models.ToolInfo)
+	b.enabled = make(map[string]bool)
+	b.collisions = nil
+	b.toolSet = nil
+	b.lazyTools = false
+	b.mu.Unlock()
+
+	view := b.pin()
+	view.server.ClearResourceTemplates()
+	if err := view.setup(); err != nil {
+		return 0, err
+	}
+	if loaded {
+		if err := view.server.LoadTools(); err != nil {
+			return 0, err
+		}
+		view.restoreEnabled(enabled)
+	}
+
+	b.mu.RLock()
+	for name := range b.tools {
+		delete(stale, name)
+	}
+	tools := len(b.tools)
+	b.mu.RUnlock()
+	if loaded {
+		for name := range stale {
+			b.server.RemoveTool(name)
+		}
+	}
+	return tools, nil
+}
+
+// refreshMetadataPeriodically calls RefreshMetadata at --metadata-refresh-interval until the bridge stops
+func (b *ODataMCPBridge) refreshMetadataPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopChan:
+			return
+		case <-ticker.C:
+			if _, err := b.RefreshMetadata(context.Background()); err != nil {
+				b.server.Log("warning", "odata-bridge", fmt.Sprintf("metadata refresh failed: %v", err))
+			}
+		}
+	}
+}
+
+// sameMetadata reports whether two parsed metadata documents describe the same service
+func sameMetadata(a, b *models.ODataMetadata) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	x, y := *a, *b
+	x.ParsedAt, y.ParsedAt = time.Time{}, time.Time{}
+	dataX, errX := json.Marshal(&x)
+	dataY, errY := json.Marshal(&y)
+	return errX == nil && errY == nil && bytes.Equal(dataX, dataY)
+}
@@ -0,0 +1,112 @@
+package bridge
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+)
+
+// ApplyConfig switches the bridge to an updated configuration (--watch-config). Limits,
+// verbosity and output options take effect with the next tool call; calls under way
+// finish with the configuration they started with. When the tools of
+// the new configuration differ, they are regenerated and the client is notified with
+// tools/list_changed. Entity sets and functions enabled on demand (--max-tools) stay
+// enabled. HTTP sessions that already exist keep their configuration.
+func (b *ODataMCPBridge) ApplyConfig(cfg *config.Config) (bool, error) {
+	b.refreshMu.Lock()
+	defer b.refreshMu.Unlock()
+
+	// Tools that were never listed are generated with the new configuration when first needed
+	loaded := b.server.ToolsLoaded()
+	changed := false
+	if loaded {
+		var err error
+		if changed, err = b.toolsChangeWith(cfg); err != nil {
+			return false, err
+		}
+	}
+
+	b.settings.Store(cfg)
+	b.client.SetVerbose(cfg.Verbose)
+	b.server.SetVerbose(cfg.Verbose || cfg.VerboseErrors)
+	b.client.SetSlowQueryThreshold(cfg.SlowQueryThreshold)
+	b.client.SetSAPStatistics(cfg.SAPStatistics)
+	// A limit removed from the configuration lifts the previous one
+	b.client.SetMaxBodySize(int64(cfg.MaxResponseSize) * constants.ResponseBodySizeFactor)
+	b.pin().warnInactiveUnfilteredGuard()
+
+	if !loaded {
+		// Only the resource templates are regenerated now
+		_, err := b.regenerateTools()
+		return false, err
+	}
+	if !changed {
+		return false, nil
+	}
+	count, err := b.regenerateTools()
+	if err != nil {
+		return false, err
+	}
+
+	b.logger.Info("Configuration changed, regenerated tools", "tools", count)
+	return true, nil
+}
+
+// toolsChangeWith reports whether the tools of a configuration differ from the listed
+// ones. They are generated on a quiet scratch bridge with the entity sets and functions
+// enabled on this one.
+func (b *ODataMCPBridge) toolsChangeWith(cfg *config.Config) (bool, error) {
+	quiet := *cfg
+	quiet.Verbose = false
+	scratch := newBridge(&quiet)
+	scratch.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	current := b.current.Load()
+	scratch.client.ApplyMetadata(current.metadata)
+	scratch.current.Store(current)
+	if err := scratch.pin().setup(); err != nil {
+		return false, err
+	}
+	if err := scratch.server.LoadTools(); err != nil {
+		return false, err
+	}
+	b.mu.RLock()
+	enabled := make(map[string]bool, len(b.enabled))
+	for key := range b.enabled {
+		enabled[key] = true
+	}
+	b.mu.RUnlock()
+	scratch.pin().restoreEnabled(enabled)
+
+	tools, err := b.server.GetTools()
+	if err != nil {
+		return false, err
+	}
+	scratchTools, err := scratch.server.GetTools()
+	if err != nil {
+		return false, err
+	}
+	return !sameTools(tools, scratchTools), nil
+}
+
+// sameTools reports whether two tool lists have the same tools, regardless of order
+func sameTools(a, b []*mcp.Tool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	definitions := make(map[string]string, len(a))
+	for _, tool := range a {
+		data, _ := json.Marshal(tool)
+		definitions[tool.Name] = string(data)
+	}
+	for _, tool := range b {
+		data, _ := json.Marshal(tool)
+		if definitions[tool.Name] != string(data) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// entityResourceScheme is the URI scheme of entity resources (odata://{entitySet}/{key})
+const entityResourceScheme = "odata://"
+
+// generateResourceTemplates registers the entity resource template when at
+// least one included entity set supports reading single entities
+func (b *ODataMCPBridge) generateResourceTemplates() {
+	var entitySets []string
+	for _, name := range b.includedEntityNames() {
+		if b.isEntityOperationEnabled(name, constants.OpGet) {
+			entitySets = append(entitySets, name)
+		}
+	}
+	if len(entitySets) == 0 {
+		return
+	}
+
+	b.server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: entityResourceScheme + "{entitySet}/{key}",
+		Name:        "entity",
+		Description: fmt.Sprintf("Read a single entity by key. Use the key value directly for single keys "+
+			"(odata://Products/42) or Name=value pairs for composite keys (odata://OrderItems/OrderID='A1',ItemID=10). "+
+			"Entity sets: %s", strings.Join(entitySets, ", ")),
+		MimeType: "application/json",
+	})
+	b.server.SetResourceHandler(func(ctx context.Context, uri string) ([]mcp.ResourceContent, error) {
+		view, ctx := b.pinCall(ctx)
+		return view.handleResourceRead(ctx, uri)
+	})
+
+	b.logger.Debug("Registered entity resource template", "entity_sets", len(entitySets))
+}
+
+// handleResourceRead serves resources/read requests for entity resource URIs
+func (b *ODataMCPBridge) handleResourceRead(ctx context.Context, uri string) ([]mcp.ResourceContent, error) {
+	entitySetName, entityType, args, err := b.parseEntityResourceURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+	}
+
+	result, err := b.handleEntityGet(ctx, entitySetName, entityType, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContent{{
+		URI:      uri,
+		MimeType: "application/json",
+		Text:     result.(string),
+	}}, nil
+}
+
+// parseEntityResourceURI resolves an odata://{entitySet}/{key} URI to the
+// entity set, its type and key arguments suitable for handleEntityGet
+func (b *ODataMCPBridge) parseEntityResourceURI(uri string) (string, *models.EntityType, map[string]interface{}, error) {
+	if !strings.HasPrefix(uri, entityResourceScheme) {
+		return "", nil, nil, fmt.Errorf("unsupported resource URI: %s", uri)
+	}
+
+	path := strings.TrimPrefix(uri, entityResourceScheme)
+	slash := strings.Index(path, "/")
+	if slash <= 0 || slash == len(path)-1 {
+		return "", nil, nil, fmt.Errorf("resource URI must have the form %s{entitySet}/{key}: %s", entityResourceScheme, uri)
+	}
+
+	entitySetName := path[:slash]
+	rawKey, err := url.PathUnescape(path[slash+1:])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid key in resource URI: %w", err)
+	}
+
+	entitySet, exists := b.metadata.EntitySets[entitySetName]
+	if !exists || !b.shouldIncludeEntity(entitySetName) || !b.isEntityOperationEnabled(entitySetName, constants.OpGet) {
+		return "", nil, nil, fmt.Errorf("entity set not available: %s", entitySetName)
+	}
+	entityType := b.entityTypeOf(entitySetName)
+	if entityType == nil {
+		return "", nil, nil, fmt.Errorf("entity type not found for entity set: %s", entitySet.Name)
+	}
+
+	rawKey = strings.TrimSuffix(strings.TrimPrefix(rawKey, "("), ")")
+	args := make(map[string]interface{})
+
+	parts := splitKeyPredicate(rawKey)
+	if len(parts) == 1 && !strings.Contains(parts[0], "=") {
+		if len(entityType.KeyProperties) != 1 {
+			return "", nil, nil, fmt.Errorf("entity set %s has a composite key; use Name=value pairs", entitySetName)
+		}
+		keyProp := entityType.KeyProperties[0]
+		args[keyProp] = keyResourceValue(entityType, keyProp, parts[0])
+		return entitySetName, entityType, args, nil
+	}
+
+	for _, part := range parts {
+		eq := strings.Index(part, "=")
+		if eq <= 0 {
+			return "", nil, nil, fmt.Errorf("invalid key segment: %s", part)
+		}
+		name := strings.TrimSpace(part[:eq])
+		args[name] = keyResourceValue(entityType, name, strings.TrimSpace(part[eq+1:]))
+	}
+
+	return entitySetName, entityType, args, nil
+}
+
+// splitKeyPredicate splits a key predicate on commas outside of quoted literals
+func splitKeyPredicate(predicate string) []string {
+	var parts []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range predicate {
+		switch {
+		case r == '\'':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case r == ',' && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	return append(parts, current.String())
+}
+
+// keyResourceValue converts a key literal from a resource URI to a value
+// typed after the key property
+func keyResourceValue(entityType *models.EntityType, propertyName, literal string) interface{} {
+	if len(literal) >= 2 && strings.HasPrefix(literal, "'") && strings.HasSuffix(literal, "'") {
+		return strings.ReplaceAll(literal[1:len(literal)-1], "''", "'")
+	}
+
+	for _, prop := range entityType.Properties {
+		if prop.Name != propertyName {
+			continue
+		}
+		switch prop.Type {
+		case "Edm.Int16", "Edm.Int32", "Edm.Int64", "Edm.Byte", "Edm.SByte":
+			if n, err := strconv.ParseInt(literal, 10, 64); err == nil {
+				return n
+			}
+		case "Edm.Boolean":
+			if v, err := strconv.ParseBool(literal); err == nil {
+				return v
+			}
+		}
+	}
+
+	return literal
+}
@@ -0,0 +1,78 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/odata-mcp/go/internal/utils"
+)
+
+// applyAutoSelect sets a minimal $select on list queries without one (--auto-select)
+// and reports whether it did
+func (b *ODataMCPBridge) applyAutoSelect(entitySetName string, options map[string]string) bool {
+	if !b.config.AutoSelect || options[constants.QuerySelect] != "" {
+		return false
+	}
+	entityType := b.entityTypeOf(entitySetName)
+	if entityType == nil {
+		return false
+	}
+	fields := b.importantProperties(entityType)
+	if len(fields) == 0 {
+		return false
+	}
+
+	// OData v2 only returns expanded navigation properties that are selected as well
+	if expand := options[constants.QueryExpand]; expand != "" && !b.dialect.IsV4() {
+		for _, path := range strings.Split(expand, ",") {
+			if nav := strings.TrimSpace(strings.SplitN(path, "/", 2)[0]); nav != "" && !utils.ContainsName(fields, nav) {
+				fields = append(fields, nav)
+			}
+		}
+	}
+
+	options[constants.QuerySelect] = strings.Join(fields, ",")
+	return true
+}
+
+// importantProperties returns the properties selected by --auto-select in metadata order:
+// keys, the sap:text descriptions of keys, properties with sap:semantics and properties
+// whose name or sap:label matches --auto-select-fields
+func (b *ODataMCPBridge) importantProperties(entityType *models.EntityType) []string {
+	important := make(map[string]bool)
+	for _, key := range entityType.KeyProperties {
+		important[key] = true
+	}
+	for _, prop := range entityType.Properties {
+		if prop.IsKey && prop.Text != "" {
+			important[prop.Text] = true
+		}
+		if prop.Semantics != "" {
+			important[prop.Name] = true
+		}
+		for _, pattern := range b.config.ImportantFields {
+			if b.matchesPattern(prop.Name, pattern) || (prop.Label != "" && b.matchesPattern(prop.Label, pattern)) {
+				important[prop.Name] = true
+				break
+			}
+		}
+	}
+
+	var fields []string
+	for _, prop := range entityType.Properties {
+		if important[prop.Name] {
+			fields = append(fields, prop.Name)
+		}
+	}
+	return fields
+}
+
+// markAutoSelect tells the agent that a response only carries automatically selected properties
+func markAutoSelect(response *models.ODataResponse, options map[string]string) {
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]interface{})
+	}
+	response.Metadata["auto_select"] = fmt.Sprintf("Only %s were returned because no $select was given; pass $select to choose other properties", options[constants.QuerySelect])
+}
@@ -0,0 +1,78 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// semanticsDescriptions explains the sap:semantics values that constrain a property's format
+var semanticsDescriptions = map[string]string{
+	"email":           "email address",
+	"tel":             "phone number",
+	"url":             "URL",
+	"currency-code":   "ISO currency code, e.g. EUR",
+	"unit-of-measure": "unit of measure, e.g. KG",
+	"year":            "year as YYYY",
+	"yearmonth":       "year and month as YYYYMM",
+	"yearmonthday":    "date as YYYYMMDD",
+	"fiscalyear":      "fiscal year as YYYY",
+	"fiscalperiod":    "fiscal period as PPP",
+}
+
+// propertyDescription describes an entity property for tool schemas, adding what its
+// sap:semantics and sap:unit say about the expected value
+func propertyDescription(prop *models.EntityProperty) string {
+	var hints []string
+	if prop.Semantics != "" {
+		// Values such as "tel;type=cell,work" carry parameters after the semantics
+		semantics := strings.SplitN(prop.Semantics, ";", 2)[0]
+		if description, ok := semanticsDescriptions[semantics]; ok {
+			hints = append(hints, description)
+		} else {
+			hints = append(hints, semantics)
+		}
+	}
+	if prop.Unit != "" {
+		hints = append(hints, fmt.Sprintf("in the currency or unit given by %s", prop.Unit))
+	}
+
+	description := fmt.Sprintf("Property: %s", prop.Name)
+	if len(hints) > 0 {
+		description += " (" + strings.Join(hints, ", ") + ")"
+	}
+	return description
+}
+
+// unitProperties maps the amounts and quantities of an entity type to the properties
+// holding their currency or unit of measure
+func unitProperties(entityType *models.EntityType) map[string]string {
+	if entityType == nil {
+		return nil
+	}
+	units := make(map[string]string)
+	for _, prop := range entityType.Properties {
+		if prop.Unit != "" {
+			units[prop.Name] = prop.Unit
+		}
+	}
+	return units
+}
+
+// entitySetTitle names an entity set in tool descriptions by its sap:label or Common.Label,
+// falling back to the entity type's label, followed by the technical name:
+// "Sales Orders (SalesOrderSet)". Sets without a label keep their technical name.
+func (b *ODataMCPBridge) entitySetTitle(entitySetName string) string {
+	var label string
+	if entitySet, ok := b.metadata.EntitySets[entitySetName]; ok {
+		label = entitySet.Label
+	}
+	if entityType := b.entityTypeOf(entitySetName); label == "" && entityType != nil {
+		label = entityType.Label
+	}
+	if label == "" || label == entitySetName {
+		return entitySetName
+	}
+	return fmt.Sprintf("%s (%s)", label, entitySetName)
+}
@@ -0,0 +1,77 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/logging"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// Delays between attempts to load the metadata within --startup-timeout
+const (
+	startupRetryDelay    = 500 * time.Millisecond
+	maxStartupRetryDelay = 30 * time.Second
+)
+
+// loadStartupMetadata loads the metadata when the bridge starts. With --startup-timeout,
+// attempts that fail because the service is unreachable or answers with a server error
+// are retried until the timeout expires; other errors, such as rejected credentials,
+// are returned at once.
+func (b *ODataMCPBridge) loadStartupMetadata() (*models.ODataMetadata, error) {
+	if b.config.StartupTimeout <= 0 {
+		return b.loadMetadata(context.Background())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.config.StartupTimeout)
+	defer cancel()
+
+	logger := logging.Subsystem(logging.SubsystemMetadata)
+	delay := startupRetryDelay
+	for attempt := 1; ; attempt++ {
+		metadata, err := b.loadMetadata(ctx)
+		if err == nil || !isUnavailable(err) {
+			return metadata, err
+		}
+
+		logger.Warn("Service not available, retrying", "attempt", attempt, "retry_in", delay.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("service not available within --startup-timeout %s: %w", b.config.StartupTimeout, err)
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxStartupRetryDelay {
+			delay = maxStartupRetryDelay
+		}
+	}
+}
+
+// isUnavailable reports whether an error means the service could not be reached or
+// failed with a server error, as opposed to rejecting the request
+func isUnavailable(err error) bool {
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// CheckService verifies that the service is reachable and accepts the configured
+// credentials, for the readiness probe of the HTTP transport. Without configured
+// credentials, when clients pass their own Authorization header through, a rejected
+// request still shows that the service is reachable.
+func (b *ODataMCPBridge) CheckService(ctx context.Context) error {
+	err := b.client.Ping(ctx)
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && b.config.PassThroughAuth && !b.config.HasBasicAuth() && !b.config.HasCookieAuth() &&
+		(statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden) {
+		return nil
+	}
+	return err
+}
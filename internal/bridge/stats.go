@@ -0,0 +1,102 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/metrics"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// generateStatsTool creates the tool that reports uptime, tool call statistics and cache
+// hit rates of the bridge process
+func (b *ODataMCPBridge) generateStatsTool() {
+	toolName := b.formatToolName("bridge_stats", "")
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: "Get runtime statistics of the bridge: uptime, calls, errors and average latency per tool, OData requests by status and cache hit rates",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		data, err := json.Marshal(statsResult(metrics.Default.Stats()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to format response: %w", err)
+		}
+		return string(data), nil
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: tool.Description,
+		Operation:   constants.OpInfo,
+	})
+}
+
+// statsResult formats a metrics snapshot as the bridge_stats result
+func statsResult(stats metrics.Stats) map[string]interface{} {
+	tools := make([]map[string]interface{}, 0, len(stats.Tools))
+	for _, t := range stats.Tools {
+		tools = append(tools, map[string]interface{}{
+			"tool":               t.Tool,
+			"calls":              t.Calls,
+			"errors":             t.Errors,
+			"average_latency_ms": milliseconds(t.AverageLatency),
+			"response_bytes":     t.ResponseBytes,
+		})
+	}
+
+	var requests uint64
+	for _, count := range stats.BackendRequests {
+		requests += count
+	}
+
+	caches := make([]map[string]interface{}, 0, len(stats.Caches))
+	for _, c := range stats.Caches {
+		caches = append(caches, map[string]interface{}{
+			"cache":    c.Cache,
+			"hits":     c.Hits,
+			"misses":   c.Misses,
+			"hit_rate": c.HitRate(),
+		})
+	}
+
+	backend := map[string]interface{}{
+		"requests":           requests,
+		"by_status":          stats.BackendRequests,
+		"average_latency_ms": milliseconds(stats.BackendAverageLatency),
+	}
+	if stats.SAPRequests > 0 {
+		// Time spent in the gateway and backend, as reported by SAP (--sap-statistics)
+		averages := make(map[string]float64, len(stats.SAPAverages))
+		for name, ms := range stats.SAPAverages {
+			averages[name] = math.Round(ms*10) / 10
+		}
+		backend["sap_statistics"] = map[string]interface{}{
+			"requests":   stats.SAPRequests,
+			"average_ms": averages,
+		}
+	}
+
+	return map[string]interface{}{
+		"uptime":         stats.Uptime.Round(time.Second).String(),
+		"uptime_seconds": int64(stats.Uptime.Seconds()),
+		"tools":          tools,
+		"backend":        backend,
+		"caches":         caches,
+	}
+}
+
+// milliseconds returns a duration in milliseconds with one decimal
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Round(100*time.Microsecond)) / float64(time.Millisecond)
+}
@@ -0,0 +1,42 @@
+package bridge
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// logStartupSummary logs a one-line summary of what loading the service cost: the size
+// and load time of the metadata, what it defines and the resident memory of the process.
+// It is logged at the info level, so --verbose shows it. The tools are generated when
+// first listed, which the tools loader logs.
+func (b *ODataMCPBridge) logStartupSummary() {
+	stats := b.client.MetadataStats()
+
+	b.logger.Info("Startup summary",
+		"metadata_bytes", stats.Size,
+		"metadata_ms", stats.Duration.Milliseconds(),
+		"metadata_cached", stats.Cached,
+		"entity_sets", len(b.metadata.EntitySets),
+		"entity_types", len(b.metadata.EntityTypes),
+		"complex_types", len(b.metadata.ComplexTypes),
+		"functions", len(b.metadata.FunctionImports),
+		"rss_mb", residentMemory()/(1024*1024))
+}
+
+// residentMemory returns the resident set size of the process in bytes on Linux, and
+// the memory obtained from the OS by the Go runtime elsewhere
+func residentMemory() uint64 {
+	if data, err := os.ReadFile("/proc/self/statm"); err == nil {
+		fields := bytes.Fields(data)
+		if len(fields) > 1 {
+			if pages, err := strconv.ParseUint(string(fields[1]), 10, 64); err == nil {
+				return pages * uint64(os.Getpagesize())
+			}
+		}
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.Sys
+}
@@ -0,0 +1,119 @@
+package bridge
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// ValidateMetadata reports problems that keep parts of the service from getting tools:
+// metadata that could not be parsed, unresolved entity types, entity sets without keys,
+// unresolved navigation targets, constructs the parser skipped, tool name collisions and
+// filters that match nothing (--validate-metadata)
+func (b *ODataMCPBridge) ValidateMetadata() *models.MetadataReport {
+	// Name collisions are found while generating the tools
+	loadErr := b.server.LoadTools()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	report := &models.MetadataReport{
+		ServiceURL: b.config.ServiceURL,
+		MetadataSummary: models.MetadataSummary{
+			EntityTypes:     len(b.metadata.EntityTypes),
+			EntitySets:      len(b.metadata.EntitySets),
+			FunctionImports: len(b.metadata.FunctionImports),
+		},
+		TotalTools: len(b.tools),
+		Issues:     make([]models.MetadataIssue, 0),
+	}
+	add := func(severity, kind, target, format string, args ...interface{}) {
+		report.Issues = append(report.Issues, models.MetadataIssue{
+			Severity: severity,
+			Kind:     kind,
+			Target:   target,
+			Message:  fmt.Sprintf(format, args...),
+		})
+		switch severity {
+		case "error":
+			report.Errors++
+		case "warning":
+			report.Warnings++
+		}
+	}
+
+	if b.metadata.ParseError != "" {
+		add("error", "parse_error", "$metadata", "The metadata document could not be parsed, so no entity tools are generated: %s", b.metadata.ParseError)
+	}
+	if len(b.metadata.EntitySets) == 0 {
+		add("error", "no_entity_sets", "", "The metadata declares no entity sets; only the service tools are available")
+	}
+
+	entitySetNames := make([]string, 0, len(b.metadata.EntitySets))
+	for name := range b.metadata.EntitySets {
+		entitySetNames = append(entitySetNames, name)
+	}
+	sort.Strings(entitySetNames)
+
+	for _, name := range entitySetNames {
+		entitySet := b.metadata.EntitySets[name]
+		entityType, exists := b.metadata.EntityTypes[entitySet.EntityType]
+		if !exists {
+			add("error", "unresolved_entity_type", name, "Entity set %s references entity type %s, which is not declared; it gets no tools", name, entitySet.EntityType)
+			continue
+		}
+		if len(entityType.KeyProperties) == 0 {
+			add("warning", "missing_key", name, "Entity type %s of entity set %s declares no key; get, update and delete cannot address single entities", entityType.Name, name)
+		}
+		for _, key := range entityType.KeyProperties {
+			if !hasProperty(entityType, key) {
+				add("error", "unresolved_key", name, "Key property %s of entity type %s is not declared as a property", key, entityType.Name)
+			}
+		}
+	}
+
+	typeNames := make([]string, 0, len(b.metadata.EntityTypes))
+	for name := range b.metadata.EntityTypes {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+
+	for _, name := range typeNames {
+		for _, nav := range b.metadata.EntityTypes[name].NavigationProps {
+			if _, exists := b.metadata.EntityTypes[nav.TargetEntityType]; !exists {
+				add("warning", "unresolved_navigation", name+"."+nav.Name, "Navigation property %s.%s has no resolvable target entity type; $expand may fail", name, nav.Name)
+			}
+		}
+	}
+
+	for _, construct := range b.metadata.Unsupported {
+		add("info", "unsupported", "", "%s", construct)
+	}
+
+	for _, collision := range b.collisions {
+		add("warning", "name_collision", "", "Tool name collision, renamed %s", collision)
+	}
+	if loadErr != nil {
+		add("error", "tool_generation", "", "%v", loadErr)
+	}
+
+	if len(b.config.AllowedEntities) > 0 && len(b.metadata.EntitySets) > 0 && len(b.includedEntityNames()) == 0 {
+		add("error", "filter_matches_nothing", "--entities", "--entities %v matches none of the %d entity sets", b.config.AllowedEntities, len(b.metadata.EntitySets))
+	}
+	if len(b.config.AllowedFunctions) > 0 && len(b.metadata.FunctionImports) > 0 && len(b.includedFunctionNames()) == 0 {
+		add("error", "filter_matches_nothing", "--functions", "--functions %v matches none of the %d function imports", b.config.AllowedFunctions, len(b.metadata.FunctionImports))
+	}
+
+	return report
+}
+
+// hasProperty reports whether an entity type declares a property
+func hasProperty(entityType *models.EntityType, name string) bool {
+	for _, prop := range entityType.Properties {
+		if prop.Name == name {
+			return true
+		}
+	}
+	return false
+}
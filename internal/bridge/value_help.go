@@ -0,0 +1,122 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// valueHelpProperties returns the properties of the entity type of an entity set that
+// have a value help (sap:value-list with a Common.ValueList annotation) whose entity set
+// --entities includes. Without --value-help there are none.
+func (b *ODataMCPBridge) valueHelpProperties(entitySet *models.EntitySet) []*models.EntityProperty {
+	entityType, exists := b.metadata.EntityTypes[entitySet.EntityType]
+	if !exists || !b.config.ValueHelp {
+		return nil
+	}
+	var props []*models.EntityProperty
+	for _, prop := range entityType.Properties {
+		if prop.ValueHelp != nil && b.shouldIncludeEntity(prop.ValueHelp.CollectionPath) {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// generateValueHelpTools creates a value help (F4) tool per property of an entity set
+// with a value help. The tool lists the allowed values from the value help entity set,
+// so agents can look up valid codes (plants, currencies, ...) before creating documents.
+func (b *ODataMCPBridge) generateValueHelpTools(entitySetName string, entitySet *models.EntitySet) {
+	for _, prop := range b.valueHelpProperties(entitySet) {
+		b.generateValueHelpTool(entitySetName, prop)
+	}
+}
+
+// generateValueHelpTool creates the value help tool of one property
+func (b *ODataMCPBridge) generateValueHelpTool(entitySetName string, prop *models.EntityProperty) {
+	help := prop.ValueHelp
+	opName := constants.GetToolOperationName(constants.OpValueHelp, b.config.ToolShrink)
+	toolName := b.formatToolName(opName, entitySetName+"_"+prop.Name)
+
+	label := prop.Label
+	if label == "" {
+		label = help.Label
+	}
+	description := fmt.Sprintf("Look up the allowed values of %s.%s in the value help %s", entitySetName, prop.Name, help.CollectionPath)
+	if label != "" {
+		description = fmt.Sprintf("Look up the allowed values of %s.%s (%s) in the value help %s", entitySetName, prop.Name, label, help.CollectionPath)
+	}
+	description += fmt.Sprintf(". The value to use is %s; look it up before creating or updating %s entities.", help.ValueProperty, entitySetName)
+
+	properties := map[string]interface{}{
+		"$filter": map[string]interface{}{
+			"type":        "string",
+			"description": fmt.Sprintf("OData filter expression on the value help properties: %s", strings.Join(help.Properties, ", ")),
+		},
+		"$top": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum number of values to return",
+		},
+	}
+	if help.SearchSupported {
+		properties["search"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Free-text search for values, e.g. part of a name (replaces $filter)",
+		}
+	}
+
+	tool := &mcp.Tool{
+		Name:        toolName,
+		Description: description,
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		},
+	}
+	if collection := b.entityTypeOf(help.CollectionPath); collection != nil && !b.config.DryRun {
+		tool.OutputSchema = responseOutputSchema(map[string]interface{}{"type": "array", "items": entityOutputSchema(collection)})
+	}
+
+	handler := func(b *ODataMCPBridge, ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return b.handleValueHelp(ctx, help, args)
+	}
+
+	b.registerTool(tool, handler, &models.ToolInfo{
+		Name:        toolName,
+		Description: description,
+		EntitySet:   entitySetName,
+		Operation:   constants.OpValueHelp,
+	})
+}
+
+// handleValueHelp lists the values of a value help entity set: its value help properties,
+// ordered by the value, narrowed by a filter or a search term
+func (b *ODataMCPBridge) handleValueHelp(ctx context.Context, help *models.ValueHelp, args map[string]interface{}) (interface{}, error) {
+	// The tool may outlive a configuration that no longer includes the value help entity set
+	if !b.shouldIncludeEntity(help.CollectionPath) {
+		return nil, fmt.Errorf("invalid entity: entity set not found: %s", help.CollectionPath)
+	}
+
+	queryArgs := make(map[string]interface{})
+	if len(help.Properties) > 0 {
+		queryArgs["$select"] = strings.Join(help.Properties, ",")
+	}
+	if top, ok := args["$top"]; ok {
+		queryArgs["$top"] = top
+	}
+	if search, ok := args["search"].(string); ok && search != "" && help.SearchSupported {
+		queryArgs["search"] = search
+		return b.handleEntitySearch(ctx, help.CollectionPath, queryArgs)
+	}
+	if filter, ok := args["$filter"]; ok {
+		queryArgs["$filter"] = filter
+	}
+	if help.ValueProperty != "" {
+		queryArgs["$orderby"] = help.ValueProperty
+	}
+	return b.handleEntityFilter(ctx, help.CollectionPath, queryArgs)
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// ChangesetRequest is a modifying request of a $batch changeset
+type ChangesetRequest struct {
+	Method string                 // POST, PUT, MERGE, PATCH or DELETE
+	Path   string                 // Relative to the service root, e.g. "Products" or "Products(1)"
+	Key    map[string]interface{} // Key of the entity in the entity set Path; nil to use Path as is
+	Body   map[string]interface{} // JSON payload; nil for DELETE
+}
+
+// ExecuteChangeset sends requests as one changeset of a $batch request, which the service
+// applies all or nothing, with a single CSRF token fetch. It returns the responses in the
+// order of the requests; if the service rejects the changeset, the error applies to all
+// of its requests.
+func (c *ODataClient) ExecuteChangeset(ctx context.Context, requests []ChangesetRequest) ([]*models.ODataResponse, error) {
+	ctx, d := c.pinDialect(ctx)
+
+	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
+	if err := c.fetchCSRFToken(ctx); err != nil {
+		c.logf("warning", "Failed to fetch CSRF token, proceeding without it: %v", err)
+	}
+
+	resolved := make([]ChangesetRequest, len(requests))
+	for i, r := range requests {
+		if r.Key != nil {
+			r.Path = fmt.Sprintf("%s(%s)", r.Path, d.buildKeyPredicate(r.Path, r.Key))
+		}
+		resolved[i] = r
+	}
+
+	body, batchBoundary, err := buildChangeset(resolved)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.buildRequest(ctx, constants.POST, constants.BatchEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(constants.ContentType, "multipart/mixed; boundary="+batchBoundary)
+	req.ContentLength = int64(len(body))
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, c.parseError(resp)
+	}
+	responses, err := c.readBatchResponse(resp, d)
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) != len(requests) {
+		return nil, fmt.Errorf("$batch response has %d responses for %d requests", len(responses), len(requests))
+	}
+	return responses, nil
+}
+
+// buildChangeset writes the multipart body of a $batch request with one changeset and
+// returns it with its boundary
+func buildChangeset(requests []ChangesetRequest) ([]byte, string, error) {
+	id := NewRequestID()
+	batchBoundary, changesetBoundary := "batch_"+id, "changeset_"+id
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "--%s\r\nContent-Type: multipart/mixed; boundary=%s\r\n\r\n", batchBoundary, changesetBoundary)
+	for i, r := range requests {
+		fmt.Fprintf(&body, "--%s\r\nContent-Type: application/http\r\nContent-Transfer-Encoding: binary\r\nContent-ID: %d\r\n\r\n", changesetBoundary, i+1)
+		fmt.Fprintf(&body, "%s %s HTTP/1.1\r\n%s: %s\r\n", r.Method, r.Path, constants.Accept, constants.ContentTypeJSON)
+		if r.Body == nil {
+			body.WriteString("\r\n\r\n")
+			continue
+		}
+		data, err := json.Marshal(r.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal entity data: %w", err)
+		}
+		fmt.Fprintf(&body, "%s: %s\r\nContent-Length: %d\r\n\r\n", constants.ContentType, constants.ContentTypeJSON, len(data))
+		body.Write(data)
+		body.WriteString("\r\n")
+	}
+	fmt.Fprintf(&body, "--%s--\r\n--%s--\r\n", changesetBoundary, batchBoundary)
+	return body.Bytes(), batchBoundary, nil
+}
+
+// readBatchResponse parses the responses of a $batch response. A changeset the service
+// rejected is answered with a single error response instead of a changeset part, which is
+// returned as the error.
+func (c *ODataClient) readBatchResponse(resp *http.Response, d *Dialect) ([]*models.ODataResponse, error) {
+	reader, err := multipartReader(resp.Header.Get(constants.ContentType), resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []*models.ODataResponse
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				return responses, nil
+			}
+			return nil, fmt.Errorf("failed to read $batch response: %w", err)
+		}
+
+		contentType := part.Header.Get(constants.ContentType)
+		if !strings.HasPrefix(contentType, "multipart/mixed") {
+			response, err := c.readBatchPart(part, d)
+			if err != nil {
+				return nil, err
+			}
+			responses = append(responses, response)
+			continue
+		}
+
+		changeset, err := multipartReader(contentType, part)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			inner, err := changeset.NextPart()
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, fmt.Errorf("failed to read $batch changeset response: %w", err)
+			}
+			response, err := c.readBatchPart(inner, d)
+			if err != nil {
+				return nil, err
+			}
+			responses = append(responses, response)
+		}
+	}
+}
+
+// readBatchPart parses the HTTP response in a part of a $batch response
+func (c *ODataClient) readBatchPart(part *multipart.Part, d *Dialect) (*models.ODataResponse, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(part), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read $batch response part: %w", err)
+	}
+	defer resp.Body.Close()
+	return c.parseODataResponse(resp, d)
+}
+
+// multipartReader reads a multipart/mixed body with the boundary of its content type
+func multipartReader(contentType string, body io.Reader) (*multipart.Reader, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, fmt.Errorf("unexpected $batch response content type %q", contentType)
+	}
+	return multipart.NewReader(body, params["boundary"]), nil
+}
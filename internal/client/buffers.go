@@ -0,0 +1,31 @@
+package client
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which a buffer is not returned to the pool, so
+// that one unusually large response does not pin its memory for the life of the process
+const maxPooledBuffer = 1 << 20
+
+// bodyBuffers holds the buffers response bodies are read into. With many concurrent tool
+// calls (HTTP transport), reusing them saves allocating and growing a buffer per response.
+var bodyBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getBuffer takes an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf := bodyBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns a buffer to the pool; its contents must no longer be referenced
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bodyBuffers.Put(buf)
+}
@@ -4,19 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/logging"
 	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/odata-mcp/go/internal/metrics"
+	"github.com/odata-mcp/go/internal/tracing"
 	"github.com/odata-mcp/go/internal/models"
+	"github.com/odata-mcp/go/internal/redact"
+	"github.com/odata-mcp/go/internal/utils"
 )
 
+// logger logs the requests of the client
+var logger = logging.Subsystem(logging.SubsystemClient)
+
 // ODataClient handles HTTP communication with OData services
 type ODataClient struct {
 	baseURL        string
@@ -24,12 +37,26 @@ type ODataClient struct {
 	cookies        map[string]string
 	username       string
 	password       string
+	authorization  string         // Authorization header passed through from the MCP client
 	csrfToken      string
-	verbose        bool
+	mu             sync.Mutex     // Guards csrfToken and sessionCookies for concurrent tool calls
+	verbose        atomic.Bool    // Switched at runtime by configuration reloads
 	sessionCookies []*http.Cookie // Track session cookies from server
-	isV4           bool           // Whether the service is OData v4
+	dialect        atomic.Pointer[Dialect] // Dialect of the adopted metadata (nil = none yet)
+	formatParam    string         // $format injection mode (auto, always, never)
+	maxBodySize    atomic.Int64   // Maximum size of a response body in bytes (0 = unlimited)
+	slowQueryThreshold atomic.Int64 // Log queries taking at least this long (0 = off)
+	sapStatistics  atomic.Bool    // Request SAP Gateway performance statistics
+	recent         *requestLog    // Recent requests for debug_recent_requests (nil = off)
+	metadataCacheDir string       // Directory of the parsed metadata cache (empty = off)
+	maxMetadataSize  int64        // Maximum size of the $metadata document in bytes (0 = unlimited)
+	metadataStats    MetadataStats // Size and load time of the last metadata load, guarded by mu
+	logHook        LogHook        // Receives diagnostics, e.g. for MCP log notifications
 }
 
+// LogHook receives client diagnostics; level is an MCP log level such as "debug" or "warning"
+type LogHook func(level, message string)
+
 // NewODataClient creates a new OData client
 func NewODataClient(baseURL string, verbose bool) *ODataClient {
 	// Ensure base URL ends with /
@@ -37,13 +64,27 @@ func NewODataClient(baseURL string, verbose bool) *ODataClient {
 		baseURL += "/"
 	}
 
-	return &ODataClient{
+	c := &ODataClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: time.Duration(constants.DefaultTimeout) * time.Second,
 		},
-		verbose: verbose,
-		isV4:    false, // Will be determined when fetching metadata
+	}
+	c.verbose.Store(verbose)
+	return c
+}
+
+// SetLogHook sets a hook that receives client diagnostics in addition to the log
+func (c *ODataClient) SetLogHook(hook LogHook) {
+	c.logHook = hook
+}
+
+// logf logs a diagnostic at an MCP logging level and forwards it to the log hook
+func (c *ODataClient) logf(level, format string, args ...interface{}) {
+	message := redact.String(fmt.Sprintf(format, args...))
+	logger.Log(context.Background(), logging.FromMCP(level), message)
+	if c.logHook != nil {
+		c.logHook(level, message)
 	}
 }
 
@@ -53,6 +94,61 @@ func (c *ODataClient) SetBasicAuth(username, password string) {
 	c.password = password
 }
 
+// SetAuthorization forwards a raw Authorization header, replacing basic auth
+func (c *ODataClient) SetAuthorization(header string) {
+	c.authorization = header
+}
+
+// User returns the user name the service is called with: the basic auth user or the
+// user of a passed-through Basic Authorization header, or "" if unknown
+func (c *ODataClient) User() string {
+	if c.authorization != "" {
+		if req, err := http.NewRequest(http.MethodGet, "/", nil); err == nil {
+			req.Header.Set("Authorization", c.authorization)
+			if user, _, ok := req.BasicAuth(); ok {
+				return user
+			}
+		}
+		return ""
+	}
+	return c.username
+}
+
+// SetVerbose switches the inner error details of OData error messages on or off
+func (c *ODataClient) SetVerbose(verbose bool) {
+	c.verbose.Store(verbose)
+}
+
+// SetMaxBodySize limits the size of response bodies read from the service (0 = unlimited)
+func (c *ODataClient) SetMaxBodySize(size int64) {
+	c.maxBodySize.Store(size)
+}
+
+// SetFormatParam controls when $format=json is added to queries: "auto"
+// (SAP OData v2 only), "always" or "never". Other services rely on the Accept header.
+func (c *ODataClient) SetFormatParam(mode string) {
+	c.formatParam = mode
+}
+
+// useFormatParam reports whether $format=json should be added to queries of a dialect
+func (c *ODataClient) useFormatParam(d *Dialect) bool {
+	switch c.formatParam {
+	case constants.FormatParamAlways:
+		return true
+	case constants.FormatParamNever:
+		return false
+	default:
+		return !d.isV4 && d.isSAP
+	}
+}
+
+// pinDialect returns ctx with the dialect of its requests pinned, and that dialect, so
+// that all requests of one call are built and decoded alike
+func (c *ODataClient) pinDialect(ctx context.Context) (context.Context, *Dialect) {
+	d := c.dialectFor(ctx)
+	return WithDialect(ctx, d), d
+}
+
 // SetCookies configures cookie authentication
 func (c *ODataClient) SetCookies(cookies map[string]string) {
 	c.cookies = cookies
@@ -69,14 +165,16 @@ func (c *ODataClient) buildRequest(ctx context.Context, method, endpoint string,
 
 	// Set standard headers
 	req.Header.Set(constants.UserAgent, constants.DefaultUserAgent)
-	if c.isV4 {
+	if c.dialectFor(ctx).isV4 {
 		req.Header.Set(constants.Accept, constants.ContentTypeODataJSONV4)
 	} else {
 		req.Header.Set(constants.Accept, constants.ContentTypeJSON)
 	}
 
 	// Set authentication
-	if c.username != "" && c.password != "" {
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	} else if c.username != "" && c.password != "" {
 		req.SetBasicAuth(c.username, c.password)
 	}
 
@@ -88,22 +186,25 @@ func (c *ODataClient) buildRequest(ctx context.Context, method, endpoint string,
 		})
 	}
 	
+	c.mu.Lock()
+	sessionCookies := c.sessionCookies
+	csrfToken := c.csrfToken
+	c.mu.Unlock()
+
 	// Add session cookies received from server
-	for _, cookie := range c.sessionCookies {
+	for _, cookie := range sessionCookies {
 		req.AddCookie(cookie)
 	}
 
 	// Set CSRF token if available
-	if c.csrfToken != "" {
-		req.Header.Set(constants.CSRFTokenHeader, c.csrfToken)
-		if c.verbose {
-			// Show first 20 chars of token like Python does
-			tokenPreview := c.csrfToken
-			if len(tokenPreview) > 20 {
-				tokenPreview = tokenPreview[:20] + "..."
-			}
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Adding CSRF token to request: %s\n", tokenPreview)
+	if csrfToken != "" {
+		req.Header.Set(constants.CSRFTokenHeader, csrfToken)
+		// Show first 20 chars of token like Python does
+		tokenPreview := csrfToken
+		if len(tokenPreview) > 20 {
+			tokenPreview = tokenPreview[:20] + "..."
 		}
+		logger.Debug("Adding CSRF token to request", "token", tokenPreview)
 	}
 
 	return req, nil
@@ -127,8 +228,10 @@ func (c *ODataClient) doRequest(req *http.Request) (*http.Response, error) {
 
 // doRequestWithRetry executes an HTTP request with CSRF retry logic
 func (c *ODataClient) doRequestWithRetry(req *http.Request, bodyBytes []byte, isRetry bool) (*http.Response, error) {
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] %s %s\n", req.Method, req.URL.String())
+	c.logf("debug", "%s %s", req.Method, req.URL.String())
+
+	if isDryRun(req.Context()) {
+		return nil, newDryRunRequest(req, bodyBytes)
 	}
 
 	// Reset body if we have it (for retry scenarios)
@@ -137,7 +240,11 @@ func (c *ODataClient) doRequestWithRetry(req *http.Request, bodyBytes []byte, is
 		req.ContentLength = int64(len(bodyBytes))
 	}
 
-	resp, err := c.httpClient.Do(req)
+	retries := 0
+	if isRetry {
+		retries = 1
+	}
+	resp, err := c.send(req, retries)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
@@ -164,12 +271,10 @@ func (c *ODataClient) doRequestWithRetry(req *http.Request, bodyBytes []byte, is
 			strings.EqualFold(resp.Header.Get("x-csrf-token"), "required")
 		
 		if csrfFailed {
-			if c.verbose {
-				fmt.Fprintf(os.Stderr, "[VERBOSE] CSRF token validation failed, attempting to refetch...\n")
-			}
+			c.logf("warning", "CSRF token validation failed, attempting to refetch...")
 			
 			// Clear the invalid token
-			c.csrfToken = ""
+			c.setCSRFToken("")
 			
 			// Try to fetch new CSRF token
 			if err := c.fetchCSRFToken(req.Context()); err != nil {
@@ -178,10 +283,8 @@ func (c *ODataClient) doRequestWithRetry(req *http.Request, bodyBytes []byte, is
 			}
 
 			// Retry original request with new CSRF token
-			req.Header.Set(constants.CSRFTokenHeader, c.csrfToken)
-			if c.verbose {
-				fmt.Fprintf(os.Stderr, "[VERBOSE] Retrying request with new CSRF token...\n")
-			}
+			req.Header.Set(constants.CSRFTokenHeader, c.getCSRFToken())
+			c.logf("info", "Retrying request with new CSRF token...")
 			return c.doRequestWithRetry(req, bodyBytes, true)
 		}
 		
@@ -192,30 +295,96 @@ func (c *ODataClient) doRequestWithRetry(req *http.Request, bodyBytes []byte, is
 	return resp, nil
 }
 
+// send sends a request to the service in a client span of the tool call's trace, with
+// a traceparent header and the tool call's X-Request-ID, and logs and measures it. retries is the number
+// of times the request was sent before (CSRF token refetch).
+func (c *ODataClient) send(req *http.Request, retries int) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), "HTTP "+req.Method, tracing.KindClient)
+	if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+		req.Header.Set("traceparent", traceparent)
+	}
+	if id := RequestIDFrom(ctx); id != "" {
+		req.Header.Set(constants.RequestIDHeader, id)
+	}
+	if c.sapStatistics.Load() {
+		req.Header.Set(constants.SAPStatisticsHeader, "true")
+	}
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("url.full", req.URL.Redacted())
+	if retries > 0 {
+		span.SetAttribute("http.request.resend_count", retries)
+	}
+
+	started := time.Now()
+	resp, err := c.httpClient.Do(req)
+	logRequest(req, resp, err, started)
+	c.recordRequest(req, resp, err, started)
+	if err != nil {
+		span.SetError(err)
+	} else {
+		recordSAPStatistics(req, resp, time.Since(started))
+		span.SetAttribute("http.response.status_code", resp.StatusCode)
+		if resp.StatusCode >= 400 {
+			span.SetError(fmt.Errorf("HTTP %d", resp.StatusCode))
+		}
+	}
+	span.End()
+	return resp, err
+}
+
+// logRequest logs the method, URL, status and duration of a request sent to the service
+// and records them in the metrics
+func logRequest(req *http.Request, resp *http.Response, err error, started time.Time) {
+	duration := time.Since(started)
+	attrs := []interface{}{"method", req.Method, "url", req.URL.String()}
+	if id := RequestIDFrom(req.Context()); id != "" {
+		attrs = append(attrs, "request_id", id)
+	}
+	if err != nil {
+		metrics.Default.ObserveBackendRequest(0, duration)
+		logger.Info("OData request failed", append(attrs, "duration_ms", duration.Milliseconds(), "error", err)...)
+		return
+	}
+	metrics.Default.ObserveBackendRequest(resp.StatusCode, duration)
+	logger.Info("OData request", append(attrs, "status", resp.StatusCode, "duration_ms", duration.Milliseconds())...)
+}
+
 // fetchCSRFToken fetches a CSRF token from the service
 func (c *ODataClient) fetchCSRFToken(ctx context.Context) error {
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Fetching CSRF token...\n")
+	if isDryRun(ctx) {
+		return nil
+	}
+	req, err := c.csrfTokenRequest(ctx)
+	if err != nil {
+		return err
 	}
+	return c.sendCSRFTokenRequest(req)
+}
+
+// csrfTokenRequest clears the current CSRF token and builds the request fetching a new one
+func (c *ODataClient) csrfTokenRequest(ctx context.Context) (*http.Request, error) {
+	c.logf("debug", "Fetching CSRF token...")
 	
 	// Clear any existing CSRF token (Python behavior)
-	c.csrfToken = ""
+	c.setCSRFToken("")
 	
 	// Use service root for CSRF token fetching (more reliable than empty string)
 	req, err := c.buildRequest(ctx, constants.GET, "", nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set(constants.CSRFTokenHeader, constants.CSRFTokenFetch)
 	
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Token fetch request: %s %s\n", req.Method, req.URL.String())
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Token fetch headers: %v\n", req.Header)
-	}
+	logger.Debug("Token fetch request", "method", req.Method, "url", req.URL.String(), "headers", req.Header)
+	return req, nil
+}
 
+// sendCSRFTokenRequest sends a request built by csrfTokenRequest and stores the token
+// and the session cookies of the response
+func (c *ODataClient) sendCSRFTokenRequest(req *http.Request) error {
 	// Don't use doRequest here to avoid retry loops - fetch token requests shouldn't retry
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req, 0)
 	if err != nil {
 		return fmt.Errorf("CSRF token request failed: %w", err)
 	}
@@ -223,19 +392,16 @@ func (c *ODataClient) fetchCSRFToken(ctx context.Context) error {
 	
 	// Store any session cookies from the response
 	if cookies := resp.Cookies(); len(cookies) > 0 {
+		c.mu.Lock()
 		c.sessionCookies = append(c.sessionCookies, cookies...)
-		if c.verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Received %d session cookies during token fetch\n", len(cookies))
-			for _, cookie := range cookies {
-				fmt.Fprintf(os.Stderr, "[VERBOSE] Cookie: %s=%s (Path=%s)\n", cookie.Name, cookie.Value[:min(len(cookie.Value), 20)]+"...", cookie.Path)
-			}
+		c.mu.Unlock()
+		logger.Debug("Received session cookies during token fetch", "cookies", len(cookies))
+		for _, cookie := range cookies {
+			logger.Debug("Session cookie", "name", cookie.Name, "path", cookie.Path)
 		}
 	}
 	
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Token fetch response status: %d\n", resp.StatusCode)
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Token fetch response headers: %v\n", resp.Header)
-	}
+	logger.Debug("Token fetch response", "status", resp.StatusCode, "headers", resp.Header)
 
 	// Check both possible header names (case variations)
 	token := resp.Header.Get(constants.CSRFTokenHeader)
@@ -255,14 +421,26 @@ func (c *ODataClient) fetchCSRFToken(ctx context.Context) error {
 		return fmt.Errorf("CSRF token not found in response headers")
 	}
 
-	c.csrfToken = token
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] CSRF token fetched successfully: %s...\n", token[:min(len(token), 20)])
-	}
+	c.setCSRFToken(token)
+	logger.Debug("CSRF token fetched successfully", "token", token[:min(len(token), 20)]+"...")
 
 	return nil
 }
 
+// getCSRFToken returns the current CSRF token
+func (c *ODataClient) getCSRFToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.csrfToken
+}
+
+// setCSRFToken replaces the current CSRF token
+func (c *ODataClient) setCSRFToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.csrfToken = token
+}
+
 // Helper function for min
 func min(a, b int) int {
 	if a < b {
@@ -273,6 +451,14 @@ func min(a, b int) int {
 
 // GetMetadata fetches and parses the OData service metadata
 func (c *ODataClient) GetMetadata(ctx context.Context) (*models.ODataMetadata, error) {
+	return c.getMetadata(ctx, func() (*models.ODataMetadata, error) {
+		return c.getServiceDocument(ctx)
+	})
+}
+
+// getMetadata fetches and parses the service metadata; serviceDocument provides the
+// entity sets of the service document when the metadata cannot be parsed
+func (c *ODataClient) getMetadata(ctx context.Context, serviceDocument func() (*models.ODataMetadata, error)) (*models.ODataMetadata, error) {
 	req, err := c.buildRequest(ctx, constants.GET, constants.MetadataEndpoint, nil)
 	if err != nil {
 		return nil, err
@@ -280,53 +466,124 @@ func (c *ODataClient) GetMetadata(ctx context.Context) (*models.ODataMetadata, e
 
 	req.Header.Set(constants.Accept, constants.ContentTypeXML)
 
+	var cached *metadataCacheEntry
+	if c.metadataCacheDir != "" {
+		cached = c.loadMetadataCache()
+		setMetadataValidators(req, cached)
+	}
+
+	started := time.Now()
 	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		logger.Debug("Metadata not modified, using the cache", "file", c.metadataCachePath())
+		c.ApplyMetadata(cached.parsed)
+		c.setMetadataStats(MetadataStats{Duration: time.Since(started), Cached: true})
+		return cached.parsed, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, c.parseError(resp)
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read metadata response: %w", err)
+	if c.maxMetadataSize > 0 && resp.ContentLength > c.maxMetadataSize {
+		return nil, c.metadataTooLarge(fmt.Sprintf("%d bytes", resp.ContentLength))
 	}
 
-	// Parse metadata XML (to be implemented)
-	metadata, err := c.parseMetadataXML(body)
+	// Parse the metadata XML as it streams in rather than buffering the document
+	body := &metadataReader{client: c, r: resp.Body}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{body, resp.Body}
+	var metadata *models.ODataMetadata
+	reused := false
+	if c.metadataCacheDir != "" {
+		metadata, reused, err = c.readCachedMetadata(resp, cached)
+	} else {
+		metadata, err = c.parseMetadataXML(resp.Body)
+	}
+	if errors.Is(err, ErrMetadataTooLarge) {
+		// Neither parse nor fall back; the service is too large for the budget
+		return nil, err
+	}
 	if err != nil {
 		// Fallback to service document if metadata parsing fails
-		return c.getServiceDocument(ctx)
+		c.logf("warning", "Failed to parse metadata, falling back to the service document: %v", err)
+		metadata, docErr := serviceDocument()
+		if docErr != nil {
+			return nil, docErr
+		}
+		metadata.ParseError = err.Error()
+		return metadata, nil
 	}
 
+	c.setMetadataStats(MetadataStats{Size: body.n, Duration: time.Since(started), Cached: reused})
 	return metadata, nil
 }
 
+// LoadMetadataFile reads the service metadata from a file (--metadata-file) in place of
+// GetMetadata
+func (c *ODataClient) LoadMetadataFile(path string) (*models.ODataMetadata, error) {
+	started := time.Now()
+	meta, err := metadata.LoadFile(path, c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	c.ApplyMetadata(meta)
+	stats := MetadataStats{Duration: time.Since(started)}
+	if info, err := os.Stat(path); err == nil {
+		stats.Size = info.Size()
+	}
+	c.setMetadataStats(stats)
+	return meta, nil
+}
+
 // GetEntitySet retrieves entities from an entity set
 func (c *ODataClient) GetEntitySet(ctx context.Context, entitySet string, options map[string]string) (*models.ODataResponse, error) {
+	ctx, d := c.pinDialect(ctx)
+	req, err := c.buildRequest(ctx, constants.GET, c.entitySetEndpoint(d, entitySet, options), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response, err := c.parseODataResponse(resp, d)
+	if err == nil {
+		c.logSlowQuery(ctx, entitySet, options, response, started)
+	}
+	return response, err
+}
+
+// entitySetEndpoint returns the URL of a query of an entity set relative to the service root
+func (c *ODataClient) entitySetEndpoint(d *Dialect, entitySet string, options map[string]string) string {
 	endpoint := entitySet
 	
 	// Build query parameters with standard OData v2 parameters
 	params := url.Values{}
 	
-	// Always add JSON format for consistent responses (v2 only)
-	if !c.isV4 {
+	// Request JSON explicitly where the service expects it (SAP v2 by default)
+	if c.useFormatParam(d) {
 		params.Add(constants.QueryFormat, "json")
 	}
 	
-	// Add inline count for pagination support unless explicitly requesting count only
-	// OData v4 uses $count=true instead of $inlinecount
-	if !c.isV4 {
-		if _, hasInlineCount := options[constants.QueryInlineCount]; !hasInlineCount {
-			params.Add(constants.QueryInlineCount, "allpages")
-		}
-	}
-	
 	// Add user-provided parameters
 	for key, value := range options {
+		if key == constants.QueryFilter {
+			value = utils.NormalizeGUIDLiterals(value)
+		}
+		// Callers ask for an inline count with $inlinecount=allpages; OData v4 uses $count=true
+		if key == constants.QueryInlineCount && d.isV4 {
+			key, value = constants.QueryCount, "true"
+		}
 		if value != "" {
 			params.Set(key, value) // Use Set to override defaults if needed
 		}
@@ -335,25 +592,35 @@ func (c *ODataClient) GetEntitySet(ctx context.Context, entitySet string, option
 	if len(params) > 0 {
 		endpoint += "?" + params.Encode()
 	}
+	return endpoint
+}
 
-	req, err := c.buildRequest(ctx, constants.GET, endpoint, nil)
+// GetEntity retrieves a single entity by key
+func (c *ODataClient) GetEntity(ctx context.Context, entitySet string, key map[string]interface{}, options map[string]string) (*models.ODataResponse, error) {
+	ctx, d := c.pinDialect(ctx)
+	req, err := c.buildRequest(ctx, constants.GET, c.entityEndpoint(d, entitySet, key, options), nil)
 	if err != nil {
 		return nil, err
 	}
 
+	started := time.Now()
 	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	return c.parseODataResponse(resp)
+	response, err := c.parseODataResponse(resp, d)
+	if err == nil {
+		c.logSlowQuery(ctx, entitySet, options, response, started)
+	}
+	return response, err
 }
 
-// GetEntity retrieves a single entity by key
-func (c *ODataClient) GetEntity(ctx context.Context, entitySet string, key map[string]interface{}, options map[string]string) (*models.ODataResponse, error) {
+// entityEndpoint returns the URL of an entity relative to the service root
+func (c *ODataClient) entityEndpoint(d *Dialect, entitySet string, key map[string]interface{}, options map[string]string) string {
 	// Build key predicate
-	keyPredicate := c.buildKeyPredicate(key)
+	keyPredicate := d.buildKeyPredicate(entitySet, key)
 	endpoint := fmt.Sprintf("%s(%s)", entitySet, keyPredicate)
 
 	// Build query parameters
@@ -368,28 +635,16 @@ func (c *ODataClient) GetEntity(ctx context.Context, entitySet string, key map[s
 			endpoint += "?" + params.Encode()
 		}
 	}
-
-	req, err := c.buildRequest(ctx, constants.GET, endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	return c.parseODataResponse(resp)
+	return endpoint
 }
 
 // CreateEntity creates a new entity
 func (c *ODataClient) CreateEntity(ctx context.Context, entitySet string, data map[string]interface{}) (*models.ODataResponse, error) {
+	ctx, d := c.pinDialect(ctx)
+
 	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
 	if err := c.fetchCSRFToken(ctx); err != nil {
-		if c.verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
-		}
+		c.logf("warning", "Failed to fetch CSRF token, proceeding without it: %v", err)
 		// Continue without token - some services might not require it
 	}
 
@@ -398,9 +653,7 @@ func (c *ODataClient) CreateEntity(ctx context.Context, entitySet string, data m
 		return nil, fmt.Errorf("failed to marshal entity data: %w", err)
 	}
 
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Creating entity with data: %s\n", string(jsonData))
-	}
+	logger.Debug("Creating entity", "entity_set", entitySet, "data", string(jsonData))
 
 	req, err := c.buildRequest(ctx, constants.POST, entitySet, bytes.NewReader(jsonData))
 	if err != nil {
@@ -417,20 +670,20 @@ func (c *ODataClient) CreateEntity(ctx context.Context, entitySet string, data m
 	}
 	defer resp.Body.Close()
 
-	return c.parseODataResponse(resp)
+	return c.parseODataResponse(resp, d)
 }
 
 // UpdateEntity updates an existing entity
 func (c *ODataClient) UpdateEntity(ctx context.Context, entitySet string, key map[string]interface{}, data map[string]interface{}, method string) (*models.ODataResponse, error) {
+	ctx, d := c.pinDialect(ctx)
+
 	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
 	if err := c.fetchCSRFToken(ctx); err != nil {
-		if c.verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
-		}
+		c.logf("warning", "Failed to fetch CSRF token, proceeding without it: %v", err)
 		// Continue without token - some services might not require it
 	}
 
-	keyPredicate := c.buildKeyPredicate(key)
+	keyPredicate := d.buildKeyPredicate(entitySet, key)
 	endpoint := fmt.Sprintf("%s(%s)", entitySet, keyPredicate)
 
 	jsonData, err := json.Marshal(data)
@@ -442,9 +695,7 @@ func (c *ODataClient) UpdateEntity(ctx context.Context, entitySet string, key ma
 		method = constants.PUT
 	}
 
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Updating entity with data: %s\n", string(jsonData))
-	}
+	logger.Debug("Updating entity", "data", string(jsonData))
 
 	req, err := c.buildRequest(ctx, method, endpoint, bytes.NewReader(jsonData))
 	if err != nil {
@@ -461,20 +712,20 @@ func (c *ODataClient) UpdateEntity(ctx context.Context, entitySet string, key ma
 	}
 	defer resp.Body.Close()
 
-	return c.parseODataResponse(resp)
+	return c.parseODataResponse(resp, d)
 }
 
 // DeleteEntity deletes an entity
 func (c *ODataClient) DeleteEntity(ctx context.Context, entitySet string, key map[string]interface{}) (*models.ODataResponse, error) {
+	ctx, d := c.pinDialect(ctx)
+
 	// Always fetch a fresh CSRF token for modifying operations (Python behavior)
 	if err := c.fetchCSRFToken(ctx); err != nil {
-		if c.verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
-		}
+		c.logf("warning", "Failed to fetch CSRF token, proceeding without it: %v", err)
 		// Continue without token - some services might not require it
 	}
 
-	keyPredicate := c.buildKeyPredicate(key)
+	keyPredicate := d.buildKeyPredicate(entitySet, key)
 	endpoint := fmt.Sprintf("%s(%s)", entitySet, keyPredicate)
 
 	req, err := c.buildRequest(ctx, constants.DELETE, endpoint, nil)
@@ -488,11 +739,12 @@ func (c *ODataClient) DeleteEntity(ctx context.Context, entitySet string, key ma
 	}
 	defer resp.Body.Close()
 
-	return c.parseODataResponse(resp)
+	return c.parseODataResponse(resp, d)
 }
 
 // CallFunction calls a function import
 func (c *ODataClient) CallFunction(ctx context.Context, functionName string, parameters map[string]interface{}, method string) (*models.ODataResponse, error) {
+	ctx, d := c.pinDialect(ctx)
 	endpoint := functionName
 
 	var req *http.Request
@@ -501,9 +753,10 @@ func (c *ODataClient) CallFunction(ctx context.Context, functionName string, par
 	if method == constants.GET {
 		// For GET requests, add parameters to URL with proper OData formatting
 		if len(parameters) > 0 {
+			types, names := d.functionParameters(functionName, parameters)
 			var paramStrings []string
-			for key, value := range parameters {
-				paramStrings = append(paramStrings, c.formatFunctionParameter(key, value))
+			for _, key := range names {
+				paramStrings = append(paramStrings, d.formatFunctionParameter(key, parameters[key], types[key]))
 			}
 			endpoint += "?" + strings.Join(paramStrings, "&")
 		}
@@ -511,9 +764,7 @@ func (c *ODataClient) CallFunction(ctx context.Context, functionName string, par
 	} else {
 		// Always fetch a fresh CSRF token for modifying operations (Python behavior)
 		if err := c.fetchCSRFToken(ctx); err != nil {
-			if c.verbose {
-				fmt.Fprintf(os.Stderr, "[VERBOSE] Failed to fetch CSRF token, proceeding without it: %v\n", err)
-			}
+			c.logf("warning", "Failed to fetch CSRF token, proceeding without it: %v", err)
 			// Continue without token - some services might not require it
 		}
 
@@ -523,9 +774,7 @@ func (c *ODataClient) CallFunction(ctx context.Context, functionName string, par
 			return nil, fmt.Errorf("failed to marshal function parameters: %w", marshalErr)
 		}
 
-		if c.verbose {
-			fmt.Fprintf(os.Stderr, "[VERBOSE] Calling function with data: %s\n", string(jsonData))
-		}
+		logger.Debug("Calling function", "data", string(jsonData))
 
 		req, err = c.buildRequest(ctx, constants.POST, endpoint, bytes.NewReader(jsonData))
 		if err == nil {
@@ -545,51 +794,69 @@ func (c *ODataClient) CallFunction(ctx context.Context, functionName string, par
 	}
 	defer resp.Body.Close()
 
-	return c.parseODataResponse(resp)
+	return c.parseODataResponse(resp, d)
 }
 
-// buildKeyPredicate builds OData key predicate from key-value pairs
-func (c *ODataClient) buildKeyPredicate(key map[string]interface{}) string {
+// buildKeyPredicate builds OData key predicate from key-value pairs. Values are
+// formatted as literals of their metadata type (guid'...', datetime'...', 42L, ...)
+// and composite keys follow the declaration order of the key properties.
+func (d *Dialect) buildKeyPredicate(entitySet string, key map[string]interface{}) string {
+	types := d.propertyTypes(entitySet)
+	format := func(name string, value interface{}) string {
+		if edmType := types[name]; edmType != "" {
+			return d.formatLiteral(value, edmType)
+		}
+		return formatKeyLiteral(value)
+	}
+
 	if len(key) == 1 {
 		// Single key
-		for _, value := range key {
-			return c.formatKeyValue(value)
+		for name, value := range key {
+			return utils.EscapeKeyPredicate(format(name, value))
 		}
 	}
 
-	// Composite key
+	// Composite key, in the order of the key definition
 	var parts []string
-	for k, v := range key {
-		parts = append(parts, fmt.Sprintf("%s=%s", k, c.formatKeyValue(v)))
+	for _, k := range d.keyOrder(entitySet, key) {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, format(k, key[k])))
 	}
-	return strings.Join(parts, ",")
+	return utils.EscapeKeyPredicate(strings.Join(parts, ","))
 }
 
-// formatKeyValue formats a key value for OData URL
-func (c *ODataClient) formatKeyValue(value interface{}) string {
+// formatKeyLiteral formats a key value of unknown type for OData URL
+func formatKeyLiteral(value interface{}) string {
 	switch v := value.(type) {
 	case string:
-		// For key predicates, don't URL encode the value inside quotes
-		// URL encoding happens at the full URL level
-		return fmt.Sprintf("'%s'", v)
+		// Characters that break the URL path are escaped when the predicate is built
+		return utils.QuoteODataString(v)
 	case int, int32, int64:
 		return fmt.Sprintf("%d", v)
-	case float32, float64:
-		return fmt.Sprintf("%g", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
 	case bool:
 		return fmt.Sprintf("%t", v)
 	default:
-		return fmt.Sprintf("'%s'", fmt.Sprintf("%v", v))
+		return utils.QuoteODataString(fmt.Sprintf("%v", v))
 	}
 }
 
-// formatFunctionParameter formats a function parameter for OData URL
-func (c *ODataClient) formatFunctionParameter(key string, value interface{}) string {
+// formatFunctionParameter formats a function parameter for OData URL. Parameters with a
+// known non-string EDM type use typed literals such as datetime'2024-12-25T00:00:00'.
+func (d *Dialect) formatFunctionParameter(key string, value interface{}, edmType string) string {
+	if edmType != "" && edmType != "Edm.String" {
+		// URL encode the literal but keep its quotes readable
+		literal := url.QueryEscape(d.formatLiteral(value, edmType))
+		return fmt.Sprintf("%s=%s", key, strings.ReplaceAll(literal, "%27", "'"))
+	}
+
 	switch v := value.(type) {
 	case string:
-		// OData requires string parameters to be single-quoted
+		// OData requires string parameters to be single-quoted with embedded quotes doubled
 		// URL encode the value but not the quotes
-		return fmt.Sprintf("%s='%s'", key, url.QueryEscape(v))
+		return fmt.Sprintf("%s='%s'", key, url.QueryEscape(strings.ReplaceAll(v, "'", "''")))
 	case int, int32, int64:
 		return fmt.Sprintf("%s=%d", key, v)
 	case float32, float64:
@@ -598,33 +865,71 @@ func (c *ODataClient) formatFunctionParameter(key string, value interface{}) str
 		return fmt.Sprintf("%s=%t", key, v)
 	default:
 		// Default to string representation with quotes
-		return fmt.Sprintf("%s='%s'", key, url.QueryEscape(fmt.Sprintf("%v", v)))
+		return fmt.Sprintf("%s='%s'", key, url.QueryEscape(strings.ReplaceAll(fmt.Sprintf("%v", v), "'", "''")))
 	}
 }
 
-// parseODataResponse parses an OData response
-func (c *ODataClient) parseODataResponse(resp *http.Response) (*models.ODataResponse, error) {
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// readBody reads a response body into buf, failing instead of buffering bodies above the
+// size limit
+func (c *ODataClient) readBody(resp *http.Response, buf *bytes.Buffer) error {
+	reader := io.Reader(resp.Body)
+	maxBodySize := c.maxBodySize.Load()
+	if maxBodySize > 0 {
+		reader = io.LimitReader(resp.Body, maxBodySize+1)
+	}
+	if _, err := buf.ReadFrom(reader); err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if maxBodySize > 0 && int64(buf.Len()) > maxBodySize {
+		return fmt.Errorf("response body exceeds %d bytes; use $select to request fewer properties, or $top and $filter to request fewer entities", maxBodySize)
+	}
+	return nil
+}
+
+// parseODataResponse parses an OData response of a request made with the given dialect.
+// The body is read into a pooled buffer; everything returned is decoded from it, so
+// nothing refers to the buffer afterwards.
+func (c *ODataClient) parseODataResponse(resp *http.Response, d *Dialect) (*models.ODataResponse, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := c.readBody(resp, buf); err != nil {
+		return nil, err
 	}
+	body := buf.Bytes()
 
 	if resp.StatusCode >= 400 {
 		return nil, c.parseErrorFromBody(body, resp.StatusCode)
 	}
 
+	// Log raw response for debugging
+	if len(body) > 0 && logger.Enabled(context.Background(), slog.LevelDebug) {
+		logger.Debug("Raw response", "body", string(body))
+	}
+
+	response, err := d.decodeResponse(body, c.responseEntityType(d, resp))
+	if err != nil {
+		return nil, err
+	}
+	response.Messages = parseSAPMessages(resp.Header)
+	return response, nil
+}
+
+// DecodeResponse decodes the JSON body of a successful OData response. Entities
+// without a declared type (__metadata or @odata.type) are left as they are.
+func (c *ODataClient) DecodeResponse(body []byte) (*models.ODataResponse, error) {
+	return c.Dialect().decodeResponse(body, "")
+}
+
+// decodeResponse decodes the JSON body of a successful OData response whose entities are
+// of the given entity type (the key in the metadata's EntityTypes, "" if unknown)
+func (d *Dialect) decodeResponse(body []byte, entityType string) (*models.ODataResponse, error) {
 	// Handle empty responses (e.g., from DELETE operations)
 	if len(body) == 0 {
 		return &models.ODataResponse{}, nil
 	}
 
-	// Log raw response for debugging
-	if c.verbose {
-		fmt.Fprintf(os.Stderr, "[VERBOSE] Raw response: %s\n", string(body))
-	}
-
 	// Parse using the appropriate parser
-	parsedResponse, err := parseODataResponse(body, c.isV4)
+	parsedResponse, err := parseODataResponse(body, d.isV4)
 	if err != nil {
 		return nil, err
 	}
@@ -635,7 +940,7 @@ func (c *ODataClient) parseODataResponse(resp *http.Response) (*models.ODataResp
 	switch v := parsedResponse.(type) {
 	case map[string]interface{}:
 		// Check for v4 format
-		if c.isV4 {
+		if d.isV4 {
 			// OData v4 format
 			if value, ok := v["value"]; ok {
 				odataResp.Value = value
@@ -649,8 +954,8 @@ func (c *ODataClient) parseODataResponse(resp *http.Response) (*models.ODataResp
 					odataResp.Count = &countInt
 				}
 			}
-			if nextLink, ok := v["@odata.nextLink"]; ok {
-				odataResp.NextLink = nextLink.(string)
+			if nextLink, ok := v["@odata.nextLink"].(string); ok {
+				odataResp.NextLink = nextLink
 			}
 			if context, ok := v["@odata.context"]; ok {
 				odataResp.Context = context.(string)
@@ -663,14 +968,18 @@ func (c *ODataClient) parseODataResponse(resp *http.Response) (*models.ODataResp
 				// Single entity
 				odataResp.Value = v
 			}
-			if count, ok := v["@odata.count"]; ok {
-				if countFloat, ok := count.(float64); ok {
-					countInt := int64(countFloat)
+			// v2 serializes __count as a string
+			switch count := v["@odata.count"].(type) {
+			case float64:
+				countInt := int64(count)
+				odataResp.Count = &countInt
+			case string:
+				if countInt, err := strconv.ParseInt(count, 10, 64); err == nil {
 					odataResp.Count = &countInt
 				}
 			}
-			if nextLink, ok := v["@odata.nextLink"]; ok {
-				odataResp.NextLink = nextLink.(string)
+			if nextLink, ok := v["@odata.nextLink"].(string); ok {
+				odataResp.NextLink = nextLink
 			}
 		}
 	default:
@@ -678,35 +987,55 @@ func (c *ODataClient) parseODataResponse(resp *http.Response) (*models.ODataResp
 		odataResp.Value = parsedResponse
 	}
 
-	// Process GUIDs if needed (to be implemented)
-	c.optimizeResponse(&odataResp)
+	d.optimizeResponse(&odataResp, entityType)
 
 	return &odataResp, nil
 }
 
 // parseError parses error from HTTP response
 func (c *ODataClient) parseError(resp *http.Response) error {
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("HTTP %d: failed to read error response", resp.StatusCode)
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return &StatusError{StatusCode: resp.StatusCode, Err: fmt.Errorf("HTTP %d: failed to read error response", resp.StatusCode)}
 	}
 
-	return c.parseErrorFromBody(body, resp.StatusCode)
+	return c.parseErrorFromBody(buf.Bytes(), resp.StatusCode)
 }
 
 // parseErrorFromBody parses error from response body
 func (c *ODataClient) parseErrorFromBody(body []byte, statusCode int) error {
 	// Try to parse as JSON error
-	var errorResp struct {
-		Error *models.ODataError `json:"error"`
-	}
-
-	if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Error != nil {
-		return c.buildDetailedError(errorResp.Error, statusCode, body)
+	if odataErr := decodeODataError(body); odataErr != nil {
+		return &StatusError{StatusCode: statusCode, Err: c.buildDetailedError(odataErr, statusCode, body), OData: odataErr}
 	}
 
 	// Fallback to generic error
-	return fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	return &StatusError{StatusCode: statusCode, Err: fmt.Errorf("HTTP %d: %s", statusCode, string(body))}
+}
+
+// StatusError is an error response of the OData service, carrying its HTTP status code
+type StatusError struct {
+	StatusCode int
+	Err        error
+	OData      *models.ODataError // Parsed error body (nil = not an OData error)
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// ServiceMessages returns the typed messages of the error response, which the MCP error
+// data of a failed tool call includes
+func (e *StatusError) ServiceMessages() []models.SAPMessage {
+	if e.OData == nil {
+		return nil
+	}
+	return e.OData.Messages
 }
 
 // buildDetailedError creates a comprehensive error message from OData error details
@@ -747,9 +1076,26 @@ func (c *ODataClient) buildDetailedError(odataErr *models.ODataError, statusCode
 			}
 		}
 	}
+
+	// Add the SAP messages, one per field or business rule that failed
+	if len(odataErr.Messages) > 0 {
+		errMsg.WriteString(" | Messages: ")
+		for i, message := range odataErr.Messages {
+			if i > 0 {
+				errMsg.WriteString("; ")
+			}
+			if message.Severity != "" {
+				errMsg.WriteString(fmt.Sprintf("[%s] ", message.Severity))
+			}
+			errMsg.WriteString(message.Message)
+			if message.Target != "" {
+				errMsg.WriteString(fmt.Sprintf(" (target: %s)", message.Target))
+			}
+		}
+	}
 	
 	// Add inner error info if available and verbose mode is on
-	if c.verbose && len(odataErr.InnerError) > 0 {
+	if c.verbose.Load() && len(odataErr.InnerError) > 0 {
 		errMsg.WriteString(" | Inner error: ")
 		if innerErrBytes, err := json.Marshal(odataErr.InnerError); err == nil {
 			errMsg.WriteString(string(innerErrBytes))
@@ -759,35 +1105,168 @@ func (c *ODataClient) buildDetailedError(odataErr *models.ODataError, statusCode
 	return fmt.Errorf(errMsg.String())
 }
 
-// optimizeResponse applies optimizations to the response
-func (c *ODataClient) optimizeResponse(resp *models.ODataResponse) {
-	// TODO: Implement GUID conversion and other optimizations
-	// This would include the sophisticated response optimization logic
-	// from the Python version
+// optimizeResponse applies optimizations to the response, whose entities are of the
+// given entity type ("" if unknown)
+func (d *Dialect) optimizeResponse(resp *models.ODataResponse, entityType string) {
+	// Some SAP v2 services return Edm.Guid values as base64 blobs
+	if len(d.guidProperties) > 0 {
+		resp.Value = d.normalizeGUIDs(resp.Value, entityType)
+	}
+}
+
+// normalizeGUIDs converts base64 encoded values of the Edm.Guid properties of an entity
+// type to canonical GUID strings. An entity's declared type takes precedence, and
+// expanded entities are normalized by the target type of their navigation property.
+func (d *Dialect) normalizeGUIDs(data interface{}, entityType string) interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		for i, item := range v {
+			v[i] = d.normalizeGUIDs(item, entityType)
+		}
+	case map[string]interface{}:
+		if declared := d.declaredEntityType(v); declared != "" {
+			entityType = declared
+		}
+		guids := d.guidProperties[entityType]
+		for key, value := range v {
+			switch {
+			case guids[key]:
+				v[key] = utils.NormalizeGUID(value)
+			case key == "results":
+				// A v2 collection of expanded entities
+				v[key] = d.normalizeGUIDs(value, entityType)
+			default:
+				v[key] = d.normalizeGUIDs(value, d.navigationTarget(entityType, key))
+			}
+		}
+	}
+	return data
+}
+
+// declaredEntityType returns the key in EntityTypes of the type an entity declares in
+// __metadata (v2) or @odata.type (v4), or "" if it declares none the metadata knows
+func (d *Dialect) declaredEntityType(entity map[string]interface{}) string {
+	name, _ := entity["@odata.type"].(string)
+	if meta, ok := entity["__metadata"].(map[string]interface{}); ok {
+		name, _ = meta["type"].(string)
+	}
+	name = strings.TrimPrefix(name, "#")
+	if name == "" || d.metadata == nil {
+		return ""
+	}
+	// Types are keyed by their name, qualified only where names collide
+	if _, ok := d.metadata.EntityTypes[name]; ok {
+		return name
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		if _, ok := d.metadata.EntityTypes[name[i+1:]]; ok {
+			return name[i+1:]
+		}
+	}
+	return ""
+}
+
+// navigationTarget returns the target entity type of a navigation property, or "" if
+// the entity type has no such navigation property
+func (d *Dialect) navigationTarget(entityType, name string) string {
+	if d.metadata == nil || d.metadata.EntityTypes[entityType] == nil {
+		return ""
+	}
+	for _, nav := range d.metadata.EntityTypes[entityType].NavigationProps {
+		if nav.Name == name {
+			return nav.TargetEntityType
+		}
+	}
+	return ""
+}
+
+// responseEntityType returns the entity type of the entities a request returns, from the
+// entity set and navigation properties in its URL path, or "" if the path names none
+func (c *ODataClient) responseEntityType(d *Dialect, resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil || d.metadata == nil {
+		return ""
+	}
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimPrefix(resp.Request.URL.Path, strings.TrimSuffix(base.Path, "/"))
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Key predicates such as Products(1) do not change the type
+	name, _, _ := strings.Cut(segments[0], "(")
+	entitySet := d.metadata.EntitySets[name]
+	if entitySet == nil {
+		return ""
+	}
+	entityType := entitySet.EntityType
+	for _, segment := range segments[1:] {
+		name, _, _ = strings.Cut(segment, "(")
+		if entityType = d.navigationTarget(entityType, name); entityType == "" {
+			return ""
+		}
+	}
+	return entityType
 }
 
 // parseMetadataXML parses OData metadata XML
-func (c *ODataClient) parseMetadataXML(data []byte) (*models.ODataMetadata, error) {
-	meta, err := metadata.ParseMetadata(data, c.baseURL)
+func (c *ODataClient) parseMetadataXML(r io.Reader) (*models.ODataMetadata, error) {
+	meta, err := metadata.ParseMetadataReader(r, c.baseURL)
 	if err != nil {
 		return nil, err
 	}
 	
-	// Set the client's v4 flag based on metadata version
-	c.isV4 = meta.Version == "4.0" || meta.Version == "4.01"
+	// Set the client's v4 and SAP flags based on metadata
+	c.ApplyMetadata(meta)
 	
 	return meta, nil
 }
 
+// Ping requests the service document to verify that the service is reachable and
+// accepts the credentials
+func (c *ODataClient) Ping(ctx context.Context) error {
+	req, err := c.buildRequest(ctx, constants.GET, "", nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(constants.Accept, constants.ContentTypeJSON)
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
 // getServiceDocument gets the service document as fallback
 func (c *ODataClient) getServiceDocument(ctx context.Context) (*models.ODataMetadata, error) {
+	req, err := c.serviceDocumentRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.readServiceDocument(req)
+}
+
+// serviceDocumentRequest builds the request of the service document
+func (c *ODataClient) serviceDocumentRequest(ctx context.Context) (*http.Request, error) {
 	req, err := c.buildRequest(ctx, constants.GET, "", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set(constants.Accept, constants.ContentTypeJSON)
+	return req, nil
+}
 
+// readServiceDocument sends a request built by serviceDocumentRequest
+func (c *ODataClient) readServiceDocument(req *http.Request) (*models.ODataMetadata, error) {
 	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
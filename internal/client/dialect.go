@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// Dialect is what the client derives from the service metadata: the OData version, the
+// SAP conventions and the types of literals and Edm.Guid properties. A dialect is never
+// changed; ApplyMetadata publishes a new one, so that a request sees one dialect throughout.
+type Dialect struct {
+	metadata       *models.ODataMetadata      // Parsed metadata, used for typed literals
+	isV4           bool                       // Whether the service is OData v4
+	isSAP          bool                       // Whether the service uses SAP annotations
+	guidProperties map[string]map[string]bool // Names of Edm.Guid properties by entity type
+}
+
+// noDialect is the dialect of a client without metadata
+var noDialect = &Dialect{}
+
+// NewDialect derives the dialect of parsed metadata
+func NewDialect(meta *models.ODataMetadata) *Dialect {
+	d := &Dialect{
+		metadata:       meta,
+		isV4:           meta.Version == "4.0" || meta.Version == "4.01",
+		isSAP:          meta.IsSAP,
+		guidProperties: make(map[string]map[string]bool),
+	}
+	for key, entityType := range meta.EntityTypes {
+		for _, prop := range entityType.Properties {
+			if prop.Type == "Edm.Guid" {
+				if d.guidProperties[key] == nil {
+					d.guidProperties[key] = make(map[string]bool)
+				}
+				d.guidProperties[key][prop.Name] = true
+			}
+		}
+	}
+	return d
+}
+
+// IsV4 reports whether the service speaks OData v4
+func (d *Dialect) IsV4() bool {
+	return d.isV4
+}
+
+// ApplyMetadata adopts the OData version and dialect of already parsed metadata.
+// Requests already under way keep the dialect they started with.
+func (c *ODataClient) ApplyMetadata(meta *models.ODataMetadata) {
+	c.dialect.Store(NewDialect(meta))
+}
+
+// Dialect returns the dialect of the metadata the client last adopted
+func (c *ODataClient) Dialect() *Dialect {
+	if d := c.dialect.Load(); d != nil {
+		return d
+	}
+	return noDialect
+}
+
+// IsV4 reports whether the service speaks OData v4
+func (c *ODataClient) IsV4() bool {
+	return c.Dialect().IsV4()
+}
+
+// dialectKey carries the dialect the requests of a tool call use
+type dialectKey struct{}
+
+// WithDialect returns a context whose requests use the given dialect instead of the
+// client's current one, e.g. the one of the metadata a tool call started with
+func WithDialect(ctx context.Context, d *Dialect) context.Context {
+	return context.WithValue(ctx, dialectKey{}, d)
+}
+
+// dialectFor returns the dialect of the requests of a context: the one pinned with
+// WithDialect, or the client's current one
+func (c *ODataClient) dialectFor(ctx context.Context) *Dialect {
+	if d, ok := ctx.Value(dialectKey{}).(*Dialect); ok && d != nil {
+		return d
+	}
+	return c.Dialect()
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// dryRunKey marks the context of a tool call made with --dry-run
+type dryRunKey struct{}
+
+// WithDryRun returns a context in which the client does not send requests but returns
+// each request it would have sent as a *DryRunRequest error. CSRF tokens are not fetched.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// isDryRun reports whether requests of a context must not be sent
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// DryRunRequest is a request the client did not send because of --dry-run
+type DryRunRequest struct {
+	DryRun  bool              `json:"dry_run"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+func (r *DryRunRequest) Error() string {
+	return fmt.Sprintf("dry run: %s %s", r.Method, r.URL)
+}
+
+// newDryRunRequest describes a request that is not sent. A JSON body is included as a
+// JSON value, any other body as a string.
+func newDryRunRequest(req *http.Request, body []byte) *DryRunRequest {
+	r := &DryRunRequest{
+		DryRun:  true,
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: make(map[string]string),
+	}
+	for name := range req.Header {
+		// Credentials are left out
+		if !redact.IsCredentialHeader(name) {
+			r.Headers[name] = req.Header.Get(name)
+		}
+	}
+	if len(body) > 0 {
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err == nil {
+			r.Body = value
+		} else {
+			r.Body = string(body)
+		}
+	}
+	return r
+}
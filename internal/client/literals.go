@@ -0,0 +1,212 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/odata-mcp/go/internal/utils"
+)
+
+// entityTypeOf returns the entity type of an entity set, or nil when the metadata
+// does not describe the entity set
+func (d *Dialect) entityTypeOf(entitySet string) *models.EntityType {
+	if d.metadata == nil {
+		return nil
+	}
+	es, ok := d.metadata.EntitySets[entitySet]
+	if !ok {
+		return nil
+	}
+	return d.metadata.EntityTypes[es.EntityType]
+}
+
+// propertyTypes returns the EDM types of the properties of an entity set's entity type
+func (d *Dialect) propertyTypes(entitySet string) map[string]string {
+	entityType := d.entityTypeOf(entitySet)
+	if entityType == nil {
+		return nil
+	}
+
+	types := make(map[string]string, len(entityType.Properties))
+	for _, prop := range entityType.Properties {
+		types[prop.Name] = prop.Type
+	}
+	return types
+}
+
+// keyOrder returns the names of a key in the order of the entity type's key definition.
+// Names the metadata does not declare follow in alphabetical order.
+func (d *Dialect) keyOrder(entitySet string, key map[string]interface{}) []string {
+	names := make([]string, 0, len(key))
+	if entityType := d.entityTypeOf(entitySet); entityType != nil {
+		for _, name := range entityType.KeyProperties {
+			if _, ok := key[name]; ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var rest []string
+	for name := range key {
+		if !utils.ContainsName(names, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// functionParameters returns the EDM types of a function import's parameters and the
+// names of the given parameters in declaration order, undeclared ones last
+func (d *Dialect) functionParameters(functionName string, parameters map[string]interface{}) (map[string]string, []string) {
+	types := make(map[string]string)
+	names := make([]string, 0, len(parameters))
+	if d.metadata != nil {
+		if function, ok := d.metadata.FunctionImports[functionName]; ok {
+			for _, param := range function.Parameters {
+				types[param.Name] = param.Type
+				if _, ok := parameters[param.Name]; ok {
+					names = append(names, param.Name)
+				}
+			}
+		}
+	}
+
+	var rest []string
+	for name := range parameters {
+		if !utils.ContainsName(names, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return types, append(names, rest...)
+}
+
+// formatLiteral formats a value as an OData URI literal of the given EDM type, using the
+// v2 prefixes and suffixes (guid'...', datetime'...', 42L, 1.5M) or the plain v4 forms.
+// Values that do not fit the type are formatted by their JSON type instead.
+func (d *Dialect) formatLiteral(value interface{}, edmType string) string {
+	switch edmType {
+	case "Edm.String":
+		return utils.QuoteODataString(fmt.Sprintf("%v", value))
+
+	case "Edm.Guid":
+		guid := fmt.Sprintf("%v", utils.NormalizeGUID(value))
+		if d.isV4 {
+			return guid
+		}
+		return "guid'" + guid + "'"
+
+	case "Edm.DateTime", "Edm.DateTimeOffset", "Edm.Date":
+		if t, ok := parseDateLiteral(value); ok {
+			return d.formatDateLiteral(t, edmType)
+		}
+
+	case "Edm.Time", "Edm.TimeOfDay":
+		if text, ok := value.(string); ok {
+			if d.isV4 {
+				return text
+			}
+			if t, err := time.Parse("15:04:05", text); err == nil {
+				text = fmt.Sprintf("PT%02dH%02dM%02dS", t.Hour(), t.Minute(), t.Second())
+			}
+			return "time'" + text + "'"
+		}
+
+	case "Edm.Int64", "Edm.Decimal", "Edm.Int32", "Edm.Int16", "Edm.Byte", "Edm.SByte", "Edm.Double", "Edm.Single":
+		integer := edmType != "Edm.Decimal" && edmType != "Edm.Double" && edmType != "Edm.Single"
+		if number, ok := numericLiteral(value, integer); ok {
+			if !d.isV4 {
+				switch edmType {
+				case "Edm.Int64":
+					return number + "L"
+				case "Edm.Decimal":
+					return number + "M"
+				}
+			}
+			return number
+		}
+
+	case "Edm.Boolean":
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v)
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return strconv.FormatBool(b)
+			}
+		}
+	}
+	return formatKeyLiteral(value)
+}
+
+// formatDateLiteral formats a point in time for Edm.DateTime, Edm.DateTimeOffset and Edm.Date
+func (d *Dialect) formatDateLiteral(t time.Time, edmType string) string {
+	switch {
+	case edmType == "Edm.Date":
+		return t.Format("2006-01-02")
+	case d.isV4:
+		return t.Format(time.RFC3339Nano)
+	case edmType == "Edm.DateTime":
+		// Edm.DateTime carries no offset; SAP interprets it as UTC
+		return "datetime'" + t.UTC().Format("2006-01-02T15:04:05.9999999") + "'"
+	default:
+		return "datetimeoffset'" + t.Format(time.RFC3339Nano) + "'"
+	}
+}
+
+// parseDateLiteral reads ISO 8601 and /Date(...)/ values
+func parseDateLiteral(value interface{}) (time.Time, bool) {
+	text, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	if utils.IsODataLegacyDate(text) {
+		text = utils.ConvertODataLegacyToISO(text)
+	}
+	return utils.ParseISODateTime(text)
+}
+
+// decimalText and integerText match plain decimal and integer numbers, without exponent
+var (
+	decimalText = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+	integerText = regexp.MustCompile(`^[+-]?\d+$`)
+)
+
+// numericLiteral returns the decimal text of a number or numeric string, without exponent;
+// with integer, only of whole numbers. NaN, infinities, exponents and hex floats have no
+// such text.
+func numericLiteral(value interface{}, integer bool) (string, bool) {
+	var text string
+	switch v := value.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return "", false
+		}
+		text = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), true
+	case json.Number:
+		text = v.String()
+	case string:
+		text = strings.TrimSpace(v)
+	default:
+		return "", false
+	}
+	if integer {
+		return text, integerText.MatchString(text)
+	}
+	return text, decimalText.MatchString(text)
+}
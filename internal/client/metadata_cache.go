@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// metadataCacheEntry is a parsed $metadata document stored with the validators of the
+// response it was parsed from
+type metadataCacheEntry struct {
+	ServiceURL   string          `json:"service_url"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Digest       string          `json:"digest"` // SHA-256 of the document
+	Metadata     json.RawMessage `json:"metadata"`
+
+	parsed *models.ODataMetadata // Decoded Metadata
+}
+
+// SetMetadataCache stores parsed metadata in a directory (--metadata-cache-dir). Later
+// fetches revalidate it with If-None-Match/If-Modified-Since and parse the document only
+// if it changed.
+func (c *ODataClient) SetMetadataCache(dir string) {
+	c.metadataCacheDir = dir
+}
+
+// metadataCachePath returns the cache file of the service and user of the client
+func (c *ODataClient) metadataCachePath() string {
+	sum := sha256.Sum256([]byte(c.baseURL + "\x00" + c.User()))
+	return filepath.Join(c.metadataCacheDir, "metadata-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadMetadataCache reads and decodes the cache entry of the service, or returns nil if
+// there is no usable one
+func (c *ODataClient) loadMetadataCache() *metadataCacheEntry {
+	data, err := os.ReadFile(c.metadataCachePath())
+	if err != nil {
+		return nil
+	}
+	var entry metadataCacheEntry
+	if err = json.Unmarshal(data, &entry); err == nil && entry.ServiceURL == c.baseURL {
+		entry.parsed, err = metadata.FromJSON(entry.Metadata, c.baseURL)
+	}
+	if err != nil || entry.parsed == nil {
+		logger.Debug("Ignoring unreadable metadata cache", "file", c.metadataCachePath())
+		return nil
+	}
+	return &entry
+}
+
+// setMetadataValidators makes a $metadata request conditional on the cached document
+func setMetadataValidators(req *http.Request, entry *metadataCacheEntry) {
+	if entry == nil {
+		return
+	}
+	if entry.ETag != "" {
+		req.Header.Set(constants.IfNoneMatch, entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set(constants.IfModifiedSince, entry.LastModified)
+	}
+}
+
+// readCachedMetadata reads a $metadata response with --metadata-cache-dir. A document
+// identical to the cached one is not parsed again, which the returned bool reports; a
+// changed one is parsed and cached.
+func (c *ODataClient) readCachedMetadata(resp *http.Response, entry *metadataCacheEntry) (*models.ODataMetadata, bool, error) {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if entry != nil && entry.Digest == digest {
+		logger.Debug("Metadata unchanged, using the cached parse", "file", c.metadataCachePath())
+		// The validators may be new even if the document is not
+		if resp.Header.Get(constants.ETag) != entry.ETag || resp.Header.Get(constants.LastModified) != entry.LastModified {
+			c.storeMetadataCache(resp, digest, entry.Metadata)
+		}
+		c.ApplyMetadata(entry.parsed)
+		return entry.parsed, true, nil
+	}
+
+	meta, err := c.parseMetadataXML(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, err
+	}
+	if encoded, err := json.Marshal(meta); err == nil {
+		c.storeMetadataCache(resp, digest, encoded)
+	}
+	return meta, false, nil
+}
+
+// storeMetadataCache writes the cache entry of a $metadata response. Failures are logged
+// only, as the cache merely saves time.
+func (c *ODataClient) storeMetadataCache(resp *http.Response, digest string, encoded json.RawMessage) {
+	data, err := json.Marshal(metadataCacheEntry{
+		ServiceURL:   c.baseURL,
+		ETag:         resp.Header.Get(constants.ETag),
+		LastModified: resp.Header.Get(constants.LastModified),
+		Digest:       digest,
+		Metadata:     encoded,
+	})
+	if err == nil {
+		err = os.MkdirAll(c.metadataCacheDir, 0o700)
+	}
+	if err == nil {
+		// Write a temporary file first, so that a concurrent start never reads half a file
+		path := c.metadataCachePath()
+		tmp := path + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o600); err == nil {
+			err = os.Rename(tmp, path)
+		}
+	}
+	if err != nil {
+		c.logf("warning", "Failed to write the metadata cache: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrMetadataTooLarge is returned when the $metadata document exceeds --max-metadata-size
+var ErrMetadataTooLarge = errors.New("metadata document too large")
+
+// MetadataStats describes the last load of the service metadata
+type MetadataStats struct {
+	Size     int64         // Bytes of the $metadata document or --metadata-file (0 = not known)
+	Duration time.Duration // Time to fetch and parse it
+	Cached   bool          // The parse of --metadata-cache-dir was reused
+}
+
+// SetMaxMetadataSize makes metadata fetches fail once the $metadata document exceeds
+// size bytes, before the whole document is parsed (0 = unlimited)
+func (c *ODataClient) SetMaxMetadataSize(size int64) {
+	c.maxMetadataSize = size
+}
+
+// MetadataStats returns the size and load time of the last metadata load
+func (c *ODataClient) MetadataStats() MetadataStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.metadataStats
+}
+
+// setMetadataStats records the size and load time of a metadata load
+func (c *ODataClient) setMetadataStats(stats MetadataStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metadataStats = stats
+}
+
+// metadataTooLarge returns the error for a $metadata document larger than the budget,
+// with what to do about it
+func (c *ODataClient) metadataTooLarge(size string) error {
+	return fmt.Errorf("%w: %s exceeds --max-metadata-size %d bytes. Export the metadata once with 'odata-mcp metadata', trim unneeded entity sets and pass the file with --metadata-file, or raise --max-metadata-size",
+		ErrMetadataTooLarge, size, c.maxMetadataSize)
+}
+
+// metadataReader counts the bytes of a $metadata document and fails the read once they
+// exceed the budget
+type metadataReader struct {
+	client *ODataClient
+	r      io.Reader
+	n      int64
+}
+
+func (m *metadataReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.n += int64(n)
+	if limit := m.client.maxMetadataSize; limit > 0 && m.n > limit {
+		return n, m.client.metadataTooLarge(fmt.Sprintf("more than %d bytes", limit))
+	}
+	return n, err
+}
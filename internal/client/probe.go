@@ -0,0 +1,36 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/odata-mcp/go/internal/constants"
+)
+
+// Probe sends a GET request for an endpoint of the service with the client's transport
+// and credentials, and the given headers in addition, and returns the response and its
+// body whatever the status, for diagnostics such as odata-mcp doctor. Unlike the other
+// requests it does not retry, and a CSRF token the service issues is not adopted.
+func (c *ODataClient) Probe(ctx context.Context, endpoint string, headers map[string]string) (*http.Response, []byte, error) {
+	req, err := c.buildRequest(ctx, constants.GET, endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.send(req, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request to %s failed: %w", req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if err := c.readBody(resp, &buf); err != nil {
+		return nil, nil, err
+	}
+	return resp, buf.Bytes(), nil
+}
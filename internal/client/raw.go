@@ -0,0 +1,49 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/odata-mcp/go/internal/constants"
+)
+
+// GetEntitySetRaw queries an entity set like GetEntitySet but returns the JSON body of
+// the response as sent by the service, without decoding it
+func (c *ODataClient) GetEntitySetRaw(ctx context.Context, entitySet string, options map[string]string) ([]byte, error) {
+	ctx, d := c.pinDialect(ctx)
+	return c.getRaw(ctx, entitySet, c.entitySetEndpoint(d, entitySet, options), options)
+}
+
+// GetEntityRaw retrieves a single entity like GetEntity but returns the JSON body of the
+// response as sent by the service, without decoding it
+func (c *ODataClient) GetEntityRaw(ctx context.Context, entitySet string, key map[string]interface{}, options map[string]string) ([]byte, error) {
+	ctx, d := c.pinDialect(ctx)
+	return c.getRaw(ctx, entitySet, c.entityEndpoint(d, entitySet, key, options), options)
+}
+
+// getRaw sends a GET request and returns the body of a successful response. The body is
+// read into a buffer of its own, as it outlives the request.
+func (c *ODataClient) getRaw(ctx context.Context, entitySet, endpoint string, options map[string]string) ([]byte, error) {
+	req, err := c.buildRequest(ctx, constants.GET, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if err := c.readBody(resp, &buf); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, c.parseErrorFromBody(buf.Bytes(), resp.StatusCode)
+	}
+	c.logSlowQuery(ctx, entitySet, options, nil, started)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// recordedBodyLimit is the number of bytes of a request or response body kept in the
+// recent request log
+const recordedBodyLimit = 4096
+
+// RecordedRequest is a request sent to the service and its response, with credential
+// headers redacted and bodies cut to 4 KB
+type RecordedRequest struct {
+	Time            time.Time         `json:"time"`
+	RequestID       string            `json:"request_id,omitempty"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	Status          int               `json:"status,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	DurationMs      int64             `json:"duration_ms"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// requestLog is a ring buffer of the most recent requests
+type requestLog struct {
+	mu      sync.Mutex
+	entries []*RecordedRequest
+	next    int
+	full    bool
+}
+
+// SetRequestLog keeps the last size requests and responses in memory for
+// RecentRequests (0 disables the log)
+func (c *ODataClient) SetRequestLog(size int) {
+	if size <= 0 {
+		c.recent = nil
+		return
+	}
+	c.recent = &requestLog{entries: make([]*RecordedRequest, size)}
+}
+
+// RecentRequests returns up to limit recorded requests, the most recent first
+func (c *ODataClient) RecentRequests(limit int) []RecordedRequest {
+	buffer := c.recent
+	if buffer == nil {
+		return nil
+	}
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+
+	count := buffer.next
+	if buffer.full {
+		count = len(buffer.entries)
+	}
+	if limit <= 0 || limit > count {
+		limit = count
+	}
+	result := make([]RecordedRequest, 0, limit)
+	for i := 1; i <= limit; i++ {
+		index := (buffer.next - i + len(buffer.entries)) % len(buffer.entries)
+		result = append(result, *buffer.entries[index])
+	}
+	return result
+}
+
+// recordRequest adds a sent request and its response to the recent request log. The
+// response body is recorded as it is read by the client.
+func (c *ODataClient) recordRequest(req *http.Request, resp *http.Response, err error, started time.Time) {
+	buffer := c.recent
+	if buffer == nil {
+		return
+	}
+
+	entry := &RecordedRequest{
+		Time:           started,
+		RequestID:      RequestIDFrom(req.Context()),
+		Method:         req.Method,
+		URL:            req.URL.Redacted(),
+		RequestHeaders: redact.Header(req.Header),
+		DurationMs:     time.Since(started).Milliseconds(),
+	}
+	if req.GetBody != nil {
+		if body, bodyErr := req.GetBody(); bodyErr == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, recordedBodyLimit+1))
+			body.Close()
+			entry.RequestBody = cutBody(data)
+		}
+	}
+	if err != nil {
+		entry.Error = redact.String(err.Error())
+	} else {
+		entry.Status = resp.StatusCode
+		entry.ResponseHeaders = redact.Header(resp.Header)
+		resp.Body = &recordingBody{ReadCloser: resp.Body, buffer: buffer, entry: entry}
+	}
+
+	buffer.mu.Lock()
+	buffer.entries[buffer.next] = entry
+	buffer.next = (buffer.next + 1) % len(buffer.entries)
+	if buffer.next == 0 {
+		buffer.full = true
+	}
+	buffer.mu.Unlock()
+}
+
+// recordingBody keeps the first bytes of a response body for the recent request log
+type recordingBody struct {
+	io.ReadCloser
+	buffer *requestLog
+	entry  *RecordedRequest
+	data   bytes.Buffer
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if remaining := recordedBodyLimit + 1 - b.data.Len(); remaining > 0 {
+		b.data.Write(p[:min(n, remaining)])
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.buffer.mu.Lock()
+	b.entry.ResponseBody = cutBody(b.data.Bytes())
+	b.buffer.mu.Unlock()
+	return b.ReadCloser.Close()
+}
+
+// cutBody returns a body as a string without credentials, cut to recordedBodyLimit bytes
+func cutBody(data []byte) string {
+	if len(data) > recordedBodyLimit {
+		return redact.String(string(data[:recordedBodyLimit])) + "... (truncated)"
+	}
+	return redact.String(string(data))
+}
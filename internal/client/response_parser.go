@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/odata-mcp/go/internal/utils"
 )
 
 // parseODataResponse parses OData responses, handling both v2 and v4 formats
@@ -148,7 +150,7 @@ func formatKeyValue(val interface{}) string {
 	switch v := val.(type) {
 	case string:
 		// String keys need to be quoted
-		return fmt.Sprintf("'%s'", v)
+		return utils.QuoteODataString(v)
 	case float64:
 		// Check if it's actually an integer
 		if v == float64(int64(v)) {
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// sapNotification is the payload of the sap-message header. SAP sends it as JSON for
+// JSON requests and as a <notification> XML document otherwise.
+type sapNotification struct {
+	Code     string            `json:"code" xml:"code"`
+	Message  string            `json:"message" xml:"message"`
+	Severity string            `json:"severity" xml:"severity"`
+	Target   string            `json:"target" xml:"target"`
+	Details  []sapNotification `json:"details" xml:"details>detail"`
+}
+
+// parseSAPMessages returns the messages of all sap-message headers of a response,
+// the top-level message followed by its details
+func parseSAPMessages(header http.Header) []models.SAPMessage {
+	var messages []models.SAPMessage
+	for _, value := range header.Values(constants.SAPMessageHeader) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		var notification sapNotification
+		var err error
+		if strings.HasPrefix(value, "<") {
+			err = xml.Unmarshal([]byte(value), &notification)
+		} else {
+			err = json.Unmarshal([]byte(value), &notification)
+		}
+		if err != nil {
+			// Keep unparseable headers readable rather than dropping them
+			messages = append(messages, models.SAPMessage{Message: value})
+			continue
+		}
+
+		for _, n := range append([]sapNotification{notification}, notification.Details...) {
+			if n.Message == "" {
+				continue
+			}
+			messages = append(messages, models.SAPMessage{
+				Code:     n.Code,
+				Message:  n.Message,
+				Severity: n.Severity,
+				Target:   n.Target,
+			})
+		}
+	}
+	return messages
+}
+
+// odataErrorBody is the body of an OData error response. The message is a string in v4
+// and a {"lang": ..., "value": ...} object in v2.
+type odataErrorBody struct {
+	Error *struct {
+		models.ODataError
+		Message json.RawMessage `json:"message"`
+	} `json:"error"`
+}
+
+// sapErrorDetail is an entry of innererror.errordetails, the messages SAP Gateway adds to
+// an error response besides the top-level message, typically one per invalid field
+type sapErrorDetail struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Severity    string `json:"severity"`
+	Target      string `json:"target"`
+	PropertyRef string `json:"propertyref"`
+}
+
+// decodeODataError parses the body of an error response, or returns nil if it is not an
+// OData error
+func decodeODataError(body []byte) *models.ODataError {
+	var resp odataErrorBody
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error == nil {
+		return nil
+	}
+	odataErr := resp.Error.ODataError
+	if err := json.Unmarshal(resp.Error.Message, &odataErr.Message); err != nil {
+		var message struct {
+			Value string `json:"value"`
+		}
+		json.Unmarshal(resp.Error.Message, &message)
+		odataErr.Message = message.Value
+	}
+	odataErr.Messages = parseSAPErrorDetails(odataErr.InnerError)
+	return &odataErr
+}
+
+// parseSAPErrorDetails returns the messages of innererror.errordetails of an SAP error
+// response, in the order SAP sent them
+func parseSAPErrorDetails(innerError map[string]interface{}) []models.SAPMessage {
+	raw, ok := innerError["errordetails"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var details []sapErrorDetail
+	if err := json.Unmarshal(data, &details); err != nil {
+		return nil
+	}
+
+	var messages []models.SAPMessage
+	for _, detail := range details {
+		if detail.Message == "" {
+			continue
+		}
+		target := detail.Target
+		if target == "" {
+			// Older Gateway releases name the invalid property only here
+			target = detail.PropertyRef
+		}
+		messages = append(messages, models.SAPMessage{
+			Code:     detail.Code,
+			Message:  detail.Message,
+			Severity: detail.Severity,
+			Target:   target,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/metrics"
+)
+
+// SetSAPStatistics requests the performance statistics of SAP Gateway with every request
+// (sap-statistics: true). The returned timings are logged at debug level and recorded in
+// the metrics for bridge_stats.
+func (c *ODataClient) SetSAPStatistics(enabled bool) {
+	c.sapStatistics.Store(enabled)
+}
+
+// parseSAPStatistics parses the sap-statistics response header, a comma-separated list
+// of timings in milliseconds such as "total=120,fw=3,app=117,gwtotal=98,gwbe=80"
+func parseSAPStatistics(value string) map[string]float64 {
+	stats := make(map[string]float64)
+	for _, part := range strings.Split(value, ",") {
+		name, number, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		ms, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil {
+			continue
+		}
+		stats[strings.TrimSpace(name)] = ms
+	}
+	return stats
+}
+
+// recordSAPStatistics logs and records the statistics of a response next to the duration
+// the bridge measured for the request
+func recordSAPStatistics(req *http.Request, resp *http.Response, duration time.Duration) {
+	stats := parseSAPStatistics(resp.Header.Get(constants.SAPStatisticsHeader))
+	if len(stats) == 0 {
+		return
+	}
+	metrics.Default.ObserveSAPStatistics(stats)
+
+	attrs := []interface{}{"method", req.Method, "url", req.URL.String(), "duration_ms", duration.Milliseconds()}
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attrs = append(attrs, name+"_ms", stats[name])
+	}
+	logger.Debug("SAP statistics", attrs...)
+}
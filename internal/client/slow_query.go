@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// SetSlowQueryThreshold logs queries that take at least this long with their query
+// options and row count (0 disables slow query logging)
+func (c *ODataClient) SetSlowQueryThreshold(threshold time.Duration) {
+	c.slowQueryThreshold.Store(int64(threshold))
+}
+
+// logSlowQuery warns about a query of an entity set that reached the slow query
+// threshold, naming the options the agent passed and the number of rows returned
+func (c *ODataClient) logSlowQuery(ctx context.Context, entitySet string, options map[string]string, response *models.ODataResponse, started time.Time) {
+	duration := time.Since(started)
+	threshold := time.Duration(c.slowQueryThreshold.Load())
+	if threshold <= 0 || duration < threshold {
+		return
+	}
+
+	// Options are logged unescaped and sorted, as the agent wrote them
+	params := make([]string, 0, len(options))
+	for key, value := range options {
+		if value != "" {
+			params = append(params, key+"="+value)
+		}
+	}
+	sort.Strings(params)
+	attrs := []interface{}{
+		"entity_set", entitySet,
+		"options", strings.Join(params, "&"),
+	}
+	// Raw responses (nil) are not decoded, so their rows are unknown
+	if response != nil {
+		attrs = append(attrs, "rows", rowCount(response))
+	}
+	attrs = append(attrs, "duration_ms", duration.Milliseconds(), "threshold_ms", threshold.Milliseconds())
+	if response != nil && response.Count != nil {
+		attrs = append(attrs, "total_count", *response.Count)
+	}
+	if id := RequestIDFrom(ctx); id != "" {
+		attrs = append(attrs, "request_id", id)
+	}
+	logger.Warn("Slow OData query", attrs...)
+}
+
+// rowCount returns the number of entities of a response
+func rowCount(response *models.ODataResponse) int {
+	if response == nil || response.Value == nil {
+		return 0
+	}
+	if items, ok := response.Value.([]interface{}); ok {
+		return len(items)
+	}
+	return 1
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// GetMetadataWithProbes fetches $metadata and, concurrently, the service document and a
+// CSRF token, so that startup against a slow service waits for one round trip instead of
+// several: the service document replaces metadata that cannot be parsed without a
+// further request, and the token fetch opens the SAP session whose cookies later
+// requests reuse. Failed probes are logged and otherwise ignored.
+func (c *ODataClient) GetMetadataWithProbes(ctx context.Context) (*models.ODataMetadata, error) {
+	// The probe requests are built before the parsed metadata is applied to the client
+	docReq, err := c.serviceDocumentRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokenReq, err := c.csrfTokenRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var probes sync.WaitGroup
+	var doc *models.ODataMetadata
+	var docErr error
+	probes.Add(2)
+	go func() {
+		defer probes.Done()
+		doc, docErr = c.readServiceDocument(docReq)
+		if docErr != nil {
+			c.logf("debug", "Service document probe failed: %v", docErr)
+		}
+	}()
+	go func() {
+		defer probes.Done()
+		if err := c.sendCSRFTokenRequest(tokenReq); err != nil {
+			c.logf("debug", "CSRF token probe failed: %v", err)
+		}
+	}()
+	// Later requests must not race with the probes, so they finish before returning
+	defer probes.Wait()
+
+	return c.getMetadata(ctx, func() (*models.ODataMetadata, error) {
+		probes.Wait()
+		return doc, docErr
+	})
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TransportOptions tune the connections to the OData service. Zero values keep the
+// defaults of Go's http.DefaultTransport.
+type TransportOptions struct {
+	TCPKeepAlive        time.Duration // Interval of TCP keep-alive probes
+	IdleConnTimeout     time.Duration // How long an idle connection is kept for reuse
+	MaxIdleConns        int           // Idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // Idle connections kept per host
+	TLSSessionCache     int           // TLS sessions kept for resumption (0 = no resumption)
+	DisableHTTP2        bool          // Use HTTP/1.1 even if the service offers HTTP/2
+}
+
+// transports are the transports created for options; clients with the same options, such
+// as the sessions of the HTTP transport, share their connection pool
+var transports sync.Map // TransportOptions -> *http.Transport
+
+// SetTransport applies transport options to the connections of the client
+func (c *ODataClient) SetTransport(options TransportOptions) {
+	if options == (TransportOptions{}) {
+		return
+	}
+	if transport, ok := transports.Load(options); ok {
+		c.httpClient.Transport = transport.(*http.Transport)
+		return
+	}
+	transport, _ := transports.LoadOrStore(options, newTransport(options))
+	c.httpClient.Transport = transport.(*http.Transport)
+}
+
+// newTransport creates a transport with options
+func newTransport(options TransportOptions) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.TCPKeepAlive != 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: options.TCPKeepAlive}
+		transport.DialContext = dialer.DialContext
+	}
+	if options.IdleConnTimeout != 0 {
+		transport.IdleConnTimeout = options.IdleConnTimeout
+	}
+	if options.MaxIdleConns != 0 {
+		transport.MaxIdleConns = options.MaxIdleConns
+	}
+	if options.MaxIdleConnsPerHost != 0 {
+		transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	}
+	if options.TLSSessionCache > 0 {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(options.TLSSessionCache)
+	}
+	if options.DisableHTTP2 {
+		// A non-nil empty TLSNextProto turns off the automatic HTTP/2 upgrade
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
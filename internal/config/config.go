@@ -1,9 +1,29 @@
 package config
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/utils"
+)
+
 // Config holds all configuration options for the OData MCP bridge
 type Config struct {
+	// YAML or JSON file with flag values (--config) and the named profile to use from it
+	ConfigFile string `mapstructure:"config"`
+	Profile    string `mapstructure:"profile"`
+
+	// Env files loaded into the environment in order (--env-file, default ./.env)
+	EnvFiles []string `mapstructure:"env_files"`
+
 	// Service configuration
-	ServiceURL string `mapstructure:"service_url"`
+	ServiceURL       string `mapstructure:"service_url"`
+	MetadataFile     string `mapstructure:"metadata_file"`      // Load metadata from this file instead of $metadata
+	MetadataCacheDir string `mapstructure:"metadata_cache_dir"` // Keep parsed metadata here, revalidated by ETag/Last-Modified
+	MaxMetadataSize  int    `mapstructure:"max_metadata_size"`  // Fail startup when $metadata exceeds this many bytes (0 = unlimited)
 
 	// Authentication
 	Username     string            `mapstructure:"username"`
@@ -18,17 +38,141 @@ type Config struct {
 	NoPostfix   bool   `mapstructure:"no_postfix"`
 	ToolShrink  bool   `mapstructure:"tool_shrink"`
 
+	ToolNameMaxLength  int    `mapstructure:"tool_name_max_length"` // Length budget for generated tool names
+	ToolNameCollisions string `mapstructure:"tool_name_collisions"` // How colliding tool names are told apart: suffix or namespace
+
 	// Entity and function filtering
 	Entities         string   `mapstructure:"entities"`
 	Functions        string   `mapstructure:"functions"`
 	AllowedEntities  []string // Parsed from Entities
 	AllowedFunctions []string // Parsed from Functions
 
+	// Operation filtering
+	Operations        string          `mapstructure:"operations"` // Enabled operation classes (e.g. "cr")
+	DisableOperations string          `mapstructure:"disable"`    // Disabled operations (e.g. "delete,update")
+	EnabledOperations map[string]bool // Parsed from Operations/DisableOperations (nil = all enabled)
+
+	// Per-entity operation rules (e.g. "Products:read, Orders:read+create")
+	EntityOperations     string                `mapstructure:"entity_operations"`
+	EntityOperationsFile string                `mapstructure:"entity_operations_file"`
+	EntityOperationRules []EntityOperationRule // Parsed from EntityOperations/EntityOperationsFile
+
+	// Compiled entity, function, field and rule patterns (see CompilePatterns)
+	Patterns *utils.Patterns
+
+	// Tool count limit; above it entity tools are registered on demand
+	MaxTools int `mapstructure:"max_tools"`
+
+	// Compact mode: generic tools taking the entity set name as a parameter
+	CompactTools bool `mapstructure:"compact_tools"`
+
+	// Operations that require user confirmation via MCP elicitation (e.g. "delete,update")
+	ConfirmOperations   string          `mapstructure:"confirm"`
+	ConfirmedOperations map[string]bool // Parsed from ConfirmOperations
+
+	// Return the requests tools would send instead of sending them
+	DryRun bool `mapstructure:"dry_run"`
+
+	// Append a record of every modifying operation to this file
+	AuditLog string `mapstructure:"audit_log"`
+
+	// Number of tool calls executed in parallel (0 = unbounded)
+	MaxConcurrentCalls int `mapstructure:"max_concurrent_calls"`
+
+	// Maximum size in bytes of JSON-RPC messages; larger ones are answered with an error
+	MaxMessageSize int `mapstructure:"max_message_size"`
+
+	// Split queries with a larger $top into pages of this many entities (0 = off),
+	// fetched by PageWorkers concurrent requests
+	PageSize    int `mapstructure:"page_size"`
+	PageWorkers int `mapstructure:"page_workers"`
+
+	// Generate bulk create tools sending entities in $batch changesets of BulkChangesetSize,
+	// BulkWorkers of them concurrently
+	BulkCreate        bool `mapstructure:"bulk_create"`
+	BulkChangesetSize int  `mapstructure:"bulk_changeset_size"`
+	BulkWorkers       int  `mapstructure:"bulk_workers"`
+
+	// Generate the aggregate_across tool querying AggregateWorkers entity sets concurrently
+	AggregateAcross  bool `mapstructure:"aggregate_across"`
+	AggregateWorkers int  `mapstructure:"aggregate_workers"`
+
+	// Generate value help tools for properties whose value help entity set is included
+	ValueHelp bool `mapstructure:"value_help"`
+
+	// Generate the execute_changeset tool running create, update and delete operations
+	// atomically in one $batch changeset
+	ChangesetTool bool `mapstructure:"changeset_tool"`
+
+	// Maximum execution time of a single tool call (0 = no limit)
+	ToolTimeout time.Duration `mapstructure:"tool_timeout"`
+
+	// Log entity set queries taking at least this long (0 = off)
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+
+	// Request SAP Gateway performance statistics (sap-statistics) with every request
+	SAPStatistics bool `mapstructure:"sap_statistics"`
+
+	// Number of recent requests kept for the debug_recent_requests tool (0 = off)
+	DebugRequests int `mapstructure:"debug_requests"`
+
+	// Session keepalive: ping interval and idle timeout (0 = disabled)
+	KeepAliveInterval time.Duration `mapstructure:"keepalive_interval"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+
+	// Fetch the service document and a CSRF token concurrently with the first $metadata
+	StartupProbes bool `mapstructure:"startup_probes"`
+
+	// Reject queries with neither $filter nor $top of entity sets with more entities (0 = off)
+	MaxUnfilteredRows int `mapstructure:"max_unfiltered_rows"`
+
+	// Reuse entity counts per entity set and filter for this long (0 = always query)
+	CountCacheTTL time.Duration `mapstructure:"count_cache_ttl"`
+
+	// Connections to the OData service: TCP keep-alive interval, idle connection pool,
+	// TLS sessions kept for resumption and HTTP/2 (0 = Go defaults)
+	TCPKeepAlive        time.Duration `mapstructure:"tcp_keepalive"`
+	IdleConnTimeout     time.Duration `mapstructure:"idle_conn_timeout"`
+	MaxIdleConns        int           `mapstructure:"max_idle_conns"`
+	MaxIdleConnsPerHost int           `mapstructure:"max_idle_conns_per_host"`
+	TLSSessionCache     int           `mapstructure:"tls_session_cache"`
+	DisableHTTP2        bool          `mapstructure:"disable_http2"`
+
+	// Retry loading the metadata at startup for this long while the service is unavailable (0 = one attempt)
+	StartupTimeout time.Duration `mapstructure:"startup_timeout"`
+
+	// Re-fetch metadata and regenerate tools at this interval (0 = disabled)
+	MetadataRefreshInterval time.Duration `mapstructure:"metadata_refresh_interval"`
+
+	// Apply changes of the config and .env files at runtime
+	WatchConfig bool `mapstructure:"watch_config"`
+
+	// Transport: "stdio" (default) or "http" for multiple network clients
+	Transport       string `mapstructure:"transport"`
+	HTTPAddr        string `mapstructure:"http_addr"`
+	PassThroughAuth bool   `mapstructure:"pass_through_auth"` // Forward each client's Authorization header to the service
+	MaxSessions     int    `mapstructure:"max_sessions"`      // Open HTTP sessions; the longest idle one is closed for a new one (0 = unlimited)
+
+	// When to add $format=json to queries: auto (SAP v2 only), always, never
+	FormatParam string `mapstructure:"format_param"`
+
+	// Page size for tools/list responses (0 = return all tools in one page)
+	ToolsPageSize int `mapstructure:"tools_page_size"`
+
 	// Output and debugging
 	Verbose   bool `mapstructure:"verbose"`
+	VerboseScopes string `mapstructure:"-"` // Subsystems of --verbose=client,metadata whose debug messages are logged (empty = all)
 	Debug     bool `mapstructure:"debug"`
+	LogLevel  string `mapstructure:"log_level"` // Minimum level of log messages (debug, info, warn, error)
+	LogFormat string `mapstructure:"log_format"` // Format of log messages (text, json)
+	LogFile   string `mapstructure:"log_file"`  // Write log messages to this file instead of stderr
+	ErrorFormat string `mapstructure:"error_format"` // Format of fatal startup errors: text or json
+
+	// OpenTelemetry collector receiving spans of tool calls and OData requests (OTLP/HTTP)
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"`
 	SortTools bool `mapstructure:"sort_tools"`
 	Trace     bool `mapstructure:"trace"`
+	ValidateMetadata bool `mapstructure:"validate_metadata"` // Print a metadata diagnostics report and exit
 	
 	// Response enhancement options
 	PaginationHints  bool `mapstructure:"pagination_hints"`   // Add pagination support with hints
@@ -36,10 +180,29 @@ type Config struct {
 	NoLegacyDates    bool `mapstructure:"no_legacy_dates"`    // Disable legacy date format
 	VerboseErrors    bool `mapstructure:"verbose_errors"`     // Detailed error context
 	ResponseMetadata bool `mapstructure:"response_metadata"`  // Include __metadata in responses
+	RawResponses     bool `mapstructure:"raw_responses"`      // Return the JSON bodies of read requests verbatim
 	
 	// Response size limits
 	MaxResponseSize int `mapstructure:"max_response_size"` // Maximum response size in bytes
 	MaxItems        int `mapstructure:"max_items"`         // Maximum number of items in response
+	DefaultTop      int `mapstructure:"default_top"`       // $top of list queries without one (0 = MaxItems)
+	HardTop         int `mapstructure:"hard_top"`          // Larger $top values are lowered to this (0 = no cap)
+
+	// Automatic $select for list queries without one
+	AutoSelect       bool     `mapstructure:"auto_select"`
+	AutoSelectFields string   `mapstructure:"auto_select_fields"`
+	ImportantFields  []string // Parsed from AutoSelectFields
+
+	// List rendering
+	OutputFormat    string `mapstructure:"output_format"`     // Default output_format of filter/search tools
+	TableMaxColumns int    `mapstructure:"table_max_columns"` // Markdown table column limit (0 = unlimited)
+	TableMaxRows    int    `mapstructure:"table_max_rows"`    // Markdown table row limit (0 = unlimited)
+}
+
+// EntityOperationRule restricts the operations generated for entity sets matching Pattern
+type EntityOperationRule struct {
+	Pattern    string          `json:"pattern"`
+	Operations map[string]bool `json:"operations"`
 }
 
 // HasBasicAuth returns true if username and password are configured
@@ -55,4 +218,186 @@ func (c *Config) HasCookieAuth() bool {
 // UsePostfix returns true if tool postfix should be used instead of prefix
 func (c *Config) UsePostfix() bool {
 	return !c.NoPostfix
-}
\ No newline at end of file
+}
+
+// IsOperationEnabled returns true if tools for the given operation should be generated
+func (c *Config) IsOperationEnabled(operation string) bool {
+	if c.EnabledOperations == nil {
+		return true
+	}
+	return c.EnabledOperations[operation]
+}
+
+// ParseOperationFilters builds EnabledOperations from Operations and DisableOperations
+func (c *Config) ParseOperationFilters() error {
+	if c.Operations == "" && c.DisableOperations == "" {
+		c.EnabledOperations = nil
+		return nil
+	}
+
+	enabled := make(map[string]bool)
+	if c.Operations != "" {
+		ops, err := ParseOperations(c.Operations)
+		if err != nil {
+			return fmt.Errorf("invalid --operations value: %w", err)
+		}
+		for op := range ops {
+			enabled[op] = true
+		}
+	} else {
+		for _, ops := range constants.OperationCodes {
+			for _, op := range ops {
+				enabled[op] = true
+			}
+		}
+	}
+
+	if c.DisableOperations != "" {
+		ops, err := ParseOperations(c.DisableOperations)
+		if err != nil {
+			return fmt.Errorf("invalid --disable value: %w", err)
+		}
+		for op := range ops {
+			delete(enabled, op)
+		}
+	}
+
+	c.EnabledOperations = enabled
+	return nil
+}
+
+// ParseConfirmOperations parses ConfirmOperations into ConfirmedOperations
+func (c *Config) ParseConfirmOperations() error {
+	c.ConfirmedOperations = nil
+	if c.ConfirmOperations == "" {
+		return nil
+	}
+
+	ops, err := ParseOperations(c.ConfirmOperations)
+	if err != nil {
+		return fmt.Errorf("invalid --confirm value: %w", err)
+	}
+	c.ConfirmedOperations = ops
+	return nil
+}
+
+// RequiresConfirmation checks if an operation must be confirmed by the user before it runs
+func (c *Config) RequiresConfirmation(operation string) bool {
+	return c.ConfirmedOperations[operation]
+}
+
+// EnabledOperationNames returns the sorted list of enabled operations (nil = all enabled)
+func (c *Config) EnabledOperationNames() []string {
+	if c.EnabledOperations == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.EnabledOperations))
+	for op := range c.EnabledOperations {
+		names = append(names, op)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// operationCodeOrder is the order operation codes are combined in. Requiring it tells
+// code strings ("crud") apart from misspelled names ("card"), which are rejected.
+const operationCodeOrder = "crudfsga"
+
+// ParseOperations expands an operation spec into tool operations.
+// The spec is either a string of operation codes ("cr", "CRUD") or a list of
+// operation names separated by commas or '+' ("read,create", "delete+update").
+func ParseOperations(spec string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	tokens := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ',' || r == '+' || r == ' '
+	})
+
+	for _, token := range tokens {
+		if ops, ok := constants.OperationAliases[token]; ok {
+			for _, op := range ops {
+				result[op] = true
+			}
+			continue
+		}
+
+		last := -1
+		for _, code := range token {
+			ops, ok := constants.OperationCodes[code]
+			position := strings.IndexRune(operationCodeOrder, code)
+			if !ok || position <= last {
+				return nil, fmt.Errorf("unknown operation %q: use names (e.g. \"read,create\") or codes in the order %q (e.g. \"cr\")", token, operationCodeOrder)
+			}
+			last = position
+			for _, op := range ops {
+				result[op] = true
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no operations specified")
+	}
+	return result, nil
+}
+
+// ParseEntityOperationRules parses per-entity operation rules such as
+// "Products:read, Orders:read+create". Rules are separated by commas or newlines;
+// a comma-separated token without a colon extends the previous rule's operations,
+// so "Orders:create,update" is also accepted. Commas are split as in utils.SplitPatterns,
+// so regex patterns may contain them. Lines starting with '#' are ignored.
+func ParseEntityOperationRules(spec string) ([]EntityOperationRule, error) {
+	var rules []EntityOperationRule
+	var specs []string
+
+	for _, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, token := range utils.SplitPatterns(line) {
+			// Operations never contain a colon, while a regex pattern may ("(?:...)")
+			colon := strings.LastIndex(token, ":")
+			if colon < 0 {
+				if len(rules) == 0 {
+					return nil, fmt.Errorf("invalid entity operation rule %q: expected <entity>:<operations>", token)
+				}
+				specs[len(specs)-1] += "," + token
+				continue
+			}
+
+			pattern, ops := strings.TrimSpace(token[:colon]), token[colon+1:]
+			if pattern == "" {
+				return nil, fmt.Errorf("invalid entity operation rule %q: missing entity name", token)
+			}
+			rules = append(rules, EntityOperationRule{Pattern: pattern})
+			specs = append(specs, ops)
+		}
+	}
+
+	for i := range rules {
+		ops, err := ParseOperations(specs[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid operations for entity %s: %w", rules[i].Pattern, err)
+		}
+		rules[i].Operations = ops
+	}
+
+	return rules, nil
+}
+
+// CompilePatterns compiles the entity, function, field and operation rule patterns
+// into Patterns, failing on the first invalid one
+func (c *Config) CompilePatterns() error {
+	patterns := append(append([]string{}, c.AllowedEntities...), c.AllowedFunctions...)
+	patterns = append(patterns, c.ImportantFields...)
+	for _, rule := range c.EntityOperationRules {
+		patterns = append(patterns, rule.Pattern)
+	}
+
+	compiled, err := utils.NewPatterns(patterns...)
+	if err != nil {
+		return err
+	}
+	c.Patterns = compiled
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// EnvName returns the environment variable of a command-line flag, e.g. ODATA_MAX_ITEMS
+// for --max-items
+func EnvName(flag string) string {
+	return "ODATA_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// LoadEnv returns the values of the ODATA_* environment variables of the given flags,
+// keyed by flag name. Empty variables count as unset, as they do for viper.
+func LoadEnv(flags []string) map[string]string {
+	values := make(map[string]string)
+	for _, flag := range flags {
+		if value := os.Getenv(EnvName(flag)); value != "" {
+			values[flag] = value
+		}
+	}
+	return values
+}
+
+// ReadEnvFiles reads env files (--env-file) into one set of variables, later files
+// overriding earlier ones. Missing files are skipped if allowMissing is set.
+func ReadEnvFiles(files []string, allowMissing bool) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, file := range files {
+		fileValues, err := godotenv.Read(file)
+		if err != nil {
+			if allowMissing && os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read env file %s: %w", file, err)
+		}
+		for name, value := range fileValues {
+			values[name] = value
+		}
+	}
+	return values, nil
+}
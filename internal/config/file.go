@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// LoadFile reads a YAML or JSON configuration file (--config) whose keys are the
+// command-line flag names, e.g. service, user, entities, tool-prefix or max-items
+// (underscores are accepted for dashes). It returns the values as flag strings;
+// lists become comma-separated values.
+//
+// Named connection profiles are kept under "profiles"; the values of the selected
+// profile (--profile, or the file's "profile" key) override the top-level ones.
+func LoadFile(path, profile string) (map[string]string, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	settings := v.AllSettings()
+	profiles, _ := settings["profiles"].(map[string]interface{})
+	if profile == "" {
+		profile, _ = settings["profile"].(string)
+	}
+	delete(settings, "profiles")
+	delete(settings, "profile")
+
+	values := make(map[string]string)
+	if err := addFlagValues(values, settings); err != nil {
+		return nil, fmt.Errorf("%w in config file %s", err, path)
+	}
+
+	if profile != "" {
+		// Keys are case-insensitive in viper, so profile names are too
+		selected, ok := profiles[strings.ToLower(profile)].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found in config file %s (available: %s)", profile, path, strings.Join(profileNames(profiles), ", "))
+		}
+		if err := addFlagValues(values, selected); err != nil {
+			return nil, fmt.Errorf("%w in profile %q of config file %s", err, profile, path)
+		}
+	}
+	return values, nil
+}
+
+// addFlagValues formats settings as flag values, replacing those already present
+func addFlagValues(values map[string]string, settings map[string]interface{}) error {
+	for key, value := range settings {
+		text, err := flagValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		values[strings.ReplaceAll(key, "_", "-")] = text
+	}
+	return nil
+}
+
+// profileNames returns the sorted names of the profiles of a config file
+func profileNames(profiles map[string]interface{}) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
+
+// flagValue formats a decoded YAML/JSON value as a flag value
+func flagValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			text, err := flagValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, text)
+		}
+		return strings.Join(items, ","), nil
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("unsupported value %v (%T)", value, value)
+}
@@ -72,6 +72,15 @@ const (
 	CSRFTokenHeaderLower = "x-csrf-token"
 )
 
+// RequestIDHeader carries the correlation ID of a tool call on its requests to the service
+const RequestIDHeader = "X-Request-ID"
+
+// SAPStatisticsHeader requests (with "true") and returns the SAP Gateway performance statistics
+const SAPStatisticsHeader = "sap-statistics"
+
+// SAPMessageHeader carries business messages (warnings, info) on successful SAP responses
+const SAPMessageHeader = "sap-message"
+
 // HTTP headers
 const (
 	ContentType     = "Content-Type"
@@ -80,6 +89,9 @@ const (
 	UserAgent       = "User-Agent"
 	IfMatch         = "If-Match"
 	IfNoneMatch     = "If-None-Match"
+	IfModifiedSince = "If-Modified-Since"
+	ETag            = "ETag"
+	LastModified    = "Last-Modified"
 )
 
 // Content types
@@ -109,8 +121,41 @@ const (
 	OpUpdate = "update"
 	OpDelete = "delete"
 	OpInfo   = "info"
+
+	OpBulkCreate = "bulk_create"
+	OpFunction   = "function"
+	OpValueHelp  = "value_help"
+	OpChangeset  = "changeset"
 )
 
+// OperationCodes maps single-letter operation codes (used by --operations and
+// --disable) to the tool operations they control
+var OperationCodes = map[rune][]string{
+	'c': {OpCreate},
+	'r': {OpFilter, OpCount, OpSearch, OpGet},
+	'u': {OpUpdate},
+	'd': {OpDelete},
+	'f': {OpFilter, OpCount},
+	's': {OpSearch},
+	'g': {OpGet},
+	'a': {OpFunction},
+}
+
+// OperationAliases maps long operation names to the tool operations they control
+var OperationAliases = map[string][]string{
+	"create":    {OpCreate},
+	"read":      {OpFilter, OpCount, OpSearch, OpGet},
+	"update":    {OpUpdate},
+	"delete":    {OpDelete},
+	"filter":    {OpFilter, OpCount},
+	"count":     {OpCount},
+	"search":    {OpSearch},
+	"get":       {OpGet},
+	"function":  {OpFunction},
+	"functions": {OpFunction},
+	"actions":   {OpFunction},
+}
+
 // Tool operation names (for shrinking)
 var ToolOperationNames = map[string]string{
 	OpFilter: "filter",
@@ -121,6 +166,9 @@ var ToolOperationNames = map[string]string{
 	OpUpdate: "update",
 	OpDelete: "delete",
 	OpInfo:   "info",
+
+	OpBulkCreate: "bulk_create",
+	OpValueHelp:  "value_help",
 }
 
 // Shortened tool operation names
@@ -133,6 +181,9 @@ var ShortenedToolOperationNames = map[string]string{
 	OpUpdate: "upd",
 	OpDelete: "del",
 	OpInfo:   "info",
+
+	OpBulkCreate: "bulk_create",
+	OpValueHelp:  "vh",
 }
 
 // Error messages
@@ -153,17 +204,79 @@ const (
 	DefaultUserAgent          = "OData-MCP-Bridge/1.0 (Go)"
 	DefaultTimeout            = 30 // seconds
 	DefaultMaxResponseSize    = 10 * 1024 * 1024 // 10MB
+	DefaultMaxMessageSize     = 10 * 1024 * 1024 // 10MB, JSON-RPC requests on stdio
 	DefaultMaxItems           = 1000
+	ResponseBodySizeFactor    = 4 // Backend bodies may exceed --max-response-size by this factor before stripping and truncation
 	DefaultToolNameMaxLength  = 64
+	MinToolNameCoreLength     = 16 // Minimum length kept for the operation/entity part of a tool name
+	DefaultMaxConcurrentCalls = 4  // Tool calls executing in parallel
+	DefaultPageWorkers        = 4  // Pages of a split query fetched in parallel
+	DefaultBulkChangesetSize  = 100 // Entities per $batch changeset of bulk create tools
+	DefaultBulkWorkers        = 4  // Changesets of a bulk create sent in parallel
+	DefaultAggregateWorkers   = 4  // Entity sets of an aggregate_across call queried in parallel
+	DefaultSubtreeMaxNodes    = 1000 // Nodes a hierarchy subtree query returns without $top
+	DefaultCountCacheTTL      = 30 // seconds an entity count is reused (--count-cache-ttl)
+	DefaultTCPKeepAlive       = 30 // seconds between TCP keep-alive probes to the service
+	DefaultIdleConnTimeout    = 90 // seconds an idle connection to the service is kept
+	DefaultMaxIdleConns       = 100 // Idle connections kept across all hosts
+	DefaultIdleConnsPerHost   = 2  // Idle connections kept per host
+	DefaultTableMaxColumns    = 10 // Columns of Markdown tables before the rest is omitted
+	DefaultTableMaxRows       = 50 // Rows of Markdown tables before the rest is omitted
+	DefaultAutoSelectFields   = "*Name,*Description,*Text,*Status" // Property names or sap:labels selected by --auto-select
+	ConfigWatchInterval       = 2  // seconds between checks of the config and .env files (--watch-config)
 )
 
 // MCP-specific constants
 const (
-	MCPProtocolVersion = "2024-11-05"
+	MCPProtocolVersion = "2025-06-18" // Latest supported protocol version
 	MCPServerName      = "odata-mcp-bridge"
 	MCPServerVersion   = "1.0.0"
 )
 
+// $format injection modes for --format-param
+const (
+	FormatParamAuto   = "auto"   // Only for SAP OData v2 services
+	FormatParamAlways = "always"
+	FormatParamNever  = "never"
+)
+
+// Tool name collision resolution modes for --tool-name-collisions
+const (
+	ToolNameCollisionsSuffix    = "suffix"    // Append _2, _3, ...
+	ToolNameCollisionsNamespace = "namespace" // Prefix the schema namespace, then append a number if still taken
+)
+
+// Output formats of list tools (output_format argument)
+const (
+	OutputFormatJSON     = "json"
+	OutputFormatCSV      = "csv"
+	OutputFormatMarkdown = "markdown"
+	OutputFormatColumns  = "columns" // JSON with the property names once: {"columns": [...], "rows": [[...]]}
+)
+
+// MCP transports
+const (
+	TransportStdio     = "stdio"
+	TransportHTTP      = "http"
+	DefaultHTTPAddr    = "localhost:8080"
+	DefaultMaxSessions = 100
+	MCPEndpoint        = "/mcp"
+	MetricsEndpoint    = "/metrics" // Prometheus metrics of the HTTP transport
+	LivenessEndpoint   = "/healthz" // Liveness probe: the process serves HTTP
+	ReadinessEndpoint  = "/readyz"  // Readiness probe: metadata loaded and the service accepts the credentials
+	MCPSessionIDHeader = "Mcp-Session-Id"
+)
+
+// MCP protocol revisions that introduced features the server adapts to
+const (
+	MCPProtocol20241105 = "2024-11-05"
+	MCPProtocol20250326 = "2025-03-26" // Tool annotations, completions, progress messages
+	MCPProtocol20250618 = "2025-06-18" // Structured tool output, elicitation
+)
+
+// MCPSupportedProtocolVersions lists the protocol versions the server negotiates, newest first
+var MCPSupportedProtocolVersions = []string{MCPProtocol20250618, MCPProtocol20250326, MCPProtocol20241105}
+
 // GetGoType returns the Go type for an OData type
 func GetGoType(odataType string) string {
 	if goType, ok := ODataTypeMap[odataType]; ok {
@@ -0,0 +1,310 @@
+// Package doctor diagnoses connectivity and authentication problems with an OData
+// service (odata-mcp doctor)
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// Check outcomes
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Check is the outcome of one diagnostic step
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Report collects the checks run against a service
+type Report struct {
+	ServiceURL string  `json:"service_url"`
+	Checks     []Check `json:"checks"`
+}
+
+// Failed reports whether any check failed
+func (r *Report) Failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// doctor runs the checks in order; later checks are skipped once one fails
+type doctor struct {
+	cfg      *config.Config
+	client   *client.ODataClient
+	report   *Report
+	metadata *models.ODataMetadata
+}
+
+// Run checks DNS resolution, the TCP/TLS connection, authentication, $metadata retrieval,
+// CSRF token handling and JSON support of the configured service. The requests are sent
+// by an OData client configured like the bridge's, so that they see the same transport
+// and credentials.
+func Run(ctx context.Context, cfg *config.Config) *Report {
+	d := &doctor{
+		cfg:    cfg,
+		client: bridge.NewClient(cfg),
+		report: &Report{ServiceURL: cfg.ServiceURL},
+	}
+
+	steps := []struct {
+		name string
+		run  func(ctx context.Context) (string, string)
+	}{
+		{"DNS", d.checkDNS},
+		{"Connection", d.checkConnection},
+		{"Authentication", d.checkAuthentication},
+		{"Metadata", d.checkMetadata},
+		{"CSRF token", d.checkCSRF},
+		{"JSON format", d.checkJSON},
+	}
+
+	failed := false
+	for _, step := range steps {
+		if failed {
+			d.add(step.name, StatusSkip, "skipped after the previous failure")
+			continue
+		}
+		status, detail := step.run(ctx)
+		d.add(step.name, status, detail)
+		failed = status == StatusFail
+	}
+	return d.report
+}
+
+func (d *doctor) add(name, status, detail string) {
+	d.report.Checks = append(d.report.Checks, Check{Name: name, Status: status, Detail: detail})
+}
+
+// checkDNS resolves the service host
+func (d *doctor) checkDNS(ctx context.Context) (string, string) {
+	u, err := url.Parse(d.cfg.ServiceURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return StatusFail, fmt.Sprintf("%q is not an http(s) URL", d.cfg.ServiceURL)
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+	if err != nil {
+		return StatusFail, fmt.Sprintf("cannot resolve %s: %v (check the host name, VPN and proxy settings)", u.Hostname(), err)
+	}
+	return StatusOK, fmt.Sprintf("%s resolves to %s", u.Hostname(), strings.Join(addrs, ", "))
+}
+
+// checkConnection opens a TCP connection and, for https, completes the TLS handshake
+func (d *doctor) checkConnection(ctx context.Context) (string, string) {
+	u, _ := url.Parse(d.cfg.ServiceURL)
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	address := net.JoinHostPort(u.Hostname(), port)
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	if u.Scheme == "http" {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return StatusFail, fmt.Sprintf("cannot connect to %s: %v", address, err)
+		}
+		conn.Close()
+		if d.cfg.HasBasicAuth() || d.cfg.HasCookieAuth() {
+			return StatusWarn, fmt.Sprintf("connected to %s over plain HTTP; credentials are sent unencrypted", address)
+		}
+		return StatusOK, fmt.Sprintf("connected to %s (plain HTTP)", address)
+	}
+
+	tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}
+	conn, err := tlsDialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return StatusFail, fmt.Sprintf("TLS handshake with %s failed: %v (self-signed or corporate CA certificates must be trusted by the system)", address, err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	detail := fmt.Sprintf("connected to %s, %s", address, tls.VersionName(state.Version))
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		detail += fmt.Sprintf(", certificate %s valid until %s", cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02"))
+		if time.Until(cert.NotAfter) < 14*24*time.Hour {
+			return StatusWarn, detail + " (expires soon)"
+		}
+	}
+	return StatusOK, detail
+}
+
+// checkAuthentication requests the service document with the configured credentials
+func (d *doctor) checkAuthentication(ctx context.Context) (string, string) {
+	resp, body, err := d.get(ctx, "", nil)
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+
+	method := "anonymous access"
+	switch {
+	case d.cfg.HasBasicAuth():
+		method = fmt.Sprintf("basic authentication as %s", d.cfg.Username)
+	case d.cfg.HasCookieAuth():
+		method = fmt.Sprintf("%d cookies", len(d.cfg.Cookies))
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return StatusFail, fmt.Sprintf("the service rejected %s (HTTP 401); check user and password or refresh the cookies", method)
+	case resp.StatusCode == http.StatusForbidden:
+		return StatusFail, fmt.Sprintf("%s is not authorized for this service (HTTP 403); check the user's roles", method)
+	case resp.StatusCode >= 400:
+		return StatusFail, fmt.Sprintf("the service document returned HTTP %d: %s", resp.StatusCode, snippet(body))
+	case isHTML(resp, body):
+		return StatusFail, fmt.Sprintf("the service returned an HTML page (final URL %s), typically a login or SSO redirect; use cookie authentication from a browser session", resp.Request.URL)
+	}
+	return StatusOK, fmt.Sprintf("service document returned HTTP %d with %s", resp.StatusCode, method)
+}
+
+// checkMetadata retrieves and parses $metadata
+func (d *doctor) checkMetadata(ctx context.Context) (string, string) {
+	headers := map[string]string{constants.Accept: constants.ContentTypeXML}
+	resp, body, err := d.get(ctx, constants.MetadataEndpoint, headers)
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return StatusFail, fmt.Sprintf("$metadata returned HTTP %d: %s", resp.StatusCode, snippet(body))
+	}
+
+	meta, err := metadata.ParseMetadata(body, d.cfg.ServiceURL)
+	if err != nil {
+		return StatusFail, fmt.Sprintf("$metadata (%d bytes) could not be parsed: %v", len(body), err)
+	}
+	d.metadata = meta
+
+	flavor := "OData v2"
+	if meta.Version == "4.0" || meta.Version == "4.01" {
+		flavor = "OData v4"
+	}
+	if meta.IsSAP {
+		flavor = "SAP " + flavor
+	}
+	detail := fmt.Sprintf("%s, %d bytes, %d entity sets, %d function imports", flavor, len(body), len(meta.EntitySets), len(meta.FunctionImports))
+	if len(meta.EntitySets) == 0 {
+		return StatusWarn, detail + "; no entity tools will be generated"
+	}
+	return StatusOK, detail
+}
+
+// checkCSRF fetches a CSRF token as the bridge does before modifying requests
+func (d *doctor) checkCSRF(ctx context.Context) (string, string) {
+	headers := map[string]string{constants.CSRFTokenHeader: constants.CSRFTokenFetch}
+	resp, _, err := d.get(ctx, "", headers)
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+
+	token := resp.Header.Get(constants.CSRFTokenHeader)
+	switch {
+	case token != "" && !strings.EqualFold(token, "Required"):
+		cookies := len(resp.Cookies())
+		return StatusOK, fmt.Sprintf("token issued with %d session cookies; create, update, delete and POST functions can run", cookies)
+	case d.metadata != nil && d.metadata.IsSAP:
+		return StatusWarn, "the SAP service issued no CSRF token; modifying operations are likely to fail with HTTP 403"
+	}
+	return StatusOK, "no CSRF token issued; the service does not appear to use CSRF protection"
+}
+
+// checkJSON queries one entity of an entity set and checks the response is JSON, with
+// the Accept header alone and with $format=json
+func (d *doctor) checkJSON(ctx context.Context) (string, string) {
+	if d.metadata == nil || len(d.metadata.EntitySets) == 0 {
+		return StatusSkip, "no entity set to query"
+	}
+	names := make([]string, 0, len(d.metadata.EntitySets))
+	for name := range d.metadata.EntitySets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entitySet := names[0]
+
+	accept := map[string]string{constants.Accept: constants.ContentTypeJSON}
+	query := func(params string) (bool, string, error) {
+		resp, body, err := d.get(ctx, entitySet+"?"+constants.QueryTop+"=1"+params, accept)
+		if err != nil {
+			return false, "", err
+		}
+		if resp.StatusCode >= 400 {
+			return false, fmt.Sprintf("HTTP %d: %s", resp.StatusCode, snippet(body)), nil
+		}
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return false, fmt.Sprintf("%s response is not JSON", resp.Header.Get(constants.ContentType)), nil
+		}
+		return true, "", nil
+	}
+
+	ok, problem, err := query("")
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+	if ok {
+		return StatusOK, fmt.Sprintf("%s returns JSON for Accept: application/json", entitySet)
+	}
+	okFormat, problemFormat, err := query("&" + constants.QueryFormat + "=json")
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+	if okFormat {
+		return StatusWarn, fmt.Sprintf("%s returns JSON only with $format=json (%s); use --format-param always", entitySet, problem)
+	}
+	return StatusFail, fmt.Sprintf("querying %s did not return JSON: %s; with $format=json: %s", entitySet, problem, problemFormat)
+}
+
+// get sends a GET request to the service, asking for JSON unless headers say otherwise
+func (d *doctor) get(ctx context.Context, endpoint string, headers map[string]string) (*http.Response, []byte, error) {
+	merged := map[string]string{constants.Accept: constants.ContentTypeJSON}
+	for name, value := range headers {
+		merged[name] = value
+	}
+	return d.client.Probe(ctx, endpoint, merged)
+}
+
+// isHTML reports whether a response is an HTML page rather than OData
+func isHTML(resp *http.Response, body []byte) bool {
+	if strings.Contains(resp.Header.Get(constants.ContentType), "text/html") {
+		return true
+	}
+	start := strings.ToLower(strings.TrimSpace(string(body[:min(len(body), 100)])))
+	return strings.HasPrefix(start, "<!doctype html") || strings.HasPrefix(start, "<html")
+}
+
+// snippet shortens a response body for the report
+func snippet(body []byte) string {
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if len(text) > 200 {
+		return text[:200] + "..."
+	}
+	return text
+}
@@ -0,0 +1,146 @@
+// Package logging configures the leveled logger of the bridge. Log records are written
+// through log/slog to stderr or, with --log-file, to a file, so that MCP clients which
+// surface stderr to users only see warnings and errors by default.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// DefaultLevel is the minimum level logged when neither --log-level nor --verbose is set
+const DefaultLevel = "warn"
+
+// Levels are the accepted values of --log-level
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// Values of --log-format
+const (
+	FormatText = "text" // logfmt-style key=value lines
+	FormatJSON = "json" // One JSON object per line, for log collectors such as Splunk or ELK
+)
+
+// Formats are the accepted values of --log-format
+var Formats = []string{FormatText, FormatJSON}
+
+var level = new(slog.LevelVar)
+
+func init() {
+	level.Set(slog.LevelWarn)
+}
+
+// Init makes the default slog logger write text records at or above the warn level to
+// stderr until Configure is called
+func Init() {
+	setOutput(os.Stderr, FormatText)
+}
+
+// ParseLevel parses a --log-level value
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level '%s': must be one of %s", name, strings.Join(Levels, ", "))
+	}
+}
+
+// SetLevel changes the minimum level of logged records, e.g. when --watch-config
+// reloads --log-level or --verbose
+func SetLevel(name string) error {
+	l, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// Configure sets the minimum level and the format (text or json) of logged records and,
+// if file is not empty, appends them to that file instead of writing them to stderr. The
+// returned function closes the file.
+func Configure(name, format, file string) (func() error, error) {
+	if err := SetLevel(name); err != nil {
+		return nil, err
+	}
+	if format != FormatText && format != FormatJSON {
+		return nil, fmt.Errorf("invalid log format '%s': must be one of %s", format, strings.Join(Formats, ", "))
+	}
+	if file == "" {
+		setOutput(os.Stderr, format)
+		return func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	setOutput(f, format)
+	return f.Close, nil
+}
+
+// redactAttr removes credentials from the message and attributes of log records:
+// attributes named like credentials (token, cookie, password, ...) are masked, headers
+// lose their credential values and other text is cleaned by redact.String
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if redact.IsSensitiveKey(a.Key) {
+			return slog.String(a.Key, redact.Mask)
+		}
+		return slog.String(a.Key, redact.String(a.Value.String()))
+	case slog.KindAny:
+		if redact.IsSensitiveKey(a.Key) {
+			return slog.String(a.Key, redact.Mask)
+		}
+		switch v := a.Value.Any().(type) {
+		case http.Header:
+			return slog.Any(a.Key, redact.Header(v))
+		case error:
+			return slog.String(a.Key, redact.String(v.Error()))
+		default:
+			// Other values keep their type unless their text contains credentials
+			text := fmt.Sprint(v)
+			if redacted := redact.String(text); redacted != text {
+				return slog.String(a.Key, redacted)
+			}
+		}
+	}
+	return a
+}
+
+// FromMCP maps an MCP logging level (RFC 5424 severity name) to a slog level
+func FromMCP(name string) slog.Level {
+	switch name {
+	case "debug":
+		return slog.LevelDebug
+	case "info", "notice":
+		return slog.LevelInfo
+	case "warning":
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// setOutput makes the default logger write records in a format to w
+func setOutput(w io.Writer, format string) {
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	if format == FormatJSON {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(w, options)))
+		return
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, options)))
+}
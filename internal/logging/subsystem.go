@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// Subsystems of the bridge that log debug messages; --verbose=client,metadata limits the
+// debug messages to these subsystems
+const (
+	SubsystemClient   = "client"   // HTTP requests to the OData service, CSRF tokens, cookies
+	SubsystemMetadata = "metadata" // Loading and parsing of the service metadata
+	SubsystemTools    = "tools"    // Tool and resource generation
+	SubsystemMCP      = "mcp"      // MCP sessions and transports
+	SubsystemConfig   = "config"   // Flags, config and env files, authentication
+	SubsystemTracing  = "tracing"  // Export of trace spans
+)
+
+// Subsystems are the accepted values of --verbose=<subsystems>
+var Subsystems = []string{SubsystemClient, SubsystemMetadata, SubsystemTools, SubsystemMCP, SubsystemConfig, SubsystemTracing}
+
+// scopes are the subsystems whose debug messages are logged; nil logs all
+var scopes atomic.Pointer[map[string]bool]
+
+// ParseScopes parses a comma-separated list of subsystems; an empty list selects all
+func ParseScopes(list string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !isSubsystem(name) {
+			return nil, fmt.Errorf("invalid subsystem '%s': must be one of %s", name, strings.Join(Subsystems, ", "))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// SetScopes limits debug messages to the subsystems of a comma-separated list, or logs
+// the debug messages of all subsystems if the list is empty. Messages at the info level
+// and above are always logged.
+func SetScopes(list string) error {
+	names, err := ParseScopes(list)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		scopes.Store(nil)
+		return nil
+	}
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = true
+	}
+	scopes.Store(&selected)
+	return nil
+}
+
+// inScope reports whether the debug messages of a subsystem are logged
+func inScope(subsystem string) bool {
+	selected := scopes.Load()
+	return selected == nil || (*selected)[subsystem]
+}
+
+func isSubsystem(name string) bool {
+	for _, s := range Subsystems {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Subsystem returns a logger for the messages of a subsystem. Its records carry a
+// subsystem attribute and are written by the current default logger, so loggers created
+// before Configure follow the configured output.
+func Subsystem(name string) *slog.Logger {
+	return slog.New(&subsystemHandler{subsystem: name, wrap: func(h slog.Handler) slog.Handler { return h }})
+}
+
+// subsystemHandler drops debug records of subsystems out of scope and passes the other
+// records to the handler of the default logger
+type subsystemHandler struct {
+	subsystem string
+	wrap      func(slog.Handler) slog.Handler // Applies the attributes and groups of With
+}
+
+func (h *subsystemHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if l < slog.LevelInfo && !inScope(h.subsystem) {
+		return false
+	}
+	return slog.Default().Handler().Enabled(ctx, l)
+}
+
+func (h *subsystemHandler) Handle(ctx context.Context, r slog.Record) error {
+	handler := slog.Default().Handler().WithAttrs([]slog.Attr{slog.String("subsystem", h.subsystem)})
+	return h.wrap(handler).Handle(ctx, r)
+}
+
+func (h *subsystemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	wrap := h.wrap
+	return &subsystemHandler{subsystem: h.subsystem, wrap: func(next slog.Handler) slog.Handler {
+		return wrap(next).WithAttrs(attrs)
+	}}
+}
+
+func (h *subsystemHandler) WithGroup(name string) slog.Handler {
+	wrap := h.wrap
+	return &subsystemHandler{subsystem: h.subsystem, wrap: func(next slog.Handler) slog.Handler {
+		return wrap(next).WithGroup(name)
+	}}
+}
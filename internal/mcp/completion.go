@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxCompletionValues is the maximum number of values in a completion response
+const maxCompletionValues = 100
+
+// CompletionRequest describes the argument being completed. Ref is the
+// reference from the request: {"type": "ref/resource", "uri": ...},
+// {"type": "ref/prompt", "name": ...} or, for tool arguments, {"type": "ref/tool",
+// "name": ...}. ref/tool is an extension of this server; MCP defines only the
+// prompt and resource references.
+type CompletionRequest struct {
+	Ref       map[string]interface{}
+	Argument  string
+	Value     string
+	Arguments map[string]string // Already resolved arguments from the request context
+}
+
+// CompletionHandler returns completion candidates for an argument
+type CompletionHandler func(ctx context.Context, req *CompletionRequest) ([]string, error)
+
+// SetCompletionHandler sets the handler for completion/complete requests
+func (s *Server) SetCompletionHandler(handler CompletionHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.completionHandler = handler
+}
+
+// handleComplete handles the completion/complete request
+func (s *Server) handleComplete(req *Request) error {
+	ref, _ := req.Params["ref"].(map[string]interface{})
+	argument, _ := req.Params["argument"].(map[string]interface{})
+	if ref == nil || argument == nil {
+		return s.sendError(req.ID, -32602, "Invalid params", "Missing ref or argument")
+	}
+
+	completion := &CompletionRequest{
+		Ref:       ref,
+		Arguments: make(map[string]string),
+	}
+	completion.Argument, _ = argument["name"].(string)
+	completion.Value, _ = argument["value"].(string)
+	if ctxParams, ok := req.Params["context"].(map[string]interface{}); ok {
+		if args, ok := ctxParams["arguments"].(map[string]interface{}); ok {
+			for name, value := range args {
+				completion.Arguments[name] = fmt.Sprintf("%v", value)
+			}
+		}
+	}
+
+	// Tool arguments with an enum in the input schema complete from the enum
+	values := s.enumCompletions(completion)
+	if values == nil {
+		s.mu.RLock()
+		handler := s.completionHandler
+		s.mu.RUnlock()
+
+		if handler != nil {
+			var err error
+			values, err = handler(s.ctx, completion)
+			if err != nil {
+				return s.sendError(req.ID, -32602, "Invalid params", err.Error())
+			}
+		}
+	}
+	if values == nil {
+		values = []string{}
+	}
+
+	total := len(values)
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+	}
+
+	result := map[string]interface{}{
+		"completion": map[string]interface{}{
+			"values":  values,
+			"total":   total,
+			"hasMore": total > len(values),
+		},
+	}
+	return s.sendResponse(req.ID, result)
+}
+
+// enumCompletions completes a tool argument from the enum of its input schema, or returns nil
+func (s *Server) enumCompletions(req *CompletionRequest) []string {
+	if req.Ref["type"] != "ref/tool" {
+		return nil
+	}
+	name, _ := req.Ref["name"].(string)
+
+	s.mu.RLock()
+	tool := s.tools[name]
+	s.mu.RUnlock()
+	if tool == nil {
+		return nil
+	}
+
+	properties, _ := tool.InputSchema["properties"].(map[string]interface{})
+	property, _ := properties[req.Argument].(map[string]interface{})
+	if property == nil {
+		return nil
+	}
+
+	enum := schemaStrings(property["enum"])
+	if enum == nil {
+		return nil
+	}
+	return FilterCompletions(enum, req.Value)
+}
+
+// FilterCompletions returns the candidates starting with prefix (case-insensitive)
+func FilterCompletions(candidates []string, prefix string) []string {
+	values := []string{}
+	lowerPrefix := strings.ToLower(prefix)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), lowerPrefix) {
+			values = append(values, candidate)
+		}
+	}
+	return values
+}
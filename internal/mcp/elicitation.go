@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/odata-mcp/go/internal/constants"
+)
+
+// ErrElicitationUnsupported is returned by Elicit when the client did not declare the
+// elicitation capability or negotiated a protocol version without elicitation
+var ErrElicitationUnsupported = errors.New("client does not support elicitation")
+
+// ElicitResult is the client's answer to an elicitation request
+type ElicitResult struct {
+	Action  string                 `json:"action"` // "accept", "decline" or "cancel"
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// clientResponse is a JSON-RPC response sent by the client to a server-initiated request
+type clientResponse struct {
+	ID     interface{}     `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// ClientSupports reports whether the client declared a capability (e.g. "elicitation") in initialize
+func (s *Server) ClientSupports(capability string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, supported := s.clientCapabilities[capability]
+	return supported
+}
+
+// Elicit asks the user for input through the client. The requested schema is
+// a flat JSON object schema with primitive properties.
+func (s *Server) Elicit(ctx context.Context, message string, requestedSchema map[string]interface{}) (*ElicitResult, error) {
+	if !s.ClientSupports("elicitation") || !s.protocolAtLeast(constants.MCPProtocol20250618) {
+		return nil, ErrElicitationUnsupported
+	}
+
+	raw, err := s.request(ctx, "elicitation/create", map[string]interface{}{
+		"message":         message,
+		"requestedSchema": requestedSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result ElicitResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid elicitation response: %w", err)
+	}
+	return &result, nil
+}
+
+// request sends a server-initiated JSON-RPC request and waits for the client's response
+func (s *Server) request(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+	s.mu.Lock()
+	s.nextRequestID++
+	id := fmt.Sprintf("server-%d", s.nextRequestID)
+	responses := make(chan *clientResponse, 1)
+	s.pending[requestKey(id)] = responses
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, requestKey(id))
+		s.mu.Unlock()
+	}()
+
+	data, err := json.Marshal(Request{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeMessage(data); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-responses:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s failed: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// handleClientResponse routes a response from the client to the waiting server-initiated request
+func (s *Server) handleClientResponse(line string) error {
+	var resp clientResponse
+	if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	responses, waiting := s.pending[requestKey(resp.ID)]
+	s.mu.RUnlock()
+
+	if waiting {
+		responses <- &resp
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/odata-mcp/go/internal/constants"
+)
+
+// ErrMessageTooLarge is returned for JSON-RPC messages larger than the maximum message size
+var ErrMessageTooLarge = errors.New("message exceeds the maximum message size")
+
+// SetMaxMessageSize limits the size in bytes of inbound and outbound JSON-RPC messages;
+// oversize messages are answered with a JSON-RPC error. 0 limits inbound messages to the
+// 10MB default and leaves outbound ones unlimited, as tool results are already bounded
+// by the response size limits.
+func (s *Server) SetMaxMessageSize(size int) {
+	if size <= 0 {
+		s.maxMessageSize.Store(constants.DefaultMaxMessageSize)
+		s.maxOutboundSize.Store(0)
+		return
+	}
+	s.maxMessageSize.Store(int64(size))
+	s.maxOutboundSize.Store(int64(size))
+}
+
+// readMessages reads newline-delimited messages from the input and sends them to lines
+// until the input ends. Lines longer than the maximum message size are discarded and
+// answered with an Invalid request error, and reading continues with the next line.
+func (s *Server) readMessages(lines chan<- string) error {
+	reader := bufio.NewReader(s.input)
+	limit := int(s.maxMessageSize.Load())
+	for {
+		line, size, err := readLine(reader, limit)
+		if size > limit {
+			s.sendError(oversizeRequestID(line), -32600, "Invalid request",
+				fmt.Sprintf("%v: %d bytes, limit %d bytes", ErrMessageTooLarge, size, limit))
+		} else if size > 0 {
+			select {
+			case lines <- string(line):
+			case <-s.ctx.Done():
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readLine reads one line without its line ending and returns its size. Of lines longer
+// than limit, only the first limit bytes are kept.
+func readLine(reader *bufio.Reader, limit int) ([]byte, int, error) {
+	var line []byte
+	size := 0
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		size += len(chunk)
+		if keep := limit - len(line); keep > 0 {
+			line = append(line, chunk[:min(keep, len(chunk))]...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == nil {
+			size--
+			line = bytes.TrimSuffix(line, []byte("\n"))
+		}
+		if len(line) > 0 && line[len(line)-1] == '\r' {
+			size--
+			line = line[:len(line)-1]
+		}
+		return line, size, err
+	}
+}
+
+// oversizeRequestID recovers the top-level "id" of a message from its first bytes, so
+// that the error for an oversize request can be matched by the client; nil if the id is
+// not among them
+func oversizeRequestID(prefix []byte) interface{} {
+	dec := json.NewDecoder(bytes.NewReader(prefix))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if key == "id" {
+			var id interface{}
+			if err := dec.Decode(&id); err != nil {
+				return nil
+			}
+			return id
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// logLevels orders the MCP log levels (RFC 5424 severities) from least to most severe
+var logLevels = map[string]int{
+	"debug":     0,
+	"info":      1,
+	"notice":    2,
+	"warning":   3,
+	"error":     4,
+	"critical":  5,
+	"alert":     6,
+	"emergency": 7,
+}
+
+// defaultLogLevel is the minimum level sent before the client calls logging/setLevel
+const defaultLogLevel = "info"
+
+// Log sends a notifications/message log entry to the client if the server is
+// initialized and the level is at or above the level requested by the client.
+// Credentials in string data are redacted, whoever logs it.
+func (s *Server) Log(level, logger string, data interface{}) {
+	severity, known := logLevels[level]
+	if !known {
+		return
+	}
+
+	s.mu.RLock()
+	initialized := s.initialized
+	minLevel := s.logLevel
+	s.mu.RUnlock()
+
+	if !initialized || severity < logLevels[minLevel] {
+		return
+	}
+
+	if message, ok := data.(string); ok {
+		data = redact.String(message)
+	}
+	params := map[string]interface{}{
+		"level": level,
+		"data":  data,
+	}
+	if logger != "" {
+		params["logger"] = logger
+	}
+	s.sendNotification("notifications/message", params)
+}
+
+// handleSetLevel handles the logging/setLevel request
+func (s *Server) handleSetLevel(req *Request) error {
+	level, _ := req.Params["level"].(string)
+	if _, known := logLevels[level]; !known {
+		return s.sendError(req.ID, -32602, "Invalid params", fmt.Sprintf("Unknown log level: %s", level))
+	}
+
+	s.mu.Lock()
+	s.logLevel = level
+	s.mu.Unlock()
+
+	return s.sendResponse(req.ID, map[string]interface{}{})
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/odata-mcp/go/internal/constants"
+)
+
+// progressKey is the context key for the progress reporter of a request
+type progressKey struct{}
+
+// progressReporter sends notifications/progress for a single request
+type progressReporter struct {
+	server *Server
+	token  interface{}
+
+	mu   sync.Mutex
+	last float64
+	sent bool
+}
+
+// withProgress returns a context that reports progress for the given token
+func (s *Server) withProgress(ctx context.Context, token interface{}) context.Context {
+	if token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressReporter{server: s, token: token})
+}
+
+// progressToken extracts params._meta.progressToken from a request
+func progressToken(params map[string]interface{}) interface{} {
+	meta, ok := params["_meta"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return meta["progressToken"]
+}
+
+// WithoutProgress returns a context in which ReportProgress sends nothing, for work done
+// on behalf of a request that reports its progress itself
+func WithoutProgress(ctx context.Context) context.Context {
+	return context.WithValue(ctx, progressKey{}, struct{}{})
+}
+
+// HasProgress reports whether the client requested progress notifications for the request in ctx
+func HasProgress(ctx context.Context) bool {
+	_, ok := ctx.Value(progressKey{}).(*progressReporter)
+	return ok
+}
+
+// ReportProgress sends a notifications/progress message for the request in ctx
+// if the client supplied a progress token. A total <= 0 means the total is
+// unknown. Progress values that do not increase are dropped, as the protocol
+// requires progress to grow with each notification.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	reporter, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok {
+		return
+	}
+
+	reporter.mu.Lock()
+	if reporter.sent && progress <= reporter.last {
+		reporter.mu.Unlock()
+		return
+	}
+	reporter.last = progress
+	reporter.sent = true
+	reporter.mu.Unlock()
+
+	params := map[string]interface{}{
+		"progressToken": reporter.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" && reporter.server.protocolAtLeast(constants.MCPProtocol20250326) {
+		params["message"] = message
+	}
+	reporter.server.sendNotification("notifications/progress", params)
+}
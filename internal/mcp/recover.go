@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/logging"
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// logger logs panics recovered from handlers
+var logger = logging.Subsystem(logging.SubsystemMCP)
+
+// PanicError is a panic of a handler that the server recovered from
+type PanicError struct {
+	Value interface{}
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return redact.String(fmt.Sprintf("internal error: %v", e.Value))
+}
+
+// SetVerbose includes the stack traces of recovered panics in error responses
+func (s *Server) SetVerbose(verbose bool) {
+	s.verbose.Store(verbose)
+}
+
+// recoverPanic turns a panic of the deferring function into a *PanicError stored in err
+// and logs it with its stack trace. It must be deferred directly.
+func recoverPanic(err *error, method, name string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	panicErr := &PanicError{Value: r, Stack: string(debug.Stack())}
+	logger.Error("Recovered from panic in handler", "method", method, "name", name, "panic", panicErr.Error(), "stack", panicErr.Stack)
+	*err = panicErr
+}
+
+// callTool runs a tool handler, returning a panic of the handler as a *PanicError
+func callTool(ctx context.Context, name string, handler ToolHandler, params map[string]interface{}) (result interface{}, err error) {
+	defer recoverPanic(&err, "tools/call", name)
+	return handler(ctx, params)
+}
+
+// Recovered runs fn, returning a panic of fn as a *PanicError. The server only recovers
+// from panics of a handler's own goroutine, so the goroutines a handler starts run their
+// work with Recovered.
+func Recovered(name string, fn func() error) (err error) {
+	defer recoverPanic(&err, "tools/call", name)
+	return fn()
+}
+
+// sendPanicError answers a request whose handler panicked with an internal error; the
+// stack trace is appended to the error data in verbose mode
+func (s *Server) sendPanicError(id interface{}, message, data string, panicErr *PanicError) error {
+	if s.verbose.Load() {
+		data = strings.TrimPrefix(data+"\n\n"+panicErr.Stack, "\n\n")
+	}
+	return s.sendError(id, -32603, message, data)
+}
@@ -1,30 +1,68 @@
 package mcp
 
 import (
-	"bufio"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
 )
 
 // Tool represents an MCP tool
 type Tool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"inputSchema"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	InputSchema  map[string]interface{} `json:"inputSchema"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations       `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describes tool behavior to clients (e.g. for confirmation prompts).
+// Unset hints fall back to the protocol defaults.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
 }
 
 // ToolHandler is a function that handles tool execution
 type ToolHandler func(ctx context.Context, args map[string]interface{}) (interface{}, error)
 
+// ResourceTemplate describes a parameterized resource URI (RFC 6570 template)
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContent is the content returned when reading a resource
+type ResourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// ErrResourceNotFound is returned (wrapped) by resource handlers for URIs that do not identify a resource
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ResourceHandler reads the resource identified by a URI matching a registered template
+type ResourceHandler func(ctx context.Context, uri string) ([]ResourceContent, error)
+
 // Request represents an incoming MCP request
 type Request struct {
 	JSONRPC string                 `json:"jsonrpc"`
@@ -62,35 +100,78 @@ type Server struct {
 	tools       map[string]*Tool
 	toolOrder   []string    // Maintains insertion order
 	handlers    map[string]ToolHandler
+	templates   []*ResourceTemplate
+	resourceHandler ResourceHandler
+	completionHandler CompletionHandler
+	pageSize    int         // Tools per tools/list page (0 = no pagination)
+	logLevel    string      // Minimum level of log notifications sent to the client
+	protocolVersion string  // Negotiated protocol version
+	keepAliveInterval time.Duration // Interval of keepalive pings to the client (0 = disabled)
+	idleTimeout       time.Duration // Session is closed after this long without client requests (0 = disabled)
+	lastActivity      atomic.Int64  // Unix nanoseconds of the last client request
+	toolTimeout       time.Duration // Maximum execution time of a tool call (0 = no limit)
+	callSlots         chan struct{} // Bounds concurrently executing tool calls (nil = unbounded)
+	verbose           atomic.Bool   // Stack traces of recovered panics are sent to the client
+	toolsLoader       *toolsLoader  // Registers the tools when first needed (nil = registered up front)
+	toolsLoaded       bool          // A tools loader has succeeded
+	loadingTools      bool          // The first tools loader is running; tools/list_changed is not sent
+	maxMessageSize    atomic.Int64  // Maximum size in bytes of inbound messages
+	maxOutboundSize   atomic.Int64  // Maximum size in bytes of outbound messages (0 = unlimited)
 	input       io.Reader
 	output      io.Writer
 	ctx         context.Context
-	cancel      context.CancelFunc
+	cancel      context.CancelCauseFunc
 	mu          sync.RWMutex
 	initialized bool
+	writeMu     sync.Mutex                          // Serializes writes to output
+	inFlight    map[string]context.CancelCauseFunc // Cancel functions of running tool calls by request ID
+	calls       sync.WaitGroup                      // Running tool calls and pending notifications
+	active      int                                 // Count of calls, guarded by mu
+	closing     bool                                // Run is waiting for calls; only running calls may add more (guarded by mu)
+	listChangedPending bool                            // A tools/list_changed notification is scheduled
+	clientCapabilities map[string]interface{}          // Capabilities declared by the client in initialize
+	pending            map[string]chan *clientResponse // Server-initiated requests awaiting a response
+	nextRequestID      int
 }
 
+// listChangedDelay is how long tool changes are collected before notifying the client
+const listChangedDelay = 50 * time.Millisecond
+
+// ErrToolTimeout is the cause of tool calls aborted by the tool timeout
+var ErrToolTimeout = errors.New("tool execution timed out")
+
+// errRequestCancelled is the cancellation cause for requests cancelled by the client
+var errRequestCancelled = errors.New("request cancelled by client")
+
 // NewServer creates a new MCP server
 func NewServer(name, version string) *Server {
 	// Disable logging to avoid contaminating stdio communication
 	log.SetOutput(ioutil.Discard)
 	
-	ctx, cancel := context.WithCancel(context.Background())
-	return &Server{
+	ctx, cancel := context.WithCancelCause(context.Background())
+	s := &Server{
 		name:     name,
 		version:  version,
 		tools:     make(map[string]*Tool),
 		toolOrder: make([]string, 0),
 		handlers:  make(map[string]ToolHandler),
+		inFlight:  make(map[string]context.CancelCauseFunc),
+		logLevel:  defaultLogLevel,
+		protocolVersion: constants.MCPProtocolVersion,
+		pending:   make(map[string]chan *clientResponse),
 		input:    os.Stdin,
 		output:   os.Stdout,
 		ctx:      ctx,
 		cancel:   cancel,
 	}
+	s.maxMessageSize.Store(constants.DefaultMaxMessageSize)
+	return s
 }
 
 // AddTool registers a new tool with the server
 func (s *Server) AddTool(tool *Tool, handler ToolHandler) {
+	compileSchemaPatterns(tool.Name, tool.InputSchema)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	
@@ -101,6 +182,7 @@ func (s *Server) AddTool(tool *Tool, handler ToolHandler) {
 	
 	s.tools[tool.Name] = tool
 	s.handlers[tool.Name] = handler
+	s.scheduleToolsListChanged()
 }
 
 // RemoveTool removes a tool from the server
@@ -118,10 +200,37 @@ func (s *Server) RemoveTool(name string) {
 			break
 		}
 	}
+	s.scheduleToolsListChanged()
+}
+
+// scheduleToolsListChanged queues a notifications/tools/list_changed message
+// once the client is initialized. Changes within listChangedDelay are coalesced
+// into one notification so that registering many tools does not flood the
+// client. Callers must hold s.mu.
+func (s *Server) scheduleToolsListChanged() {
+	if !s.initialized || s.listChangedPending || s.loadingTools || !s.addCallLocked() {
+		return
+	}
+	s.listChangedPending = true
+
+	time.AfterFunc(listChangedDelay, func() {
+		defer s.doneCall()
+
+		s.mu.Lock()
+		s.listChangedPending = false
+		s.mu.Unlock()
+
+		s.sendNotification("notifications/tools/list_changed", nil)
+	})
 }
 
-// GetTools returns all registered tools in insertion order
-func (s *Server) GetTools() []*Tool {
+// GetTools returns all registered tools in insertion order, or the error of the tools
+// loader if generating them failed
+func (s *Server) GetTools() ([]*Tool, error) {
+	if err := s.LoadTools(); err != nil {
+		return nil, err
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	
@@ -131,7 +240,114 @@ func (s *Server) GetTools() []*Tool {
 			tools = append(tools, tool)
 		}
 	}
-	return tools
+	return tools, nil
+}
+
+// SetToolsPageSize sets the maximum number of tools returned per tools/list page (0 disables pagination)
+func (s *Server) SetToolsPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pageSize = size
+}
+
+// CallTool invokes a registered tool handler directly, bypassing JSON-RPC framing
+func (s *Server) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	if err := s.LoadTools(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	handler, exists := s.handlers[name]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("tool not found: %s", name)
+	}
+	if args == nil {
+		args = make(map[string]interface{})
+	}
+	if err := s.validateToolArguments(name, args); err != nil {
+		return nil, err
+	}
+	
+	ctx, cancel := s.withToolTimeout(ctx)
+	defer cancel()
+	result, err := callTool(ctx, name, handler, args)
+	if errors.Is(context.Cause(ctx), ErrToolTimeout) {
+		return nil, context.Cause(ctx)
+	}
+	return result, err
+}
+
+// SetMaxConcurrentCalls bounds the number of tool calls executing at the same
+// time; further calls wait for a free slot (0 = unbounded). Must be called before Run.
+func (s *Server) SetMaxConcurrentCalls(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit > 0 {
+		s.callSlots = make(chan struct{}, limit)
+	} else {
+		s.callSlots = nil
+	}
+}
+
+// SetToolTimeout limits the execution time of each tool call (0 = no limit)
+func (s *Server) SetToolTimeout(timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.toolTimeout = timeout
+}
+
+// withToolTimeout applies the configured tool timeout to a tool call context
+func (s *Server) withToolTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	s.mu.RLock()
+	timeout := s.toolTimeout
+	s.mu.RUnlock()
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %s", ErrToolTimeout, timeout))
+}
+
+// AddResourceTemplate registers a resource template; reads of matching URIs
+// are served by the handler set with SetResourceHandler
+func (s *Server) AddResourceTemplate(template *ResourceTemplate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.templates = append(s.templates, template)
+}
+
+// ClearResourceTemplates removes all registered resource templates
+func (s *Server) ClearResourceTemplates() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.templates = nil
+}
+
+// SetResourceHandler sets the handler used for resources/read requests
+func (s *Server) SetResourceHandler(handler ResourceHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resourceHandler = handler
+}
+
+// ReadResource reads a resource directly, bypassing JSON-RPC framing
+func (s *Server) ReadResource(ctx context.Context, uri string) ([]ResourceContent, error) {
+	s.mu.RLock()
+	handler := s.resourceHandler
+	s.mu.RUnlock()
+
+	if handler == nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+	return handler(ctx, uri)
 }
 
 // SetIO sets the input and output streams for the server
@@ -140,41 +356,85 @@ func (s *Server) SetIO(input io.Reader, output io.Writer) {
 	s.output = output
 }
 
+// addCallLocked adds a tool call or pending notification to calls, unless Run is closing
+// and none is running any more. Adding under mu while the count is positive keeps Add
+// from racing with the Wait of a closing Run. Callers must hold s.mu.
+func (s *Server) addCallLocked() bool {
+	if s.closing && s.active == 0 {
+		return false
+	}
+	s.active++
+	s.calls.Add(1)
+	return true
+}
+
+// doneCall marks a call added with addCallLocked as finished
+func (s *Server) doneCall() {
+	s.mu.Lock()
+	s.active--
+	s.mu.Unlock()
+	s.calls.Done()
+}
+
+// waitForCalls stops new tool calls and notifications from being started once the
+// running ones have finished, and waits for them
+func (s *Server) waitForCalls() {
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+	s.calls.Wait()
+}
+
 // Run starts the MCP server
 func (s *Server) Run() error {
-	scanner := bufio.NewScanner(s.input)
-	// Increase buffer size to handle large messages (10MB)
-	const maxScanTokenSize = 10 * 1024 * 1024
-	buf := make([]byte, maxScanTokenSize)
-	scanner.Buffer(buf, maxScanTokenSize)
+	s.mu.Lock()
+	s.closing = false
+	s.mu.Unlock()
+
+	lines := make(chan string)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanErr <- s.readMessages(lines)
+	}()
+	
+	s.touch()
+	monitorDone := make(chan struct{})
+	defer close(monitorDone)
+	if s.keepAliveInterval > 0 || s.idleTimeout > 0 {
+		go s.monitorSession(monitorDone)
+	}
 	
-	for scanner.Scan() {
+	for {
 		select {
 		case <-s.ctx.Done():
-			return s.ctx.Err()
-		default:
-		}
-		
-		line := scanner.Text()
-		if line == "" {
-			continue
-		}
-		
-		if err := s.handleMessage(line); err != nil {
-			// Error already sent as JSON-RPC response, don't log to stdout/stderr
+			// Running tool calls see the cancelled context; wait for them to return
+			s.waitForCalls()
+			return context.Cause(s.ctx)
+		case line, ok := <-lines:
+			if !ok {
+				// Let running tool calls finish and send their responses and notifications
+				s.waitForCalls()
+				return <-scanErr
+			}
+			if line == "" {
+				continue
+			}
+			
+			if err := s.handleMessage(line); err != nil {
+				// Error already sent as JSON-RPC response, don't log to stdout/stderr
+			}
 		}
 	}
-	
-	return scanner.Err()
 }
 
 // Stop stops the MCP server
 func (s *Server) Stop() {
-	s.cancel()
+	s.cancel(nil)
 }
 
 // handleMessage processes a single JSON-RPC message
-func (s *Server) handleMessage(line string) error {
+func (s *Server) handleMessage(line string) (err error) {
 	// Parse as generic JSON first to check structure
 	var rawMsg map[string]interface{}
 	if err := json.Unmarshal([]byte(line), &rawMsg); err != nil {
@@ -182,6 +442,16 @@ func (s *Server) handleMessage(line string) error {
 		return err
 	}
 	
+	// Responses to server-initiated requests have an ID but no method
+	if _, hasMethod := rawMsg["method"]; !hasMethod {
+		_, hasResult := rawMsg["result"]
+		_, hasError := rawMsg["error"]
+		if hasResult || hasError {
+			return s.handleClientResponse(line)
+		}
+	}
+	s.touch()
+	
 	// Check if it's a notification (no ID) or request (has ID)
 	var req Request
 	if err := json.Unmarshal([]byte(line), &req); err != nil {
@@ -193,10 +463,26 @@ func (s *Server) handleMessage(line string) error {
 		return s.sendError(id, -32700, "Parse error", err.Error())
 	}
 	
+	// A panicking handler fails its request instead of the server
+	defer func() {
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) && req.ID != nil {
+			err = s.sendPanicError(req.ID, "Internal error", panicErr.Error(), panicErr)
+		}
+	}()
+	defer recoverPanic(&err, req.Method, "")
+
 	// Handle notifications differently (no response expected)
-	if req.Method == "initialized" {
+	if req.Method == "initialized" || req.Method == "notifications/initialized" {
 		return s.handleInitialized(&req)
 	}
+	if req.Method == "notifications/cancelled" {
+		return s.handleCancelled(&req)
+	}
+	if req.ID == nil && strings.HasPrefix(req.Method, "notifications/") {
+		// Other notifications need no handling
+		return nil
+	}
 	
 	// For requests, ensure we have an ID (except notifications)
 	if req.ID == nil && req.Method != "initialized" {
@@ -210,6 +496,16 @@ func (s *Server) handleMessage(line string) error {
 		return s.handleToolsList(&req)
 	case "tools/call":
 		return s.handleToolsCall(&req)
+	case "resources/list":
+		return s.handleResourcesList(&req)
+	case "resources/templates/list":
+		return s.handleResourceTemplatesList(&req)
+	case "resources/read":
+		return s.handleResourcesRead(&req)
+	case "completion/complete":
+		return s.handleComplete(&req)
+	case "logging/setLevel":
+		return s.handleSetLevel(&req)
 	case "ping":
 		return s.handlePing(&req)
 	default:
@@ -219,13 +515,34 @@ func (s *Server) handleMessage(line string) error {
 
 // handleInitialize handles the initialize request
 func (s *Server) handleInitialize(req *Request) error {
-	result := map[string]interface{}{
-		"protocolVersion": constants.MCPProtocolVersion,
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{
-				"listChanged": true,
-			},
+	clientCapabilities, _ := req.Params["capabilities"].(map[string]interface{})
+	requestedVersion, _ := req.Params["protocolVersion"].(string)
+	version := negotiateProtocolVersion(requestedVersion)
+
+	s.mu.Lock()
+	s.clientCapabilities = clientCapabilities
+	s.protocolVersion = version
+	s.mu.Unlock()
+
+	capabilities := map[string]interface{}{
+		"tools": map[string]interface{}{
+			"listChanged": true,
 		},
+		"logging": map[string]interface{}{},
+	}
+
+	s.mu.RLock()
+	if len(s.templates) > 0 {
+		capabilities["resources"] = map[string]interface{}{}
+	}
+	if s.completionHandler != nil && version >= constants.MCPProtocol20250326 {
+		capabilities["completions"] = map[string]interface{}{}
+	}
+	s.mu.RUnlock()
+
+	result := map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    capabilities,
 		"serverInfo": map[string]interface{}{
 			"name":    s.name,
 			"version": s.version,
@@ -235,6 +552,31 @@ func (s *Server) handleInitialize(req *Request) error {
 	return s.sendResponse(req.ID, result)
 }
 
+// negotiateProtocolVersion returns the requested protocol version if it is
+// supported, otherwise the latest version the server supports
+func negotiateProtocolVersion(requested string) string {
+	for _, version := range constants.MCPSupportedProtocolVersions {
+		if version == requested {
+			return version
+		}
+	}
+	return constants.MCPProtocolVersion
+}
+
+// ProtocolVersion returns the negotiated protocol version
+func (s *Server) ProtocolVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.protocolVersion
+}
+
+// protocolAtLeast reports whether the negotiated protocol version is at least the given version
+func (s *Server) protocolAtLeast(version string) bool {
+	// Protocol versions are ISO dates, so they compare lexically
+	return s.ProtocolVersion() >= version
+}
+
 // handleInitialized handles the initialized notification
 func (s *Server) handleInitialized(req *Request) error {
 	s.mu.Lock()
@@ -245,6 +587,10 @@ func (s *Server) handleInitialized(req *Request) error {
 
 // handleToolsList handles the tools/list request
 func (s *Server) handleToolsList(req *Request) error {
+	if err := s.LoadTools(); err != nil {
+		return s.sendError(req.ID, -32603, "Internal error", err.Error())
+	}
+
 	s.mu.RLock()
 	tools := make([]*Tool, 0, len(s.tools))
 	// Use the ordered list to maintain insertion order
@@ -253,15 +599,72 @@ func (s *Server) handleToolsList(req *Request) error {
 			tools = append(tools, tool)
 		}
 	}
+	pageSize := s.pageSize
 	s.mu.RUnlock()
 	
-	result := map[string]interface{}{
-		"tools": tools,
+	result := map[string]interface{}{}
+	if pageSize > 0 {
+		start := 0
+		if cursor, ok := req.Params["cursor"].(string); ok && cursor != "" {
+			offset, err := decodeCursor(cursor)
+			if err != nil || offset > len(tools) {
+				return s.sendError(req.ID, -32602, "Invalid params", "Invalid cursor")
+			}
+			start = offset
+		}
+		end := start + pageSize
+		if end < len(tools) {
+			result["nextCursor"] = encodeCursor(end)
+		} else {
+			end = len(tools)
+		}
+		tools = tools[start:end]
 	}
+	result["tools"] = s.toolsForProtocol(tools)
 	
 	return s.sendResponse(req.ID, result)
 }
 
+// toolsForProtocol removes tool fields that the negotiated protocol version does not define
+func (s *Server) toolsForProtocol(tools []*Tool) []*Tool {
+	withOutputSchema := s.protocolAtLeast(constants.MCPProtocol20250618)
+	withAnnotations := s.protocolAtLeast(constants.MCPProtocol20250326)
+	if withOutputSchema && withAnnotations {
+		return tools
+	}
+
+	adapted := make([]*Tool, len(tools))
+	for i, tool := range tools {
+		copied := *tool
+		if !withOutputSchema {
+			copied.OutputSchema = nil
+		}
+		if !withAnnotations {
+			copied.Annotations = nil
+		}
+		adapted[i] = &copied
+	}
+	return adapted
+}
+
+// encodeCursor encodes a list offset as an opaque pagination cursor
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("offset:%d", offset)))
+}
+
+// decodeCursor decodes a pagination cursor created by encodeCursor
+func decodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(data), "offset:"))
+	if err != nil || offset < 0 || !strings.HasPrefix(string(data), "offset:") {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	return offset, nil
+}
+
 // handleToolsCall handles the tools/call request
 func (s *Server) handleToolsCall(req *Request) error {
 	params, ok := req.Params["arguments"].(map[string]interface{})
@@ -273,6 +676,9 @@ func (s *Server) handleToolsCall(req *Request) error {
 	if !ok {
 		return s.sendError(req.ID, -32602, "Invalid params", "Missing tool name")
 	}
+	if err := s.LoadTools(); err != nil {
+		return s.sendError(req.ID, -32603, "Internal error", err.Error())
+	}
 	
 	s.mu.RLock()
 	handler, exists := s.handlers[name]
@@ -281,24 +687,192 @@ func (s *Server) handleToolsCall(req *Request) error {
 	if !exists {
 		return s.sendError(req.ID, -32602, "Invalid params", fmt.Sprintf("Tool not found: %s", name))
 	}
+
+	// Reject malformed arguments before any backend request is made
+	if err := s.validateToolArguments(name, params); err != nil {
+		return s.sendError(req.ID, -32602, "Invalid params", err.Error())
+	}
 	
-	result, err := handler(s.ctx, params)
+	// Run the call in the background so that notifications/cancelled, pings and
+	// further requests are read while the backend request is in flight.
+	// Responses are sent out of order, matched to requests by ID.
+	ctx, cancel := context.WithCancelCause(s.ctx)
+	key := requestKey(req.ID)
+	s.mu.Lock()
+	if !s.addCallLocked() {
+		s.mu.Unlock()
+		cancel(nil)
+		return s.sendError(req.ID, -32603, "Internal error", "Server is shutting down")
+	}
+	s.inFlight[key] = cancel
+	s.mu.Unlock()
+
+	go func() {
+		defer s.doneCall()
+		defer func() {
+			s.mu.Lock()
+			delete(s.inFlight, key)
+			s.mu.Unlock()
+			cancel(nil)
+		}()
+		
+		// Wait for a free worker slot; queued calls can still be cancelled
+		if slots := s.callSlots; slots != nil {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				if !errors.Is(context.Cause(ctx), errRequestCancelled) {
+					s.sendError(req.ID, -32603, "Internal error", "Server is shutting down")
+				}
+				return
+			}
+		}
+		s.runToolCall(s.withProgress(ctx, progressToken(req.Params)), req.ID, name, handler, params)
+	}()
+	
+	return nil
+}
+
+// runToolCall executes a tool handler and sends its result, unless the client cancelled the request
+func (s *Server) runToolCall(ctx context.Context, id interface{}, name string, handler ToolHandler, params map[string]interface{}) error {
+	ctx, cancel := s.withToolTimeout(ctx)
+	defer cancel()
+	
+	result, err := callTool(ctx, name, handler, params)
+	if errors.Is(context.Cause(ctx), errRequestCancelled) {
+		// The client is no longer waiting for a response
+		return nil
+	}
+	if errors.Is(context.Cause(ctx), ErrToolTimeout) {
+		return s.sendError(id, -32603, fmt.Sprintf("OData MCP tool '%s' failed: %v", name, context.Cause(ctx)), "")
+	}
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return s.sendPanicError(id, fmt.Sprintf("OData MCP tool '%s' failed: %v", name, panicErr), "", panicErr)
+	}
 	if err != nil {
 		// Map OData errors to appropriate MCP error codes and provide detailed context
 		errorCode, errorMessage, errorData := s.categorizeError(err, name)
-		return s.sendError(req.ID, errorCode, errorMessage, errorData)
+		return s.sendError(id, errorCode, errorMessage, errorData)
+	}
+	
+	text, structured := result, result
+	if textResult, ok := result.(*TextResult); ok {
+		text, structured = textResult.Text, textResult.Structured
 	}
 	
 	response := map[string]interface{}{
 		"content": []map[string]interface{}{
 			{
 				"type": "text",
-				"text": result,
+				"text": text,
 			},
 		},
 	}
 	
-	return s.sendResponse(req.ID, response)
+	// Tools with an output schema also return the parsed JSON object as structured content
+	s.mu.RLock()
+	tool := s.tools[name]
+	s.mu.RUnlock()
+	if tool != nil && tool.OutputSchema != nil && s.protocolAtLeast(constants.MCPProtocol20250618) {
+		if structured := structuredContent(structured); structured != nil {
+			response["structuredContent"] = structured
+		}
+	}
+	
+	return s.sendResponse(id, response)
+}
+
+// TextResult is a tool result whose text content differs from its structured
+// content, e.g. a CSV rendering of an entity list that is also returned as JSON
+type TextResult struct {
+	Text       string
+	Structured interface{} // JSON object or JSON string for clients reading structuredContent
+}
+
+// structuredContent returns a tool result as a JSON object, or nil if it is not one
+func structuredContent(result interface{}) map[string]interface{} {
+	switch v := result.(type) {
+	case map[string]interface{}:
+		return v
+	case string:
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &object); err == nil {
+			return object
+		}
+	}
+	return nil
+}
+
+// handleCancelled handles the notifications/cancelled notification by
+// cancelling the context of the referenced in-flight tool call
+func (s *Server) handleCancelled(req *Request) error {
+	requestID, exists := req.Params["requestId"]
+	if !exists {
+		return nil
+	}
+
+	s.mu.RLock()
+	cancel, running := s.inFlight[requestKey(requestID)]
+	s.mu.RUnlock()
+
+	if running {
+		cancel(errRequestCancelled)
+	}
+	return nil
+}
+
+// requestKey normalizes a JSON-RPC request ID for use as a map key
+func requestKey(id interface{}) string {
+	return fmt.Sprintf("%T:%v", id, id)
+}
+
+// handleResourcesList handles the resources/list request. Entity resources are
+// only reachable through templates, so the list of concrete resources is empty.
+func (s *Server) handleResourcesList(req *Request) error {
+	result := map[string]interface{}{
+		"resources": []interface{}{},
+	}
+
+	return s.sendResponse(req.ID, result)
+}
+
+// handleResourceTemplatesList handles the resources/templates/list request
+func (s *Server) handleResourceTemplatesList(req *Request) error {
+	s.mu.RLock()
+	templates := make([]*ResourceTemplate, len(s.templates))
+	copy(templates, s.templates)
+	s.mu.RUnlock()
+
+	result := map[string]interface{}{
+		"resourceTemplates": templates,
+	}
+
+	return s.sendResponse(req.ID, result)
+}
+
+// handleResourcesRead handles the resources/read request
+func (s *Server) handleResourcesRead(req *Request) error {
+	uri, ok := req.Params["uri"].(string)
+	if !ok || uri == "" {
+		return s.sendError(req.ID, -32602, "Invalid params", "Missing resource uri")
+	}
+
+	contents, err := s.ReadResource(s.ctx, uri)
+	if err != nil {
+		if errors.Is(err, ErrResourceNotFound) {
+			// -32002 is the MCP error code for unknown resources
+			return s.sendError(req.ID, -32002, "Resource not found", err.Error())
+		}
+		return s.sendError(req.ID, -32603, "Internal error", err.Error())
+	}
+
+	result := map[string]interface{}{
+		"contents": contents,
+	}
+
+	return s.sendResponse(req.ID, result)
 }
 
 // handlePing handles the ping request
@@ -320,8 +894,12 @@ func (s *Server) sendResponse(id interface{}, result interface{}) error {
 		return err
 	}
 	
-	_, err = fmt.Fprintf(s.output, "%s\n", data)
-	return err
+	if err := s.writeMessage(data); errors.Is(err, ErrMessageTooLarge) {
+		return s.sendError(id, -32603, "Response too large", err.Error())
+	} else if err != nil {
+		return err
+	}
+	return nil
 }
 
 // sendError sends a JSON-RPC error response
@@ -341,8 +919,14 @@ func (s *Server) sendError(id interface{}, code int, message, data string) error
 		return err
 	}
 	
-	_, err = fmt.Fprintf(s.output, "%s\n", responseData)
-	return err
+	return s.writeMessage(responseData)
+}
+
+// serviceMessagesError is an error that carries the typed messages of the service,
+// such as the SAP error details of an OData error response
+type serviceMessagesError interface {
+	error
+	ServiceMessages() []models.SAPMessage
 }
 
 // categorizeError maps OData errors to appropriate MCP error codes and enhances error messages
@@ -353,8 +937,17 @@ func (s *Server) categorizeError(err error, toolName string) (int, string, strin
 	// The MCP client will see this as the main error message
 	fullErrorMessage := fmt.Sprintf("OData MCP tool '%s' failed: %s", toolName, errStr)
 	
-	// Create structured data for programmatic use (though most clients ignore this)
-	errorData := fmt.Sprintf("{\"tool\":\"%s\",\"original_error\":\"%s\"}", toolName, errStr)
+	// Create structured data for programmatic use (though most clients ignore this),
+	// with the typed messages of the service for clients that fix errors field by field
+	data := map[string]interface{}{"tool": toolName, "original_error": errStr}
+	var messagesErr serviceMessagesError
+	if errors.As(err, &messagesErr) {
+		if messages := messagesErr.ServiceMessages(); len(messages) > 0 {
+			data["messages"] = messages
+		}
+	}
+	encoded, _ := json.Marshal(data)
+	errorData := string(encoded)
 	
 	// Check for specific OData error patterns and map to appropriate MCP codes
 	switch {
@@ -422,6 +1015,20 @@ func (s *Server) sendNotification(method string, params map[string]interface{})
 		return err
 	}
 	
-	_, err = fmt.Fprintf(s.output, "%s\n", data)
+	return s.writeMessage(data)
+}
+
+// writeMessage writes one newline-delimited JSON-RPC message to the output
+func (s *Server) writeMessage(data []byte) error {
+	if limit := s.maxOutboundSize.Load(); limit > 0 && int64(len(data)) > limit {
+		err := fmt.Errorf("%w: %d bytes, limit %d bytes", ErrMessageTooLarge, len(data), limit)
+		logger.Warn("Outbound message not sent", "error", err)
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	_, err := fmt.Fprintf(s.output, "%s\n", data)
 	return err
 }
\ No newline at end of file
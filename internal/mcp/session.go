@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIdleTimeout is returned by Run when the session was closed for inactivity
+var ErrIdleTimeout = errors.New("session idle timeout")
+
+// ErrClientUnresponsive is returned by Run when the client stopped answering keepalive pings
+var ErrClientUnresponsive = errors.New("client did not answer keepalive ping")
+
+// SetKeepAlive configures keepalive pings sent to the client every interval and
+// closes the session after idleTimeout without client requests. Zero disables either.
+func (s *Server) SetKeepAlive(interval, idleTimeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keepAliveInterval = interval
+	s.idleTimeout = idleTimeout
+}
+
+// touch records client activity
+func (s *Server) touch() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+// monitorSession sends keepalive pings and enforces the idle timeout until done is closed
+func (s *Server) monitorSession(done <-chan struct{}) {
+	tick := s.keepAliveInterval
+	if tick <= 0 || (s.idleTimeout > 0 && s.idleTimeout < tick) {
+		tick = s.idleTimeout
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	lastPing := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.RLock()
+			busy := len(s.inFlight) > 0
+			initialized := s.initialized
+			s.mu.RUnlock()
+			if busy {
+				// A running tool call counts as activity
+				s.touch()
+			}
+
+			idle := now.Sub(time.Unix(0, s.lastActivity.Load()))
+			if s.idleTimeout > 0 && idle >= s.idleTimeout {
+				s.cancel(fmt.Errorf("%w: no requests for %s", ErrIdleTimeout, idle.Round(time.Second)))
+				return
+			}
+
+			if s.keepAliveInterval > 0 && initialized && now.Sub(lastPing) >= s.keepAliveInterval {
+				lastPing = now
+				go s.ping()
+			}
+		}
+	}
+}
+
+// ping sends a keepalive ping and closes the session if the client does not answer in time
+func (s *Server) ping() {
+	ctx, cancel := context.WithTimeout(s.ctx, s.keepAliveInterval)
+	defer cancel()
+
+	if _, err := s.request(ctx, "ping", nil); err != nil && s.ctx.Err() == nil {
+		s.cancel(fmt.Errorf("%w: %v", ErrClientUnresponsive, err))
+	}
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import "sync"
+
+// toolsLoader registers the tools of a server when they are first needed
+type toolsLoader struct {
+	mu   sync.Mutex // Serializes runs, so that concurrent requests wait for one load
+	done bool       // The loader succeeded; a failed loader runs again on the next request
+	load func() error
+}
+
+// SetToolsLoader defers registering tools until they are first needed: load runs before
+// the first tools/list or tools/call request or call of GetTools or CallTool, so that
+// servers whose tools are never listed do not pay for generating them, and again on the
+// next such request if it fails. Tools registered by the first loader of a server do not
+// send tools/list_changed, as no client can have seen a tool list before; a later loader
+// replaces the pending one.
+func (s *Server) SetToolsLoader(load func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.toolsLoader = &toolsLoader{load: load}
+}
+
+// LoadTools runs the pending tools loader unless it already succeeded and returns its error
+func (s *Server) LoadTools() error {
+	s.mu.RLock()
+	loader := s.toolsLoader
+	s.mu.RUnlock()
+	if loader == nil {
+		return nil
+	}
+
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+	if loader.done {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.loadingTools = !s.toolsLoaded
+	s.mu.Unlock()
+
+	err := loader.load()
+
+	s.mu.Lock()
+	s.loadingTools = false
+	if err == nil {
+		s.toolsLoaded = true
+	}
+	s.mu.Unlock()
+
+	loader.done = err == nil
+	return err
+}
+
+// ToolsLoaded reports whether a tools loader has succeeded
+func (s *Server) ToolsLoaded() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.toolsLoaded
+}
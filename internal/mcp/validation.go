@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrInvalidArguments is returned when tool arguments do not match the tool's input schema
+var ErrInvalidArguments = errors.New("invalid arguments")
+
+// schemaPatterns holds the compiled regular expressions of input schema patterns by
+// pattern, shared by the tools of all servers; invalid patterns are held as nil
+var schemaPatterns sync.Map
+
+// compileSchemaPatterns compiles the patterns of a tool's input schema when the tool is
+// registered. Invalid patterns are logged and not checked.
+func compileSchemaPatterns(toolName string, schema map[string]interface{}) {
+	if pattern, ok := schema["pattern"].(string); ok {
+		if _, compiled := schemaPatterns.Load(pattern); !compiled {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				logger.Warn("Invalid pattern in tool input schema is not checked", "tool", toolName, "pattern", pattern, "error", err)
+			}
+			schemaPatterns.Store(pattern, re)
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, property := range properties {
+			if property, ok := property.(map[string]interface{}); ok {
+				compileSchemaPatterns(toolName, property)
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		compileSchemaPatterns(toolName, items)
+	}
+}
+
+// schemaPattern returns the compiled regular expression of a pattern, or nil if it is invalid
+func schemaPattern(pattern string) *regexp.Regexp {
+	if re, ok := schemaPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	// Schemas changed after their tool was registered
+	re, _ := regexp.Compile(pattern)
+	schemaPatterns.Store(pattern, re)
+	return re
+}
+
+// validateToolArguments checks arguments against the input schema of a registered tool
+func (s *Server) validateToolArguments(name string, args map[string]interface{}) error {
+	s.mu.RLock()
+	tool := s.tools[name]
+	s.mu.RUnlock()
+	if tool == nil || tool.InputSchema == nil {
+		return nil
+	}
+
+	problems := validateObject("", tool.InputSchema, args)
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w for tool '%s': %s", ErrInvalidArguments, name, strings.Join(problems, "; "))
+}
+
+// validateObject validates an object against a schema with properties and required
+// fields. Unknown properties are allowed and null counts as an absent value.
+func validateObject(path string, schema map[string]interface{}, object map[string]interface{}) []string {
+	var problems []string
+
+	for _, field := range schemaStrings(schema["required"]) {
+		if value, ok := object[field]; !ok || value == nil {
+			problems = append(problems, fmt.Sprintf("missing required argument '%s'", path+field))
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok || object[name] == nil {
+			continue
+		}
+		problems = append(problems, validateValue(path+name, property, object[name])...)
+	}
+	return problems
+}
+
+// validateValue validates one value against its property schema (type, enum, string length
+// and pattern, nested objects and arrays)
+func validateValue(path string, schema map[string]interface{}, value interface{}) []string {
+	if schemaType, ok := schema["type"].(string); ok && !matchesSchemaType(schemaType, value) {
+		return []string{fmt.Sprintf("argument '%s' must be %s, got %s", path, articleFor(schemaType), describeValue(value))}
+	}
+
+	if enum := schemaStrings(schema["enum"]); len(enum) > 0 {
+		actual := fmt.Sprintf("%v", value)
+		found := false
+		for _, allowed := range enum {
+			if allowed == actual {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return []string{fmt.Sprintf("argument '%s' must be one of %s, got %q", path, strings.Join(enum, ", "), actual)}
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		if maxLength, ok := schemaInt(schema["maxLength"]); ok && utf8.RuneCountInString(s) > maxLength {
+			return []string{fmt.Sprintf("argument '%s' must be at most %d characters, got %d", path, maxLength, utf8.RuneCountInString(s))}
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re := schemaPattern(pattern); re != nil && !re.MatchString(s) {
+				return []string{fmt.Sprintf("argument '%s' must match %s, got %q", path, pattern, s)}
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := schema["properties"]; ok {
+			return validateObject(path+".", schema, v)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			var problems []string
+			for i, item := range v {
+				if item != nil {
+					problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", path, i), items, item)...)
+				}
+			}
+			return problems
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether a decoded JSON value has the given JSON Schema type.
+// Numeric strings are accepted for numbers, as OData v2 serializes Edm.Int64 and Edm.Decimal as strings.
+func matchesSchemaType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		switch v := value.(type) {
+		case float64:
+			return v == math.Trunc(v)
+		case int, int32, int64:
+			return true
+		case string:
+			_, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			return err == nil
+		}
+		return false
+	case "number":
+		switch v := value.(type) {
+		case float64, float32, int, int32, int64:
+			return true
+		case string:
+			_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return err == nil
+		}
+		return false
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	}
+	return true
+}
+
+// describeValue names the JSON type of a value for error messages
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case float64, float32, int, int32, int64:
+		return fmt.Sprintf("number %v", v)
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func articleFor(schemaType string) string {
+	if schemaType == "integer" || schemaType == "object" || schemaType == "array" {
+		return "an " + schemaType
+	}
+	return "a " + schemaType
+}
+
+// schemaInt reads an integer schema keyword built either as int or decoded JSON
+func schemaInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// schemaStrings reads a string list from a schema keyword built either as []string or decoded JSON
+func schemaStrings(value interface{}) []string {
+	switch values := value.(type) {
+	case []string:
+		return values
+	case []interface{}:
+		result := make([]string, 0, len(values))
+		for _, v := range values {
+			result = append(result, fmt.Sprintf("%v", v))
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package metadata
+
+import (
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// ReferenceV4 references another CSDL document, typically a vocabulary
+type ReferenceV4 struct {
+	XMLName  xml.Name    `xml:"Reference"`
+	URI      string      `xml:"Uri,attr"`
+	Includes []IncludeV4 `xml:"Include"`
+}
+
+// IncludeV4 includes a namespace of a referenced document under an optional alias
+type IncludeV4 struct {
+	XMLName   xml.Name `xml:"Include"`
+	Namespace string   `xml:"Namespace,attr"`
+	Alias     string   `xml:"Alias,attr"`
+}
+
+// AnnotationsV4 applies annotations to an external target (entity type, property or entity set)
+type AnnotationsV4 struct {
+	XMLName     xml.Name       `xml:"Annotations"`
+	Target      string         `xml:"Target,attr"`
+	Qualifier   string         `xml:"Qualifier,attr"`
+	Annotations []AnnotationV4 `xml:"Annotation"`
+}
+
+// AnnotationV4 is an annotation, a record's property value or a collection item. Constant
+// values are given as attributes or as element text; structured values as a nested
+// Record or Collection element.
+type AnnotationV4 struct {
+	XMLName                xml.Name
+	Term                   string         `xml:"Term,attr"`
+	Qualifier              string         `xml:"Qualifier,attr"`
+	Property               string         `xml:"Property,attr"`
+	Bool                   string         `xml:"Bool,attr"`
+	Int                    string         `xml:"Int,attr"`
+	Decimal                string         `xml:"Decimal,attr"`
+	String                 string         `xml:"String,attr"`
+	EnumMember             string         `xml:"EnumMember,attr"`
+	Path                   string         `xml:"Path,attr"`
+	PropertyPath           string         `xml:"PropertyPath,attr"`
+	NavigationPropertyPath string         `xml:"NavigationPropertyPath,attr"`
+	AnnotationPath         string         `xml:"AnnotationPath,attr"`
+	Children               []AnnotationV4 `xml:",any"`
+	Text                   string         `xml:",chardata"`
+}
+
+// value returns the value of an annotation or property value: a constant attribute, the
+// nested expression, or true for a tagging annotation without a value
+func (a AnnotationV4) value() interface{} {
+	for _, attr := range []struct{ kind, text string }{
+		{"Bool", a.Bool}, {"Int", a.Int}, {"Decimal", a.Decimal}, {"String", a.String},
+		{"EnumMember", a.EnumMember}, {"Path", a.Path}, {"PropertyPath", a.PropertyPath},
+		{"NavigationPropertyPath", a.NavigationPropertyPath}, {"AnnotationPath", a.AnnotationPath},
+	} {
+		if attr.text != "" {
+			return constantValue(attr.kind, attr.text)
+		}
+	}
+	for _, child := range a.Children {
+		if child.XMLName.Local != "Annotation" {
+			return child.expression()
+		}
+	}
+	if a.XMLName.Local == "Annotation" {
+		return true
+	}
+	return nil
+}
+
+// expression converts a Record, Collection or constant expression element
+func (a AnnotationV4) expression() interface{} {
+	switch a.XMLName.Local {
+	case "Record":
+		record := make(map[string]interface{})
+		for _, child := range a.Children {
+			if child.XMLName.Local == "PropertyValue" {
+				record[child.Property] = child.value()
+			}
+		}
+		return record
+	case "Collection":
+		items := make([]interface{}, 0, len(a.Children))
+		for _, child := range a.Children {
+			items = append(items, child.expression())
+		}
+		return items
+	}
+	return constantValue(a.XMLName.Local, strings.TrimSpace(a.Text))
+}
+
+// constantValue converts the text of a constant expression to a Go value by its kind
+func constantValue(kind, text string) interface{} {
+	switch kind {
+	case "Bool":
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	case "Int":
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return i
+		}
+	case "Decimal", "Float":
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	}
+	return text
+}
+
+// addAnnotations stores annotations under their namespace-qualified term, followed by
+// #qualifier when qualified. Vocabulary aliases are resolved to their namespace.
+func addAnnotations(target *map[string]interface{}, annotations []AnnotationV4, qualifier string, aliases map[string]string) {
+	for _, annotation := range annotations {
+		if annotation.Term == "" {
+			continue
+		}
+		term := qualifiedTerm(annotation.Term, aliases)
+		if q := annotation.Qualifier; q != "" {
+			term += "#" + q
+		} else if qualifier != "" {
+			term += "#" + qualifier
+		}
+
+		if *target == nil {
+			*target = make(map[string]interface{})
+		}
+		(*target)[term] = annotation.value()
+	}
+}
+
+// qualifiedTerm resolves the vocabulary alias of an annotation term to its namespace
+func qualifiedTerm(term string, aliases map[string]string) string {
+	if i := strings.LastIndex(term, "."); i > 0 {
+		if namespace, ok := aliases[term[:i]]; ok {
+			return namespace + term[i:]
+		}
+	}
+	return term
+}
+
+// referenceAliases returns the namespaces of the vocabularies a document includes by alias
+func referenceAliases(references []ReferenceV4) map[string]string {
+	aliases := make(map[string]string)
+	for _, ref := range references {
+		for _, include := range ref.Includes {
+			if include.Alias != "" {
+				aliases[include.Alias] = include.Namespace
+			}
+		}
+	}
+	return aliases
+}
+
+// capabilitiesNamespace is the namespace of the Org.OData.Capabilities.V1 vocabulary
+const capabilitiesNamespace = "Org.OData.Capabilities.V1."
+
+// applyCapabilities applies the Insert/Update/Delete/FilterRestrictions annotations of an
+// entity set to its capability flags. Restrictions not given leave the flags unchanged.
+func applyCapabilities(entitySet *models.EntitySet) {
+	restriction := func(term, property string) (bool, bool) {
+		record, ok := entitySet.Annotations[capabilitiesNamespace+term].(map[string]interface{})
+		if !ok {
+			return false, false
+		}
+		value, ok := record[property].(bool)
+		return value, ok
+	}
+
+	if insertable, ok := restriction("InsertRestrictions", "Insertable"); ok {
+		entitySet.Creatable = insertable
+	}
+	if updatable, ok := restriction("UpdateRestrictions", "Updatable"); ok {
+		entitySet.Updatable = updatable
+	}
+	if deletable, ok := restriction("DeleteRestrictions", "Deletable"); ok {
+		entitySet.Deletable = deletable
+	}
+	if filterable, ok := restriction("FilterRestrictions", "Filterable"); ok {
+		entitySet.Filterable = filterable
+	}
+
+	if record, ok := entitySet.Annotations[capabilitiesNamespace+"FilterRestrictions"].(map[string]interface{}); ok {
+		paths, _ := record["NonFilterableProperties"].([]interface{})
+		for _, path := range paths {
+			if name, ok := path.(string); ok && name != "" {
+				entitySet.NonFilterableProperties = append(entitySet.NonFilterableProperties, name)
+			}
+		}
+	}
+}
+
+// annotationTarget returns the annotations map of an Annotations target path: an entity
+// type, a property of an entity type, or an entity set of the container. Other targets
+// (functions, complex types, ...) are not kept.
+func annotationTarget(metadata *models.ODataMetadata, typeKeys map[string]string, target string) *map[string]interface{} {
+	parts := strings.Split(target, "/")
+	if len(parts) > 2 {
+		return nil
+	}
+
+	if key, ok := typeKeys[parts[0]]; ok {
+		entityType := metadata.EntityTypes[key]
+		if len(parts) == 1 {
+			return &entityType.Annotations
+		}
+		for _, prop := range entityType.Properties {
+			if prop.Name == parts[1] {
+				return &prop.Annotations
+			}
+		}
+		return nil
+	}
+
+	if len(parts) == 2 && localName(parts[0]) == metadata.ContainerName {
+		if entitySet, ok := metadata.EntitySets[parts[1]]; ok {
+			return &entitySet.Annotations
+		}
+	}
+	return nil
+}
+
+// valueListTerm is the term of the value help annotation of a property
+const valueListTerm = "com.sap.vocabularies.Common.v1.ValueList"
+
+// applyValueLists sets the value help of the entity type properties with a ValueList
+// annotation whose entity set is part of the service. Of qualified variants, the
+// unqualified annotation is preferred, then the first qualifier in sort order.
+func applyValueLists(metadata *models.ODataMetadata) {
+	for _, entityType := range metadata.EntityTypes {
+		for _, prop := range entityType.Properties {
+			record, ok := prop.Annotations[valueListTerm].(map[string]interface{})
+			if !ok {
+				var terms []string
+				for term := range prop.Annotations {
+					if strings.HasPrefix(term, valueListTerm+"#") {
+						terms = append(terms, term)
+					}
+				}
+				sort.Strings(terms)
+				for _, term := range terms {
+					if record, ok = prop.Annotations[term].(map[string]interface{}); ok {
+						break
+					}
+				}
+			}
+			if record == nil {
+				continue
+			}
+			if help := valueHelp(record, prop.Name); help != nil && metadata.EntitySets[help.CollectionPath] != nil {
+				prop.ValueHelp = help
+			}
+		}
+	}
+}
+
+// valueHelp converts a ValueList record of a property. Value helps of other services
+// (CollectionRoot) are not supported.
+func valueHelp(record map[string]interface{}, property string) *models.ValueHelp {
+	collection, _ := record["CollectionPath"].(string)
+	if root, _ := record["CollectionRoot"].(string); collection == "" || root != "" {
+		return nil
+	}
+	help := &models.ValueHelp{CollectionPath: collection}
+	help.Label, _ = record["Label"].(string)
+	help.SearchSupported, _ = record["SearchSupported"].(bool)
+
+	parameters, _ := record["Parameters"].([]interface{})
+	firstLocal := ""
+	for _, item := range parameters {
+		parameter, _ := item.(map[string]interface{})
+		name, _ := parameter["ValueListProperty"].(string)
+		if name == "" {
+			continue
+		}
+		if !contains(help.Properties, name) {
+			help.Properties = append(help.Properties, name)
+		}
+		// In, Out and InOut parameters map a property of the entity to one of the value help
+		if local, _ := parameter["LocalDataProperty"].(string); local == property {
+			help.ValueProperty = name
+		} else if local != "" && firstLocal == "" {
+			firstLocal = name
+		}
+	}
+	if help.ValueProperty == "" {
+		help.ValueProperty = firstLocal
+	}
+	if help.ValueProperty == "" && len(help.Properties) > 0 {
+		help.ValueProperty = help.Properties[0]
+	}
+	return help
+}
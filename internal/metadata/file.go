@@ -0,0 +1,59 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// LoadFile reads service metadata from a file instead of the service: either a $metadata
+// XML document or parsed metadata exported by `odata-mcp metadata` (possibly trimmed by
+// hand). serviceRoot replaces the service root recorded in an export.
+func LoadFile(path, serviceRoot string) (*models.ODataMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		meta, err := ParseMetadata(data, serviceRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse metadata file %s: %w", path, err)
+		}
+		return meta, nil
+	}
+
+	meta, err := FromJSON(data, serviceRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata file %s: %w", path, err)
+	}
+	return meta, nil
+}
+
+// FromJSON decodes parsed metadata serialized as JSON, as written by `odata-mcp metadata`
+// or the metadata cache. serviceRoot, if not empty, replaces the recorded service root.
+func FromJSON(data []byte, serviceRoot string) (*models.ODataMetadata, error) {
+	var meta models.ODataMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	if meta.EntityTypes == nil {
+		meta.EntityTypes = make(map[string]*models.EntityType)
+	}
+	if meta.ComplexTypes == nil {
+		meta.ComplexTypes = make(map[string]*models.ComplexType)
+	}
+	if meta.EntitySets == nil {
+		meta.EntitySets = make(map[string]*models.EntitySet)
+	}
+	if meta.FunctionImports == nil {
+		meta.FunctionImports = make(map[string]*models.FunctionImport)
+	}
+	if serviceRoot != "" {
+		meta.ServiceRoot = serviceRoot
+	}
+	return &meta, nil
+}
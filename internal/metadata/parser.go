@@ -1,8 +1,10 @@
 package metadata
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,33 +16,47 @@ import (
 type EDMX struct {
 	XMLName   xml.Name `xml:"Edmx"`
 	Version   string   `xml:"Version,attr"`
+	References []ReferenceV4 `xml:"Reference"` // Vocabularies of the annotations, e.g. SAP's Common
 	DataServices DataServices `xml:"DataServices"`
 }
 
-// DataServices contains the schema
+// DataServices contains the schemas; SAP services may split types and container across several
 type DataServices struct {
 	XMLName xml.Name `xml:"DataServices"`
-	Schema  Schema   `xml:"Schema"`
+	Schemas []Schema `xml:"Schema"`
 }
 
 // Schema contains entity types, entity sets, and function imports
 type Schema struct {
 	XMLName           xml.Name           `xml:"Schema"`
 	Namespace         string             `xml:"Namespace,attr"`
+	Alias             string             `xml:"Alias,attr"`
 	EntityTypes       []EntityType       `xml:"EntityType"`
+	ComplexTypes      []ComplexType      `xml:"ComplexType"`
+	Associations      []Association      `xml:"Association"`
 	EntityContainer   EntityContainer    `xml:"EntityContainer"`
 	FunctionImports   []FunctionImport   `xml:"FunctionImport"`
+	Annotations       []AnnotationsV4    `xml:"Annotations"` // Vocabulary annotations SAP adds to v2 schemas
 }
 
 // EntityType represents an OData entity type
 type EntityType struct {
 	XMLName    xml.Name    `xml:"EntityType"`
 	Name       string      `xml:"Name,attr"`
+	Label      string      `xml:"label,attr"` // sap:label
+	BaseType   string      `xml:"BaseType,attr"`
 	Key        Key         `xml:"Key"`
 	Properties []Property  `xml:"Property"`
 	NavigationProperties []NavigationProperty `xml:"NavigationProperty"`
 }
 
+// ComplexType represents a complex type
+type ComplexType struct {
+	XMLName    xml.Name   `xml:"ComplexType"`
+	Name       string     `xml:"Name,attr"`
+	Properties []Property `xml:"Property"`
+}
+
 // Key contains key properties
 type Key struct {
 	XMLName        xml.Name        `xml:"Key"`
@@ -62,6 +78,20 @@ type Property struct {
 	MaxLength  string   `xml:"MaxLength,attr"`
 	Precision  string   `xml:"Precision,attr"`
 	Scale      string   `xml:"Scale,attr"`
+	DefaultValue string `xml:"DefaultValue,attr"`
+	// SAP-specific attributes
+	Label      string `xml:"label,attr"`
+	Semantics  string `xml:"semantics,attr"`
+	Text       string `xml:"text,attr"` // Property holding the description of this one
+	Unit       string `xml:"unit,attr"` // Property holding the currency or unit of measure of this one
+	DisplayFormat string `xml:"display-format,attr"`
+	ValueList  string `xml:"value-list,attr"` // standard or fixed-values
+	// SAP hierarchy annotations, naming the node property the property belongs to
+	HierarchyNodeFor            string `xml:"hierarchy-node-for,attr"`
+	HierarchyParentNodeFor      string `xml:"hierarchy-parent-node-for,attr"`
+	HierarchyLevelFor           string `xml:"hierarchy-level-for,attr"`
+	HierarchyDrillStateFor      string `xml:"hierarchy-drill-state-for,attr"`
+	HierarchyDescendantCountFor string `xml:"hierarchy-node-descendant-count-for,attr"`
 }
 
 // NavigationProperty represents a navigation property
@@ -73,11 +103,53 @@ type NavigationProperty struct {
 	FromRole     string   `xml:"FromRole,attr"`
 }
 
+// Association represents a relationship between two entity types
+type Association struct {
+	XMLName               xml.Name               `xml:"Association"`
+	Name                  string                 `xml:"Name,attr"`
+	Ends                  []AssociationEnd       `xml:"End"`
+	ReferentialConstraint *ReferentialConstraint `xml:"ReferentialConstraint"`
+}
+
+// AssociationEnd is one end of an association
+type AssociationEnd struct {
+	Type         string `xml:"Type,attr"`
+	Multiplicity string `xml:"Multiplicity,attr"`
+	Role         string `xml:"Role,attr"`
+}
+
+// ReferentialConstraint maps the dependent end's properties to the principal end's keys
+type ReferentialConstraint struct {
+	Principal ConstraintEnd `xml:"Principal"`
+	Dependent ConstraintEnd `xml:"Dependent"`
+}
+
+// ConstraintEnd is the principal or dependent end of a referential constraint
+type ConstraintEnd struct {
+	Role         string        `xml:"Role,attr"`
+	PropertyRefs []PropertyRef `xml:"PropertyRef"`
+}
+
+// AssociationSet binds the ends of an association to entity sets
+type AssociationSet struct {
+	XMLName     xml.Name            `xml:"AssociationSet"`
+	Name        string              `xml:"Name,attr"`
+	Association string              `xml:"Association,attr"`
+	Ends        []AssociationSetEnd `xml:"End"`
+}
+
+// AssociationSetEnd is one end of an association set
+type AssociationSetEnd struct {
+	Role      string `xml:"Role,attr"`
+	EntitySet string `xml:"EntitySet,attr"`
+}
+
 // EntityContainer contains entity sets and function imports
 type EntityContainer struct {
 	XMLName         xml.Name         `xml:"EntityContainer"`
 	Name            string           `xml:"Name,attr"`
 	EntitySets      []EntitySet      `xml:"EntitySet"`
+	AssociationSets []AssociationSet `xml:"AssociationSet"`
 	FunctionImports []FunctionImport `xml:"FunctionImport"`
 }
 
@@ -87,6 +159,7 @@ type EntitySet struct {
 	Name       string   `xml:"Name,attr"`
 	EntityType string   `xml:"EntityType,attr"`
 	// SAP-specific attributes
+	Label      string `xml:"label,attr"`
 	Creatable  string `xml:"creatable,attr"`
 	Updatable  string `xml:"updatable,attr"`
 	Deletable  string `xml:"deletable,attr"`
@@ -115,47 +188,107 @@ type Parameter struct {
 // ParseMetadata parses OData metadata XML and returns structured metadata
 // It automatically detects whether the metadata is v2 or v4 and uses the appropriate parser
 func ParseMetadata(data []byte, serviceRoot string) (*models.ODataMetadata, error) {
-	// Check if it's OData v4
-	if IsODataV4(data) {
-		return ParseMetadataV4(data, serviceRoot)
+	return ParseMetadataReader(bytes.NewReader(data), serviceRoot)
+}
+
+// parseMetadataV2 converts a decoded OData v2 document to model
+func parseMetadataV2(edmx *EDMX, isSAP bool, serviceRoot string) (*models.ODataMetadata, error) {
+	if len(edmx.DataServices.Schemas) == 0 {
+		return nil, fmt.Errorf("no schemas found in metadata")
 	}
-	
-	// Parse as OData v2
-	var edmx EDMX
-	if err := xml.Unmarshal(data, &edmx); err != nil {
-		return nil, fmt.Errorf("failed to parse metadata XML: %w", err)
+
+	// The namespace and container name are taken from the schema holding the entity container
+	mainSchema := &edmx.DataServices.Schemas[0]
+	for i := range edmx.DataServices.Schemas {
+		if len(edmx.DataServices.Schemas[i].EntityContainer.EntitySets) > 0 {
+			mainSchema = &edmx.DataServices.Schemas[i]
+			break
+		}
 	}
 
-	schema := edmx.DataServices.Schema
-	
 	metadata := &models.ODataMetadata{
 		ServiceRoot:     serviceRoot,
 		EntityTypes:     make(map[string]*models.EntityType),
+		ComplexTypes:    make(map[string]*models.ComplexType),
 		EntitySets:      make(map[string]*models.EntitySet),
 		FunctionImports: make(map[string]*models.FunctionImport),
-		SchemaNamespace: schema.Namespace,
-		ContainerName:   schema.EntityContainer.Name,
+		SchemaNamespace: mainSchema.Namespace,
+		ContainerName:   mainSchema.EntityContainer.Name,
 		Version:         edmx.Version,
+		IsSAP:           isSAP,
 		ParsedAt:        time.Now(),
 	}
 
-	// Parse entity types
-	for _, et := range schema.EntityTypes {
-		entityType := parseEntityType(et)
-		metadata.EntityTypes[et.Name] = entityType
+	// Parse entity types from all schemas
+	typeKeys := make(map[string]string)
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, et := range schema.EntityTypes {
+			registerEntityType(metadata, typeKeys, schema.Namespace, schema.Alias, parseEntityType(et))
+			if et.BaseType != "" {
+				metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("EntityType %s derives from %s; inherited properties and keys are not resolved", et.Name, et.BaseType))
+			}
+		}
 	}
 
-	// Parse entity sets
-	for _, es := range schema.EntityContainer.EntitySets {
-		entitySet := parseEntitySet(es, schema.Namespace)
-		metadata.EntitySets[es.Name] = entitySet
+	// Parse complex types from all schemas
+	complexKeys := make(map[string]string)
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			complexType := &models.ComplexType{Name: ct.Name}
+			for _, prop := range ct.Properties {
+				complexType.Properties = append(complexType.Properties, parseProperty(prop, nil))
+			}
+			registerComplexType(metadata, complexKeys, schema.Namespace, schema.Alias, complexType)
+		}
 	}
+	linkComplexTypes(metadata, complexKeys)
+
+	// Parse entity sets and function imports of all containers
+	var associations []Association
+	var associationSets []AssociationSet
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, es := range schema.EntityContainer.EntitySets {
+			entitySet := parseEntitySet(es, schema.Namespace)
+			entitySet.EntityType = resolveTypeKey(typeKeys, es.EntityType)
+			metadata.EntitySets[es.Name] = entitySet
+		}
+		for _, fi := range schema.EntityContainer.FunctionImports {
+			functionImport := parseFunctionImport(fi)
+			functionImport.Namespace = schema.Namespace
+			metadata.FunctionImports[fi.Name] = functionImport
+		}
+		associations = append(associations, schema.Associations...)
+		associationSets = append(associationSets, schema.EntityContainer.AssociationSets...)
+	}
+
+	// Resolve navigation targets and key mappings from associations
+	resolveAssociations(metadata, typeKeys, associations, associationSets)
+	linkNavigationTargets(metadata)
 
-	// Parse function imports
-	for _, fi := range schema.EntityContainer.FunctionImports {
-		functionImport := parseFunctionImport(fi)
-		metadata.FunctionImports[fi.Name] = functionImport
+	// Of the vocabulary annotations, only the value helps are used in v2
+	aliases := referenceAliases(edmx.References)
+	for _, schema := range edmx.DataServices.Schemas {
+		if schema.Alias != "" {
+			aliases[schema.Alias] = schema.Namespace
+		}
+	}
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, group := range schema.Annotations {
+			var valueLists []AnnotationV4
+			for _, annotation := range group.Annotations {
+				if qualifiedTerm(annotation.Term, aliases) == valueListTerm {
+					valueLists = append(valueLists, annotation)
+				}
+			}
+			if len(valueLists) == 0 {
+				continue
+			}
+			if target := annotationTarget(metadata, typeKeys, group.Target); target != nil {
+				addAnnotations(target, valueLists, group.Qualifier, aliases)
+			}
+		}
 	}
+	applyValueLists(metadata)
 
 	return metadata, nil
 }
@@ -164,6 +297,7 @@ func ParseMetadata(data []byte, serviceRoot string) (*models.ODataMetadata, erro
 func parseEntityType(et EntityType) *models.EntityType {
 	entityType := &models.EntityType{
 		Name:            et.Name,
+		Label:           et.Label,
 		Properties:      make([]*models.EntityProperty, 0),
 		KeyProperties:   make([]string, 0),
 		NavigationProps: make([]*models.NavigationProperty, 0),
@@ -176,14 +310,9 @@ func parseEntityType(et EntityType) *models.EntityType {
 
 	// Parse properties
 	for _, prop := range et.Properties {
-		property := &models.EntityProperty{
-			Name:     prop.Name,
-			Type:     prop.Type,
-			Nullable: prop.Nullable != "false", // Default to true if not specified
-			IsKey:    contains(entityType.KeyProperties, prop.Name),
-		}
-		entityType.Properties = append(entityType.Properties, property)
+		entityType.Properties = append(entityType.Properties, parseProperty(prop, entityType.KeyProperties))
 	}
+	entityType.Hierarchy = parseHierarchy(et.Properties)
 
 	// Parse navigation properties
 	for _, navProp := range et.NavigationProperties {
@@ -199,6 +328,206 @@ func parseEntityType(et EntityType) *models.EntityType {
 	return entityType
 }
 
+// parseHierarchy returns the hierarchy of an entity type whose properties carry the SAP
+// hierarchy annotations, or nil if it lacks a node or parent node property
+func parseHierarchy(properties []Property) *models.Hierarchy {
+	hierarchy := &models.Hierarchy{}
+	for _, prop := range properties {
+		switch {
+		case prop.HierarchyNodeFor != "":
+			hierarchy.NodeProperty = prop.Name
+		case prop.HierarchyParentNodeFor != "":
+			hierarchy.ParentProperty = prop.Name
+		case prop.HierarchyLevelFor != "":
+			hierarchy.LevelProperty = prop.Name
+		case prop.HierarchyDrillStateFor != "":
+			hierarchy.DrillStateProperty = prop.Name
+		case prop.HierarchyDescendantCountFor != "":
+			hierarchy.DescendantCountProperty = prop.Name
+		}
+	}
+	if hierarchy.NodeProperty == "" || hierarchy.ParentProperty == "" {
+		return nil
+	}
+	return hierarchy
+}
+
+// parseProperty converts an XML property of an entity or complex type to model
+func parseProperty(prop Property, keyProperties []string) *models.EntityProperty {
+	property := &models.EntityProperty{
+		Name:      prop.Name,
+		Type:      prop.Type,
+		Nullable:  prop.Nullable != "false", // Default to true if not specified
+		IsKey:     contains(keyProperties, prop.Name),
+		Label:     prop.Label,
+		Semantics: prop.Semantics,
+		Text:      prop.Text,
+		Unit:      prop.Unit,
+		DisplayFormat: prop.DisplayFormat,
+		ValueList: prop.ValueList,
+	}
+	parseFacets(property, prop.MaxLength, prop.Precision, prop.Scale, prop.DefaultValue)
+	return property
+}
+
+// parseFacets copies the MaxLength, Precision, Scale and DefaultValue facets of a
+// property. Non-numeric values such as MaxLength="max" or Scale="variable" are ignored.
+func parseFacets(property *models.EntityProperty, maxLength, precision, scale, defaultValue string) {
+	if n, err := strconv.Atoi(maxLength); err == nil && n > 0 {
+		property.MaxLength = n
+	}
+	if n, err := strconv.Atoi(precision); err == nil && n > 0 {
+		property.Precision = n
+	}
+	if n, err := strconv.Atoi(scale); err == nil && n >= 0 {
+		property.Scale = &n
+	}
+	property.DefaultValue = defaultValue
+}
+
+// linkComplexTypes marks the properties of entity and complex types whose type (or
+// collection item type) is one of the parsed complex types. Qualified type names are
+// resolved through complexKeys, so that equally named types of other schemas are told apart.
+func linkComplexTypes(metadata *models.ODataMetadata, complexKeys map[string]string) {
+	link := func(prop *models.EntityProperty) {
+		typeName := prop.Type
+		if strings.HasPrefix(typeName, "Collection(") && strings.HasSuffix(typeName, ")") {
+			typeName = typeName[len("Collection(") : len(typeName)-1]
+			prop.IsCollection = true
+		}
+		if strings.HasPrefix(typeName, "Edm.") {
+			return
+		}
+		key := resolveTypeKey(complexKeys, typeName)
+		if _, ok := metadata.ComplexTypes[key]; ok {
+			prop.ComplexType = key
+		}
+	}
+
+	for _, entityType := range metadata.EntityTypes {
+		for _, prop := range entityType.Properties {
+			link(prop)
+		}
+	}
+	for _, complexType := range metadata.ComplexTypes {
+		for _, prop := range complexType.Properties {
+			link(prop)
+		}
+	}
+}
+
+// resolveAssociations fills in the target entity type, multiplicity, target entity set and
+// referential constraints of v2 navigation properties from their associations
+func resolveAssociations(metadata *models.ODataMetadata, typeKeys map[string]string, associationList []Association, associationSets []AssociationSet) {
+	associations := make(map[string]Association)
+	for _, a := range associationList {
+		associations[a.Name] = a
+	}
+
+	for _, entityType := range metadata.EntityTypes {
+		for _, nav := range entityType.NavigationProps {
+			name := localName(nav.Relationship)
+			association, ok := associations[name]
+			if !ok {
+				continue
+			}
+
+			for _, end := range association.Ends {
+				if end.Role == nav.ToRole {
+					nav.TargetEntityType = resolveTypeKey(typeKeys, end.Type)
+					nav.IsCollection = end.Multiplicity == "*"
+				}
+			}
+
+			for _, set := range associationSets {
+				if localName(set.Association) != name {
+					continue
+				}
+				for _, end := range set.Ends {
+					if end.Role == nav.ToRole {
+						nav.TargetEntitySet = end.EntitySet
+					}
+				}
+				break
+			}
+
+			// Constraints are stated from the dependent end; flip them when navigating
+			// from the principal
+			if rc := association.ReferentialConstraint; rc != nil {
+				from, to := rc.Dependent, rc.Principal
+				if rc.Principal.Role == nav.FromRole {
+					from, to = rc.Principal, rc.Dependent
+				}
+				for i := 0; i < len(from.PropertyRefs) && i < len(to.PropertyRefs); i++ {
+					nav.ReferentialConstraints = append(nav.ReferentialConstraints, &models.ReferentialConstraint{
+						Property:           from.PropertyRefs[i].Name,
+						ReferencedProperty: to.PropertyRefs[i].Name,
+					})
+				}
+			}
+		}
+	}
+}
+
+// linkNavigationTargets sets the target entity set of navigation properties the metadata
+// does not bind explicitly, when exactly one entity set holds the target entity type
+func linkNavigationTargets(metadata *models.ODataMetadata) {
+	setsByType := make(map[string][]string)
+	for name, es := range metadata.EntitySets {
+		setsByType[es.EntityType] = append(setsByType[es.EntityType], name)
+	}
+
+	for _, entityType := range metadata.EntityTypes {
+		for _, nav := range entityType.NavigationProps {
+			if nav.TargetEntitySet == "" && len(setsByType[nav.TargetEntityType]) == 1 {
+				nav.TargetEntitySet = setsByType[nav.TargetEntityType][0]
+			}
+		}
+	}
+}
+
+// registerEntityType adds an entity type under its name, or under its namespace-qualified
+// name when another schema already declares that name, and records the namespace- and
+// alias-qualified names it is referenced by
+func registerEntityType(metadata *models.ODataMetadata, typeKeys map[string]string, namespace, alias string, entityType *models.EntityType) {
+	key := entityType.Name
+	if _, exists := metadata.EntityTypes[key]; exists {
+		key = namespace + "." + entityType.Name
+	}
+	metadata.EntityTypes[key] = entityType
+	typeKeys[namespace+"."+entityType.Name] = key
+	if alias != "" {
+		typeKeys[alias+"."+entityType.Name] = key
+	}
+}
+
+// registerComplexType adds a complex type under its name, or under its namespace-qualified
+// name when another schema already declares that name, like registerEntityType
+func registerComplexType(metadata *models.ODataMetadata, complexKeys map[string]string, namespace, alias string, complexType *models.ComplexType) {
+	key := complexType.Name
+	if _, exists := metadata.ComplexTypes[key]; exists {
+		key = namespace + "." + complexType.Name
+	}
+	metadata.ComplexTypes[key] = complexType
+	complexKeys[namespace+"."+complexType.Name] = key
+	if alias != "" {
+		complexKeys[alias+"."+complexType.Name] = key
+	}
+}
+
+// resolveTypeKey returns the key in EntityTypes (or ComplexTypes) of a qualified type name
+func resolveTypeKey(typeKeys map[string]string, qualifiedName string) string {
+	if key, ok := typeKeys[qualifiedName]; ok {
+		return key
+	}
+	return localName(qualifiedName)
+}
+
+// localName strips the namespace (or alias) from a qualified name
+func localName(qualifiedName string) string {
+	return qualifiedName[strings.LastIndex(qualifiedName, ".")+1:]
+}
+
 // parseEntitySet converts XML entity set to model
 func parseEntitySet(es EntitySet, namespace string) *models.EntitySet {
 	// Remove namespace prefix from entity type if present
@@ -211,11 +540,14 @@ func parseEntitySet(es EntitySet, namespace string) *models.EntitySet {
 	entitySet := &models.EntitySet{
 		Name:       es.Name,
 		EntityType: entityTypeName,
+		Namespace:  namespace,
+		Label:      es.Label,
 		Creatable:  es.Creatable != "false", // Default to true
 		Updatable:  es.Updatable != "false", // Default to true
 		Deletable:  es.Deletable != "false", // Default to true
 		Searchable: es.Searchable == "true",  // Default to false
 		Pageable:   es.Pageable != "false",   // Default to true
+		Filterable: true,
 	}
 
 	return entitySet
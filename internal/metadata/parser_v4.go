@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"strings"
@@ -13,6 +14,7 @@ import (
 type EDMXV4 struct {
 	XMLName      xml.Name       `xml:"Edmx"`
 	Version      string         `xml:"Version,attr"`
+	References   []ReferenceV4  `xml:"Reference"`
 	DataServices DataServicesV4 `xml:"DataServices"`
 }
 
@@ -26,12 +28,14 @@ type DataServicesV4 struct {
 type SchemaV4 struct {
 	XMLName          xml.Name           `xml:"Schema"`
 	Namespace        string             `xml:"Namespace,attr"`
+	Alias            string             `xml:"Alias,attr"`
 	EntityTypes      []EntityTypeV4     `xml:"EntityType"`
 	ComplexTypes     []ComplexTypeV4    `xml:"ComplexType"`
 	EnumTypes        []EnumTypeV4       `xml:"EnumType"`
 	EntityContainers []EntityContainerV4 `xml:"EntityContainer"`
 	Functions        []FunctionV4        `xml:"Function"`
 	Actions          []ActionV4          `xml:"Action"`
+	Annotations      []AnnotationsV4     `xml:"Annotations"`
 }
 
 // EntityTypeV4 represents an OData v4 entity type
@@ -44,6 +48,7 @@ type EntityTypeV4 struct {
 	Key                  KeyV4                  `xml:"Key"`
 	Properties           []PropertyV4           `xml:"Property"`
 	NavigationProperties []NavigationPropertyV4 `xml:"NavigationProperty"`
+	Annotations          []AnnotationV4         `xml:"Annotation"`
 }
 
 // ComplexTypeV4 represents an OData v4 complex type
@@ -96,6 +101,7 @@ type PropertyV4 struct {
 	Scale         string   `xml:"Scale,attr"`
 	Unicode       string   `xml:"Unicode,attr"`
 	DefaultValue  string   `xml:"DefaultValue,attr"`
+	Annotations   []AnnotationV4 `xml:"Annotation"`
 }
 
 // NavigationPropertyV4 represents a navigation property in OData v4
@@ -106,6 +112,13 @@ type NavigationPropertyV4 struct {
 	Nullable             string   `xml:"Nullable,attr"`
 	Partner              string   `xml:"Partner,attr"`
 	ContainsTarget       string   `xml:"ContainsTarget,attr"`
+	ReferentialConstraints []ReferentialConstraintV4 `xml:"ReferentialConstraint"`
+}
+
+// ReferentialConstraintV4 maps a property of the declaring entity to one of the target entity
+type ReferentialConstraintV4 struct {
+	Property           string `xml:"Property,attr"`
+	ReferencedProperty string `xml:"ReferencedProperty,attr"`
 }
 
 // EntityContainerV4 contains entity sets and singletons for OData v4
@@ -125,6 +138,7 @@ type EntitySetV4 struct {
 	Name                     string                       `xml:"Name,attr"`
 	EntityType               string                       `xml:"EntityType,attr"`
 	NavigationPropertyBindings []NavigationPropertyBinding `xml:"NavigationPropertyBinding"`
+	Annotations              []AnnotationV4               `xml:"Annotation"`
 }
 
 // SingletonV4 represents an OData v4 singleton
@@ -195,11 +209,20 @@ type ReturnTypeV4 struct {
 
 // ParseMetadataV4 parses OData v4 metadata XML and returns structured metadata
 func ParseMetadataV4(data []byte, serviceRoot string) (*models.ODataMetadata, error) {
-	var edmx EDMXV4
-	if err := xml.Unmarshal(data, &edmx); err != nil {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	root, err := readRoot(d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse v4 metadata XML: %w", err)
+	}
+	edmx, err := decodeEDMXV4(d, root)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse v4 metadata XML: %w", err)
 	}
+	return parseMetadataV4(edmx, serviceRoot)
+}
 
+// parseMetadataV4 converts a decoded OData v4 document to model
+func parseMetadataV4(edmx *EDMXV4, serviceRoot string) (*models.ODataMetadata, error) {
 	if len(edmx.DataServices.Schemas) == 0 {
 		return nil, fmt.Errorf("no schemas found in metadata")
 	}
@@ -224,6 +247,7 @@ func ParseMetadataV4(data []byte, serviceRoot string) (*models.ODataMetadata, er
 	metadata := &models.ODataMetadata{
 		ServiceRoot:     serviceRoot,
 		EntityTypes:     make(map[string]*models.EntityType),
+		ComplexTypes:    make(map[string]*models.ComplexType),
 		EntitySets:      make(map[string]*models.EntitySet),
 		FunctionImports: make(map[string]*models.FunctionImport),
 		SchemaNamespace: mainSchema.Namespace,
@@ -233,35 +257,173 @@ func ParseMetadataV4(data []byte, serviceRoot string) (*models.ODataMetadata, er
 	}
 
 	// Parse entity types from all schemas
+	typeKeys := make(map[string]string)
 	for _, schema := range edmx.DataServices.Schemas {
 		for _, et := range schema.EntityTypes {
-			entityType := parseEntityTypeV4(et)
-			metadata.EntityTypes[et.Name] = entityType
+			registerEntityType(metadata, typeKeys, schema.Namespace, schema.Alias, parseEntityTypeV4(et))
+			if et.BaseType != "" {
+				metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("EntityType %s derives from %s; inherited properties and keys are not resolved", et.Name, et.BaseType))
+			}
+			for _, nav := range et.NavigationProperties {
+				if nav.ContainsTarget == "true" {
+					metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("Containment navigation property %s.%s is only reachable through $expand", et.Name, nav.Name))
+				}
+			}
+		}
+		for _, function := range schema.Functions {
+			if function.IsBound == "true" {
+				metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("Bound function %s is not exposed as a tool", function.Name))
+			}
+		}
+		for _, action := range schema.Actions {
+			if action.IsBound == "true" {
+				metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("Bound action %s is not exposed as a tool", action.Name))
+			}
+		}
+	}
+	for _, entityType := range metadata.EntityTypes {
+		for _, nav := range entityType.NavigationProps {
+			nav.TargetEntityType = resolveTypeKey(typeKeys, strings.TrimSuffix(strings.TrimPrefix(nav.Type, "Collection("), ")"))
+		}
+	}
+
+	// Parse complex types from all schemas
+	complexTypes := make(map[string]ComplexTypeV4)
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			complexTypes[schema.Namespace+"."+ct.Name] = ct
+			if schema.Alias != "" {
+				complexTypes[schema.Alias+"."+ct.Name] = ct
+			}
+		}
+	}
+	complexKeys := make(map[string]string)
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			registerComplexType(metadata, complexKeys, schema.Namespace, schema.Alias, parseComplexTypeV4(ct, complexTypes))
 		}
 	}
+	linkComplexTypes(metadata, complexKeys)
 
 	// Parse entity sets
 	for _, es := range mainContainer.EntitySets {
 		entitySet := parseEntitySetV4(es, mainSchema.Namespace)
+		entitySet.EntityType = resolveTypeKey(typeKeys, es.EntityType)
 		metadata.EntitySets[es.Name] = entitySet
 	}
 
+	// Resolve navigation targets from the entity sets' navigation property bindings
+	for _, es := range mainContainer.EntitySets {
+		entityType := metadata.EntityTypes[metadata.EntitySets[es.Name].EntityType]
+		if entityType == nil {
+			continue
+		}
+		for _, binding := range es.NavigationPropertyBindings {
+			path := binding.Path[strings.LastIndex(binding.Path, "/")+1:]
+			for _, nav := range entityType.NavigationProps {
+				if nav.Name == path && nav.TargetEntitySet == "" {
+					nav.TargetEntitySet = binding.Target[strings.LastIndex(binding.Target, "/")+1:]
+				}
+			}
+		}
+	}
+	linkNavigationTargets(metadata)
+
+	// Collect inline and external annotations, resolving vocabulary aliases to namespaces
+	aliases := referenceAliases(edmx.References)
+	for _, schema := range edmx.DataServices.Schemas {
+		if schema.Alias != "" {
+			aliases[schema.Alias] = schema.Namespace
+		}
+	}
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, et := range schema.EntityTypes {
+			entityType := metadata.EntityTypes[typeKeys[schema.Namespace+"."+et.Name]]
+			addAnnotations(&entityType.Annotations, et.Annotations, "", aliases)
+			for i, prop := range et.Properties {
+				addAnnotations(&entityType.Properties[i].Annotations, prop.Annotations, "", aliases)
+			}
+		}
+	}
+	for _, es := range mainContainer.EntitySets {
+		addAnnotations(&metadata.EntitySets[es.Name].Annotations, es.Annotations, "", aliases)
+	}
+	for _, schema := range edmx.DataServices.Schemas {
+		for _, group := range schema.Annotations {
+			if target := annotationTarget(metadata, typeKeys, group.Target); target != nil {
+				addAnnotations(target, group.Annotations, group.Qualifier, aliases)
+			}
+		}
+	}
+
+	// Common.Label names entity sets and types like sap:label does in v2
+	const labelTerm = "com.sap.vocabularies.Common.v1.Label"
+	for _, entityType := range metadata.EntityTypes {
+		entityType.Label, _ = entityType.Annotations[labelTerm].(string)
+		for _, prop := range entityType.Properties {
+			prop.Label, _ = prop.Annotations[labelTerm].(string)
+		}
+	}
+	for _, entitySet := range metadata.EntitySets {
+		entitySet.Label, _ = entitySet.Annotations[labelTerm].(string)
+	}
+
+	// Capabilities restrictions narrow the operations assumed above
+	for _, entitySet := range metadata.EntitySets {
+		applyCapabilities(entitySet)
+	}
+	applyValueLists(metadata)
+
+	// Measures annotations with a path name the currency or unit property of an amount or
+	// quantity, like sap:unit does in v2
+	for _, entityType := range metadata.EntityTypes {
+		names := make(map[string]bool, len(entityType.Properties))
+		for _, prop := range entityType.Properties {
+			names[prop.Name] = true
+		}
+		for _, prop := range entityType.Properties {
+			for _, term := range []string{"Org.OData.Measures.V1.ISOCurrency", "Org.OData.Measures.V1.Unit"} {
+				if unit, ok := prop.Annotations[term].(string); ok && names[unit] && prop.Unit == "" {
+					prop.Unit = unit
+				}
+			}
+		}
+	}
+
+	// Functions and actions may be declared in any schema
+	var functions []FunctionV4
+	var actions []ActionV4
+	for _, schema := range edmx.DataServices.Schemas {
+		functions = append(functions, schema.Functions...)
+		actions = append(actions, schema.Actions...)
+	}
+
 	// Parse function imports
 	for _, fi := range mainContainer.FunctionImports {
-		functionImport := parseFunctionImportV4(fi, mainSchema.Functions)
+		functionImport := parseFunctionImportV4(fi, functions)
 		if functionImport != nil {
+			functionImport.Namespace = mainSchema.Namespace
 			metadata.FunctionImports[fi.Name] = functionImport
+		} else {
+			metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("FunctionImport %s references function %s, which is not declared", fi.Name, fi.Function))
 		}
 	}
 
 	// Parse action imports as function imports (for compatibility)
 	for _, ai := range mainContainer.ActionImports {
-		actionImport := parseActionImportV4(ai, mainSchema.Actions)
+		actionImport := parseActionImportV4(ai, actions)
 		if actionImport != nil {
+			actionImport.Namespace = mainSchema.Namespace
 			metadata.FunctionImports[ai.Name] = actionImport
+		} else {
+			metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("ActionImport %s references action %s, which is not declared", ai.Name, ai.Action))
 		}
 	}
 
+	for _, singleton := range mainContainer.Singletons {
+		metadata.Unsupported = append(metadata.Unsupported, fmt.Sprintf("Singleton %s is not exposed as a tool", singleton.Name))
+	}
+
 	return metadata, nil
 }
 
@@ -283,10 +445,11 @@ func parseEntityTypeV4(et EntityTypeV4) *models.EntityType {
 	for _, prop := range et.Properties {
 		property := &models.EntityProperty{
 			Name:     prop.Name,
-			Type:     normalizeTypeV4(prop.Type),
+			Type:     prop.Type, // kept qualified to tell equally named complex types apart
 			Nullable: prop.Nullable != "false",
 			IsKey:    contains(entityType.KeyProperties, prop.Name),
 		}
+		parseFacets(property, prop.MaxLength, prop.Precision, prop.Scale, prop.DefaultValue)
 		entityType.Properties = append(entityType.Properties, property)
 	}
 
@@ -298,12 +461,55 @@ func parseEntityTypeV4(et EntityTypeV4) *models.EntityType {
 			Partner:  navProp.Partner,
 			Nullable: navProp.Nullable != "false",
 		}
+		navigationProp.IsCollection = strings.HasPrefix(navProp.Type, "Collection(")
+		for _, rc := range navProp.ReferentialConstraints {
+			navigationProp.ReferentialConstraints = append(navigationProp.ReferentialConstraints, &models.ReferentialConstraint{
+				Property:           rc.Property,
+				ReferencedProperty: rc.ReferencedProperty,
+			})
+		}
 		entityType.NavigationProps = append(entityType.NavigationProps, navigationProp)
 	}
 
 	return entityType
 }
 
+// parseComplexTypeV4 converts XML complex type to model for OData v4. Properties of
+// base types come first, as in the serialized form of the type. Base types are looked up
+// in all by their namespace- or alias-qualified name.
+func parseComplexTypeV4(ct ComplexTypeV4, all map[string]ComplexTypeV4) *models.ComplexType {
+	complexType := &models.ComplexType{
+		Name:     ct.Name,
+		BaseType: normalizeTypeV4(ct.BaseType),
+	}
+
+	// Walk up the inheritance chain, guarding against cycles
+	chain := []ComplexTypeV4{ct}
+	seen := make(map[string]bool)
+	for base := ct.BaseType; base != "" && !seen[base]; {
+		seen[base] = true
+		found, ok := all[base]
+		if !ok {
+			break
+		}
+		chain = append([]ComplexTypeV4{found}, chain...)
+		base = found.BaseType
+	}
+
+	for _, t := range chain {
+		for _, prop := range t.Properties {
+			property := &models.EntityProperty{
+				Name:     prop.Name,
+				Type:     prop.Type,
+				Nullable: prop.Nullable != "false",
+			}
+			parseFacets(property, prop.MaxLength, prop.Precision, prop.Scale, prop.DefaultValue)
+			complexType.Properties = append(complexType.Properties, property)
+		}
+	}
+	return complexType
+}
+
 // parseEntitySetV4 converts XML entity set to model for OData v4
 func parseEntitySetV4(es EntitySetV4, namespace string) *models.EntitySet {
 	// Remove namespace prefix from entity type if present
@@ -316,6 +522,7 @@ func parseEntitySetV4(es EntitySetV4, namespace string) *models.EntitySet {
 	return &models.EntitySet{
 		Name:       es.Name,
 		EntityType: entityTypeName,
+		Namespace:  namespace,
 		// OData v4 doesn't have explicit CRUD capability attributes in metadata
 		// We assume all operations are allowed unless restricted by service
 		Creatable:  true,
@@ -323,6 +530,7 @@ func parseEntitySetV4(es EntitySetV4, namespace string) *models.EntitySet {
 		Deletable:  true,
 		Searchable: true,
 		Pageable:   true,
+		Filterable: true,
 	}
 }
 
@@ -451,9 +659,9 @@ func normalizeTypeV4(typeName string) string {
 
 // IsODataV4 checks if the metadata is OData v4
 func IsODataV4(data []byte) bool {
-	var edmx EDMXV4
-	if err := xml.Unmarshal(data, &edmx); err != nil {
+	root, err := readRoot(xml.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
 		return false
 	}
-	return edmx.Version == "4.0" || edmx.Version == "4.01"
+	return isV4Version(attrValue(root, "Version"))
 }
\ No newline at end of file
@@ -0,0 +1,186 @@
+package metadata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/models"
+)
+
+// ParseMetadataReader parses an OData v2 or v4 metadata document from a stream. The
+// document is decoded one schema element at a time rather than read into memory and
+// unmarshalled as a whole, which keeps multi-megabyte S/4HANA documents cheap to load.
+// Elements the model does not use are skipped.
+func ParseMetadataReader(r io.Reader, serviceRoot string) (*models.ODataMetadata, error) {
+	d := xml.NewDecoder(r)
+	root, err := readRoot(d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata XML: %w", err)
+	}
+
+	if isV4Version(attrValue(root, "Version")) {
+		edmx, err := decodeEDMXV4(d, root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse v4 metadata XML: %w", err)
+		}
+		return parseMetadataV4(edmx, serviceRoot)
+	}
+
+	edmx, isSAP, err := decodeEDMX(d, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata XML: %w", err)
+	}
+	return parseMetadataV2(edmx, isSAP, serviceRoot)
+}
+
+// readRoot reads up to and including the Edmx root element
+func readRoot(d *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Local != "Edmx" {
+				return start, fmt.Errorf("expected Edmx root element, got %s", start.Name.Local)
+			}
+			return start, nil
+		}
+	}
+}
+
+// forEachChild calls visit for each child element of the element whose start tag was
+// read last, up to its end tag. visit must consume the child (DecodeElement or Skip).
+func forEachChild(d *xml.Decoder, visit func(start xml.StartElement) error) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := visit(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// decodeEDMX decodes an OData v2 document after its root element and reports whether
+// it declares the SAP annotation namespace
+func decodeEDMX(d *xml.Decoder, root xml.StartElement) (*EDMX, bool, error) {
+	edmx := &EDMX{XMLName: root.Name, Version: attrValue(root, "Version")}
+	isSAP := declaresSAP(root)
+
+	err := forEachChild(d, func(start xml.StartElement) error {
+		if start.Name.Local == "Reference" {
+			return d.DecodeElement(&edmx.References, &start)
+		}
+		if start.Name.Local != "DataServices" {
+			return d.Skip()
+		}
+		isSAP = isSAP || declaresSAP(start)
+		return forEachChild(d, func(start xml.StartElement) error {
+			if start.Name.Local != "Schema" {
+				return d.Skip()
+			}
+			isSAP = isSAP || declaresSAP(start)
+
+			schema := Schema{XMLName: start.Name, Namespace: attrValue(start, "Namespace"), Alias: attrValue(start, "Alias")}
+			err := forEachChild(d, func(start xml.StartElement) error {
+				switch start.Name.Local {
+				case "EntityType":
+					return d.DecodeElement(&schema.EntityTypes, &start)
+				case "ComplexType":
+					return d.DecodeElement(&schema.ComplexTypes, &start)
+				case "Association":
+					return d.DecodeElement(&schema.Associations, &start)
+				case "EntityContainer":
+					return d.DecodeElement(&schema.EntityContainer, &start)
+				case "FunctionImport":
+					return d.DecodeElement(&schema.FunctionImports, &start)
+				case "Annotations":
+					return d.DecodeElement(&schema.Annotations, &start)
+				default:
+					return d.Skip()
+				}
+			})
+			edmx.DataServices.Schemas = append(edmx.DataServices.Schemas, schema)
+			return err
+		})
+	})
+	return edmx, isSAP, err
+}
+
+// decodeEDMXV4 decodes an OData v4 document after its root element
+func decodeEDMXV4(d *xml.Decoder, root xml.StartElement) (*EDMXV4, error) {
+	edmx := &EDMXV4{XMLName: root.Name, Version: attrValue(root, "Version")}
+
+	err := forEachChild(d, func(start xml.StartElement) error {
+		if start.Name.Local == "Reference" {
+			return d.DecodeElement(&edmx.References, &start)
+		}
+		if start.Name.Local != "DataServices" {
+			return d.Skip()
+		}
+		return forEachChild(d, func(start xml.StartElement) error {
+			if start.Name.Local != "Schema" {
+				return d.Skip()
+			}
+
+			schema := SchemaV4{XMLName: start.Name, Namespace: attrValue(start, "Namespace"), Alias: attrValue(start, "Alias")}
+			err := forEachChild(d, func(start xml.StartElement) error {
+				switch start.Name.Local {
+				case "EntityType":
+					return d.DecodeElement(&schema.EntityTypes, &start)
+				case "ComplexType":
+					return d.DecodeElement(&schema.ComplexTypes, &start)
+				case "EnumType":
+					return d.DecodeElement(&schema.EnumTypes, &start)
+				case "EntityContainer":
+					return d.DecodeElement(&schema.EntityContainers, &start)
+				case "Function":
+					return d.DecodeElement(&schema.Functions, &start)
+				case "Action":
+					return d.DecodeElement(&schema.Actions, &start)
+				case "Annotations":
+					return d.DecodeElement(&schema.Annotations, &start)
+				default:
+					return d.Skip()
+				}
+			})
+			edmx.DataServices.Schemas = append(edmx.DataServices.Schemas, schema)
+			return err
+		})
+	})
+	return edmx, err
+}
+
+// attrValue returns the value of an element's attribute, ignoring its namespace
+func attrValue(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// declaresSAP reports whether an element declares or uses the SAP annotation namespace
+func declaresSAP(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Value == constants.SAPNamespace || attr.Name.Space == constants.SAPNamespace {
+			return true
+		}
+	}
+	return false
+}
+
+// isV4Version reports whether an Edmx version is OData v4
+func isV4Version(version string) bool {
+	return version == "4.0" || version == "4.01"
+}
@@ -0,0 +1,318 @@
+// Package metrics records tool calls, OData backend requests and cache lookups and renders
+// them in the Prometheus text exposition format, served on /metrics with --transport http,
+// or as a Stats snapshot for the bridge_stats tool. The metrics of all sessions are
+// recorded in Default.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DurationBuckets are the upper bounds (seconds) of the latency histograms
+var DurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Default is the registry the bridge and the OData client record into
+var Default = NewRegistry()
+
+// Registry holds the metrics of tool calls and backend requests
+type Registry struct {
+	mu       sync.Mutex
+	started  time.Time
+	tools    map[string]*toolMetrics
+	statuses map[string]uint64 // Backend requests by HTTP status code ("error" without a response)
+	backend  histogram         // Backend request latency
+	caches   map[string]*cacheMetrics
+	sap      sapMetrics
+}
+
+// sapMetrics sums the SAP Gateway statistics (sap-statistics) of backend requests
+type sapMetrics struct {
+	requests uint64
+	sums     map[string]float64 // Milliseconds by statistic, e.g. gwtotal
+}
+
+// toolMetrics are the metrics of one tool
+type toolMetrics struct {
+	calls   uint64
+	errors  uint64
+	bytes   uint64
+	latency histogram
+}
+
+// cacheMetrics are the lookups of one cache
+type cacheMetrics struct {
+	hits   uint64
+	misses uint64
+}
+
+// histogram counts observations per bucket of DurationBuckets
+type histogram struct {
+	buckets []uint64 // Observations per bucket, not cumulative; the last one is +Inf
+	count   uint64
+	sum     float64
+}
+
+func (h *histogram) observe(seconds float64) {
+	if h.buckets == nil {
+		h.buckets = make([]uint64, len(DurationBuckets)+1)
+	}
+	i := sort.SearchFloat64s(DurationBuckets, seconds)
+	h.buckets[i]++
+	h.count++
+	h.sum += seconds
+}
+
+// average returns the mean observed duration, 0 without observations
+func (h *histogram) average() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	return time.Duration(h.sum / float64(h.count) * float64(time.Second))
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		started:  time.Now(),
+		tools:    make(map[string]*toolMetrics),
+		statuses: make(map[string]uint64),
+		caches:   make(map[string]*cacheMetrics),
+	}
+}
+
+// ObserveToolCall records a tool call, its duration, the size of its result and whether
+// it failed
+func (r *Registry) ObserveToolCall(tool string, duration time.Duration, resultBytes int, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.tools[tool]
+	if !ok {
+		m = &toolMetrics{}
+		r.tools[tool] = m
+	}
+	m.calls++
+	if failed {
+		m.errors++
+	}
+	m.bytes += uint64(resultBytes)
+	m.latency.observe(duration.Seconds())
+}
+
+// ObserveBackendRequest records a request to the OData service by its HTTP status code
+// (0 if it got no response) and duration
+func (r *Registry) ObserveBackendRequest(status int, duration time.Duration) {
+	label := "error"
+	if status > 0 {
+		label = strconv.Itoa(status)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses[label]++
+	r.backend.observe(duration.Seconds())
+}
+
+// ObserveCache records a lookup in a cache and whether it was a hit
+func (r *Registry) ObserveCache(cache string, hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.caches[cache]
+	if !ok {
+		m = &cacheMetrics{}
+		r.caches[cache] = m
+	}
+	if hit {
+		m.hits++
+	} else {
+		m.misses++
+	}
+}
+
+// ObserveSAPStatistics records the timings in milliseconds SAP Gateway returned in the
+// sap-statistics header of a response
+func (r *Registry) ObserveSAPStatistics(stats map[string]float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sap.sums == nil {
+		r.sap.sums = make(map[string]float64)
+	}
+	r.sap.requests++
+	for name, ms := range stats {
+		r.sap.sums[name] += ms
+	}
+}
+
+// ToolStats are the recorded calls of one tool
+type ToolStats struct {
+	Tool           string
+	Calls          uint64
+	Errors         uint64
+	ResponseBytes  uint64
+	AverageLatency time.Duration
+}
+
+// CacheStats are the recorded lookups of one cache
+type CacheStats struct {
+	Cache  string
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate returns the share of lookups that were hits, 0 without lookups
+func (c CacheStats) HitRate() float64 {
+	if c.Hits+c.Misses == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Hits+c.Misses)
+}
+
+// Stats is a snapshot of a registry
+type Stats struct {
+	Uptime                time.Duration
+	Tools                 []ToolStats       // Sorted by tool name
+	BackendRequests       map[string]uint64 // By HTTP status code
+	BackendAverageLatency time.Duration
+	Caches                []CacheStats       // Sorted by cache name
+	SAPRequests           uint64             // Responses with SAP Gateway statistics
+	SAPAverages           map[string]float64 // Average milliseconds by SAP statistic
+}
+
+// Stats returns a snapshot of the recorded metrics
+func (r *Registry) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := Stats{
+		Uptime:                time.Since(r.started),
+		BackendRequests:       make(map[string]uint64, len(r.statuses)),
+		BackendAverageLatency: r.backend.average(),
+	}
+	for name, m := range r.tools {
+		stats.Tools = append(stats.Tools, ToolStats{
+			Tool:           name,
+			Calls:          m.calls,
+			Errors:         m.errors,
+			ResponseBytes:  m.bytes,
+			AverageLatency: m.latency.average(),
+		})
+	}
+	sort.Slice(stats.Tools, func(i, j int) bool { return stats.Tools[i].Tool < stats.Tools[j].Tool })
+	for status, count := range r.statuses {
+		stats.BackendRequests[status] = count
+	}
+	for name, m := range r.caches {
+		stats.Caches = append(stats.Caches, CacheStats{Cache: name, Hits: m.hits, Misses: m.misses})
+	}
+	sort.Slice(stats.Caches, func(i, j int) bool { return stats.Caches[i].Cache < stats.Caches[j].Cache })
+	if r.sap.requests > 0 {
+		stats.SAPRequests = r.sap.requests
+		stats.SAPAverages = make(map[string]float64, len(r.sap.sums))
+		for name, sum := range r.sap.sums {
+			stats.SAPAverages[name] = sum / float64(r.sap.requests)
+		}
+	}
+	return stats
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sb strings.Builder
+	tools := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		tools = append(tools, name)
+	}
+	sort.Strings(tools)
+
+	writeHeader(&sb, "odata_mcp_tool_calls_total", "counter", "Tool calls by tool")
+	for _, name := range tools {
+		fmt.Fprintf(&sb, "odata_mcp_tool_calls_total{tool=%q} %d\n", name, r.tools[name].calls)
+	}
+	writeHeader(&sb, "odata_mcp_tool_errors_total", "counter", "Tool calls that returned an error, by tool")
+	for _, name := range tools {
+		fmt.Fprintf(&sb, "odata_mcp_tool_errors_total{tool=%q} %d\n", name, r.tools[name].errors)
+	}
+	writeHeader(&sb, "odata_mcp_tool_response_bytes_total", "counter", "Bytes of tool results, by tool")
+	for _, name := range tools {
+		fmt.Fprintf(&sb, "odata_mcp_tool_response_bytes_total{tool=%q} %d\n", name, r.tools[name].bytes)
+	}
+	writeHeader(&sb, "odata_mcp_tool_duration_seconds", "histogram", "Duration of tool calls, by tool")
+	for _, name := range tools {
+		writeHistogram(&sb, "odata_mcp_tool_duration_seconds", fmt.Sprintf("tool=%q,", name), &r.tools[name].latency)
+	}
+
+	statuses := make([]string, 0, len(r.statuses))
+	for status := range r.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	writeHeader(&sb, "odata_mcp_backend_requests_total", "counter", "Requests to the OData service by HTTP status code")
+	for _, status := range statuses {
+		fmt.Fprintf(&sb, "odata_mcp_backend_requests_total{status=%q} %d\n", status, r.statuses[status])
+	}
+	writeHeader(&sb, "odata_mcp_backend_request_duration_seconds", "histogram", "Duration of requests to the OData service")
+	writeHistogram(&sb, "odata_mcp_backend_request_duration_seconds", "", &r.backend)
+
+	caches := make([]string, 0, len(r.caches))
+	for name := range r.caches {
+		caches = append(caches, name)
+	}
+	sort.Strings(caches)
+
+	writeHeader(&sb, "odata_mcp_cache_hits_total", "counter", "Cache lookups that found an entry, by cache")
+	for _, name := range caches {
+		fmt.Fprintf(&sb, "odata_mcp_cache_hits_total{cache=%q} %d\n", name, r.caches[name].hits)
+	}
+	writeHeader(&sb, "odata_mcp_cache_misses_total", "counter", "Cache lookups that found no entry, by cache")
+	for _, name := range caches {
+		fmt.Fprintf(&sb, "odata_mcp_cache_misses_total{cache=%q} %d\n", name, r.caches[name].misses)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Handler serves the metrics of the registry
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WritePrometheus(w)
+	})
+}
+
+func writeHeader(sb *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeHistogram writes the cumulative buckets, sum and count of a histogram; labels are
+// prepended to the le label and end with a comma
+func writeHistogram(sb *strings.Builder, name, labels string, h *histogram) {
+	var cumulative uint64
+	for i, bound := range DurationBuckets {
+		if h.buckets != nil {
+			cumulative += h.buckets[i]
+		}
+		fmt.Fprintf(sb, "%s_bucket{%sle=\"%s\"} %d\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(sb, "%s_bucket{%sle=\"+Inf\"} %d\n", name, labels, h.count)
+
+	labels = strings.TrimSuffix(labels, ",")
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(sb, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(sb, "%s_count%s %d\n", name, labels, h.count)
+}
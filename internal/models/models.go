@@ -9,6 +9,37 @@ type EntityProperty struct {
 	Nullable    bool    `json:"nullable"`
 	IsKey       bool    `json:"is_key"`
 	Description *string `json:"description,omitempty"`
+	Label       string  `json:"label,omitempty"`     // sap:label
+	Semantics   string  `json:"semantics,omitempty"` // sap:semantics
+	Text        string  `json:"text,omitempty"`      // sap:text, the property describing this one
+	Unit        string  `json:"unit,omitempty"`      // sap:unit, the property holding the currency or unit of this one
+	DisplayFormat string `json:"display_format,omitempty"` // sap:display-format, e.g. NonNegative (digits only) or UpperCase
+	MaxLength   int     `json:"max_length,omitempty"`
+	Precision   int     `json:"precision,omitempty"`
+	Scale       *int    `json:"scale,omitempty"`         // nil when not declared or variable
+	DefaultValue string `json:"default_value,omitempty"`
+	ComplexType string  `json:"complex_type,omitempty"`  // Name of the complex type of a structured property
+	IsCollection bool   `json:"is_collection,omitempty"` // Property holds a Collection(...) of values
+	Annotations map[string]interface{} `json:"annotations,omitempty"` // v4 annotations by qualified term
+	ValueList   string     `json:"value_list,omitempty"` // sap:value-list: standard or fixed-values
+	ValueHelp   *ValueHelp `json:"value_help,omitempty"` // Entity set listing the allowed values (Common.ValueList)
+}
+
+// ValueHelp is the value help (F4) of a property: the entity set listing its allowed
+// values, e.g. the plants or currencies a document may refer to
+type ValueHelp struct {
+	CollectionPath  string   `json:"collection_path"`            // Entity set listing the values
+	ValueProperty   string   `json:"value_property"`             // Property of the entity set holding the value
+	Properties      []string `json:"properties,omitempty"`       // Properties of the entity set shown with the value
+	Label           string   `json:"label,omitempty"`
+	SearchSupported bool     `json:"search_supported,omitempty"` // The entity set supports free-text search
+}
+
+// ComplexType represents an OData complex type, a keyless structured value of entity properties
+type ComplexType struct {
+	Name       string            `json:"name"`
+	BaseType   string            `json:"base_type,omitempty"` // v4 only
+	Properties []*EntityProperty `json:"properties"`          // Including inherited properties
 }
 
 // EntityType represents an OData entity type definition
@@ -17,7 +48,20 @@ type EntityType struct {
 	Properties     []*EntityProperty `json:"properties"`
 	KeyProperties  []string          `json:"key_properties"`
 	Description    *string           `json:"description,omitempty"`
+	Label          string            `json:"label,omitempty"` // sap:label or Common.Label
 	NavigationProps []*NavigationProperty `json:"navigation_properties,omitempty"`
+	Annotations    map[string]interface{} `json:"annotations,omitempty"` // v4 annotations by qualified term
+	Hierarchy      *Hierarchy             `json:"hierarchy,omitempty"`   // SAP hierarchy of the entities (sap:hierarchy-*-for)
+}
+
+// Hierarchy names the properties that arrange the entities of an SAP hierarchy entity
+// type (e.g. a cost center or profit center hierarchy) as a tree
+type Hierarchy struct {
+	NodeProperty            string `json:"node_property"`                       // sap:hierarchy-node-for, the ID of a node
+	ParentProperty          string `json:"parent_property"`                     // sap:hierarchy-parent-node-for
+	LevelProperty           string `json:"level_property,omitempty"`            // sap:hierarchy-level-for, 0 for root nodes
+	DrillStateProperty      string `json:"drill_state_property,omitempty"`      // sap:hierarchy-drill-state-for: leaf, collapsed or expanded
+	DescendantCountProperty string `json:"descendant_count_property,omitempty"` // sap:hierarchy-node-descendant-count-for
 }
 
 // NavigationProperty represents a navigation property in an entity type
@@ -29,6 +73,18 @@ type NavigationProperty struct {
 	Type         string `json:"type,omitempty"`         // v4 only
 	Partner      string `json:"partner,omitempty"`      // v4 only
 	Nullable     bool   `json:"nullable"`               // v4 only
+	// Resolved from associations (v2) or the navigation property type and bindings (v4)
+	TargetEntityType       string                   `json:"target_entity_type,omitempty"`
+	TargetEntitySet        string                   `json:"target_entity_set,omitempty"`
+	IsCollection           bool                     `json:"is_collection"`
+	ReferentialConstraints []*ReferentialConstraint `json:"referential_constraints,omitempty"`
+}
+
+// ReferentialConstraint maps a property of the source entity of a navigation property
+// to the property of the target entity it refers to (or is referred by)
+type ReferentialConstraint struct {
+	Property           string `json:"property"`
+	ReferencedProperty string `json:"referenced_property"`
 }
 
 // EntitySet represents an OData entity set
@@ -40,7 +96,12 @@ type EntitySet struct {
 	Deletable    bool    `json:"deletable"`
 	Searchable   bool    `json:"searchable"`
 	Pageable     bool    `json:"pageable"`
+	Filterable   bool    `json:"filterable"`
+	NonFilterableProperties []string `json:"non_filterable_properties,omitempty"` // v4 Capabilities.FilterRestrictions
 	Description  *string `json:"description,omitempty"`
+	Label        string  `json:"label,omitempty"` // sap:label or Common.Label
+	Annotations  map[string]interface{} `json:"annotations,omitempty"` // v4 annotations by qualified term
+	Namespace    string  `json:"namespace,omitempty"` // Schema of the entity container declaring the entity set
 }
 
 // FunctionImportParameter represents a parameter for a function import
@@ -60,6 +121,7 @@ type FunctionImport struct {
 	Description *string                    `json:"description,omitempty"`
 	IsBound     bool                       `json:"is_bound,omitempty"`     // v4 only
 	IsAction    bool                       `json:"is_action,omitempty"`    // v4 only (true for actions, false for functions)
+	Namespace   string                     `json:"namespace,omitempty"`    // Schema of the entity container declaring the import
 }
 
 // FunctionParameter represents a parameter for a function/action
@@ -74,12 +136,16 @@ type FunctionParameter struct {
 type ODataMetadata struct {
 	ServiceRoot    string                   `json:"service_root"`
 	EntityTypes    map[string]*EntityType   `json:"entity_types"`
+	ComplexTypes   map[string]*ComplexType  `json:"complex_types,omitempty"`
 	EntitySets     map[string]*EntitySet    `json:"entity_sets"`
 	FunctionImports map[string]*FunctionImport `json:"function_imports"`
 	SchemaNamespace string                   `json:"schema_namespace"`
 	ContainerName   string                   `json:"container_name"`
 	Version        string                   `json:"version"`
+	IsSAP          bool                     `json:"is_sap"` // Service uses SAP annotations (SAP Gateway dialect)
 	ParsedAt       time.Time                `json:"parsed_at"`
+	Unsupported    []string                 `json:"unsupported,omitempty"` // Constructs the parser skipped
+	ParseError     string                   `json:"parse_error,omitempty"` // $metadata could not be parsed; the service document was used instead
 }
 
 // ODataError represents an OData error response
@@ -90,6 +156,7 @@ type ODataError struct {
 	InnerError  map[string]interface{} `json:"innererror,omitempty"`
 	Target      string                 `json:"target,omitempty"`
 	Severity    string                 `json:"severity,omitempty"`
+	Messages    []SAPMessage           `json:"messages,omitempty"` // SAP messages of innererror.errordetails
 }
 
 // ODataErrorDetail represents detailed error information
@@ -99,6 +166,14 @@ type ODataErrorDetail struct {
 	Target  string `json:"target,omitempty"`
 }
 
+// SAPMessage is a business message returned by SAP in the sap-message header
+type SAPMessage struct {
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message"`
+	Severity string `json:"severity,omitempty"` // success, info, warning or error
+	Target   string `json:"target,omitempty"`
+}
+
 // ODataResponse represents a generic OData response
 type ODataResponse struct {
 	Context   string                 `json:"@odata.context,omitempty"`
@@ -107,6 +182,8 @@ type ODataResponse struct {
 	Value     interface{}            `json:"value,omitempty"`
 	Error     *ODataError            `json:"error,omitempty"`
 	Metadata  map[string]interface{} `json:"@odata.metadata,omitempty"`
+	Truncated bool                   `json:"truncated,omitempty"` // Items were dropped to respect size limits
+	Messages  []SAPMessage           `json:"messages,omitempty"`  // Business messages from the sap-message header
 	
 	// Alternative format for Python-style responses
 	Results    interface{}       `json:"results,omitempty"`
@@ -154,10 +231,30 @@ type TraceInfo struct {
 	SortTools        bool                `json:"sort_tools"`
 	EntityFilter     []string            `json:"entity_filter,omitempty"`
 	FunctionFilter   []string            `json:"function_filter,omitempty"`
+	OperationFilter  []string            `json:"operation_filter,omitempty"`
 	Authentication   string              `json:"authentication"`
 	MetadataSummary  MetadataSummary     `json:"metadata_summary"`
 	RegisteredTools  []ToolInfo          `json:"registered_tools"`
 	TotalTools       int                 `json:"total_tools"`
+	NameCollisions   []string            `json:"name_collisions,omitempty"`
+}
+
+// MetadataIssue is a problem found by --validate-metadata
+type MetadataIssue struct {
+	Severity string `json:"severity"` // error, warning or info
+	Kind     string `json:"kind"`
+	Target   string `json:"target,omitempty"`
+	Message  string `json:"message"`
+}
+
+// MetadataReport is the result of --validate-metadata
+type MetadataReport struct {
+	ServiceURL      string          `json:"service_url"`
+	MetadataSummary MetadataSummary `json:"metadata_summary"`
+	TotalTools      int             `json:"total_tools"`
+	Issues          []MetadataIssue `json:"issues"`
+	Errors          int             `json:"errors"`
+	Warnings        int             `json:"warnings"`
 }
 
 // MetadataSummary represents a summary of parsed metadata
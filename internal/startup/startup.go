@@ -0,0 +1,119 @@
+// Package startup classifies fatal startup errors, so that supervising MCP hosts can
+// tell a wrong password from an unreachable host or a broken $metadata document by the
+// exit code and, with --error-format json, by a JSON error object on stderr.
+package startup
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// Error categories
+const (
+	CategoryConfig   = "config"   // Invalid flags, config or env files
+	CategoryAuth     = "auth"     // The service rejected the credentials
+	CategoryNetwork  = "network"  // The service could not be reached
+	CategoryMetadata = "metadata" // $metadata could not be fetched or parsed
+	CategoryInternal = "internal" // Any other error
+)
+
+// Exit codes of the categories
+var exitCodes = map[string]int{
+	CategoryInternal: 1,
+	CategoryConfig:   2,
+	CategoryAuth:     3,
+	CategoryNetwork:  4,
+	CategoryMetadata: 5,
+}
+
+// Error is a startup error with its category
+type Error struct {
+	Category string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap assigns a category to an error. Authentication and network failures found in the
+// error chain take precedence over the category, so that a metadata fetch rejected with
+// HTTP 401 is reported as an auth error.
+func Wrap(category string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if c := detect(err); c != "" {
+		category = c
+	}
+	return &Error{Category: category, Err: err}
+}
+
+// Classify returns the category of an error; errors not wrapped by Wrap are classified
+// by their error chain or as internal
+func Classify(err error) string {
+	var startupErr *Error
+	if errors.As(err, &startupErr) {
+		return startupErr.Category
+	}
+	if c := detect(err); c != "" {
+		return c
+	}
+	return CategoryInternal
+}
+
+// ExitCode returns the process exit code of a category
+func ExitCode(category string) int {
+	if code, ok := exitCodes[category]; ok {
+		return code
+	}
+	return exitCodes[CategoryInternal]
+}
+
+// detect finds authentication and network failures in an error chain
+func detect(err error) string {
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden {
+			return CategoryAuth
+		}
+		return ""
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return CategoryNetwork
+	}
+	return ""
+}
+
+// report is the JSON error object written by WriteJSON
+type report struct {
+	Error struct {
+		Category string `json:"category"`
+		Message  string `json:"message"`
+		ExitCode int    `json:"exit_code"`
+	} `json:"error"`
+}
+
+// WriteJSON writes an error as a single-line JSON object:
+// {"error":{"category":"auth","message":"...","exit_code":3}}
+func WriteJSON(w io.Writer, err error) error {
+	var r report
+	r.Error.Category = Classify(err)
+	r.Error.Message = redact.String(err.Error())
+	r.Error.ExitCode = ExitCode(r.Error.Category)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
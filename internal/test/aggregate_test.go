@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/odata-mcp/go/internal/config"
+)
+
+func TestAggregateAcrossQueriesEntitySetsConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	var filters []string
+	inFlight, maxInFlight := 0, 0
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		filters = append(filters, r.URL.Query().Get("$filter"))
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if strings.Contains(r.URL.Path, "/Orders") {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":{"code":"500","message":{"value":"Orders unavailable"}}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[{"ProductID":1,"Name":"Widget"}]}}`))
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.AggregateAcross = true
+		cfg.AggregateWorkers = 2
+	})
+
+	result, err := b.CallTool(context.Background(), "aggregate_across", map[string]interface{}{
+		"entity_sets": []interface{}{"Products", "Orders"},
+		"$filter":     "Status eq 'open'",
+	})
+	require.NoError(t, err)
+
+	var aggregate struct {
+		EntitySets int `json:"entity_sets"`
+		Failed     int `json:"failed"`
+		Results    []struct {
+			EntitySet string          `json:"entity_set"`
+			Result    json.RawMessage `json:"result"`
+			Error     string          `json:"error"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &aggregate))
+	assert.Equal(t, 2, aggregate.EntitySets)
+	assert.Equal(t, 1, aggregate.Failed, "a failing entity set does not fail the others")
+	require.Len(t, aggregate.Results, 2)
+	assert.Equal(t, "Products", aggregate.Results[0].EntitySet)
+	assert.Contains(t, string(aggregate.Results[0].Result), `"Name":"Widget"`)
+	assert.Equal(t, "Orders", aggregate.Results[1].EntitySet)
+	assert.Contains(t, aggregate.Results[1].Error, "Orders unavailable")
+
+	assert.Equal(t, []string{"Status eq 'open'", "Status eq 'open'"}, filters)
+	assert.Equal(t, 2, maxInFlight, "the entity sets are queried concurrently")
+
+	_, err = b.CallTool(context.Background(), "aggregate_across", map[string]interface{}{
+		"entity_sets": []interface{}{"Customers"},
+	})
+	assert.Error(t, err)
+}
+
+func TestAggregateAcrossToolIsOptIn(t *testing.T) {
+	b := newMockBridge(t, newMockODataService(t, nil), nil)
+	_, err := b.CallTool(context.Background(), "aggregate_across", map[string]interface{}{
+		"entity_sets": []interface{}{"Products"},
+	})
+	assert.Error(t, err)
+}
+
+func TestAggregateAcrossKeepsTheResponseSizeLimit(t *testing.T) {
+	b := newMockBridge(t, newMockODataService(t, productListHandler(30)), func(cfg *config.Config) {
+		cfg.AggregateAcross = true
+		cfg.MaxResponseSize = 4000
+	})
+
+	result, err := b.CallTool(context.Background(), "aggregate_across", map[string]interface{}{
+		"entity_sets": []interface{}{"Products", "Orders"},
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(result.(string)), 4000, "the merged result stays within --max-response-size")
+
+	var aggregate struct {
+		Results []struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &aggregate))
+	require.Len(t, aggregate.Results, 2)
+	assert.Contains(t, string(aggregate.Results[0].Result), `"truncated":true`, "each entity set is truncated to its share")
+}
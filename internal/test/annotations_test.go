@@ -0,0 +1,88 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const annotatedV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:Reference Uri="https://oasis-tcs.github.io/odata-vocabularies/vocabularies/Org.OData.Capabilities.V1.xml">
+    <edmx:Include Namespace="Org.OData.Capabilities.V1" Alias="Capabilities"/>
+  </edmx:Reference>
+  <edmx:Reference Uri="https://sap.github.io/odata-vocabularies/vocabularies/Common.xml">
+    <edmx:Include Namespace="com.sap.vocabularies.Common.v1" Alias="Common"/>
+  </edmx:Reference>
+  <edmx:Reference Uri="https://sap.github.io/odata-vocabularies/vocabularies/UI.xml">
+    <edmx:Include Namespace="com.sap.vocabularies.UI.v1" Alias="UI"/>
+  </edmx:Reference>
+  <edmx:DataServices>
+    <Schema Namespace="com.sap.gateway.srvd.sales" Alias="SAP__self" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="SalesOrder">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false">
+          <Annotation Term="Common.Label" String="Sales Order"/>
+        </Property>
+        <Property Name="NetAmount" Type="Edm.Decimal">
+          <Annotation Term="Core.Computed"/>
+        </Property>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="SalesOrders" EntityType="SAP__self.SalesOrder">
+          <Annotation Term="Common.Label" String="Sales Orders"/>
+          <Annotation Term="Capabilities.DeleteRestrictions">
+            <Record><PropertyValue Property="Deletable" Bool="false"/></Record>
+          </Annotation>
+        </EntitySet>
+      </EntityContainer>
+      <Annotations Target="SAP__self.SalesOrder">
+        <Annotation Term="UI.LineItem">
+          <Collection>
+            <Record Type="UI.DataField"><PropertyValue Property="Value" Path="ID"/></Record>
+            <Record Type="UI.DataField"><PropertyValue Property="Value" Path="NetAmount"/></Record>
+          </Collection>
+        </Annotation>
+      </Annotations>
+      <Annotations Target="SAP__self.SalesOrder/NetAmount" Qualifier="Display">
+        <Annotation Term="Common.Text" Path="ID"/>
+      </Annotations>
+      <Annotations Target="SAP__self.Container/SalesOrders">
+        <Annotation Term="Capabilities.SearchRestrictions">
+          <Record>
+            <PropertyValue Property="Searchable" Bool="true"/>
+            <PropertyValue Property="MaxLevels" Int="2"/>
+            <PropertyValue Property="NonSearchableProperties">
+              <Collection><PropertyPath>NetAmount</PropertyPath></Collection>
+            </PropertyValue>
+          </Record>
+        </Annotation>
+      </Annotations>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestV4Annotations(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(annotatedV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	salesOrder := meta.EntityTypes["SalesOrder"]
+	require.NotNil(t, salesOrder)
+	assert.Equal(t, "Sales Order", salesOrder.Properties[0].Annotations["com.sap.vocabularies.Common.v1.Label"])
+	assert.Equal(t, true, salesOrder.Properties[1].Annotations["Core.Computed"], "tagging terms without value are true")
+	assert.Equal(t, "ID", salesOrder.Properties[1].Annotations["com.sap.vocabularies.Common.v1.Text#Display"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"Value": "ID"},
+		map[string]interface{}{"Value": "NetAmount"},
+	}, salesOrder.Annotations["com.sap.vocabularies.UI.v1.LineItem"])
+
+	salesOrders := meta.EntitySets["SalesOrders"]
+	assert.Equal(t, map[string]interface{}{"Deletable": false}, salesOrders.Annotations["Org.OData.Capabilities.V1.DeleteRestrictions"])
+	assert.Equal(t, map[string]interface{}{
+		"Searchable":              true,
+		"MaxLevels":               int64(2),
+		"NonSearchableProperties": []interface{}{"NetAmount"},
+	}, salesOrders.Annotations["Org.OData.Capabilities.V1.SearchRestrictions"])
+}
@@ -0,0 +1,206 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const associationsV2Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="SALES_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="OrderID"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false"/>
+        <NavigationProperty Name="Items" Relationship="SALES_SRV.Order_Items" FromRole="FromRole_Order" ToRole="ToRole_Item"/>
+      </EntityType>
+      <EntityType Name="OrderItem">
+        <Key><PropertyRef Name="OrderID"/><PropertyRef Name="ItemNo"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false"/>
+        <Property Name="ItemNo" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="Quantity" Type="Edm.Int32"/>
+        <NavigationProperty Name="Order" Relationship="SALES_SRV.Order_Items" FromRole="ToRole_Item" ToRole="FromRole_Order"/>
+      </EntityType>
+      <Association Name="Order_Items">
+        <End Type="SALES_SRV.Order" Multiplicity="1" Role="FromRole_Order"/>
+        <End Type="SALES_SRV.OrderItem" Multiplicity="*" Role="ToRole_Item"/>
+        <ReferentialConstraint>
+          <Principal Role="FromRole_Order"><PropertyRef Name="OrderID"/></Principal>
+          <Dependent Role="ToRole_Item"><PropertyRef Name="OrderID"/></Dependent>
+        </ReferentialConstraint>
+      </Association>
+      <EntityContainer Name="SALES_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Orders" EntityType="SALES_SRV.Order"/>
+        <EntitySet Name="OrderItems" EntityType="SALES_SRV.OrderItem"/>
+        <AssociationSet Name="Order_ItemsSet" Association="SALES_SRV.Order_Items">
+          <End EntitySet="Orders" Role="FromRole_Order"/>
+          <End EntitySet="OrderItems" Role="ToRole_Item"/>
+        </AssociationSet>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const associationsV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Demo" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Category">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <NavigationProperty Name="Products" Type="Collection(Demo.Product)" Partner="Category"/>
+      </EntityType>
+      <EntityType Name="Product">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="CategoryID" Type="Edm.Int32"/>
+        <NavigationProperty Name="Category" Type="Demo.Category" Partner="Products">
+          <ReferentialConstraint Property="CategoryID" ReferencedProperty="ID"/>
+        </NavigationProperty>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Categories" EntityType="Demo.Category">
+          <NavigationPropertyBinding Path="Products" Target="Products"/>
+        </EntitySet>
+        <EntitySet Name="Products" EntityType="Demo.Product"/>
+        <EntitySet Name="DiscontinuedProducts" EntityType="Demo.Product"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func findNavigationProperty(entityType *models.EntityType, name string) *models.NavigationProperty {
+	for _, nav := range entityType.NavigationProps {
+		if nav.Name == name {
+			return nav
+		}
+	}
+	return nil
+}
+
+func TestAssociationParsing(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(associationsV2Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	items := findNavigationProperty(meta.EntityTypes["Order"], "Items")
+	require.NotNil(t, items)
+	assert.Equal(t, "OrderItem", items.TargetEntityType)
+	assert.Equal(t, "OrderItems", items.TargetEntitySet)
+	assert.True(t, items.IsCollection)
+	assert.Equal(t, []*models.ReferentialConstraint{{Property: "OrderID", ReferencedProperty: "OrderID"}}, items.ReferentialConstraints)
+
+	order := findNavigationProperty(meta.EntityTypes["OrderItem"], "Order")
+	require.NotNil(t, order)
+	assert.Equal(t, "Orders", order.TargetEntitySet)
+	assert.False(t, order.IsCollection)
+
+	meta, err = metadata.ParseMetadata([]byte(associationsV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+
+	products := findNavigationProperty(meta.EntityTypes["Category"], "Products")
+	require.NotNil(t, products)
+	assert.Equal(t, "Product", products.TargetEntityType)
+	assert.Equal(t, "Products", products.TargetEntitySet, "bound by NavigationPropertyBinding")
+	assert.True(t, products.IsCollection)
+
+	category := findNavigationProperty(meta.EntityTypes["Product"], "Category")
+	require.NotNil(t, category)
+	assert.Equal(t, "Categories", category.TargetEntitySet, "the only entity set of the target type")
+	assert.Equal(t, []*models.ReferentialConstraint{{Property: "CategoryID", ReferencedProperty: "ID"}}, category.ReferentialConstraints)
+}
+
+func TestDeepInsertSchema(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, associationsV2Metadata, nil)
+	b := newMockBridge(t, service, nil)
+
+	var properties map[string]interface{}
+	for _, tool := range listTools(t, b) {
+		if tool.Name == "Orders_create" {
+			properties = tool.InputSchema["properties"].(map[string]interface{})
+		}
+	}
+	require.NotNil(t, properties, "create tool not registered")
+	items := properties["Items"].(map[string]interface{})
+	assert.Equal(t, "array", items["type"])
+	assert.Contains(t, items["description"], "OrderItems")
+
+	_, err := b.CallTool(context.Background(), "Orders_create", map[string]interface{}{
+		"OrderID": "1", "Items": map[string]interface{}{"ItemNo": 10},
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+
+	_, err = b.CallTool(context.Background(), "Orders_create", map[string]interface{}{
+		"OrderID": "1", "Items": []interface{}{map[string]interface{}{"ItemNo": "ten"}},
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+}
+
+func TestDeepInsertConvertsRelatedEntities(t *testing.T) {
+	metadata := strings.Replace(associationsV2Metadata, `<Property Name="Quantity" Type="Edm.Int32"/>`, `<Property Name="Quantity" Type="Edm.Decimal"/>`, 1)
+	var body map[string]interface{}
+	service := newMockODataServiceWithMetadata(t, metadata, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"OrderID":"1"}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	_, err := b.CallTool(context.Background(), "Orders_create", map[string]interface{}{
+		"OrderID": "1", "Items": []interface{}{map[string]interface{}{"ItemNo": 10, "Quantity": 2.5}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, body)
+	item := body["Items"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "2.5", item["Quantity"], "Edm.Decimal values of related entities are sent as strings")
+	assert.Equal(t, float64(10), item["ItemNo"])
+}
+
+func TestServiceInfoEntityCatalog(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, associationsV2Metadata, nil)
+	b := newMockBridge(t, service, nil)
+
+	result, err := b.CallTool(context.Background(), "odata_service_info", map[string]interface{}{})
+	require.NoError(t, err)
+
+	var info struct {
+		EntityCatalog []struct {
+			Name         string          `json:"name"`
+			Keys         []string        `json:"keys"`
+			Capabilities map[string]bool `json:"capabilities"`
+			Navigation   []struct {
+				Name            string `json:"name"`
+				TargetType      string `json:"target_type"`
+				TargetEntitySet string `json:"target_entity_set"`
+				Collection      bool   `json:"collection"`
+			} `json:"navigation"`
+		} `json:"entity_catalog"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &info))
+	require.Len(t, info.EntityCatalog, 2)
+
+	items := info.EntityCatalog[0]
+	assert.Equal(t, "OrderItems", items.Name)
+	assert.Equal(t, []string{"OrderID", "ItemNo"}, items.Keys)
+	assert.True(t, items.Capabilities["creatable"])
+	require.Len(t, items.Navigation, 1)
+	assert.Equal(t, "Order", items.Navigation[0].Name)
+	assert.Equal(t, "Orders", items.Navigation[0].TargetEntitySet)
+	assert.False(t, items.Navigation[0].Collection)
+
+	orders := info.EntityCatalog[1]
+	assert.Equal(t, "Orders", orders.Name)
+	require.Len(t, orders.Navigation, 1)
+	assert.Equal(t, "OrderItems", orders.Navigation[0].TargetEntitySet)
+	assert.True(t, orders.Navigation[0].Collection)
+}
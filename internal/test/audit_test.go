@@ -0,0 +1,124 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/audit"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAuditLog decodes the records of an audit log file
+func readAuditLog(t *testing.T, path string) []audit.Record {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []audit.Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record audit.Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestAuditLogRecordsModifyingOperations(t *testing.T) {
+	server := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("X-CSRF-Token"), "fetch") {
+			w.Header().Set("X-CSRF-Token", "token")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"d":{"OrderID":"4712","Customer":"ACME"}}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"404","message":{"value":"Order not found"}}}`))
+		default:
+			w.Write([]byte(`{"d":{"results":[]}}`))
+		}
+	})
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.Username, cfg.Password = "auditor", "secret"
+	})
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	closeAudit, err := audit.Open(path)
+	require.NoError(t, err)
+	defer closeAudit()
+
+	ctx := context.Background()
+	_, err = b.CallTool(ctx, "Orders_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Orders_create", map[string]interface{}{"OrderID": "4712", "Customer": "ACME"})
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Orders_delete", map[string]interface{}{"OrderID": "4711"})
+	require.Error(t, err)
+
+	records := readAuditLog(t, path)
+	require.Len(t, records, 2, "reads are not audited")
+
+	created := records[0]
+	assert.Equal(t, "Orders_create", created.Tool)
+	assert.Equal(t, "create", created.Operation)
+	assert.Equal(t, "Orders", created.Entity)
+	assert.Equal(t, "auditor", created.User)
+	assert.Equal(t, audit.StatusSuccess, created.Status)
+	assert.Equal(t, audit.HashPayload(map[string]interface{}{"OrderID": "4712", "Customer": "ACME"}), created.PayloadHash)
+	assert.False(t, created.Time.IsZero())
+
+	deleted := records[1]
+	assert.Equal(t, "Orders_delete", deleted.Tool)
+	assert.Equal(t, "delete", deleted.Operation)
+	assert.Equal(t, map[string]interface{}{"OrderID": "4711"}, deleted.Key)
+	assert.Empty(t, deleted.PayloadHash)
+	assert.Equal(t, audit.StatusFailed, deleted.Status)
+	assert.Equal(t, http.StatusNotFound, deleted.HTTPStatus)
+	assert.Contains(t, deleted.Error, "Order not found")
+
+	// Records are appended, never rewritten
+	require.NoError(t, closeAudit())
+	closeAudit, err = audit.Open(path)
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Orders_create", map[string]interface{}{"OrderID": "4713"})
+	require.NoError(t, err)
+	assert.Len(t, readAuditLog(t, path), 3)
+}
+
+func TestAuditLogSkipsDryRuns(t *testing.T) {
+	server := newMockODataService(t, productListHandler(1))
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.DryRun = true
+	})
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	closeAudit, err := audit.Open(path)
+	require.NoError(t, err)
+	defer closeAudit()
+
+	_, err = b.CallTool(context.Background(), "Orders_create", map[string]interface{}{"OrderID": "4712"})
+	require.NoError(t, err)
+	assert.Empty(t, readAuditLog(t, path))
+}
+
+func TestAuditPayloadHashIgnoresKeyOrder(t *testing.T) {
+	a := audit.HashPayload(map[string]interface{}{"a": 1, "b": "x"})
+	b := audit.HashPayload(map[string]interface{}{"b": "x", "a": 1})
+	assert.Equal(t, a, b)
+	assert.Len(t, a, 64)
+	assert.Empty(t, audit.HashPayload(nil))
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// autoSelectMetadata describes a wide SAP entity with sap:text, sap:semantics and sap:label annotations
+const autoSelectMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="MOCK_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Material">
+        <Key><PropertyRef Name="Material"/></Key>
+        <Property Name="Material" Type="Edm.String" Nullable="false" sap:label="Material" sap:text="MaterialText"/>
+        <Property Name="MaterialText" Type="Edm.String" sap:label="Material Description"/>
+        <Property Name="GrossWeight" Type="Edm.Decimal" sap:label="Gross Weight"/>
+        <Property Name="WeightUnit" Type="Edm.String" sap:label="Weight Unit" sap:semantics="unit-of-measure"/>
+        <Property Name="CrossPlantStatus" Type="Edm.String" sap:label="X-Plant Status"/>
+        <Property Name="MaterialGroup" Type="Edm.String" sap:label="Material Group"/>
+        <Property Name="CreatedByUser" Type="Edm.String" sap:label="Created By"/>
+        <NavigationProperty Name="ToPlants" Relationship="MOCK_SRV.Material_Plants" FromRole="FromRole" ToRole="ToRole"/>
+      </EntityType>
+      <EntityContainer Name="MOCK_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Materials" EntityType="MOCK_SRV.Material" sap:searchable="true"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestAutoSelect(t *testing.T) {
+	var selects []string
+	service := newMockODataServiceWithMetadata(t, autoSelectMetadata, func(w http.ResponseWriter, r *http.Request) {
+		selects = append(selects, r.URL.Query().Get("$select"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[{"Material":"M1","MaterialText":"Bolt"}]}}`))
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.AutoSelect = true
+		cfg.ImportantFields = []string{"Material Group", "*Status"}
+	})
+
+	result, err := b.CallTool(context.Background(), "Materials_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	assert.Contains(t, response["@odata.metadata"].(map[string]interface{})["auto_select"], "pass $select")
+
+	_, err = b.CallTool(context.Background(), "Materials_filter", map[string]interface{}{"$expand": "ToPlants"})
+	require.NoError(t, err)
+	_, err = b.CallTool(context.Background(), "Materials_search", map[string]interface{}{"search": "bolt"})
+	require.NoError(t, err)
+	_, err = b.CallTool(context.Background(), "Materials_filter", map[string]interface{}{"$select": "GrossWeight"})
+	require.NoError(t, err)
+
+	important := "Material,MaterialText,WeightUnit,CrossPlantStatus,MaterialGroup"
+	assert.Equal(t, []string{important, important + ",ToPlants", important, "GrossWeight"}, selects)
+}
+
+func TestAutoSelectDisabledByDefault(t *testing.T) {
+	var query string
+	service := newMockODataServiceWithMetadata(t, autoSelectMetadata, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.ImportantFields = []string{constants.DefaultAutoSelectFields}
+	})
+
+	_, err := b.CallTool(context.Background(), "Materials_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.NotContains(t, query, "select")
+}
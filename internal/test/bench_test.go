@@ -0,0 +1,68 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/odata-mcp/go/internal/bench"
+)
+
+func TestBenchRunsCallsConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	inFlight, maxInFlight := 0, 0
+	call := func(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+		mu.Lock()
+		calls[name]++
+		n := calls[name]
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if name == "count" && n%2 == 0 {
+			return nil, errors.New("backend busy")
+		}
+		return "ok", nil
+	}
+
+	results := bench.Run(context.Background(), call, []bench.Target{
+		{Tool: "filter", Args: map[string]interface{}{"$top": 10}},
+		{Tool: "count"},
+	}, bench.Options{Concurrency: 3, Requests: 6})
+
+	require.Len(t, results, 2)
+	assert.Equal(t, map[string]int{"filter": 6, "count": 6}, calls)
+	assert.Equal(t, 3, maxInFlight, "the targets run one after another, each with 3 calls in flight")
+
+	assert.Equal(t, "filter", results[0].Tool)
+	assert.Equal(t, 6, results[0].Calls)
+	assert.Zero(t, results[0].Errors)
+	assert.GreaterOrEqual(t, results[0].P50, 10*time.Millisecond)
+	assert.LessOrEqual(t, results[0].P50, results[0].P99)
+	assert.Equal(t, results[0].P99, results[0].Max)
+	assert.Greater(t, results[0].Throughput(), 0.0)
+
+	assert.Equal(t, 3, results[1].Errors)
+	assert.Equal(t, "backend busy", results[1].FirstError)
+}
+
+func TestBenchPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 10; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 5*time.Millisecond, bench.Percentile(latencies, 50))
+	assert.Equal(t, 9*time.Millisecond, bench.Percentile(latencies, 90))
+	assert.Equal(t, 10*time.Millisecond, bench.Percentile(latencies, 99))
+	assert.Equal(t, 1*time.Millisecond, bench.Percentile(latencies, 0))
+	assert.Zero(t, bench.Percentile(nil, 50))
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/odata-mcp/go/internal/config"
+)
+
+// batchService answers $batch requests with one changeset of product creations. A
+// changeset containing a product named "invalid" is rejected as a whole, like SAP
+// gateways roll back a changeset on the first failing request.
+type batchService struct {
+	mu          sync.Mutex
+	tokens      int
+	batches     int
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *batchService) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-CSRF-Token") == "Fetch" {
+		s.mu.Lock()
+		s.tokens++
+		s.mu.Unlock()
+		w.Header().Set("X-CSRF-Token", "token")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !strings.HasSuffix(r.URL.Path, "/$batch") || r.Header.Get("X-CSRF-Token") != "token" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	s.batches++
+	batchNumber := s.batches
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	var products []map[string]interface{}
+	_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	batch := multipart.NewReader(r.Body, params["boundary"])
+	part, err := batch.NextPart()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_, params, _ = mime.ParseMediaType(part.Header.Get("Content-Type"))
+	changeset := multipart.NewReader(part, params["boundary"])
+	for {
+		request, err := changeset.NextPart()
+		if err == io.EOF {
+			break
+		}
+		// Request lines carry paths relative to the service root, which http.ReadRequest rejects
+		reader := textproto.NewReader(bufio.NewReader(request))
+		line, _ := reader.ReadLine()
+		reader.ReadMIMEHeader()
+		if err != nil || line != "POST Products HTTP/1.1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var product map[string]interface{}
+		json.NewDecoder(reader.R).Decode(&product)
+		products = append(products, product)
+	}
+
+	response := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/mixed; boundary="+response.Boundary())
+	w.WriteHeader(http.StatusAccepted)
+	defer response.Close()
+
+	for _, product := range products {
+		if product["Name"] == "invalid" {
+			part, _ := response.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/http"}})
+			fmt.Fprint(part, "HTTP/1.1 400 Bad Request\r\nContent-Type: application/json\r\n\r\n"+
+				`{"error":{"code":"INVALID","message":{"lang":"en","value":"Name is invalid"}}}`)
+			return
+		}
+	}
+	changesetPart, _ := response.CreatePart(textproto.MIMEHeader{"Content-Type": {"multipart/mixed; boundary=changesetresponse"}})
+	changesetResponse := multipart.NewWriter(changesetPart)
+	changesetResponse.SetBoundary("changesetresponse")
+	for i, product := range products {
+		product["ProductID"] = batchNumber*100 + i
+		body, _ := json.Marshal(map[string]interface{}{"d": product})
+		part, _ := changesetResponse.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/http"}})
+		fmt.Fprintf(part, "HTTP/1.1 201 Created\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	changesetResponse.Close()
+}
+
+func TestBulkCreateSendsConcurrentChangesets(t *testing.T) {
+	service := &batchService{}
+	b := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.BulkCreate = true
+		cfg.BulkChangesetSize = 2
+		cfg.BulkWorkers = 2
+	})
+
+	entities := []interface{}{}
+	for _, name := range []string{"a", "b", "c", "invalid", "e"} {
+		entities = append(entities, map[string]interface{}{"Name": name, "Price": 1.5})
+	}
+	result, err := b.CallTool(context.Background(), "Products_bulk_create", map[string]interface{}{"entities": entities})
+	require.NoError(t, err)
+
+	var response struct {
+		Created    int `json:"created"`
+		Failed     int `json:"failed"`
+		Changesets int `json:"changesets"`
+		Results    []struct {
+			Index  int                    `json:"index"`
+			Status string                 `json:"status"`
+			Entity map[string]interface{} `json:"entity"`
+			Error  string                 `json:"error"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	assert.Equal(t, 3, response.Created)
+	assert.Equal(t, 2, response.Failed)
+	assert.Equal(t, 3, response.Changesets)
+	require.Len(t, response.Results, 5)
+
+	// The changeset of "c" and "invalid" is rolled back as a whole
+	for i, r := range response.Results {
+		assert.Equal(t, i, r.Index)
+		if i == 2 || i == 3 {
+			assert.Equal(t, "failed", r.Status)
+			assert.Contains(t, r.Error, "Name is invalid")
+			continue
+		}
+		assert.Equal(t, "created", r.Status)
+		assert.Equal(t, entities[i].(map[string]interface{})["Name"], r.Entity["Name"])
+		assert.Contains(t, r.Entity, "ProductID")
+	}
+
+	assert.Equal(t, 3, service.batches)
+	assert.Equal(t, 2, service.maxInFlight, "changesets should be sent by two workers")
+	assert.Equal(t, 3, service.tokens, "one CSRF token fetch per changeset")
+}
+
+func TestBulkCreateToolIsOptIn(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	ops := toolOperations(t, newMockBridge(t, server, nil))
+	assert.True(t, ops["Products:create"])
+	assert.False(t, ops["Products:bulk_create"])
+
+	ops = toolOperations(t, newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.BulkCreate = true
+		cfg.DisableOperations = "c"
+		require.NoError(t, cfg.ParseOperationFilters())
+	}))
+	assert.False(t, ops["Products:bulk_create"], "disabling create disables bulk create")
+
+	ops = toolOperations(t, newMockBridge(t, server, func(cfg *config.Config) { cfg.BulkCreate = true }))
+	assert.True(t, ops["Products:bulk_create"])
+}
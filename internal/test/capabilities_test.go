@@ -0,0 +1,102 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const capabilitiesV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:Reference Uri="https://oasis-tcs.github.io/odata-vocabularies/vocabularies/Org.OData.Capabilities.V1.xml">
+    <edmx:Include Namespace="Org.OData.Capabilities.V1" Alias="Capabilities"/>
+  </edmx:Reference>
+  <edmx:DataServices>
+    <Schema Namespace="Sales" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+        <Property Name="Notes" Type="Edm.String"/>
+      </EntityType>
+      <EntityType Name="AuditEntry">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Orders" EntityType="Sales.Order">
+          <Annotation Term="Capabilities.DeleteRestrictions">
+            <Record><PropertyValue Property="Deletable" Bool="false"/></Record>
+          </Annotation>
+        </EntitySet>
+        <EntitySet Name="AuditLog" EntityType="Sales.AuditEntry"/>
+      </EntityContainer>
+      <Annotations Target="Sales.Container/Orders">
+        <Annotation Term="Capabilities.FilterRestrictions">
+          <Record>
+            <PropertyValue Property="NonFilterableProperties">
+              <Collection><PropertyPath>Notes</PropertyPath></Collection>
+            </PropertyValue>
+          </Record>
+        </Annotation>
+      </Annotations>
+      <Annotations Target="Sales.Container/AuditLog">
+        <Annotation Term="Capabilities.InsertRestrictions">
+          <Record><PropertyValue Property="Insertable" Bool="false"/></Record>
+        </Annotation>
+        <Annotation Term="Capabilities.UpdateRestrictions">
+          <Record><PropertyValue Property="Updatable" Bool="false"/></Record>
+        </Annotation>
+        <Annotation Term="Capabilities.FilterRestrictions">
+          <Record><PropertyValue Property="Filterable" Bool="false"/></Record>
+        </Annotation>
+      </Annotations>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestCapabilitiesRestrictionsParsing(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(capabilitiesV4Metadata), "https://example.com/")
+	require.NoError(t, err)
+
+	orders := meta.EntitySets["Orders"]
+	assert.True(t, orders.Creatable)
+	assert.True(t, orders.Updatable)
+	assert.False(t, orders.Deletable)
+	assert.True(t, orders.Filterable)
+	assert.Equal(t, []string{"Notes"}, orders.NonFilterableProperties)
+
+	audit := meta.EntitySets["AuditLog"]
+	assert.False(t, audit.Creatable)
+	assert.False(t, audit.Updatable)
+	assert.True(t, audit.Deletable)
+	assert.False(t, audit.Filterable)
+}
+
+func TestCapabilitiesRestrictionsControlTools(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, capabilitiesV4Metadata, nil)
+	b := newMockBridge(t, service, nil)
+
+	ops := toolOperations(t, b)
+	assert.False(t, ops["Orders:delete"], "DeleteRestrictions should suppress the delete tool")
+	assert.True(t, ops["Orders:create"])
+	assert.False(t, ops["AuditLog:create"], "InsertRestrictions should suppress the create tool")
+	assert.False(t, ops["AuditLog:update"], "UpdateRestrictions should suppress the update tool")
+	assert.True(t, ops["AuditLog:filter"], "entity sets that cannot be filtered can still be listed")
+
+	schemas := make(map[string]map[string]interface{})
+	for _, tool := range listTools(t, b) {
+		schemas[tool.Name] = tool.InputSchema["properties"].(map[string]interface{})
+	}
+	filter := schemas["Orders_filter"]["$filter"].(map[string]interface{})
+	assert.Contains(t, filter["description"], "cannot be filtered on: Notes")
+	assert.NotContains(t, schemas["AuditLog_filter"], "$filter")
+	assert.NotContains(t, schemas["AuditLog_count"], "$filter")
+
+	_, err := b.CallTool(context.Background(), "AuditLog_filter", map[string]interface{}{"$filter": "ID eq '1'"})
+	assert.Error(t, err)
+	_, err = b.CallTool(context.Background(), "Orders_filter", map[string]interface{}{"$filter": "Notes eq 'x'"})
+	assert.NoError(t, err, "non-filterable properties are only warned about")
+}
@@ -0,0 +1,185 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/mcp"
+)
+
+// changesetService answers $batch requests with one changeset, creating entities on POST
+// and answering 204 No Content to updates and deletes. It records the request line of
+// every request in a changeset and counts CSRF token fetches and other requests.
+type changesetService struct {
+	mu       sync.Mutex
+	tokens   int
+	batches  int
+	other    int
+	requests []string
+}
+
+func (s *changesetService) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Header.Get("X-CSRF-Token") == "Fetch" {
+		s.tokens++
+		w.Header().Set("X-CSRF-Token", "token")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !strings.HasSuffix(r.URL.Path, "/$batch") || r.Header.Get("X-CSRF-Token") != "token" {
+		s.other++
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	s.batches++
+
+	_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_, params, _ = mime.ParseMediaType(part.Header.Get("Content-Type"))
+	changeset := multipart.NewReader(part, params["boundary"])
+
+	response := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/mixed; boundary="+response.Boundary())
+	w.WriteHeader(http.StatusAccepted)
+	defer response.Close()
+	changesetPart, _ := response.CreatePart(textproto.MIMEHeader{"Content-Type": {"multipart/mixed; boundary=changesetresponse"}})
+	changesetResponse := multipart.NewWriter(changesetPart)
+	changesetResponse.SetBoundary("changesetresponse")
+	defer changesetResponse.Close()
+
+	for {
+		request, err := changeset.NextPart()
+		if err == io.EOF {
+			return
+		}
+		// Request lines carry paths relative to the service root, which http.ReadRequest rejects
+		reader := textproto.NewReader(bufio.NewReader(request))
+		line, _ := reader.ReadLine()
+		reader.ReadMIMEHeader()
+		s.requests = append(s.requests, line)
+
+		part, _ := changesetResponse.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/http"}})
+		if !strings.HasPrefix(line, "POST ") {
+			fmt.Fprint(part, "HTTP/1.1 204 No Content\r\n\r\n")
+			continue
+		}
+		var entity map[string]interface{}
+		json.NewDecoder(reader.R).Decode(&entity)
+		entity["ProductID"] = 42
+		body, _ := json.Marshal(map[string]interface{}{"d": entity})
+		fmt.Fprintf(part, "HTTP/1.1 201 Created\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s", len(body), body)
+	}
+}
+
+func TestChangesetRunsOperationsInOneBatch(t *testing.T) {
+	service := &changesetService{}
+	b := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.ChangesetTool = true
+	})
+
+	result, err := b.CallTool(context.Background(), "execute_changeset", map[string]interface{}{
+		"operations": []interface{}{
+			map[string]interface{}{"operation": "create", "entity_set": "Products", "data": map[string]interface{}{"Name": "Widget", "Price": 9.5}},
+			map[string]interface{}{"operation": "update", "entity_set": "Orders", "key": map[string]interface{}{"OrderID": "0001"},
+				"data": map[string]interface{}{"Customer": "ACME"}, "method": "MERGE"},
+			map[string]interface{}{"operation": "delete", "entity_set": "Products", "key": map[string]interface{}{"ProductID": float64(7)}},
+		},
+	})
+	require.NoError(t, err)
+
+	var response struct {
+		Status     string `json:"status"`
+		Operations int    `json:"operations"`
+		Results    []struct {
+			Index     int                    `json:"index"`
+			Operation string                 `json:"operation"`
+			EntitySet string                 `json:"entity_set"`
+			Entity    map[string]interface{} `json:"entity"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	assert.Equal(t, "success", response.Status)
+	assert.Equal(t, 3, response.Operations)
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, "Widget", response.Results[0].Entity["Name"])
+	assert.Equal(t, "update", response.Results[1].Operation)
+	assert.Equal(t, "Orders", response.Results[1].EntitySet)
+	assert.Nil(t, response.Results[2].Entity)
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	assert.Equal(t, []string{
+		"POST Products HTTP/1.1",
+		"MERGE Orders('0001') HTTP/1.1",
+		"DELETE Products(7) HTTP/1.1",
+	}, service.requests)
+	assert.Equal(t, 1, service.batches)
+	assert.Equal(t, 1, service.tokens, "one CSRF token fetch for the whole changeset")
+	assert.Zero(t, service.other)
+}
+
+func TestChangesetRejectsInvalidOperationsBeforeSending(t *testing.T) {
+	service := &changesetService{}
+	b := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.ChangesetTool = true
+	})
+
+	for name, operation := range map[string]map[string]interface{}{
+		"missing key":        {"operation": "delete", "entity_set": "Products"},
+		"missing data":       {"operation": "create", "entity_set": "Products"},
+		"unknown entity set": {"operation": "create", "entity_set": "Invoices", "data": map[string]interface{}{"Name": "x"}},
+		"invalid method": {"operation": "update", "entity_set": "Orders", "key": map[string]interface{}{"OrderID": "1"},
+			"data": map[string]interface{}{"Customer": "x"}, "method": "POST"},
+	} {
+		_, err := b.CallTool(context.Background(), "execute_changeset", map[string]interface{}{
+			"operations": []interface{}{
+				map[string]interface{}{"operation": "create", "entity_set": "Products", "data": map[string]interface{}{"Name": "Widget"}},
+				operation,
+			},
+		})
+		assert.ErrorIs(t, err, mcp.ErrInvalidArguments, name)
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	assert.Zero(t, service.batches, "no operation is sent if one is invalid")
+}
+
+func TestChangesetToolIsOptIn(t *testing.T) {
+	server := newMockODataService(t, nil)
+	hasTool := func(cfg func(*config.Config)) bool {
+		for _, tool := range listTools(t, newMockBridge(t, server, cfg)) {
+			if tool.Name == "execute_changeset" {
+				return true
+			}
+		}
+		return false
+	}
+
+	assert.False(t, hasTool(nil))
+	assert.True(t, hasTool(func(cfg *config.Config) { cfg.ChangesetTool = true }))
+	assert.False(t, hasTool(func(cfg *config.Config) {
+		cfg.ChangesetTool = true
+		cfg.DisableOperations = "cud"
+		require.NoError(t, cfg.ParseOperationFilters())
+	}), "no tool without modifiable entity sets")
+}
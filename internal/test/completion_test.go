@@ -0,0 +1,57 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionEntityAndPropertyNames(t *testing.T) {
+	server := newMockODataService(t, nil)
+	b := newMockBridge(t, server, nil)
+	ctx := context.Background()
+
+	values, err := b.Complete(ctx, &mcp.CompletionRequest{
+		Ref:      map[string]interface{}{"type": "ref/resource", "uri": "odata://{entitySet}/{key}"},
+		Argument: "entitySet",
+		Value:    "pro",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Products"}, values)
+
+	values, err = b.Complete(ctx, &mcp.CompletionRequest{
+		Ref:      map[string]interface{}{"type": "ref/tool", "name": "Products_filter"},
+		Argument: "$select",
+		Value:    "ProductID,N",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ProductID,Name"}, values)
+
+	values, err = b.Complete(ctx, &mcp.CompletionRequest{
+		Ref:      map[string]interface{}{"type": "ref/tool", "name": "Products_filter"},
+		Argument: "$orderby",
+		Value:    "Price",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Price", "Price desc"}, values)
+}
+
+func TestCompletionCompactToolArguments(t *testing.T) {
+	server := newMockODataService(t, nil)
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.CompactTools = true
+	})
+
+	values, err := b.Complete(context.Background(), &mcp.CompletionRequest{
+		Ref:       map[string]interface{}{"type": "ref/tool", "name": "query_entity"},
+		Argument:  "$select",
+		Value:     "Cu",
+		Arguments: map[string]string{"entity_set": "Orders"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Customer"}, values)
+}
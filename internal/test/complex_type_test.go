@@ -0,0 +1,102 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const complexTypesV2Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="MOCK_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <ComplexType Name="Geo">
+        <Property Name="Latitude" Type="Edm.Double"/>
+        <Property Name="Longitude" Type="Edm.Double"/>
+      </ComplexType>
+      <ComplexType Name="Address">
+        <Property Name="Street" Type="Edm.String"/>
+        <Property Name="City" Type="Edm.String"/>
+        <Property Name="Location" Type="MOCK_SRV.Geo"/>
+      </ComplexType>
+      <EntityType Name="Supplier">
+        <Key><PropertyRef Name="SupplierID"/></Key>
+        <Property Name="SupplierID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="Address" Type="MOCK_SRV.Address" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="MOCK_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Suppliers" EntityType="MOCK_SRV.Supplier"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const complexTypesV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Demo" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <ComplexType Name="Address">
+        <Property Name="Street" Type="Edm.String"/>
+      </ComplexType>
+      <ComplexType Name="PostalAddress" BaseType="Demo.Address">
+        <Property Name="PostalCode" Type="Edm.String"/>
+      </ComplexType>
+      <EntityType Name="Person">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="Addresses" Type="Collection(Demo.PostalAddress)"/>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="People" EntityType="Demo.Person"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestComplexTypeParsing(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(complexTypesV2Metadata), "http://example.com/")
+	require.NoError(t, err)
+	require.Contains(t, meta.ComplexTypes, "Address")
+	assert.Len(t, meta.ComplexTypes["Address"].Properties, 3)
+	assert.Equal(t, "Geo", meta.ComplexTypes["Address"].Properties[2].ComplexType)
+
+	address := meta.EntityTypes["Supplier"].Properties[1]
+	assert.Equal(t, "Address", address.ComplexType)
+	assert.False(t, address.IsCollection)
+
+	meta, err = metadata.ParseMetadata([]byte(complexTypesV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+	require.Contains(t, meta.ComplexTypes, "PostalAddress")
+	var names []string
+	for _, prop := range meta.ComplexTypes["PostalAddress"].Properties {
+		names = append(names, prop.Name)
+	}
+	assert.Equal(t, []string{"Street", "PostalCode"}, names, "base type properties come first")
+
+	addresses := meta.EntityTypes["Person"].Properties[1]
+	assert.Equal(t, "PostalAddress", addresses.ComplexType)
+	assert.True(t, addresses.IsCollection)
+}
+
+func TestComplexTypeToolSchema(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, complexTypesV2Metadata, nil)
+	b := newMockBridge(t, service, nil)
+
+	var properties map[string]interface{}
+	for _, tool := range listTools(t, b) {
+		if tool.Name == "Suppliers_create" {
+			properties = tool.InputSchema["properties"].(map[string]interface{})
+		}
+	}
+	require.NotNil(t, properties, "create tool not registered")
+
+	address := properties["Address"].(map[string]interface{})
+	assert.Equal(t, "object", address["type"])
+	nested := address["properties"].(map[string]interface{})
+	assert.Equal(t, "string", nested["City"].(map[string]interface{})["type"])
+	location := nested["Location"].(map[string]interface{})
+	assert.Equal(t, "object", location["type"])
+	assert.Equal(t, "number", location["properties"].(map[string]interface{})["Latitude"].(map[string]interface{})["type"])
+}
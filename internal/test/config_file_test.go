@@ -0,0 +1,137 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	path := writeConfigFile(t, "odata-mcp.yaml", `
+service: https://sap.example.com/sap/opu/odata/sap/API_SALES_ORDER_SRV/
+user: DEVELOPER
+entities:
+  - A_SalesOrder*
+  - A_Customer
+tool_prefix: sales
+no-postfix: true
+max-items: 5242880
+tool-timeout: 2m
+`)
+
+	values, err := config.LoadFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"service":      "https://sap.example.com/sap/opu/odata/sap/API_SALES_ORDER_SRV/",
+		"user":         "DEVELOPER",
+		"entities":     "A_SalesOrder*,A_Customer",
+		"tool-prefix":  "sales",
+		"no-postfix":   "true",
+		"max-items":    "5242880",
+		"tool-timeout": "2m",
+	}, values)
+}
+
+func TestLoadConfigFileJSON(t *testing.T) {
+	path := writeConfigFile(t, "odata-mcp.json", `{"service": "https://example.com/odata/", "max-items": 5242880, "verbose": true}`)
+
+	values, err := config.LoadFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "5242880", values["max-items"])
+	assert.Equal(t, "true", values["verbose"])
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	_, err := config.LoadFile(filepath.Join(t.TempDir(), "missing.yaml"), "")
+	assert.Error(t, err)
+
+	_, err = config.LoadFile(writeConfigFile(t, "bad.yaml", "service: [unclosed"), "")
+	assert.Error(t, err)
+}
+
+const profilesConfig = `
+max-items: 50
+tool-shrink: true
+profile: dev
+profiles:
+  dev:
+    service: https://dev.example.com/sap/opu/odata/sap/API_SALES_ORDER_SRV/
+    user: DEVELOPER
+  QA:
+    service: https://qa.example.com/sap/opu/odata/sap/API_SALES_ORDER_SRV/
+    user: TESTER
+    max-items: 10
+    operations: r
+`
+
+func TestLoadConfigFileProfiles(t *testing.T) {
+	path := writeConfigFile(t, "odata-mcp.yaml", profilesConfig)
+
+	values, err := config.LoadFile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://dev.example.com/sap/opu/odata/sap/API_SALES_ORDER_SRV/", values["service"], "the file's profile key selects the default profile")
+	assert.Equal(t, "DEVELOPER", values["user"])
+	assert.Equal(t, "50", values["max-items"])
+	assert.NotContains(t, values, "profile")
+	assert.NotContains(t, values, "profiles")
+
+	values, err = config.LoadFile(path, "qa")
+	require.NoError(t, err)
+	assert.Equal(t, "https://qa.example.com/sap/opu/odata/sap/API_SALES_ORDER_SRV/", values["service"])
+	assert.Equal(t, "TESTER", values["user"])
+	assert.Equal(t, "10", values["max-items"], "profile values override top-level ones")
+	assert.Equal(t, "r", values["operations"])
+	assert.Equal(t, "true", values["tool-shrink"], "top-level values apply to every profile")
+
+	_, err = config.LoadFile(path, "prod")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: dev, qa")
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("ODATA_TOOL_PREFIX", "sap")
+	t.Setenv("ODATA_MAX_ITEMS", "25")
+	t.Setenv("ODATA_TOOL_TIMEOUT", "2m")
+	t.Setenv("ODATA_NO_POSTFIX", "")
+
+	values := config.LoadEnv([]string{"tool-prefix", "max-items", "tool-timeout", "no-postfix", "entities"})
+
+	assert.Equal(t, map[string]string{
+		"tool-prefix":  "sap",
+		"max-items":    "25",
+		"tool-timeout": "2m",
+	}, values, "unset and empty variables should be left out")
+	assert.Equal(t, "ODATA_METADATA_REFRESH_INTERVAL", config.EnvName("metadata-refresh-interval"))
+}
+
+func TestReadEnvFilesLayering(t *testing.T) {
+	common := writeConfigFile(t, ".env.common", "ODATA_URL=https://qa.example.com/odata/\nODATA_USERNAME=shared\nODATA_ENTITIES=Products\n")
+	secrets := writeConfigFile(t, ".env.qa", "ODATA_USERNAME=qa-user\nODATA_PASSWORD=secret\n")
+
+	values, err := config.ReadEnvFiles([]string{common, secrets}, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"ODATA_URL":      "https://qa.example.com/odata/",
+		"ODATA_USERNAME": "qa-user",
+		"ODATA_PASSWORD": "secret",
+		"ODATA_ENTITIES": "Products",
+	}, values, "later files should override earlier ones")
+
+	missing := filepath.Join(t.TempDir(), ".env.missing")
+	_, err = config.ReadEnvFiles([]string{common, missing}, false)
+	assert.Error(t, err)
+
+	values, err = config.ReadEnvFiles([]string{common, missing}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "shared", values["ODATA_USERNAME"])
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyConfigRegeneratesChangedTools(t *testing.T) {
+	server := newMockODataServiceWithMetadata(t, largeSAPMetadata(3), nil)
+	var initial *config.Config
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.AllowedEntities = []string{"Set0"}
+		initial = cfg
+	})
+	assert.False(t, toolOperations(t, b)["Set1:filter"])
+
+	next := *initial
+	next.AllowedEntities = []string{"Set0", "Set1"}
+	changed, err := b.ApplyConfig(&next)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Set0:filter"])
+	assert.True(t, ops["Set1:filter"], "tools of newly allowed entity sets should be registered")
+	assert.False(t, ops["Set2:filter"])
+
+	next.AllowedEntities = []string{"Set1"}
+	changed, err = b.ApplyConfig(&next)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.False(t, toolOperations(t, b)["Set0:filter"], "tools of entity sets no longer allowed should be removed")
+	for _, tool := range listTools(t, b) {
+		assert.NotContains(t, tool.Name, "Set0_")
+	}
+}
+
+func TestApplyConfigLimitsWithoutToolChanges(t *testing.T) {
+	var tops []string
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		tops = append(tops, r.URL.Query().Get("$top"))
+		productListHandler(3)(w, r)
+	})
+	var initial *config.Config
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxItems = 8
+		initial = cfg
+	})
+	before := len(listTools(t, b))
+
+	next := *initial
+	next.MaxItems = 3
+	changed, err := b.ApplyConfig(&next)
+	require.NoError(t, err)
+	assert.False(t, changed, "a changed limit should not regenerate the tools")
+	assert.Len(t, listTools(t, b), before)
+
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "4", tops[len(tops)-1], "the new --max-items should apply to the next call")
+}
+
+func TestApplyConfigLiftsResponseSizeLimit(t *testing.T) {
+	service := newMockODataService(t, productListHandler(30))
+	var initial *config.Config
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxResponseSize = 100
+		initial = cfg
+	})
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.Error(t, err, "the body exceeds the limit derived from --max-response-size")
+
+	next := *initial
+	next.MaxResponseSize = 0
+	_, err = b.ApplyConfig(&next)
+	require.NoError(t, err)
+
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	assert.NoError(t, err, "removing --max-response-size should lift the body limit")
+}
+
+func TestApplyConfigDuringToolCalls(t *testing.T) {
+	service := newMockODataService(t, productListHandler(3))
+	var initial *config.Config
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		initial = cfg
+	})
+
+	var calls sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		calls.Add(1)
+		go func() {
+			defer calls.Done()
+			for j := 0; j < 10; j++ {
+				_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		next := *initial
+		next.MaxItems = 2 + i%2
+		_, err := b.ApplyConfig(&next)
+		require.NoError(t, err)
+	}
+	calls.Wait()
+}
+
+func TestApplyConfigKeepsEntitiesEnabledOnDemand(t *testing.T) {
+	server := newMockODataService(t, nil)
+	var initial *config.Config
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxTools = 5
+		initial = cfg
+	})
+	_, err := b.CallTool(context.Background(), "enable_entity", map[string]interface{}{"name": "Products"})
+	require.NoError(t, err)
+	before := len(listTools(t, b))
+
+	next := *initial
+	next.MaxItems = 3
+	changed, err := b.ApplyConfig(&next)
+	require.NoError(t, err)
+	assert.False(t, changed, "a changed limit should not regenerate the tools")
+	assert.Len(t, listTools(t, b), before)
+
+	next.BulkCreate = true
+	changed, err = b.ApplyConfig(&next)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Products:filter"], "entity sets enabled on demand should stay enabled")
+	assert.True(t, ops["Products:bulk_create"])
+	assert.False(t, ops["Orders:filter"])
+}
+
+func TestApplyConfigBeforeToolsAreListed(t *testing.T) {
+	server := newMockODataServiceWithMetadata(t, largeSAPMetadata(3), nil)
+	var initial *config.Config
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.AllowedEntities = []string{"Set0"}
+		initial = cfg
+	})
+
+	next := *initial
+	next.AllowedEntities = []string{"Set1"}
+	changed, err := b.ApplyConfig(&next)
+	require.NoError(t, err)
+	assert.False(t, changed, "no client has seen the tools yet")
+
+	ops := toolOperations(t, b)
+	assert.False(t, ops["Set0:filter"])
+	assert.True(t, ops["Set1:filter"], "the tools should be generated with the new configuration")
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingService serves a collection of products with an inline count and counts the
+// collection queries by $filter
+type countingService struct {
+	mu      sync.Mutex
+	queries map[string]int
+}
+
+func (s *countingService) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodPost {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"d":{"ProductID":1}}`))
+		return
+	}
+	s.mu.Lock()
+	s.queries[r.URL.Query().Get("$filter")]++
+	s.mu.Unlock()
+	w.Write([]byte(`{"d":{"__count":"42","results":[]}}`))
+}
+
+func (s *countingService) count(filter string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries[filter]
+}
+
+func TestCountCacheReusesCounts(t *testing.T) {
+	service := &countingService{queries: make(map[string]int)}
+	server := newMockODataService(t, service.handle)
+	b := newMockBridge(t, server, func(cfg *config.Config) { cfg.CountCacheTTL = time.Minute })
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		result, err := b.CallTool(ctx, "Products_count", map[string]interface{}{"$filter": "Price gt 10"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"count": 42}`, result.(string))
+	}
+	assert.Equal(t, 1, service.count("Price gt 10"))
+
+	// Another filter is counted separately
+	_, err := b.CallTool(ctx, "Products_count", map[string]interface{}{"$filter": "Price gt 20"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, service.count("Price gt 20"))
+
+	// The inline count of a query fills the cache
+	_, err = b.CallTool(ctx, "Products_filter", map[string]interface{}{"$filter": "Price gt 30"})
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Products_count", map[string]interface{}{"$filter": "Price gt 30"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, service.count("Price gt 30"))
+
+	// A create drops the counts of the entity set
+	_, err = b.CallTool(ctx, "Products_create", map[string]interface{}{"ProductID": 1, "Name": "Widget"})
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Products_count", map[string]interface{}{"$filter": "Price gt 10"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, service.count("Price gt 10"))
+}
+
+func TestCountCacheExpires(t *testing.T) {
+	service := &countingService{queries: make(map[string]int)}
+	server := newMockODataService(t, service.handle)
+	b := newMockBridge(t, server, func(cfg *config.Config) { cfg.CountCacheTTL = 50 * time.Millisecond })
+
+	_, err := b.CallTool(context.Background(), "Products_count", map[string]interface{}{})
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	_, err = b.CallTool(context.Background(), "Products_count", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, service.count(""))
+}
+
+func TestCountCacheDisabled(t *testing.T) {
+	service := &countingService{queries: make(map[string]int)}
+	server := newMockODataService(t, service.handle)
+	b := newMockBridge(t, server, nil)
+
+	for i := 0; i < 2; i++ {
+		_, err := b.CallTool(context.Background(), "Products_count", map[string]interface{}{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, service.count(""))
+}
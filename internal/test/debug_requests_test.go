@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recentRequests calls debug_recent_requests and decodes its result
+func recentRequests(t *testing.T, call func(string, map[string]interface{}) (interface{}, error), args map[string]interface{}) []client.RecordedRequest {
+	result, err := call("debug_recent_requests", args)
+	require.NoError(t, err)
+	var response struct {
+		Requests []client.RecordedRequest `json:"requests"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	return response.Requests
+}
+
+func TestDebugRecentRequests(t *testing.T) {
+	server := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("X-CSRF-Token"), "fetch") {
+			w.Header().Set("X-CSRF-Token", "secret-token")
+			w.Header().Set("Set-Cookie", "SAP_SESSIONID=secret-session")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"d":{"OrderID":"4712"}}`))
+			return
+		}
+		w.Write([]byte(`{"d":{"results":[{"OrderID":"4711","Customer":"` + strings.Repeat("x", 5000) + `"}]}}`))
+	})
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.Username, cfg.Password = "user", "password"
+		cfg.DebugRequests = 3
+	})
+	call := func(name string, args map[string]interface{}) (interface{}, error) {
+		return b.CallTool(context.Background(), name, args)
+	}
+
+	_, err := call("Orders_filter", map[string]interface{}{"$top": float64(1)})
+	require.NoError(t, err)
+	_, err = call("Orders_create", map[string]interface{}{"OrderID": "4712", "Customer": "ACME"})
+	require.NoError(t, err)
+
+	requests := recentRequests(t, call, map[string]interface{}{"limit": float64(10)})
+	require.Len(t, requests, 3, "the buffer keeps the last 3 of $metadata, the query, the CSRF fetch and the create")
+
+	create := requests[0]
+	assert.Equal(t, "POST", create.Method)
+	assert.Contains(t, create.URL, "/Orders")
+	assert.Equal(t, http.StatusCreated, create.Status)
+	assert.JSONEq(t, `{"OrderID":"4712","Customer":"ACME"}`, create.RequestBody)
+	assert.Contains(t, create.ResponseBody, `"OrderID":"4712"`)
+	assert.Equal(t, "[REDACTED]", create.RequestHeaders["Authorization"])
+	assert.Equal(t, "[REDACTED]", create.RequestHeaders["X-Csrf-Token"])
+	assert.NotEmpty(t, create.RequestID)
+
+	fetch := requests[1]
+	assert.Equal(t, "[REDACTED]", fetch.ResponseHeaders["X-Csrf-Token"])
+	assert.Equal(t, "[REDACTED]", fetch.ResponseHeaders["Set-Cookie"])
+
+	query := requests[2]
+	assert.Equal(t, "GET", query.Method)
+	assert.Contains(t, query.URL, "%24top=1")
+	assert.True(t, strings.HasSuffix(query.ResponseBody, "... (truncated)"), "bodies are cut to 4 KB")
+	assert.Less(t, len(query.ResponseBody), 4200)
+
+	for _, r := range requests {
+		data, _ := json.Marshal(r)
+		assert.NotContains(t, string(data), "secret-token")
+		assert.NotContains(t, string(data), "secret-session")
+	}
+
+	assert.Len(t, recentRequests(t, call, map[string]interface{}{}), 3, "the default limit is 5")
+	assert.Len(t, recentRequests(t, call, map[string]interface{}{"limit": float64(1)}), 1)
+}
+
+func TestDebugRecentRequestsToolIsOptIn(t *testing.T) {
+	b := newMockBridge(t, newMockODataService(t, nil), nil)
+	for _, tool := range listTools(t, b) {
+		assert.NotEqual(t, "debug_recent_requests", tool.Name)
+	}
+}
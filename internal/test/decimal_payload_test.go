@@ -0,0 +1,58 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// itemMetadata has numeric properties whose names do not reveal their Edm type
+const itemMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="ITEM_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Item">
+        <Key><PropertyRef Name="ItemID"/></Key>
+        <Property Name="ItemID" Type="Edm.String" Nullable="false"/>
+        <Property Name="Quantity" Type="Edm.Int32"/>
+        <Property Name="Weight" Type="Edm.Double"/>
+        <Property Name="Factor" Type="Edm.Decimal" Precision="13" Scale="3"/>
+        <Property Name="Serial" Type="Edm.Int64"/>
+      </EntityType>
+      <EntityContainer Name="ITEM_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Items" EntityType="ITEM_SRV.Item"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestNumericPayloadUsesMetadataTypes(t *testing.T) {
+	var payload map[string]interface{}
+	service := newMockODataServiceWithMetadata(t, itemMetadata, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost || r.Method == http.MethodPut {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"ItemID":"A"}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	for _, tool := range []string{"Items_create", "Items_update"} {
+		payload = nil
+		_, err := b.CallTool(context.Background(), tool, map[string]interface{}{
+			"ItemID": "A", "Quantity": 3.0, "Weight": 1.5, "Factor": 0.125, "Serial": 9007199254740.0,
+		})
+		require.NoError(t, err, tool)
+		require.NotNil(t, payload, tool)
+
+		assert.Equal(t, "0.125", payload["Factor"], tool)
+		assert.Equal(t, "9007199254740", payload["Serial"], tool)
+		// Edm.Int32 and Edm.Double stay JSON numbers even though the names look like measures
+		assert.Equal(t, 3.0, payload["Quantity"], tool)
+		assert.Equal(t, 1.5, payload["Weight"], tool)
+	}
+}
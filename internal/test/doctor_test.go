@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/doctor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runDoctor runs the doctor checks against a test server and indexes them by name
+func runDoctor(t *testing.T, handler http.HandlerFunc, configure func(cfg *config.Config)) (*doctor.Report, map[string]doctor.Check) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	cfg.ServiceURL = server.URL + "/sap/opu/odata/sap/API_LARGE_SRV/"
+	if configure != nil {
+		configure(cfg)
+	}
+
+	report := doctor.Run(context.Background(), cfg)
+	checks := make(map[string]doctor.Check)
+	for _, check := range report.Checks {
+		checks[check.Name] = check
+	}
+	return report, checks
+}
+
+// sapDoctorHandler serves a SAP-like service that issues CSRF tokens and answers in
+// JSON, optionally only with $format=json
+func sapDoctorHandler(t *testing.T, formatOnly bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "DEVELOPER" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/$metadata"):
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(largeSAPMetadata(2)))
+		case strings.HasSuffix(r.URL.Path, "/Set0"):
+			assert.Equal(t, "1", r.URL.Query().Get("$top"))
+			if formatOnly && r.URL.Query().Get("$format") != "json" {
+				w.Header().Set("Content-Type", "application/atom+xml")
+				w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"/>`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"d":{"results":[]}}`))
+		default:
+			if r.Header.Get("X-CSRF-Token") == "Fetch" {
+				w.Header().Set("X-CSRF-Token", "token-123")
+				http.SetCookie(w, &http.Cookie{Name: "SAP_SESSIONID", Value: "abc"})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"d":{"EntitySets":["Set0","Set1"]}}`))
+		}
+	}
+}
+
+func withDeveloperAuth(cfg *config.Config) {
+	cfg.Username = "DEVELOPER"
+	cfg.Password = "secret"
+}
+
+func TestDoctorHealthyService(t *testing.T) {
+	report, checks := runDoctor(t, sapDoctorHandler(t, false), withDeveloperAuth)
+
+	assert.False(t, report.Failed())
+	require.Len(t, report.Checks, 6)
+	for _, check := range report.Checks {
+		if check.Name == "Connection" {
+			assert.Equal(t, doctor.StatusWarn, check.Status, "credentials over plain HTTP should be flagged")
+			continue
+		}
+		assert.Equal(t, doctor.StatusOK, check.Status, "%s: %s", check.Name, check.Detail)
+	}
+	assert.Contains(t, checks["Authentication"].Detail, "basic authentication as DEVELOPER")
+	assert.Contains(t, checks["Metadata"].Detail, "SAP OData v2")
+	assert.Contains(t, checks["Metadata"].Detail, "2 entity sets")
+	assert.Contains(t, checks["CSRF token"].Detail, "1 session cookies")
+	assert.Contains(t, checks["JSON format"].Detail, "Set0")
+}
+
+func TestDoctorUnauthorizedSkipsLaterChecks(t *testing.T) {
+	report, checks := runDoctor(t, sapDoctorHandler(t, false), func(cfg *config.Config) {
+		cfg.Username = "DEVELOPER"
+		cfg.Password = "wrong"
+	})
+
+	assert.True(t, report.Failed())
+	assert.Equal(t, doctor.StatusWarn, checks["Connection"].Status)
+	assert.Equal(t, doctor.StatusFail, checks["Authentication"].Status)
+	assert.Contains(t, checks["Authentication"].Detail, "HTTP 401")
+	for _, name := range []string{"Metadata", "CSRF token", "JSON format"} {
+		assert.Equal(t, doctor.StatusSkip, checks[name].Status, name)
+	}
+}
+
+func TestDoctorLoginPage(t *testing.T) {
+	report, checks := runDoctor(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<!DOCTYPE html><html><body><form>Login</form></body></html>`))
+	}, nil)
+
+	assert.True(t, report.Failed())
+	assert.Equal(t, doctor.StatusFail, checks["Authentication"].Status)
+	assert.Contains(t, checks["Authentication"].Detail, "HTML page")
+}
+
+func TestDoctorJSONOnlyWithFormatParam(t *testing.T) {
+	report, checks := runDoctor(t, sapDoctorHandler(t, true), withDeveloperAuth)
+
+	assert.False(t, report.Failed(), "a warning does not make the service unusable")
+	assert.Equal(t, doctor.StatusWarn, checks["JSON format"].Status)
+	assert.Contains(t, checks["JSON format"].Detail, "--format-param always")
+}
+
+func TestDoctorUnresolvableHost(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.ServiceURL = "http://odata-mcp-doctor.invalid/service/"
+
+	report := doctor.Run(context.Background(), cfg)
+
+	assert.True(t, report.Failed())
+	require.NotEmpty(t, report.Checks)
+	assert.Equal(t, "DNS", report.Checks[0].Name)
+	assert.Equal(t, doctor.StatusFail, report.Checks[0].Status)
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dryRunResult decodes the request description a tool returns with --dry-run
+func dryRunResult(t *testing.T, result interface{}) map[string]interface{} {
+	text, ok := result.(string)
+	require.True(t, ok, "dry run results are JSON strings")
+	var request map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(text), &request))
+	assert.Equal(t, true, request["dry_run"])
+	return request
+}
+
+func TestDryRunReturnsRequestsWithoutCallingService(t *testing.T) {
+	var calls atomic.Int32
+	server := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.DryRun = true
+		cfg.Username, cfg.Password = "user", "secret"
+		cfg.ConfirmOperations = "delete"
+	})
+	ctx := context.Background()
+
+	result, err := b.CallTool(ctx, "Products_filter", map[string]interface{}{
+		"$filter": "Name eq 'Tom & Jerry'",
+		"$top":    float64(5),
+	})
+	require.NoError(t, err)
+	request := dryRunResult(t, result)
+	assert.Equal(t, "GET", request["method"])
+	requestURL, err := url.Parse(request["url"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "/sap/opu/odata/sap/MOCK_SRV/Products", requestURL.Path)
+	assert.Equal(t, "Name eq 'Tom & Jerry'", requestURL.Query().Get("$filter"))
+	assert.Equal(t, "5", requestURL.Query().Get("$top"))
+	assert.NotContains(t, request["headers"], "Authorization", "credentials are not shown")
+
+	result, err = b.CallTool(ctx, "Orders_get", map[string]interface{}{"OrderID": "4711"})
+	require.NoError(t, err)
+	assert.Contains(t, dryRunResult(t, result)["url"], "Orders('4711')")
+
+	result, err = b.CallTool(ctx, "Orders_create", map[string]interface{}{"OrderID": "4712", "Customer": "ACME"})
+	require.NoError(t, err)
+	request = dryRunResult(t, result)
+	assert.Equal(t, "POST", request["method"])
+	assert.Equal(t, map[string]interface{}{"OrderID": "4712", "Customer": "ACME"}, request["body"])
+
+	result, err = b.CallTool(ctx, "Orders_delete", map[string]interface{}{"OrderID": "4712"})
+	require.NoError(t, err, "dry runs of confirmed operations are not confirmed")
+	assert.Equal(t, "DELETE", dryRunResult(t, result)["method"])
+
+	assert.Zero(t, calls.Load(), "no request except $metadata reaches the service")
+}
+
+func TestDryRunToolsDeclareNoOutputSchema(t *testing.T) {
+	b := newMockBridge(t, newMockODataService(t, nil), func(cfg *config.Config) {
+		cfg.DryRun = true
+	})
+	for _, tool := range listTools(t, b) {
+		assert.Nil(t, tool.OutputSchema, tool.Name)
+	}
+}
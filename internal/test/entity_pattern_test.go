@@ -0,0 +1,83 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    bool
+	}{
+		{"Products", "Products", true},
+		{"Products", "Product*", true},
+		{"SalesOrders", "*Orders", true},
+		{"A_SalesOrderItem", "*Order*", true},
+		{"A_SalesOrderItem", "A_*Item", true},
+		{"A_SalesOrderHeader", "A_*Item", false},
+		{"Product1", "Product?", true},
+		{"Product12", "Product?", false},
+		{"A_ProductPlant", "^A_(Product|Plant).*", true},
+		{"A_Plant", "^A_(Product|Plant).*", true},
+		{"A_Customer", "^A_(Product|Plant).*", false},
+		{"SalesOrderSet", ".*Set$", true},
+		{"SalesOrderItem", "/Order(Item|Header)/", true},
+		{"Orders.Item", "Orders.Item", true},
+		{"OrdersXItem", "Orders.Item", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"~"+tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, utils.MatchPattern(tt.name, tt.pattern))
+		})
+	}
+}
+
+func TestCompilePatternRejectsInvalidRegex(t *testing.T) {
+	_, err := utils.CompilePattern("^A_(Product")
+	assert.Error(t, err)
+	assert.False(t, utils.MatchPattern("A_Product", "^A_(Product"))
+}
+
+func TestSplitPatterns(t *testing.T) {
+	assert.Equal(t, []string{"^A_(Product|Plant){1,2}$", "Orders", "[a,b]*"}, utils.SplitPatterns("^A_(Product|Plant){1,2}$, Orders,,[a,b]*"))
+	assert.Equal(t, []string{"A,B", `\(x,y`}, utils.SplitPatterns(`A\,B,\(x\,y`))
+	assert.Nil(t, utils.SplitPatterns(" , "))
+}
+
+func TestConfigPatterns(t *testing.T) {
+	cfg := &config.Config{AllowedEntities: utils.SplitPatterns("^A_(Product|Plant){1,2}$,Orders")}
+	require.NoError(t, cfg.CompilePatterns())
+	assert.True(t, cfg.Patterns.Match("A_Product", "^A_(Product|Plant){1,2}$"))
+	assert.False(t, cfg.Patterns.Match("A_Customer", "^A_(Product|Plant){1,2}$"))
+	assert.True(t, cfg.Patterns.Match("Products", "Product*"), "patterns not compiled in advance still match")
+
+	cfg.AllowedEntities = []string{"^A_(Product"}
+	assert.Error(t, cfg.CompilePatterns())
+
+	rules, err := config.ParseEntityOperationRules("^A_(Product|Plant){1,2}$:read,Orders:read+create")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "^A_(Product|Plant){1,2}$", rules[0].Pattern)
+	assert.Equal(t, "Orders", rules[1].Pattern)
+}
+
+func TestEntityRegexFilter(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.AllowedEntities = []string{"^Ord.*s$"}
+		cfg.AllowedFunctions = []string{"/^Get.*Products$/"}
+	})
+
+	ops := toolOperations(t, b)
+	assert.Contains(t, ops, "Orders:filter")
+	assert.NotContains(t, ops, "Products:filter")
+	require.Contains(t, ops, "function:GetTopProducts")
+}
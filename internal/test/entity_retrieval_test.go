@@ -15,6 +15,23 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// orderItemMetadata declares the composite key of OrderItemSet as OrderID, ItemID
+const orderItemMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="TEST" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="OrderItem">
+        <Key><PropertyRef Name="OrderID"/><PropertyRef Name="ItemID"/></Key>
+        <Property Name="ItemID" Type="Edm.String" Nullable="false"/>
+        <Property Name="OrderID" Type="Edm.Int32" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="TEST_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="OrderItemSet" EntityType="TEST.OrderItem"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
 // TestEntityKeyPredicate tests proper key predicate formatting for entity retrieval
 func TestEntityKeyPredicate(t *testing.T) {
 	tests := []struct {
@@ -54,6 +71,11 @@ func TestEntityKeyPredicate(t *testing.T) {
 			var capturedPath string
 			
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/$metadata" {
+					w.Header().Set("Content-Type", "application/xml")
+					w.Write([]byte(orderItemMetadata))
+					return
+				}
 				capturedPath = r.URL.Path
 				t.Logf("Request URL: %s", r.URL.String())
 				
@@ -73,8 +95,12 @@ func TestEntityKeyPredicate(t *testing.T) {
 			client := client.NewODataClient(server.URL, false)
 			client.SetBasicAuth("test", "test")
 
+			// Composite keys are ordered by the key definition in the metadata
+			_, err := client.GetMetadata(context.Background())
+			require.NoError(t, err)
+
 			// Get entity
-			_, err := client.GetEntity(context.Background(), tt.entitySet, tt.key, nil)
+			_, err = client.GetEntity(context.Background(), tt.entitySet, tt.key, nil)
 			require.NoError(t, err)
 
 			// Verify the path construction
@@ -0,0 +1,153 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// singleExpandService rejects expanding more than one navigation property per request,
+// like some SAP gateways, and records how many expansions were in flight at once
+type singleExpandService struct {
+	mu          sync.Mutex
+	rejected    int
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *singleExpandService) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	expand := r.URL.Query().Get("$expand")
+	if strings.Contains(expand, ",") {
+		s.mu.Lock()
+		s.rejected++
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"/IWBEP/CM_MGW_RT/022","message":{"lang":"en","value":"Only one navigation property can be expanded"}}}`))
+		return
+	}
+
+	s.mu.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+
+	entity := func(id int) map[string]interface{} {
+		return map[string]interface{}{
+			"ProductID": id,
+			"Supplier":  map[string]interface{}{"__deferred": map[string]interface{}{"uri": "Supplier"}},
+			"Category":  map[string]interface{}{"__deferred": map[string]interface{}{"uri": "Category"}},
+			expand:      map[string]interface{}{"Name": expand + "-" + string(rune('0'+id))},
+		}
+	}
+	if strings.Contains(r.URL.Path, "Products(") {
+		json.NewEncoder(w).Encode(map[string]interface{}{"d": entity(1)})
+		return
+	}
+	// The second expansion lists the products in another order; they are merged by key
+	items := []interface{}{entity(1), entity(2)}
+	if expand == "Category" {
+		items = []interface{}{entity(2), entity(1)}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"d": map[string]interface{}{"results": items}})
+}
+
+func TestRejectedExpandIsSplitIntoConcurrentRequests(t *testing.T) {
+	service := &singleExpandService{}
+	b := newMockBridge(t, newMockODataService(t, service.handle), nil)
+
+	for i := 0; i < 2; i++ {
+		result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$expand": "Supplier,Category"})
+		require.NoError(t, err)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+		items := response["value"].([]interface{})
+		require.Len(t, items, 2)
+		for _, item := range items {
+			entity := item.(map[string]interface{})
+			id := string(rune('0' + int(entity["ProductID"].(float64))))
+			assert.Equal(t, "Supplier-"+id, entity["Supplier"].(map[string]interface{})["Name"])
+			assert.Equal(t, "Category-"+id, entity["Category"].(map[string]interface{})["Name"])
+		}
+	}
+	// The entity set is split right away after the first rejection
+	assert.Equal(t, 1, service.rejected)
+	assert.Equal(t, 2, service.maxInFlight)
+
+	result, err := b.CallTool(context.Background(), "Products_get", map[string]interface{}{"ProductID": 1, "$expand": "Supplier,Category"})
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	entity := response["value"].(map[string]interface{})
+	assert.Equal(t, "Supplier-1", entity["Supplier"].(map[string]interface{})["Name"])
+	assert.Equal(t, "Category-1", entity["Category"].(map[string]interface{})["Name"])
+}
+
+func TestExpandErrorsAreNotMasked(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"SY/530","message":{"lang":"en","value":"Property Nope not found"}}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$expand": "Supplier,Nope"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Property Nope not found")
+}
+
+func TestSplitExpandKeepsNestedPathsTogether(t *testing.T) {
+	var mu sync.Mutex
+	var expands, orders []string
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		expand := query.Get("$expand")
+		mu.Lock()
+		expands = append(expands, expand)
+		orders = append(orders, query.Get("$orderby"))
+		mu.Unlock()
+		if strings.Contains(expand, "Category") && strings.Contains(expand, "Supplier") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"/IWBEP/CM_MGW_RT/022","message":{"lang":"en","value":"Only one navigation property can be expanded"}}}`))
+			return
+		}
+		entity := map[string]interface{}{"ProductID": 1}
+		if expand == "Category" {
+			entity["Category"] = map[string]interface{}{"Name": "Beverages"}
+		} else {
+			entity["Supplier"] = map[string]interface{}{
+				"Address": map[string]interface{}{"City": "London"},
+				"Country": map[string]interface{}{"Name": "UK"},
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"d": map[string]interface{}{"results": []interface{}{entity}}})
+	})
+	b := newMockBridge(t, service, nil)
+
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$expand": "Supplier/Address,Category,Supplier/Country", "$top": float64(10)})
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	entity := response["value"].([]interface{})[0].(map[string]interface{})
+	supplier := entity["Supplier"].(map[string]interface{})
+	assert.Contains(t, supplier, "Address")
+	assert.Contains(t, supplier, "Country", "paths below the same navigation property are expanded together")
+	assert.Equal(t, "Beverages", entity["Category"].(map[string]interface{})["Name"])
+
+	assert.ElementsMatch(t, []string{"Supplier/Address,Category,Supplier/Country", "Supplier/Address,Supplier/Country", "Category"}, expands)
+	assert.Equal(t, []string{"ProductID", "ProductID"}, orders[1:], "split queries are ordered by the key")
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripJSON generates an export and encodes and decodes it, as its consumers see it
+func roundTripJSON(t *testing.T, export func() (map[string]interface{}, error)) map[string]interface{} {
+	value, err := export()
+	require.NoError(t, err)
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+func TestExportOpenAPI(t *testing.T) {
+	server := newMockODataServiceWithMetadata(t, largeSAPMetadata(2), nil)
+	b := newMockBridge(t, server, nil)
+
+	doc := roundTripJSON(t, b.ExportOpenAPI)
+	assert.Equal(t, "3.1.0", doc["openapi"])
+	assert.Contains(t, doc["info"].(map[string]interface{})["title"], server.URL)
+
+	paths := doc["paths"].(map[string]interface{})
+	assert.Len(t, paths, len(listTools(t, b)), "every tool should become an operation")
+
+	filter, ok := paths["/tools/Set0_filter"].(map[string]interface{})["post"].(map[string]interface{})
+	require.True(t, ok, "filter tool should be exported as a POST operation")
+	assert.Equal(t, "Set0_filter", filter["operationId"])
+	assert.Equal(t, []interface{}{"Set0"}, filter["tags"])
+	assert.Equal(t, true, filter["x-mcp-annotations"].(map[string]interface{})["readOnlyHint"])
+
+	schema := filter["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	assert.Equal(t, "object", schema["type"])
+	assert.Contains(t, schema["properties"], "$filter")
+
+	response := filter["responses"].(map[string]interface{})["200"].(map[string]interface{})
+	assert.Contains(t, response, "content", "the output schema should type the response")
+
+	info := paths["/tools/odata_service_info"].(map[string]interface{})["post"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"Service"}, info["tags"])
+}
+
+func TestExportOpenAPIFollowsFilters(t *testing.T) {
+	server := newMockODataServiceWithMetadata(t, largeSAPMetadata(2), nil)
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.AllowedEntities = []string{"Set1"}
+	})
+
+	paths := roundTripJSON(t, b.ExportOpenAPI)["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/tools/Set1_filter")
+	assert.NotContains(t, paths, "/tools/Set0_filter")
+}
+
+func TestExportManifest(t *testing.T) {
+	server := newMockODataServiceWithMetadata(t, largeSAPMetadata(2), nil)
+	b := newMockBridge(t, server, nil)
+
+	manifest := roundTripJSON(t, b.ExportManifest)
+	tools := manifest["tools"].([]interface{})
+	require.Len(t, tools, len(listTools(t, b)))
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		entry := tool.(map[string]interface{})
+		names = append(names, entry["name"].(string))
+		assert.Contains(t, entry, "inputSchema")
+	}
+	assert.Contains(t, names, "Set0_get")
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const facetsMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="SALES_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="SalesOrder">
+        <Key><PropertyRef Name="SalesOrderID"/></Key>
+        <Property Name="SalesOrderID" Type="Edm.String" Nullable="false" MaxLength="10" sap:display-format="NonNegative"/>
+        <Property Name="OrderType" Type="Edm.String" MaxLength="4" sap:display-format="UpperCase" DefaultValue="OR"/>
+        <Property Name="NetAmount" Type="Edm.Decimal" Precision="16" Scale="3"/>
+        <Property Name="Priority" Type="Edm.Int32" DefaultValue="3"/>
+      </EntityType>
+      <EntityContainer Name="SALES_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="SalesOrders" EntityType="SALES_SRV.SalesOrder"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestPropertyFacetsParsing(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(facetsMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	props := meta.EntityTypes["SalesOrder"].Properties
+	assert.Equal(t, 10, props[0].MaxLength)
+	assert.Equal(t, "NonNegative", props[0].DisplayFormat)
+	assert.Equal(t, "OR", props[1].DefaultValue)
+	assert.Equal(t, 16, props[2].Precision)
+	require.NotNil(t, props[2].Scale)
+	assert.Equal(t, 3, *props[2].Scale)
+	assert.Nil(t, props[3].Scale)
+}
+
+func TestPropertyFacetsInInputSchemas(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, facetsMetadata, nil)
+	b := newMockBridge(t, service, nil)
+
+	schemas := make(map[string]map[string]interface{})
+	for _, tool := range listTools(t, b) {
+		schemas[tool.Name] = tool.InputSchema["properties"].(map[string]interface{})
+	}
+	property := func(tool, name string) map[string]interface{} {
+		return schemas[tool][name].(map[string]interface{})
+	}
+
+	key := property("SalesOrders_get", "SalesOrderID")
+	assert.Equal(t, 10, key["maxLength"])
+	assert.Equal(t, "^[0-9]*$", key["pattern"])
+	assert.Contains(t, key["description"], "e.g. 0000000001")
+
+	orderType := property("SalesOrders_create", "OrderType")
+	assert.Equal(t, 4, orderType["maxLength"])
+	assert.Equal(t, "OR", orderType["default"])
+	assert.Contains(t, orderType["description"], "upper case")
+
+	assert.Contains(t, property("SalesOrders_update", "NetAmount")["description"], "up to 16 digits, 3 after the decimal point")
+	assert.Equal(t, int64(3), property("SalesOrders_create", "Priority")["default"])
+	assert.NotContains(t, property("SalesOrders_update", "Priority"), "default", "an omitted property keeps its value on update")
+
+	_, err := b.CallTool(context.Background(), "SalesOrders_get", map[string]interface{}{"SalesOrderID": "12345678901"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+	_, err = b.CallTool(context.Background(), "SalesOrders_get", map[string]interface{}{"SalesOrderID": "SO-1"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+	_, err = b.CallTool(context.Background(), "SalesOrders_get", map[string]interface{}{"SalesOrderID": "0000000001"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainV2Metadata is mockServiceMetadata without SAP annotations
+var plainV2Metadata = strings.NewReplacer(
+	` xmlns:sap="http://www.sap.com/Protocols/SAPData"`, "",
+	` sap:searchable="true"`, "",
+).Replace(mockServiceMetadata)
+
+func TestFormatParam(t *testing.T) {
+	testCases := []struct {
+		name       string
+		metadata   string
+		mode       string
+		wantFormat bool
+	}{
+		{"auto on SAP v2", mockServiceMetadata, constants.FormatParamAuto, true},
+		{"auto on plain v2", plainV2Metadata, constants.FormatParamAuto, false},
+		{"always on plain v2", plainV2Metadata, constants.FormatParamAlways, true},
+		{"never on SAP v2", mockServiceMetadata, constants.FormatParamNever, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var query, accept string
+			service := newMockODataServiceWithMetadata(t, tc.metadata, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				accept = r.Header.Get("Accept")
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"d":{"results":[]}}`))
+			})
+
+			c := client.NewODataClient(service.URL+"/odata/", false)
+			c.SetFormatParam(tc.mode)
+			_, err := c.GetMetadata(context.Background())
+			require.NoError(t, err)
+
+			_, err = c.GetEntitySet(context.Background(), "Products", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantFormat, strings.Contains(query, "%24format=json"), query)
+			assert.Equal(t, constants.ContentTypeJSON, accept)
+		})
+	}
+}
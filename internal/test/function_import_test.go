@@ -145,4 +145,74 @@ func TestActivateProgramFunction(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, activateCalled, "ACTIVATE_PROGRAM should have been called")
 	assert.NotNil(t, result)
-}
\ No newline at end of file
+}
+
+const collectionFunctionMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="STOCK_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Material">
+        <Key><PropertyRef Name="MaterialID"/></Key>
+        <Property Name="MaterialID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <ComplexType Name="StockLevel">
+        <Property Name="Plant" Type="Edm.String"/>
+        <Property Name="Quantity" Type="Edm.Int32"/>
+      </ComplexType>
+      <EntityContainer Name="STOCK_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Materials" EntityType="STOCK_SRV.Material"/>
+        <FunctionImport Name="GetStockLevels" ReturnType="Collection(STOCK_SRV.StockLevel)" m:HttpMethod="GET">
+          <Parameter Name="MaterialID" Type="Edm.String" Mode="In"/>
+        </FunctionImport>
+        <FunctionImport Name="GetPlantNames" ReturnType="Collection(Edm.String)" m:HttpMethod="GET"/>
+        <FunctionImport Name="GetTotalStock" ReturnType="Edm.Int32" m:HttpMethod="GET"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestCollectionFunctionResults(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, collectionFunctionMetadata, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/GetStockLevels"):
+			w.Write([]byte(`{"d":{"results":[{"__metadata":{"type":"STOCK_SRV.StockLevel"},"Plant":"1000","Quantity":5},{"__metadata":{"type":"STOCK_SRV.StockLevel"},"Plant":"2000","Quantity":7}]}}`))
+		case strings.HasSuffix(r.URL.Path, "/GetPlantNames"):
+			w.Write([]byte(`{"d":{"GetPlantNames":{"results":["Hamburg","Berlin"]}}}`))
+		case strings.HasSuffix(r.URL.Path, "/GetTotalStock"):
+			w.Write([]byte(`{"d":{"GetTotalStock":12}}`))
+		}
+	})
+	b := newMockBridge(t, service, nil)
+
+	tools := make(map[string]map[string]interface{})
+	descriptions := make(map[string]string)
+	for _, tool := range listTools(t, b) {
+		tools[tool.Name] = tool.OutputSchema
+		descriptions[tool.Name] = tool.Description
+	}
+	assert.Contains(t, descriptions["GetStockLevels"], "Returns an array of StockLevel objects")
+	assert.Contains(t, descriptions["GetPlantNames"], "Returns an array of Edm.String values")
+	assert.Contains(t, descriptions["GetTotalStock"], "Returns Edm.Int32")
+
+	value := tools["GetStockLevels"]["properties"].(map[string]interface{})["value"].(map[string]interface{})
+	assert.Equal(t, "array", value["type"])
+	items := value["items"].(map[string]interface{})
+	assert.Contains(t, items["properties"], "Quantity")
+
+	call := func(name string) interface{} {
+		result, err := b.CallTool(context.Background(), name, map[string]interface{}{"MaterialID": "M1"})
+		require.NoError(t, err)
+		var response struct {
+			Value interface{} `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+		return response.Value
+	}
+
+	levels := call("GetStockLevels").([]interface{})
+	require.Len(t, levels, 2)
+	assert.Equal(t, "2000", levels[1].(map[string]interface{})["Plant"])
+	assert.Equal(t, []interface{}{"Hamburg", "Berlin"}, call("GetPlantNames"))
+	assert.Equal(t, float64(12), call("GetTotalStock"))
+}
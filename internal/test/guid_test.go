@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// documentMetadata has an Edm.Guid key, as common for SAP business documents
+const documentMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="DOC_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Document">
+        <Key><PropertyRef Name="DocumentUUID"/></Key>
+        <Property Name="DocumentUUID" Type="Edm.Guid" Nullable="false"/>
+        <Property Name="Checksum" Type="Edm.String"/>
+      </EntityType>
+      <EntityContainer Name="DOC_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Documents" EntityType="DOC_SRV.Document"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const (
+	testGUID       = "00112233-4455-6677-8899-aabbccddeeff"
+	testGUIDBase64 = "ABEiM0RVZneImaq7zN3u/w=="
+)
+
+func TestBase64ToGUID(t *testing.T) {
+	guid, ok := utils.Base64ToGUID(testGUIDBase64)
+	require.True(t, ok)
+	assert.Equal(t, testGUID, guid)
+
+	for _, value := range []string{testGUID, "short==", "ABEiM0RVZneImaq7zN3u/w", "!!!!!!!!!!!!!!!!!!!!!!=="} {
+		_, ok := utils.Base64ToGUID(value)
+		assert.False(t, ok, value)
+	}
+
+	assert.Equal(t, "DocumentUUID eq guid'"+testGUID+"'",
+		utils.NormalizeGUIDLiterals("DocumentUUID eq guid'"+testGUIDBase64+"'"))
+}
+
+func TestBase64GUIDNormalization(t *testing.T) {
+	var lastURL *url.URL
+	service := newMockODataServiceWithMetadata(t, documentMetadata, func(w http.ResponseWriter, r *http.Request) {
+		lastURL = r.URL
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[{"DocumentUUID":"` + testGUIDBase64 + `","Checksum":"` + testGUIDBase64 + `"}]}}`))
+	})
+	c := client.NewODataClient(service.URL+"/odata/", false)
+	_, err := c.GetMetadata(context.Background())
+	require.NoError(t, err)
+
+	// Responses: only Edm.Guid properties are converted
+	response, err := c.GetEntitySet(context.Background(), "Documents", map[string]string{
+		"$filter": "DocumentUUID eq guid'" + testGUIDBase64 + "'",
+	})
+	require.NoError(t, err)
+	entity := response.Value.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, testGUID, entity["DocumentUUID"])
+	assert.Equal(t, testGUIDBase64, entity["Checksum"])
+
+	// Filter input: base64 literals are sent in canonical form
+	assert.Equal(t, "DocumentUUID eq guid'"+testGUID+"'", lastURL.Query().Get("$filter"))
+
+	// Key input: a base64 GUID key addresses the same entity
+	_, _ = c.GetEntity(context.Background(), "Documents", map[string]interface{}{"DocumentUUID": testGUIDBase64}, nil)
+	assert.Contains(t, lastURL.Path, testGUID)
+	assert.NotContains(t, lastURL.Path, testGUIDBase64)
+}
+
+func TestGUIDNormalizationIsScopedToEntityTypes(t *testing.T) {
+	metadata := strings.Replace(documentMetadata, `<EntityContainer`, `<EntityType Name="Attachment">
+        <Key><PropertyRef Name="AttachmentID"/></Key>
+        <Property Name="AttachmentID" Type="Edm.String" Nullable="false"/>
+        <Property Name="DocumentUUID" Type="Edm.String"/>
+      </EntityType>
+      <EntityContainer`, 1)
+	metadata = strings.Replace(metadata, `</EntityContainer>`, `<EntitySet Name="Attachments" EntityType="DOC_SRV.Attachment"/>
+      </EntityContainer>`, 1)
+	service := newMockODataServiceWithMetadata(t, metadata, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[{"AttachmentID":"1","DocumentUUID":"` + testGUIDBase64 + `"}]}}`))
+	})
+	c := client.NewODataClient(service.URL+"/odata/", false)
+	_, err := c.GetMetadata(context.Background())
+	require.NoError(t, err)
+
+	// A property of the same name but another type is left alone
+	response, err := c.GetEntitySet(context.Background(), "Attachments", nil)
+	require.NoError(t, err)
+	entity := response.Value.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, testGUIDBase64, entity["DocumentUUID"])
+
+	// The type an entity declares is used where the request does not tell
+	response, err = c.DecodeResponse([]byte(`{"d":{"results":[` +
+		`{"__metadata":{"type":"DOC_SRV.Document"},"DocumentUUID":"` + testGUIDBase64 + `"},` +
+		`{"__metadata":{"type":"DOC_SRV.Attachment"},"DocumentUUID":"` + testGUIDBase64 + `"}]}}`))
+	require.NoError(t, err)
+	entities := response.Value.([]interface{})
+	assert.Equal(t, testGUID, entities[0].(map[string]interface{})["DocumentUUID"])
+	assert.Equal(t, testGUIDBase64, entities[1].(map[string]interface{})["DocumentUUID"])
+}
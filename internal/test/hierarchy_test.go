@@ -0,0 +1,194 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hierarchyMetadata is an SAP v2 service with a cost center hierarchy
+const hierarchyMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="ZCOSTCENTER_SRV" xml:lang="en" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="CostCenterNode" sap:semantics="hierarchy" sap:content-version="1">
+        <Key><PropertyRef Name="NodeID"/></Key>
+        <Property Name="NodeID" Type="Edm.String" Nullable="false" MaxLength="10" sap:hierarchy-node-for="NodeID"/>
+        <Property Name="ParentNodeID" Type="Edm.String" MaxLength="10" sap:hierarchy-parent-node-for="NodeID"/>
+        <Property Name="Level" Type="Edm.Int32" sap:hierarchy-level-for="NodeID"/>
+        <Property Name="DrillState" Type="Edm.String" MaxLength="10" sap:hierarchy-drill-state-for="NodeID"/>
+        <Property Name="Name" Type="Edm.String" MaxLength="40"/>
+      </EntityType>
+      <EntityContainer Name="ZCOSTCENTER_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="CostCenterNodes" EntityType="ZCOSTCENTER_SRV.CostCenterNode"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// costCenterTree serves the hierarchy R -> A (A1, A2), B (B1), answering $filter
+// conditions on NodeID or ParentNodeID joined by "or"
+func costCenterTree(t *testing.T) (*sync.Mutex, *[]string, http.HandlerFunc) {
+	nodes := []map[string]interface{}{
+		{"NodeID": "R", "ParentNodeID": "", "Level": 0, "DrillState": "expanded", "Name": "Company"},
+		{"NodeID": "A", "ParentNodeID": "R", "Level": 1, "DrillState": "expanded", "Name": "Sales"},
+		{"NodeID": "B", "ParentNodeID": "R", "Level": 1, "DrillState": "expanded", "Name": "Production"},
+		{"NodeID": "A1", "ParentNodeID": "A", "Level": 2, "DrillState": "leaf", "Name": "Sales North"},
+		{"NodeID": "A2", "ParentNodeID": "A", "Level": 2, "DrillState": "leaf", "Name": "Sales South"},
+		{"NodeID": "B1", "ParentNodeID": "B", "Level": 2, "DrillState": "leaf", "Name": "Plant 1"},
+	}
+	condition := regexp.MustCompile(`(NodeID|ParentNodeID) eq '([^']*)'`)
+	var mu sync.Mutex
+	var filters []string
+	return &mu, &filters, func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("$filter")
+		mu.Lock()
+		filters = append(filters, filter)
+		mu.Unlock()
+
+		results := []interface{}{}
+		for _, node := range nodes {
+			for _, match := range condition.FindAllStringSubmatch(filter, -1) {
+				if node[match[1]] == match[2] {
+					results = append(results, node)
+					break
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"d": map[string]interface{}{"results": results}})
+	}
+}
+
+// nodeIDs returns the NodeID of each entity of a filter tool result
+func nodeIDs(t *testing.T, result interface{}) []string {
+	var response struct {
+		Value []map[string]interface{} `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	ids := make([]string, len(response.Value))
+	for i, entity := range response.Value {
+		ids[i], _ = entity["NodeID"].(string)
+	}
+	return ids
+}
+
+func TestHierarchyAnnotationsAreParsed(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(hierarchyMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	assert.Equal(t, &models.Hierarchy{
+		NodeProperty:       "NodeID",
+		ParentProperty:     "ParentNodeID",
+		LevelProperty:      "Level",
+		DrillStateProperty: "DrillState",
+	}, meta.EntityTypes["CostCenterNode"].Hierarchy)
+
+	meta, err = metadata.ParseMetadata([]byte(mockServiceMetadata), "http://example.com/")
+	require.NoError(t, err)
+	assert.Nil(t, meta.EntityTypes["Product"].Hierarchy)
+}
+
+func TestHierarchyFilterParameters(t *testing.T) {
+	mu, filters, handler := costCenterTree(t)
+	b := newMockBridge(t, newMockODataServiceWithMetadata(t, hierarchyMetadata, handler), nil)
+
+	for _, tool := range listTools(t, b) {
+		if tool.Name == "CostCenterNodes_filter" {
+			properties := tool.InputSchema["properties"].(map[string]interface{})
+			for _, param := range []string{"node", "parent", "level", "subtree"} {
+				assert.Contains(t, properties, param)
+			}
+			assert.Contains(t, tool.Description, "hierarchy")
+		}
+	}
+
+	result, err := b.CallTool(context.Background(), "CostCenterNodes_filter", map[string]interface{}{"parent": "A"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1", "A2"}, nodeIDs(t, result))
+
+	_, err = b.CallTool(context.Background(), "CostCenterNodes_filter", map[string]interface{}{
+		"$filter": "Name ne 'Closed'", "parent": "R", "level": float64(1),
+	})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"ParentNodeID eq 'A'",
+		"(Name ne 'Closed') and ParentNodeID eq 'R' and Level eq 1",
+	}, *filters)
+}
+
+func TestHierarchySubtree(t *testing.T) {
+	mu, filters, handler := costCenterTree(t)
+	b := newMockBridge(t, newMockODataServiceWithMetadata(t, hierarchyMetadata, handler), nil)
+
+	result, err := b.CallTool(context.Background(), "CostCenterNodes_filter", map[string]interface{}{"subtree": "R"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"R", "A", "B", "A1", "A2", "B1"}, nodeIDs(t, result), "parents come before their children")
+
+	mu.Lock()
+	assert.Equal(t, []string{
+		"NodeID eq 'R'",
+		"ParentNodeID eq 'R'",
+		"ParentNodeID eq 'A' or ParentNodeID eq 'B'",
+	}, *filters, "one request per level; leaves are not queried for children")
+	mu.Unlock()
+
+	result, err = b.CallTool(context.Background(), "CostCenterNodes_filter", map[string]interface{}{"subtree": "R", "$top": float64(3)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"R", "A", "B"}, nodeIDs(t, result))
+	assert.True(t, strings.Contains(result.(string), `"truncated":true`))
+
+	_, err = b.CallTool(context.Background(), "CostCenterNodes_filter", map[string]interface{}{"subtree": "R", "parent": "A"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+}
+
+func TestHierarchyParametersOfCompactTools(t *testing.T) {
+	_, _, handler := costCenterTree(t)
+	b := newMockBridge(t, newMockODataServiceWithMetadata(t, hierarchyMetadata, handler), func(cfg *config.Config) {
+		cfg.CompactTools = true
+	})
+
+	for _, tool := range listTools(t, b) {
+		if tool.Name == "query_entity" {
+			properties := tool.InputSchema["properties"].(map[string]interface{})
+			for _, param := range []string{"node", "parent", "level", "subtree"} {
+				assert.Contains(t, properties, param)
+			}
+			assert.Contains(t, tool.Description, "CostCenterNodes form hierarchies")
+		}
+	}
+
+	result, err := b.CallTool(context.Background(), "query_entity", map[string]interface{}{"entity_set": "CostCenterNodes", "parent": "A"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1", "A2"}, nodeIDs(t, result))
+
+	result, err = b.CallTool(context.Background(), "query_entity", map[string]interface{}{"entity_set": "CostCenterNodes", "subtree": "B"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"B", "B1"}, nodeIDs(t, result))
+
+	// Services without hierarchies do not get the parameters, and other entity sets reject them
+	flat := newMockBridge(t, newMockODataService(t, nil), func(cfg *config.Config) {
+		cfg.CompactTools = true
+	})
+	for _, tool := range listTools(t, flat) {
+		if tool.Name == "query_entity" {
+			assert.NotContains(t, tool.InputSchema["properties"], "subtree")
+		}
+	}
+	_, err = flat.CallTool(context.Background(), "query_entity", map[string]interface{}{"entity_set": "Products", "parent": "A"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+}
@@ -0,0 +1,267 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postMCP sends one JSON-RPC message to the HTTP transport
+func postMCP(t *testing.T, url, sessionID, authorization string, message map[string]interface{}) (*http.Response, map[string]interface{}) {
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(constants.MCPSessionIDHeader, sessionID)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	}
+	return resp, result
+}
+
+// initializeHTTPSession opens an MCP session and returns its id
+func initializeHTTPSession(t *testing.T, url, authorization string) string {
+	resp, result := postMCP(t, url, "", authorization, map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "method": "initialize",
+		"params": map[string]interface{}{"protocolVersion": constants.MCPProtocolVersion, "capabilities": map[string]interface{}{}},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotNil(t, result["result"])
+	sessionID := resp.Header.Get(constants.MCPSessionIDHeader)
+	require.NotEmpty(t, sessionID)
+
+	resp, _ = postMCP(t, url, sessionID, authorization, map[string]interface{}{
+		"jsonrpc": "2.0", "method": "notifications/initialized",
+	})
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	return sessionID
+}
+
+func TestHTTPTransportIsolatesSessions(t *testing.T) {
+	type backendCall struct{ authorization, token, cookie string }
+	var mu sync.Mutex
+	var posts []backendCall
+
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if r.Header.Get(constants.CSRFTokenHeader) == "Fetch" {
+			w.Header().Set(constants.CSRFTokenHeader, "token-"+auth)
+			http.SetCookie(w, &http.Cookie{Name: "SAP_SESSIONID", Value: strings.ReplaceAll(auth, " ", "_")})
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"d":{"results":[]}}`))
+			return
+		}
+		if r.Method == http.MethodPost {
+			cookie, _ := r.Cookie("SAP_SESSIONID")
+			call := backendCall{authorization: auth, token: r.Header.Get(constants.CSRFTokenHeader)}
+			if cookie != nil {
+				call.cookie = cookie.Value
+			}
+			mu.Lock()
+			posts = append(posts, call)
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"d":{"OrderID":"1"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	})
+	b := newMockBridge(t, service, nil)
+	httpServer := transport.NewHTTPServer(b, &config.Config{PassThroughAuth: true})
+	ts := httptest.NewServer(httpServer.Handler())
+	defer ts.Close()
+	url := ts.URL + constants.MCPEndpoint
+
+	alice := "Basic YWxpY2U6c2VjcmV0"
+	bob := "Basic Ym9iOnNlY3JldA=="
+	aliceSession := initializeHTTPSession(t, url, alice)
+	bobSession := initializeHTTPSession(t, url, bob)
+	assert.NotEqual(t, aliceSession, bobSession)
+	assert.Equal(t, 2, httpServer.SessionCount())
+
+	for i, user := range []struct{ session, auth string }{{aliceSession, alice}, {bobSession, bob}} {
+		resp, result := postMCP(t, url, user.session, user.auth, map[string]interface{}{
+			"jsonrpc": "2.0", "id": i + 10, "method": "tools/call",
+			"params": map[string]interface{}{
+				"name":      "Orders_create",
+				"arguments": map[string]interface{}{"OrderID": "1", "Customer": "ACME"},
+			},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Nil(t, result["error"], "%v", result["error"])
+		assert.EqualValues(t, i+10, result["id"])
+	}
+
+	// Each backend write carries the session's own credentials, CSRF token and cookie
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, posts, 2)
+	for _, call := range posts {
+		assert.Equal(t, "token-"+call.authorization, call.token)
+		assert.Equal(t, strings.ReplaceAll(call.authorization, " ", "_"), call.cookie)
+	}
+	assert.ElementsMatch(t, []string{alice, bob}, []string{posts[0].authorization, posts[1].authorization})
+}
+
+func TestHTTPTransportSessionLifecycle(t *testing.T) {
+	service := newMockODataService(t, nil)
+	b := newMockBridge(t, service, nil)
+	httpServer := transport.NewHTTPServer(b, &config.Config{})
+	ts := httptest.NewServer(httpServer.Handler())
+	defer ts.Close()
+	url := ts.URL + constants.MCPEndpoint
+
+	ping := map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "ping"}
+
+	// Requests other than initialize need a session
+	resp, _ := postMCP(t, url, "", "", ping)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = postMCP(t, url, "unknown", "", ping)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	sessionID := initializeHTTPSession(t, url, "Bearer one")
+
+	// A session cannot be used with different credentials
+	resp, _ = postMCP(t, url, sessionID, "Bearer two", ping)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, result := postMCP(t, url, sessionID, "Bearer one", ping)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, result["result"])
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	require.NoError(t, err)
+	req.Header.Set(constants.MCPSessionIDHeader, sessionID)
+	req.Header.Set("Authorization", "Bearer one")
+	deleteResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	deleteResp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, deleteResp.StatusCode)
+	assert.Equal(t, 0, httpServer.SessionCount())
+
+	resp, _ = postMCP(t, url, sessionID, "Bearer one", ping)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHTTPTransportRequiresAuthorizationForPassThrough(t *testing.T) {
+	service := newMockODataService(t, nil)
+	b := newMockBridge(t, service, nil)
+	ts := httptest.NewServer(transport.NewHTTPServer(b, &config.Config{PassThroughAuth: true}).Handler())
+	defer ts.Close()
+
+	resp, _ := postMCP(t, ts.URL+constants.MCPEndpoint, "", "", map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{},
+	})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestHTTPTransportMaxSessions(t *testing.T) {
+	service := newMockODataService(t, nil)
+	b := newMockBridge(t, service, nil)
+	httpServer := transport.NewHTTPServer(b, &config.Config{MaxSessions: 1})
+	ts := httptest.NewServer(httpServer.Handler())
+	defer ts.Close()
+	url := ts.URL + constants.MCPEndpoint
+	ping := map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "ping"}
+
+	// A new session closes the idle one
+	first := initializeHTTPSession(t, url, "")
+	second := initializeHTTPSession(t, url, "")
+	assert.Equal(t, 1, httpServer.SessionCount())
+	resp, _ := postMCP(t, url, first, "", ping)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// A session with an open stream is busy and is not closed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set(constants.MCPSessionIDHeader, second)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	resp, _ = postMCP(t, url, "", "", map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{},
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	resp, _ = postMCP(t, url, second, "", ping)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSessionsShareGeneratedTools(t *testing.T) {
+	service := newMockODataService(t, nil)
+	b := newMockBridge(t, service, nil)
+
+	first, err := b.NewSession("")
+	require.NoError(t, err)
+	second, err := b.NewSession("")
+	require.NoError(t, err)
+
+	firstTools, secondTools := listTools(t, first), listTools(t, second)
+	require.NotEmpty(t, firstTools)
+	require.Len(t, secondTools, len(firstTools))
+	for i := range firstTools {
+		assert.Same(t, firstTools[i], secondTools[i], "sessions share the tool definitions")
+	}
+
+	result, err := second.CallTool(context.Background(), "odata_service_info", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, result.(string), "Products")
+}
+
+func TestHTTPTransportMessageSizeLimits(t *testing.T) {
+	size := constants.DefaultMaxMessageSize + 1024
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"d": map[string]interface{}{"results": []interface{}{
+			map[string]interface{}{"ProductID": 1, "Name": strings.Repeat("x", size)},
+		}}})
+	})
+	b := newMockBridge(t, service, nil)
+	ts := httptest.NewServer(transport.NewHTTPServer(b, &config.Config{MaxMessageSize: 4096}).Handler())
+	defer ts.Close()
+	url := ts.URL + constants.MCPEndpoint
+	sessionID := initializeHTTPSession(t, url, "")
+
+	// Request bodies above --max-message-size are rejected, not cut off
+	resp, result := postMCP(t, url, sessionID, "", map[string]interface{}{
+		"jsonrpc": "2.0", "id": 2, "method": "ping", "params": map[string]interface{}{"padding": strings.Repeat("x", 8192)},
+	})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	require.NotNil(t, result["error"])
+	assert.Equal(t, float64(-32600), result["error"].(map[string]interface{})["code"])
+
+	// Responses of the session's server are not limited by the transport
+	resp, result = postMCP(t, url, sessionID, "", map[string]interface{}{
+		"jsonrpc": "2.0", "id": 3, "method": "tools/call",
+		"params": map[string]interface{}{"name": "Products_filter", "arguments": map[string]interface{}{}},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, result["result"], "a response above 10MB should be delivered")
+}
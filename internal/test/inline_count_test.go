@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/odata-mcp/go/internal/config"
+)
+
+// queryRecorder records the query strings of entity set requests
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func (q *queryRecorder) handle(w http.ResponseWriter, r *http.Request) {
+	q.mu.Lock()
+	q.queries = append(q.queries, r.URL.Query())
+	q.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Query().Get("$count") == "true" {
+		w.Write([]byte(`{"@odata.count":3,"value":[]}`))
+		return
+	}
+	w.Write([]byte(`{"d":{"__count":"3","results":[]}}`))
+}
+
+func (q *queryRecorder) last() url.Values {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queries[len(q.queries)-1]
+}
+
+func TestInlineCountOnlyWithPaginationHints(t *testing.T) {
+	recorder := &queryRecorder{}
+	server := newMockODataService(t, recorder.handle)
+
+	b := newMockBridge(t, server, nil)
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.NotContains(t, recorder.last(), "$inlinecount", "queries do not count by default")
+
+	b = newMockBridge(t, server, func(cfg *config.Config) { cfg.PaginationHints = true })
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "allpages", recorder.last().Get("$inlinecount"))
+
+	// Count tools always count
+	b = newMockBridge(t, server, nil)
+	_, err = b.CallTool(context.Background(), "Products_count", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "allpages", recorder.last().Get("$inlinecount"))
+}
+
+func TestV4CountUsesCountParameter(t *testing.T) {
+	recorder := &queryRecorder{}
+	b := newMockBridge(t, newMockODataServiceWithMetadata(t, capabilitiesV4Metadata, recorder.handle), nil)
+
+	result, err := b.CallTool(context.Background(), "AuditLog_count", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"count": 3}`, result.(string))
+	assert.NotContains(t, recorder.last(), "$inlinecount", "OData v4 services reject $inlinecount")
+	assert.Equal(t, "true", recorder.last().Get("$count"))
+}
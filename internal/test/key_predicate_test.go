@@ -0,0 +1,214 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const typedKeysV2Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="KEYS" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Document"><Key><PropertyRef Name="DocumentID"/></Key><Property Name="DocumentID" Type="Edm.Guid" Nullable="false"/></EntityType>
+      <EntityType Name="Posting"><Key><PropertyRef Name="PostingDate"/></Key><Property Name="PostingDate" Type="Edm.DateTime" Nullable="false"/></EntityType>
+      <EntityType Name="Event"><Key><PropertyRef Name="At"/></Key><Property Name="At" Type="Edm.DateTimeOffset" Nullable="false"/></EntityType>
+      <EntityType Name="LedgerEntry"><Key><PropertyRef Name="EntryNo"/></Key><Property Name="EntryNo" Type="Edm.Int64" Nullable="false"/></EntityType>
+      <EntityType Name="Price"><Key><PropertyRef Name="Amount"/></Key><Property Name="Amount" Type="Edm.Decimal" Nullable="false"/></EntityType>
+      <EntityType Name="Order"><Key><PropertyRef Name="OrderID"/></Key><Property Name="OrderID" Type="Edm.Int32" Nullable="false"/></EntityType>
+      <EntityType Name="Shift"><Key><PropertyRef Name="Start"/></Key><Property Name="Start" Type="Edm.Time" Nullable="false"/></EntityType>
+      <EntityType Name="Customer"><Key><PropertyRef Name="Name"/></Key><Property Name="Name" Type="Edm.String" Nullable="false"/></EntityType>
+      <EntityContainer Name="KEYS_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Customers" EntityType="KEYS.Customer"/>
+        <FunctionImport Name="FindCustomer" ReturnType="KEYS.Customer" EntitySet="Customers" m:HttpMethod="GET">
+          <Parameter Name="Name" Type="Edm.String" Mode="In"/>
+        </FunctionImport>
+        <FunctionImport Name="ReleasePostings" ReturnType="Collection(KEYS.Posting)" EntitySet="Postings" m:HttpMethod="GET">
+          <Parameter Name="PostingDate" Type="Edm.DateTime" Mode="In"/>
+          <Parameter Name="DocumentID" Type="Edm.Guid" Mode="In"/>
+          <Parameter Name="Amount" Type="Edm.Decimal" Mode="In"/>
+          <Parameter Name="Count" Type="Edm.Int32" Mode="In"/>
+          <Parameter Name="Note" Type="Edm.String" Mode="In"/>
+        </FunctionImport>
+        <EntitySet Name="Documents" EntityType="KEYS.Document"/>
+        <EntitySet Name="Postings" EntityType="KEYS.Posting"/>
+        <EntitySet Name="Events" EntityType="KEYS.Event"/>
+        <EntitySet Name="Ledger" EntityType="KEYS.LedgerEntry"/>
+        <EntitySet Name="Prices" EntityType="KEYS.Price"/>
+        <EntitySet Name="Orders" EntityType="KEYS.Order"/>
+        <EntitySet Name="Shifts" EntityType="KEYS.Shift"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const typedKeysV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="KEYS" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="Document"><Key><PropertyRef Name="DocumentID"/></Key><Property Name="DocumentID" Type="Edm.Guid" Nullable="false"/></EntityType>
+      <EntityType Name="Event"><Key><PropertyRef Name="At"/></Key><Property Name="At" Type="Edm.DateTimeOffset" Nullable="false"/></EntityType>
+      <EntityType Name="LedgerEntry"><Key><PropertyRef Name="EntryNo"/></Key><Property Name="EntryNo" Type="Edm.Int64" Nullable="false"/></EntityType>
+      <EntityContainer Name="KEYS_Entities">
+        <EntitySet Name="Documents" EntityType="KEYS.Document"/>
+        <EntitySet Name="Events" EntityType="KEYS.Event"/>
+        <EntitySet Name="Ledger" EntityType="KEYS.LedgerEntry"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestTypedKeyPredicates(t *testing.T) {
+	testCases := []struct {
+		name         string
+		metadata     string
+		entitySet    string
+		key          map[string]interface{}
+		expectedPath string
+	}{
+		{"v2 guid", typedKeysV2Metadata, "Documents", map[string]interface{}{"DocumentID": "005056a2-0f8e-1eda-9c8b-d1a0c3a8e0b2"}, "/Documents(guid'005056a2-0f8e-1eda-9c8b-d1a0c3a8e0b2')"},
+		{"v2 base64 guid", typedKeysV2Metadata, "Documents", map[string]interface{}{"DocumentID": "AFBWog+OHtqci9GgwwqOCw=="}, "/Documents(guid'005056a2-0f8e-1eda-9c8b-d1a0c30a8e0b')"},
+		{"v2 datetime", typedKeysV2Metadata, "Postings", map[string]interface{}{"PostingDate": "2024-12-25"}, "/Postings(datetime'2024-12-25T00:00:00')"},
+		{"v2 datetime from legacy date", typedKeysV2Metadata, "Postings", map[string]interface{}{"PostingDate": "/Date(1709287200000)/"}, "/Postings(datetime'2024-03-01T10:00:00')"},
+		{"v2 datetimeoffset", typedKeysV2Metadata, "Events", map[string]interface{}{"At": "2024-03-01T10:00:00+02:00"}, "/Events(datetimeoffset'2024-03-01T10:00:00+02:00')"},
+		{"v2 int64", typedKeysV2Metadata, "Ledger", map[string]interface{}{"EntryNo": float64(9007199254)}, "/Ledger(9007199254L)"},
+		{"v2 int64 from string", typedKeysV2Metadata, "Ledger", map[string]interface{}{"EntryNo": "123456789012"}, "/Ledger(123456789012L)"},
+		{"v2 decimal", typedKeysV2Metadata, "Prices", map[string]interface{}{"Amount": "10.50"}, "/Prices(10.50M)"},
+		{"v2 int32 from string", typedKeysV2Metadata, "Orders", map[string]interface{}{"OrderID": "42"}, "/Orders(42)"},
+		{"v2 large int32 without exponent", typedKeysV2Metadata, "Orders", map[string]interface{}{"OrderID": float64(1234567)}, "/Orders(1234567)"},
+		{"v2 int64 from exponent string", typedKeysV2Metadata, "Ledger", map[string]interface{}{"EntryNo": "1e5"}, "/Ledger('1e5')"},
+		{"v2 int64 from NaN string", typedKeysV2Metadata, "Ledger", map[string]interface{}{"EntryNo": "NaN"}, "/Ledger('NaN')"},
+		{"v2 decimal from Inf string", typedKeysV2Metadata, "Prices", map[string]interface{}{"Amount": "Inf"}, "/Prices('Inf')"},
+		{"v2 int32 from decimal string", typedKeysV2Metadata, "Orders", map[string]interface{}{"OrderID": "4.2"}, "/Orders('4.2')"},
+		{"v2 time", typedKeysV2Metadata, "Shifts", map[string]interface{}{"Start": "08:30:00"}, "/Shifts(time'PT08H30M00S')"},
+		{"v4 guid", typedKeysV4Metadata, "Documents", map[string]interface{}{"DocumentID": "005056a2-0f8e-1eda-9c8b-d1a0c3a8e0b2"}, "/Documents(005056a2-0f8e-1eda-9c8b-d1a0c3a8e0b2)"},
+		{"v4 datetimeoffset", typedKeysV4Metadata, "Events", map[string]interface{}{"At": "2024-03-01T10:00:00Z"}, "/Events(2024-03-01T10:00:00Z)"},
+		{"v4 int64", typedKeysV4Metadata, "Ledger", map[string]interface{}{"EntryNo": "123456789012"}, "/Ledger(123456789012)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var capturedPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/$metadata" {
+					w.Header().Set("Content-Type", "application/xml")
+					w.Write([]byte(tc.metadata))
+					return
+				}
+				capturedPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"d":{}}`))
+			}))
+			defer server.Close()
+
+			c := client.NewODataClient(server.URL, false)
+			_, err := c.GetMetadata(context.Background())
+			require.NoError(t, err)
+
+			_, err = c.GetEntity(context.Background(), tc.entitySet, tc.key, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, capturedPath)
+		})
+	}
+}
+
+func TestLiteralEscaping(t *testing.T) {
+	var escapedPath, rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/$metadata" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(typedKeysV2Metadata))
+			return
+		}
+		escapedPath = r.URL.EscapedPath()
+		rawQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{}}`))
+	}))
+	defer server.Close()
+
+	c := client.NewODataClient(server.URL, false)
+	_, err := c.GetMetadata(context.Background())
+	require.NoError(t, err)
+
+	_, err = c.GetEntity(context.Background(), "Customers", map[string]interface{}{"Name": "O'Brien"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/Customers('O''Brien')", escapedPath)
+
+	// Characters that would end the path segment are percent-encoded
+	_, err = c.GetEntity(context.Background(), "Customers", map[string]interface{}{"Name": "A/B #1? 100%"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/Customers('A%2FB%20%231%3F%20100%25')", escapedPath)
+
+	_, err = c.CallFunction(context.Background(), "FindCustomer", map[string]interface{}{"Name": "O'Brien"}, "GET")
+	require.NoError(t, err)
+	assert.Equal(t, "Name='O%27%27Brien'", rawQuery)
+}
+
+func TestCompositeKeyOrder(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/$metadata" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(orderItemMetadata))
+			return
+		}
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{}}`))
+	}))
+	defer server.Close()
+
+	c := client.NewODataClient(server.URL, false)
+	_, err := c.GetMetadata(context.Background())
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		_, err = c.GetEntity(context.Background(), "OrderItemSet", map[string]interface{}{"ItemID": "10", "OrderID": 7}, nil)
+		require.NoError(t, err)
+	}
+	// Entity sets without metadata fall back to alphabetical order
+	_, err = c.GetEntity(context.Background(), "Unknown", map[string]interface{}{"b": 2, "a": 1, "c": 3}, nil)
+	require.NoError(t, err)
+
+	for _, path := range paths[:20] {
+		assert.Equal(t, "/OrderItemSet(OrderID=7,ItemID='10')", path)
+	}
+	assert.Equal(t, "/Unknown(a=1,b=2,c=3)", paths[20])
+}
+
+func TestTypedFunctionParameters(t *testing.T) {
+	var rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/$metadata" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(typedKeysV2Metadata))
+			return
+		}
+		rawQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	defer server.Close()
+
+	c := client.NewODataClient(server.URL, false)
+	_, err := c.GetMetadata(context.Background())
+	require.NoError(t, err)
+
+	_, err = c.CallFunction(context.Background(), "ReleasePostings", map[string]interface{}{
+		"Note":        "Q4",
+		"Count":       "3",
+		"Amount":      12.5,
+		"DocumentID":  "005056a2-0f8e-1eda-9c8b-d1a0c3a8e0b2",
+		"PostingDate": "2024-12-25",
+	}, "GET")
+	require.NoError(t, err)
+	assert.Equal(t, "PostingDate=datetime'2024-12-25T00%3A00%3A00'"+
+		"&DocumentID=guid'005056a2-0f8e-1eda-9c8b-d1a0c3a8e0b2'"+
+		"&Amount=12.5M&Count=3&Note='Q4'", rawQuery)
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const leanTestEntity = `{"__metadata":{"uri":"Products(1)","type":"MOCK_SRV.Product"},` +
+	`"ProductID":1,"Name":"Widget","Price":null,"ReleaseDate":"",` +
+	`"ToSupplier":{"__deferred":{"uri":"Products(1)/ToSupplier"}},` +
+	`"ToCategory":{"__metadata":{"type":"MOCK_SRV.Category"},"CategoryID":7,"Description":null}}`
+
+func TestLeanResponses(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/sap/opu/odata/sap/MOCK_SRV/Products(1)" {
+			w.Write([]byte(`{"d":` + leanTestEntity + `}`))
+			return
+		}
+		w.Write([]byte(`{"d":{"results":[` + leanTestEntity + `]}}`))
+	})
+
+	decode := func(t *testing.T, result interface{}) map[string]interface{} {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+		return response
+	}
+
+	t.Run("lean by default", func(t *testing.T) {
+		b := newMockBridge(t, service, nil)
+		lean := map[string]interface{}{
+			"ProductID":  float64(1),
+			"Name":       "Widget",
+			"ToCategory": map[string]interface{}{"CategoryID": float64(7)},
+		}
+
+		result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{lean}, decode(t, result)["value"])
+
+		result, err = b.CallTool(context.Background(), "Products_search", map[string]interface{}{"search": "Widget"})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{lean}, decode(t, result)["value"])
+
+		result, err = b.CallTool(context.Background(), "Products_get", map[string]interface{}{"ProductID": 1})
+		require.NoError(t, err)
+		assert.Equal(t, lean, decode(t, result)["value"])
+	})
+
+	t.Run("response metadata keeps everything", func(t *testing.T) {
+		b := newMockBridge(t, service, func(cfg *config.Config) {
+			cfg.ResponseMetadata = true
+		})
+
+		result, err := b.CallTool(context.Background(), "Products_get", map[string]interface{}{"ProductID": 1})
+		require.NoError(t, err)
+		entity := decode(t, result)["value"].(map[string]interface{})
+		assert.Contains(t, entity, "__metadata")
+		assert.Contains(t, entity, "ToSupplier")
+		assert.Contains(t, entity, "Price")
+		assert.Equal(t, "", entity["ReleaseDate"])
+	})
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyDatesUseMetadataTypes(t *testing.T) {
+	payloads := make(map[string]map[string]interface{})
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			var payload map[string]interface{}
+			body, _ := io.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(body, &payload))
+			payloads[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]] = payload
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"d":{"ProductID":1,"ReleaseDate":"/Date(1709287200000)/"}}`))
+		case strings.Contains(r.URL.Path, "Products("):
+			w.Write([]byte(`{"d":{"ProductID":1,"ReleaseDate":"/Date(1709287200000)/"}}`))
+		default:
+			w.Write([]byte(`{"d":{"results":[]}}`))
+		}
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.LegacyDates = true
+	})
+
+	_, err := b.CallTool(context.Background(), "Products_create", map[string]interface{}{
+		"ProductID": 1, "Name": "Widget", "ReleaseDate": "2024-03-01T10:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/Date(1709287200000)/", payloads["Products"]["ReleaseDate"])
+
+	// Customer is an Edm.String: a date-like value must reach the service unchanged
+	_, err = b.CallTool(context.Background(), "Orders_create", map[string]interface{}{
+		"OrderID": "1", "Customer": "2024-01-01",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01", payloads["Orders"]["Customer"])
+
+	result, err := b.CallTool(context.Background(), "Products_get", map[string]interface{}{"ProductID": 1})
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	entity := response["value"].(map[string]interface{})
+	assert.Equal(t, "2024-03-01T10:00:00Z", entity["ReleaseDate"])
+}
+
+func TestConvertODataLegacyToISOKeepsOffset(t *testing.T) {
+	assert.Equal(t, "2024-03-01T10:00:00Z", utils.ConvertODataLegacyToISO("/Date(1709287200000)/"))
+	assert.Equal(t, "2024-03-01T12:00:00+02:00", utils.ConvertODataLegacyToISO("/Date(1709287200000+0200)/"))
+	assert.Equal(t, "2024-03-01T08:30:00-01:30", utils.ConvertODataLegacyToISO("/Date(1709287200000-0130)/"))
+}
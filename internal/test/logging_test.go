@@ -0,0 +1,207 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"warn":    slog.LevelWarn,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+	} {
+		level, err := logging.ParseLevel(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, level, name)
+	}
+
+	_, err := logging.ParseLevel("verbose")
+	assert.ErrorContains(t, err, "debug, info, warn, error")
+}
+
+func TestLogFileReceivesRecordsAtLevel(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		logging.SetLevel(logging.DefaultLevel)
+	})
+
+	path := filepath.Join(t.TempDir(), "odata-mcp.log")
+	closeLog, err := logging.Configure("info", logging.FormatText, path)
+	require.NoError(t, err)
+
+	slog.Debug("token fetch request")
+	slog.Info("configuration reloaded", "tools_changed", true)
+	slog.Warn("option changed", "option", "transport")
+	require.NoError(t, closeLog())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	log := string(data)
+	assert.NotContains(t, log, "token fetch request", "debug records are below --log-level info")
+	assert.Contains(t, log, `level=INFO msg="configuration reloaded" tools_changed=true`)
+	assert.Contains(t, log, `level=WARN msg="option changed" option=transport`)
+
+	require.NoError(t, logging.SetLevel("error"))
+	assert.False(t, slog.Default().Enabled(context.Background(), slog.LevelWarn), "SetLevel applies to the configured logger")
+}
+
+func TestConfigureRejectsInvalidLogLevel(t *testing.T) {
+	_, err := logging.Configure("loud", logging.FormatText, "")
+	assert.Error(t, err)
+
+	_, err = logging.Configure("info", "xml", "")
+	assert.ErrorContains(t, err, "text, json")
+}
+
+func TestMCPLevelsMapToLogLevels(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, logging.FromMCP("debug"))
+	assert.Equal(t, slog.LevelInfo, logging.FromMCP("notice"))
+	assert.Equal(t, slog.LevelWarn, logging.FromMCP("warning"))
+	assert.Equal(t, slog.LevelError, logging.FromMCP("critical"))
+}
+
+func TestJSONLogRecordsRequestTimingAndStatus(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		logging.SetLevel(logging.DefaultLevel)
+	})
+
+	path := filepath.Join(t.TempDir(), "odata-mcp.log")
+	closeLog, err := logging.Configure("info", logging.FormatJSON, path)
+	require.NoError(t, err)
+
+	b := newMockBridge(t, newMockODataService(t, productListHandler(2)), nil)
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	require.NoError(t, closeLog())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var requests []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record), "every line is a JSON object: %s", line)
+		if record["msg"] == "OData request" {
+			requests = append(requests, record)
+		}
+	}
+
+	require.Len(t, requests, 2, "$metadata and the query")
+	query := requests[1]
+	assert.Equal(t, "INFO", query["level"])
+	assert.Equal(t, "GET", query["method"])
+	assert.Contains(t, query["url"], "/Products")
+	assert.Equal(t, float64(200), query["status"])
+	assert.Contains(t, query, "duration_ms")
+	assert.Contains(t, query, "time")
+}
+
+func TestSlowQueriesAreLoggedWithOptionsAndRows(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		logging.SetLevel(logging.DefaultLevel)
+	})
+
+	path := filepath.Join(t.TempDir(), "odata-mcp.log")
+	closeLog, err := logging.Configure("warn", logging.FormatJSON, path)
+	require.NoError(t, err)
+
+	list := productListHandler(3)
+	b := newMockBridge(t, newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$filter") != "" {
+			time.Sleep(60 * time.Millisecond)
+		}
+		list(w, r)
+	}), func(cfg *config.Config) {
+		cfg.SlowQueryThreshold = 50 * time.Millisecond
+	})
+	ctx := context.Background()
+	_, err = b.CallTool(ctx, "Products_filter", map[string]interface{}{"$top": float64(3)})
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Products_filter", map[string]interface{}{"$filter": "Price gt 10", "$select": "Name"})
+	require.NoError(t, err)
+	require.NoError(t, closeLog())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var slow []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		if record["msg"] == "Slow OData query" {
+			slow = append(slow, record)
+		}
+	}
+
+	require.Len(t, slow, 1, "only the query over the threshold is logged")
+	assert.Equal(t, "WARN", slow[0]["level"])
+	assert.Equal(t, "Products", slow[0]["entity_set"])
+	assert.Equal(t, "$filter=Price gt 10&$select=Name", slow[0]["options"])
+	assert.Equal(t, float64(3), slow[0]["rows"])
+	assert.Equal(t, float64(50), slow[0]["threshold_ms"])
+	assert.NotEmpty(t, slow[0]["request_id"])
+}
+
+func TestVerboseScopesLimitDebugRecordsToSubsystems(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		logging.SetLevel(logging.DefaultLevel)
+		logging.SetScopes("")
+	})
+
+	path := filepath.Join(t.TempDir(), "odata-mcp.log")
+	closeLog, err := logging.Configure("debug", logging.FormatJSON, path)
+	require.NoError(t, err)
+	require.NoError(t, logging.SetScopes("client"))
+
+	b := newMockBridge(t, newMockODataService(t, productListHandler(2)), nil)
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	logging.Subsystem(logging.SubsystemTools).Info("Configuration changed")
+	require.NoError(t, closeLog())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	debug := make(map[string]int)
+	var infoSubsystems []interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		if record["level"] == "DEBUG" {
+			debug[record["subsystem"].(string)]++
+		} else {
+			infoSubsystems = append(infoSubsystems, record["subsystem"])
+		}
+	}
+	assert.NotZero(t, debug[logging.SubsystemClient], "client debug records are logged")
+	assert.Len(t, debug, 1, "debug records of other subsystems are dropped: %v", debug)
+	assert.Contains(t, infoSubsystems, logging.SubsystemTools, "records above debug are logged for all subsystems")
+}
+
+func TestParseScopesRejectsUnknownSubsystems(t *testing.T) {
+	scopes, err := logging.ParseScopes(" client, METADATA ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"client", "metadata"}, scopes)
+
+	_, err = logging.ParseScopes("client,http")
+	assert.ErrorContains(t, err, "invalid subsystem 'http'")
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/config"
+)
+
+// validatingMetadataService serves $metadata with an ETag and answers If-None-Match
+type validatingMetadataService struct {
+	mu          sync.Mutex
+	document    string
+	etag        string
+	full        int
+	notModified int
+}
+
+func (s *validatingMetadataService) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasSuffix(r.URL.Path, "/$metadata") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.full++
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Write([]byte(s.document))
+}
+
+func (s *validatingMetadataService) update(document, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.document, s.etag = document, etag
+}
+
+func TestMetadataCacheRevalidatesWithETag(t *testing.T) {
+	service := &validatingMetadataService{document: mockServiceMetadata, etag: `"v1"`}
+	server := httptest.NewServer(http.HandlerFunc(service.handle))
+	defer server.Close()
+	dir := t.TempDir()
+
+	start := func() map[string]bool {
+		b, err := bridge.NewODataMCPBridge(&config.Config{
+			ServiceURL:       server.URL + "/sap/opu/odata/sap/MOCK_SRV/",
+			NoPostfix:        true,
+			MetadataCacheDir: dir,
+		})
+		require.NoError(t, err)
+		return toolOperations(t, b)
+	}
+
+	first := start()
+	assert.True(t, first["Products:filter"])
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the parsed metadata is cached")
+
+	// An unchanged document is answered with 304 and the cached parse is used
+	assert.Equal(t, first, start())
+	assert.Equal(t, 1, service.full)
+	assert.Equal(t, 1, service.notModified)
+
+	// A changed document is parsed and cached again
+	changed := strings.Replace(mockServiceMetadata, `EntitySet Name="Orders"`, `EntitySet Name="SalesOrders"`, 1)
+	require.NotEqual(t, mockServiceMetadata, changed)
+	service.update(changed, `"v2"`)
+	ops := start()
+	assert.True(t, ops["SalesOrders:filter"])
+	assert.False(t, ops["Orders:filter"])
+	assert.Equal(t, 2, service.full)
+
+	assert.True(t, start()["SalesOrders:filter"])
+	assert.Equal(t, 2, service.notModified)
+}
+
+func TestMetadataCacheWithoutValidatorsSkipsUnchangedDocuments(t *testing.T) {
+	service := &validatingMetadataService{document: mockServiceMetadata}
+	server := httptest.NewServer(http.HandlerFunc(service.handle))
+	defer server.Close()
+	dir := t.TempDir()
+
+	start := func() map[string]bool {
+		b, err := bridge.NewODataMCPBridge(&config.Config{
+			ServiceURL:       server.URL + "/sap/opu/odata/sap/MOCK_SRV/",
+			NoPostfix:        true,
+			MetadataCacheDir: dir,
+		})
+		require.NoError(t, err)
+		return toolOperations(t, b)
+	}
+	assert.True(t, start()["Products:filter"])
+
+	// Mark the cached parse to see that an identical document is not parsed again
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	path := filepath.Join(dir, entries[0].Name())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(strings.ReplaceAll(string(data), `"Orders"`, `"CachedOrders"`)), 0o600))
+
+	ops := start()
+	assert.True(t, ops["CachedOrders:filter"], "the cached parse is used")
+	assert.Equal(t, 2, service.full, "without an ETag, the document is fetched again")
+}
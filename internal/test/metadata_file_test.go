@@ -0,0 +1,133 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMetadataFileService serves entity queries and counts the $metadata requests, which
+// a bridge with --metadata-file must not send
+func newMetadataFileService(t *testing.T, metadataRequests *int32, queries chan<- string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			atomic.AddInt32(metadataRequests, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		select {
+		case queries <- r.URL.RawQuery:
+		default:
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMetadataExportRoundTrip(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(largeSAPMetadata(3)), "https://example.com/")
+	require.NoError(t, err)
+
+	// Export, trim an entity set by hand and load the file again
+	data, err := json.MarshalIndent(meta, "", "  ")
+	require.NoError(t, err)
+	var exported map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &exported))
+	delete(exported["entity_sets"].(map[string]interface{}), "Set1")
+	data, err = json.Marshal(exported)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "parsed.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	loaded, err := metadata.LoadFile(path, "https://other.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://other.example.com/", loaded.ServiceRoot)
+	assert.True(t, loaded.IsSAP)
+	require.Contains(t, loaded.EntityTypes, "Type1", "trimming entity sets keeps their types")
+	assert.Equal(t, meta.EntityTypes["Type1"].Properties, loaded.EntityTypes["Type1"].Properties)
+	assert.Equal(t, meta.EntityTypes["Type1"].KeyProperties, loaded.EntityTypes["Type1"].KeyProperties)
+	assert.Contains(t, loaded.EntitySets, "Set0")
+	assert.NotContains(t, loaded.EntitySets, "Set1")
+	assert.NotNil(t, loaded.FunctionImports)
+}
+
+func TestBridgeWithMetadataFile(t *testing.T) {
+	var metadataRequests int32
+	queries := make(chan string, 1)
+	server := newMetadataFileService(t, &metadataRequests, queries)
+
+	meta, err := metadata.ParseMetadata([]byte(largeSAPMetadata(2)), "https://example.com/")
+	require.NoError(t, err)
+	delete(meta.EntitySets, "Set1")
+	data, err := json.Marshal(meta)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "parsed.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MetadataFile = path
+	})
+
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Set0:filter"])
+	assert.False(t, ops["Set1:filter"], "entity sets removed from the file get no tools")
+	assert.Zero(t, atomic.LoadInt32(&metadataRequests), "$metadata should not be fetched")
+
+	_, err = b.CallTool(context.Background(), "Set0_filter", map[string]interface{}{"$top": 1})
+	require.NoError(t, err)
+	assert.Contains(t, <-queries, "%24format=json", "the SAP dialect of the file should be applied to the client")
+
+	changed, err := b.RefreshMetadata(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Zero(t, atomic.LoadInt32(&metadataRequests), "refresh should re-read the file")
+}
+
+func TestBridgeWithMetadataXMLFile(t *testing.T) {
+	var metadataRequests int32
+	server := newMetadataFileService(t, &metadataRequests, nil)
+
+	path := filepath.Join(t.TempDir(), "metadata.xml")
+	require.NoError(t, os.WriteFile(path, []byte(largeSAPMetadata(2)), 0644))
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MetadataFile = path
+	})
+
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Set0:filter"])
+	assert.True(t, ops["Set1:filter"])
+	assert.Zero(t, atomic.LoadInt32(&metadataRequests))
+}
+
+func TestLoadMetadataFileErrors(t *testing.T) {
+	_, err := metadata.LoadFile(filepath.Join(t.TempDir(), "missing.json"), "")
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"entity_sets": [`), 0644))
+	_, err = metadata.LoadFile(path, "")
+	assert.Error(t, err)
+
+	var empty models.ODataMetadata
+	data, _ := json.Marshal(empty)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	loaded, err := metadata.LoadFile(path, "")
+	require.NoError(t, err)
+	assert.NotNil(t, loaded.EntitySets)
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshMetadataTool(t *testing.T) {
+	var mu sync.Mutex
+	metadata := mockServiceMetadata
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(metadata))
+	}))
+	t.Cleanup(server.Close)
+
+	// Without --metadata-refresh-interval the metadata is never refreshed
+	_, err := newMockBridge(t, server, nil).CallTool(context.Background(), "refresh_metadata", map[string]interface{}{})
+	assert.Error(t, err)
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MetadataRefreshInterval = time.Hour
+	})
+
+	result, err := b.CallTool(context.Background(), "refresh_metadata", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, result, `"changed":false`)
+
+	// The service is redeployed: Orders is gone and Customers was added
+	mu.Lock()
+	metadata = strings.Replace(mockServiceMetadata,
+		`<EntitySet Name="Orders" EntityType="MOCK_SRV.Order"/>`,
+		`<EntitySet Name="Customers" EntityType="MOCK_SRV.Order"/>`, 1)
+	mu.Unlock()
+
+	result, err = b.CallTool(context.Background(), "refresh_metadata", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Contains(t, result, `"changed":true`)
+
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Customers:filter"], "tools of new entity sets are registered")
+	assert.False(t, ops["Orders:filter"], "tools of removed entity sets are unregistered")
+	assert.True(t, ops["Products:filter"])
+
+	names := make(map[string]bool)
+	for _, tool := range listTools(t, b) {
+		names[tool.Name] = true
+	}
+	assert.True(t, names["Customers_filter"])
+	assert.False(t, names["Orders_filter"])
+	assert.True(t, names["refresh_metadata"])
+}
+
+func TestRefreshMetadataDuringToolCall(t *testing.T) {
+	var mu sync.Mutex
+	metadata := mockServiceMetadata
+	started, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/Orders") {
+			close(started)
+			<-release
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"d":{"results":[{"OrderID":"1"}]}}`))
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(metadata))
+	}))
+	t.Cleanup(server.Close)
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MetadataRefreshInterval = time.Hour
+	})
+	listTools(t, b)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.CallTool(context.Background(), "Orders_filter", map[string]interface{}{})
+		done <- err
+	}()
+	<-started
+
+	// Orders disappears while its query is under way
+	mu.Lock()
+	metadata = strings.Replace(mockServiceMetadata,
+		`<EntitySet Name="Orders" EntityType="MOCK_SRV.Order"/>`,
+		`<EntitySet Name="Customers" EntityType="MOCK_SRV.Order"/>`, 1)
+	mu.Unlock()
+	changed, err := b.RefreshMetadata(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	close(release)
+	assert.NoError(t, <-done, "the call finishes with the metadata it started with")
+	assert.True(t, toolOperations(t, b)["Customers:filter"])
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeSAPMetadata generates an S/4-style v2 document with many entity types and the
+// vocabulary annotation blocks SAP services append to their schemas
+func largeSAPMetadata(entityTypes int) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:Reference Uri="./Vocabularies(TechnicalName='%2FIWBEP%2FVOC_COMMON',Version='0001',SAP__Origin='')" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+    <edmx:Include Namespace="com.sap.vocabularies.Common.v1" Alias="Common"/>
+  </edmx:Reference>
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="API_LARGE_SRV" xml:lang="en" sap:schema-version="1" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+`)
+	for i := 0; i < entityTypes; i++ {
+		fmt.Fprintf(&sb, `      <EntityType Name="Type%d" sap:content-version="1">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false" MaxLength="10" sap:label="ID"/>
+        <Property Name="Amount" Type="Edm.Decimal" Precision="15" Scale="2" sap:unit="Currency"/>
+        <Property Name="Currency" Type="Edm.String" MaxLength="5" sap:semantics="currency-code"/>
+      </EntityType>
+`, i)
+	}
+	sb.WriteString(`      <EntityContainer Name="API_LARGE_SRV_Entities" m:IsDefaultEntityContainer="true">
+`)
+	for i := 0; i < entityTypes; i++ {
+		fmt.Fprintf(&sb, `        <EntitySet Name="Set%d" EntityType="API_LARGE_SRV.Type%d" sap:label="Set %d"/>
+`, i, i, i)
+	}
+	sb.WriteString(`      </EntityContainer>
+      <Annotations Target="API_LARGE_SRV.Type0/Amount" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+        <Annotation Term="Common.Label" String="Amount"/>
+      </Annotations>
+      <atom:link rel="self" href="./$metadata" xmlns:atom="http://www.w3.org/2005/Atom"/>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`)
+	return sb.String()
+}
+
+func TestParseMetadataReaderLargeSAPDocument(t *testing.T) {
+	doc := largeSAPMetadata(500)
+
+	meta, err := metadata.ParseMetadataReader(strings.NewReader(doc), "https://example.com/sap/opu/odata/sap/API_LARGE_SRV/")
+	require.NoError(t, err)
+
+	assert.True(t, meta.IsSAP, "SAP namespace on the root element should be detected")
+	assert.Equal(t, "1.0", meta.Version)
+	assert.Equal(t, "API_LARGE_SRV", meta.SchemaNamespace)
+	assert.Equal(t, "API_LARGE_SRV_Entities", meta.ContainerName)
+	assert.Len(t, meta.EntityTypes, 500)
+	assert.Len(t, meta.EntitySets, 500)
+
+	entityType := meta.EntityTypes["Type499"]
+	require.NotNil(t, entityType)
+	assert.Equal(t, []string{"ID"}, entityType.KeyProperties)
+	require.Len(t, entityType.Properties, 3)
+	assert.Equal(t, "Currency", entityType.Properties[1].Unit)
+	assert.Equal(t, "currency-code", entityType.Properties[2].Semantics)
+	assert.Equal(t, "Set 499", meta.EntitySets["Set499"].Label)
+}
+
+func TestParseMetadataReaderMatchesParseMetadata(t *testing.T) {
+	doc := largeSAPMetadata(3)
+
+	streamed, err := metadata.ParseMetadataReader(strings.NewReader(doc), "https://example.com/")
+	require.NoError(t, err)
+	buffered, err := metadata.ParseMetadata([]byte(doc), "https://example.com/")
+	require.NoError(t, err)
+
+	assert.Equal(t, buffered.EntityTypes, streamed.EntityTypes)
+	assert.Equal(t, buffered.EntitySets, streamed.EntitySets)
+	assert.Equal(t, buffered.IsSAP, streamed.IsSAP)
+}
+
+func TestParseMetadataReaderRoutesV4(t *testing.T) {
+	meta, err := metadata.ParseMetadataReader(strings.NewReader(multiSchemaV4Metadata), "https://example.com/")
+	require.NoError(t, err)
+
+	assert.Equal(t, "4.0", meta.Version)
+	assert.False(t, meta.IsSAP)
+	assert.Contains(t, meta.EntitySets, "SalesOrders")
+	assert.Contains(t, meta.FunctionImports, "GetOpenOrders")
+}
+
+func TestParseMetadataReaderErrors(t *testing.T) {
+	_, err := metadata.ParseMetadataReader(strings.NewReader(`<html><body>Login</body></html>`), "https://example.com/")
+	assert.Error(t, err, "a non-EDMX document should be rejected")
+
+	_, err = metadata.ParseMetadataReader(strings.NewReader(largeSAPMetadata(2)[:600]), "https://example.com/")
+	assert.Error(t, err, "a truncated document should be rejected")
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/metrics"
+	"github.com/odata-mcp/go/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsPrometheusFormat(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.ObserveToolCall("Products_filter", 80*time.Millisecond, 120, false)
+	r.ObserveToolCall("Products_filter", 3*time.Second, 30, true)
+	r.ObserveBackendRequest(200, 70*time.Millisecond)
+	r.ObserveBackendRequest(500, 20*time.Millisecond)
+	r.ObserveBackendRequest(0, time.Second)
+
+	var sb strings.Builder
+	require.NoError(t, r.WritePrometheus(&sb))
+	out := sb.String()
+
+	for _, line := range []string{
+		"# TYPE odata_mcp_tool_calls_total counter",
+		`odata_mcp_tool_calls_total{tool="Products_filter"} 2`,
+		`odata_mcp_tool_errors_total{tool="Products_filter"} 1`,
+		`odata_mcp_tool_response_bytes_total{tool="Products_filter"} 150`,
+		"# TYPE odata_mcp_tool_duration_seconds histogram",
+		`odata_mcp_tool_duration_seconds_bucket{tool="Products_filter",le="0.05"} 0`,
+		`odata_mcp_tool_duration_seconds_bucket{tool="Products_filter",le="0.1"} 1`,
+		`odata_mcp_tool_duration_seconds_bucket{tool="Products_filter",le="5"} 2`,
+		`odata_mcp_tool_duration_seconds_bucket{tool="Products_filter",le="+Inf"} 2`,
+		`odata_mcp_tool_duration_seconds_sum{tool="Products_filter"} 3.08`,
+		`odata_mcp_tool_duration_seconds_count{tool="Products_filter"} 2`,
+		`odata_mcp_backend_requests_total{status="200"} 1`,
+		`odata_mcp_backend_requests_total{status="500"} 1`,
+		`odata_mcp_backend_requests_total{status="error"} 1`,
+		`odata_mcp_backend_request_duration_seconds_bucket{le="0.05"} 1`,
+		`odata_mcp_backend_request_duration_seconds_count 3`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+}
+
+func TestHTTPTransportServesMetrics(t *testing.T) {
+	list := productListHandler(3)
+	b := newMockBridge(t, newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "(") {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		list(w, r)
+	}), nil)
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = b.CallTool(context.Background(), "Products_get", map[string]interface{}{"ProductID": float64(42)})
+	require.Error(t, err)
+
+	ts := httptest.NewServer(transport.NewHTTPServer(b, &config.Config{}).Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + constants.MetricsEndpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// Other tests record into the same registry, so only non-zero values are checked
+	assert.Regexp(t, regexp.MustCompile(`(?m)^odata_mcp_tool_calls_total\{tool="Products_filter"\} [1-9]\d*$`), string(body))
+	assert.Regexp(t, regexp.MustCompile(`(?m)^odata_mcp_tool_errors_total\{tool="Products_get"\} [1-9]\d*$`), string(body))
+	assert.Regexp(t, regexp.MustCompile(`(?m)^odata_mcp_backend_requests_total\{status="200"\} [1-9]\d*$`), string(body))
+	assert.Regexp(t, regexp.MustCompile(`(?m)^odata_mcp_backend_requests_total\{status="404"\} [1-9]\d*$`), string(body))
+}
+
+func TestMetricsStatsSnapshot(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.ObserveToolCall("Products_filter", 100*time.Millisecond, 10, false)
+	r.ObserveToolCall("Products_filter", 300*time.Millisecond, 10, true)
+	r.ObserveToolCall("Orders_get", 50*time.Millisecond, 5, false)
+	r.ObserveBackendRequest(200, 40*time.Millisecond)
+	r.ObserveCache("entity_counts", false)
+	r.ObserveCache("entity_counts", true)
+	r.ObserveCache("entity_counts", true)
+	r.ObserveCache("entity_counts", true)
+
+	stats := r.Stats()
+	assert.Greater(t, stats.Uptime, time.Duration(0))
+	require.Len(t, stats.Tools, 2)
+	assert.Equal(t, "Orders_get", stats.Tools[0].Tool, "tools are sorted by name")
+	filter := stats.Tools[1]
+	assert.Equal(t, uint64(2), filter.Calls)
+	assert.Equal(t, uint64(1), filter.Errors)
+	assert.Equal(t, 200*time.Millisecond, filter.AverageLatency)
+	assert.Equal(t, map[string]uint64{"200": 1}, stats.BackendRequests)
+	require.Len(t, stats.Caches, 1)
+	assert.Equal(t, 0.75, stats.Caches[0].HitRate())
+
+	var sb strings.Builder
+	require.NoError(t, r.WritePrometheus(&sb))
+	assert.Contains(t, sb.String(), `odata_mcp_cache_hits_total{cache="entity_counts"} 3`+"\n")
+	assert.Contains(t, sb.String(), `odata_mcp_cache_misses_total{cache="entity_counts"} 1`+"\n")
+}
+
+func TestBridgeStatsTool(t *testing.T) {
+	b := newMockBridge(t, newMockODataService(t, productListHandler(2)), func(cfg *config.Config) {
+		cfg.AllowedEntities = []string{"Prod*"}
+		cfg.CountCacheTTL = time.Minute
+	})
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = b.CallTool(context.Background(), "Products_count", map[string]interface{}{})
+	require.NoError(t, err)
+
+	result, err := b.CallTool(context.Background(), "bridge_stats", map[string]interface{}{})
+	require.NoError(t, err)
+	var stats struct {
+		Uptime string `json:"uptime"`
+		Tools  []struct {
+			Tool             string  `json:"tool"`
+			Calls            int     `json:"calls"`
+			AverageLatencyMs float64 `json:"average_latency_ms"`
+		} `json:"tools"`
+		Backend struct {
+			Requests int `json:"requests"`
+		} `json:"backend"`
+		Caches []struct {
+			Cache   string  `json:"cache"`
+			Hits    int     `json:"hits"`
+			HitRate float64 `json:"hit_rate"`
+		} `json:"caches"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &stats))
+
+	assert.NotEmpty(t, stats.Uptime)
+	assert.Positive(t, stats.Backend.Requests)
+	calls := make(map[string]int)
+	for _, tool := range stats.Tools {
+		calls[tool.Tool] = tool.Calls
+	}
+	assert.Positive(t, calls["Products_filter"])
+
+	caches := make(map[string]bool)
+	for _, cache := range stats.Caches {
+		caches[cache.Cache] = true
+	}
+	assert.True(t, caches["entity_counts"], "the count is looked up in the count cache")
+}
+
+func TestSAPStatisticsInBridgeStats(t *testing.T) {
+	list := productListHandler(1)
+	b := newMockBridge(t, newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.Header.Get(constants.SAPStatisticsHeader))
+		w.Header().Set(constants.SAPStatisticsHeader, "total=120,fw=3,app=117,gwtotal=98,gwhub=10,gwbe=80,gwapp=5,gwnongw=3")
+		list(w, r)
+	}), func(cfg *config.Config) {
+		cfg.SAPStatistics = true
+	})
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+
+	result, err := b.CallTool(context.Background(), "bridge_stats", map[string]interface{}{})
+	require.NoError(t, err)
+	var stats struct {
+		Backend struct {
+			SAPStatistics struct {
+				Requests  int                `json:"requests"`
+				AverageMs map[string]float64 `json:"average_ms"`
+			} `json:"sap_statistics"`
+		} `json:"backend"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &stats))
+	assert.Positive(t, stats.Backend.SAPStatistics.Requests)
+	assert.Equal(t, 98.0, stats.Backend.SAPStatistics.AverageMs["gwtotal"])
+	assert.Equal(t, 80.0, stats.Backend.SAPStatistics.AverageMs["gwbe"])
+}
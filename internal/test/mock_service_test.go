@@ -0,0 +1,109 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// mockServiceMetadata is a small SAP-flavoured OData v2 service used by bridge tests
+const mockServiceMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="MOCK_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Product">
+        <Key><PropertyRef Name="ProductID"/></Key>
+        <Property Name="ProductID" Type="Edm.Int32" Nullable="false"/>
+        <Property Name="Name" Type="Edm.String" Nullable="false" MaxLength="40"/>
+        <Property Name="Price" Type="Edm.Decimal" Precision="13" Scale="2"/>
+        <Property Name="ReleaseDate" Type="Edm.DateTime"/>
+      </EntityType>
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="OrderID"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false" MaxLength="10"/>
+        <Property Name="Customer" Type="Edm.String"/>
+      </EntityType>
+      <EntityContainer Name="MOCK_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Products" EntityType="MOCK_SRV.Product" sap:searchable="true"/>
+        <EntitySet Name="Orders" EntityType="MOCK_SRV.Order"/>
+        <FunctionImport Name="GetTopProducts" ReturnType="Collection(MOCK_SRV.Product)" EntitySet="Products" m:HttpMethod="GET">
+          <Parameter Name="Count" Type="Edm.Int32" Mode="In"/>
+        </FunctionImport>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// newMockODataService starts an OData v2 service serving mockServiceMetadata.
+// Requests other than $metadata are passed to handler when it is not nil;
+// otherwise an empty collection is returned.
+func newMockODataService(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	return newMockODataServiceWithMetadata(t, mockServiceMetadata, handler)
+}
+
+// newMockODataServiceWithMetadata starts a mock OData service serving the given metadata document
+func newMockODataServiceWithMetadata(t *testing.T, metadata string, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(metadata))
+			return
+		}
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"d": map[string]interface{}{"results": []interface{}{}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newMockBridge creates a bridge for a mock service, letting the caller adjust the config
+func newMockBridge(t *testing.T, server *httptest.Server, configure func(cfg *config.Config)) *bridge.ODataMCPBridge {
+	cfg := &config.Config{
+		ServiceURL: server.URL + "/sap/opu/odata/sap/MOCK_SRV/",
+		NoPostfix:  true,
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+	b, err := bridge.NewODataMCPBridge(cfg)
+	require.NoError(t, err)
+	return b
+}
+
+// listTools returns the tools of a bridge as tools/list serves them
+func listTools(t *testing.T, b *bridge.ODataMCPBridge) []*mcp.Tool {
+	tools, err := b.GetTools()
+	require.NoError(t, err)
+	return tools
+}
+
+// toolOperations returns the set of "<entity>:<operation>" pairs registered on a bridge
+func toolOperations(t *testing.T, b *bridge.ODataMCPBridge) map[string]bool {
+	info, err := b.GetTraceInfo()
+	require.NoError(t, err)
+	ops := make(map[string]bool)
+	for _, tool := range info.RegisteredTools {
+		switch {
+		case tool.EntitySet != "":
+			ops[tool.EntitySet+":"+tool.Operation] = true
+		case tool.Function != "":
+			ops["function:"+tool.Function] = true
+		default:
+			ops[tool.Operation] = true
+		}
+	}
+	return ops
+}
@@ -0,0 +1,163 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multiSchemaV2Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="API_BUSINESS_PARTNER.TYPES" Alias="BP" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="BusinessPartner">
+        <Key><PropertyRef Name="BusinessPartner"/></Key>
+        <Property Name="BusinessPartner" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityType Name="Address">
+        <Key><PropertyRef Name="AddressID"/></Key>
+        <Property Name="AddressID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+    </Schema>
+    <Schema Namespace="API_SUPPLIER.TYPES" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Address">
+        <Key><PropertyRef Name="SupplierAddressID"/></Key>
+        <Property Name="SupplierAddressID" Type="Edm.Int32" Nullable="false"/>
+      </EntityType>
+    </Schema>
+    <Schema Namespace="API_BUSINESS_PARTNER" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityContainer Name="API_BUSINESS_PARTNER_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="A_BusinessPartner" EntityType="BP.BusinessPartner"/>
+        <EntitySet Name="A_BusinessPartnerAddress" EntityType="API_BUSINESS_PARTNER.TYPES.Address"/>
+        <EntitySet Name="A_SupplierAddress" EntityType="API_SUPPLIER.TYPES.Address"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+const multiSchemaV4Metadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="com.sap.types" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="SalesOrder">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <Function Name="GetOpenOrders">
+        <Parameter Name="Customer" Type="Edm.String"/>
+        <ReturnType Type="Collection(com.sap.types.SalesOrder)"/>
+      </Function>
+    </Schema>
+    <Schema Namespace="com.sap.service" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityContainer Name="EntityContainer">
+        <EntitySet Name="SalesOrders" EntityType="com.sap.types.SalesOrder"/>
+        <FunctionImport Name="GetOpenOrders" Function="com.sap.types.GetOpenOrders" EntitySet="SalesOrders"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestMultiSchemaMetadata(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(multiSchemaV2Metadata), "http://example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "API_BUSINESS_PARTNER", meta.SchemaNamespace)
+	assert.Equal(t, "API_BUSINESS_PARTNER_Entities", meta.ContainerName)
+	require.Len(t, meta.EntitySets, 3)
+
+	// Alias-qualified references resolve to the aliased schema's type
+	partner := meta.EntityTypes[meta.EntitySets["A_BusinessPartner"].EntityType]
+	require.NotNil(t, partner)
+	assert.Equal(t, []string{"BusinessPartner"}, partner.KeyProperties)
+
+	// Types with the same name in different namespaces are both kept
+	bpAddress := meta.EntityTypes[meta.EntitySets["A_BusinessPartnerAddress"].EntityType]
+	supplierAddress := meta.EntityTypes[meta.EntitySets["A_SupplierAddress"].EntityType]
+	require.NotNil(t, bpAddress)
+	require.NotNil(t, supplierAddress)
+	assert.Equal(t, []string{"AddressID"}, bpAddress.KeyProperties)
+	assert.Equal(t, []string{"SupplierAddressID"}, supplierAddress.KeyProperties)
+
+	meta, err = metadata.ParseMetadata([]byte(multiSchemaV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+	require.Contains(t, meta.EntitySets, "SalesOrders")
+	assert.NotNil(t, meta.EntityTypes[meta.EntitySets["SalesOrders"].EntityType])
+	require.Contains(t, meta.FunctionImports, "GetOpenOrders", "functions declared in another schema are imported")
+	assert.Len(t, meta.FunctionImports["GetOpenOrders"].Parameters, 1)
+}
+
+func TestMultiSchemaComplexTypes(t *testing.T) {
+	const v2 = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="BP" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <ComplexType Name="Address">
+        <Property Name="Street" Type="Edm.String"/>
+      </ComplexType>
+    </Schema>
+    <Schema Namespace="SUPPLIER" Alias="S" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <ComplexType Name="Address">
+        <Property Name="PostBox" Type="Edm.String"/>
+      </ComplexType>
+      <EntityType Name="Supplier">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+        <Property Name="Address" Type="S.Address"/>
+        <Property Name="PartnerAddress" Type="BP.Address"/>
+      </EntityType>
+      <EntityContainer Name="Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Suppliers" EntityType="SUPPLIER.Supplier"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+	const v4 = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="bp" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <ComplexType Name="Address">
+        <Property Name="Street" Type="Edm.String"/>
+      </ComplexType>
+    </Schema>
+    <Schema Namespace="supplier" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <ComplexType Name="Address" BaseType="bp.Address">
+        <Property Name="PostBox" Type="Edm.String"/>
+      </ComplexType>
+      <EntityType Name="Supplier">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+        <Property Name="Addresses" Type="Collection(supplier.Address)"/>
+      </EntityType>
+      <EntityContainer Name="Container">
+        <EntitySet Name="Suppliers" EntityType="supplier.Supplier"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+	propertyNames := func(complexType *models.ComplexType) []string {
+		var names []string
+		for _, prop := range complexType.Properties {
+			names = append(names, prop.Name)
+		}
+		return names
+	}
+
+	meta, err := metadata.ParseMetadata([]byte(v2), "http://example.com/")
+	require.NoError(t, err)
+	supplier := meta.EntityTypes["Supplier"]
+	require.NotNil(t, supplier)
+	assert.Equal(t, "SUPPLIER.Address", supplier.Properties[1].ComplexType)
+	assert.Equal(t, "Address", supplier.Properties[2].ComplexType)
+	assert.Equal(t, []string{"PostBox"}, propertyNames(meta.ComplexTypes["SUPPLIER.Address"]))
+	assert.Equal(t, []string{"Street"}, propertyNames(meta.ComplexTypes["Address"]))
+
+	meta, err = metadata.ParseMetadata([]byte(v4), "http://example.com/")
+	require.NoError(t, err)
+	addresses := meta.EntityTypes["Supplier"].Properties[1]
+	assert.Equal(t, "supplier.Address", addresses.ComplexType)
+	assert.True(t, addresses.IsCollection)
+	assert.Equal(t, []string{"Street", "PostBox"}, propertyNames(meta.ComplexTypes["supplier.Address"]), "base type of another schema")
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOperations(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected []string
+	}{
+		{"c", []string{constants.OpCreate}},
+		{"CR", []string{constants.OpCreate, constants.OpFilter, constants.OpCount, constants.OpSearch, constants.OpGet}},
+		{"delete,update", []string{constants.OpDelete, constants.OpUpdate}},
+		{"read+create", []string{constants.OpCreate, constants.OpFilter, constants.OpCount, constants.OpSearch, constants.OpGet}},
+		{"a", []string{constants.OpFunction}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			ops, err := config.ParseOperations(tt.spec)
+			require.NoError(t, err)
+			assert.Len(t, ops, len(tt.expected))
+			for _, op := range tt.expected {
+				assert.True(t, ops[op], "expected %s to be enabled", op)
+			}
+		})
+	}
+
+	for _, spec := range []string{"xyz", "card", "rr", "read,craete"} {
+		_, err := config.ParseOperations(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestOperationFilterToolGeneration(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		b := newMockBridge(t, server, func(cfg *config.Config) {
+			cfg.Operations = "r"
+			require.NoError(t, cfg.ParseOperationFilters())
+		})
+		ops := toolOperations(t, b)
+
+		assert.True(t, ops["Products:filter"])
+		assert.True(t, ops["Products:get"])
+		assert.True(t, ops["Products:search"])
+		assert.False(t, ops["Products:create"])
+		assert.False(t, ops["Products:delete"])
+		assert.False(t, ops["function:GetTopProducts"])
+		assert.True(t, ops[constants.OpInfo], "service info is always generated")
+	})
+
+	t.Run("DisableDeleteUpdate", func(t *testing.T) {
+		b := newMockBridge(t, server, func(cfg *config.Config) {
+			cfg.DisableOperations = "delete,update"
+			require.NoError(t, cfg.ParseOperationFilters())
+		})
+		ops := toolOperations(t, b)
+
+		assert.True(t, ops["Orders:create"])
+		assert.False(t, ops["Orders:update"])
+		assert.False(t, ops["Orders:delete"])
+		assert.True(t, ops["function:GetTopProducts"])
+	})
+}
+
+func TestParseEntityOperationRules(t *testing.T) {
+	rules, err := config.ParseEntityOperationRules("Products:read, Orders:read+create,update\n# comment\nA_*:r")
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, "Products", rules[0].Pattern)
+	assert.True(t, rules[0].Operations[constants.OpGet])
+	assert.False(t, rules[0].Operations[constants.OpCreate])
+
+	assert.Equal(t, "Orders", rules[1].Pattern)
+	assert.True(t, rules[1].Operations[constants.OpCreate])
+	assert.True(t, rules[1].Operations[constants.OpUpdate])
+	assert.False(t, rules[1].Operations[constants.OpDelete])
+
+	assert.Equal(t, "A_*", rules[2].Pattern)
+
+	_, err = config.ParseEntityOperationRules("read")
+	assert.Error(t, err)
+}
+
+func TestEntityOperationRulesToolGeneration(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		rules, err := config.ParseEntityOperationRules("Products:read, Orders:read+create")
+		require.NoError(t, err)
+		cfg.EntityOperationRules = rules
+	})
+	ops := toolOperations(t, b)
+
+	assert.True(t, ops["Products:filter"])
+	assert.False(t, ops["Products:create"])
+	assert.False(t, ops["Products:update"])
+	assert.True(t, ops["Orders:create"])
+	assert.False(t, ops["Orders:delete"])
+}
+
+func TestConfirmOperationsFailClosedWithoutElicitation(t *testing.T) {
+	deleted := false
+	server := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.ConfirmOperations = "delete"
+		require.NoError(t, cfg.ParseConfirmOperations())
+	})
+
+	_, err := b.CallTool(context.Background(), "Products_delete", map[string]interface{}{"ProductID": float64(1)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires user confirmation")
+	assert.False(t, deleted)
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVOutputFormat(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"__count":"12","results":[
+			{"__metadata":{"type":"MOCK_SRV.Product"},"Name":"Widget, large","ProductID":1,"Price":"9.50"},
+			{"__metadata":{"type":"MOCK_SRV.Product"},"Name":"Say \"hi\"","ProductID":2,"Price":null}
+		]}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"output_format": "csv"})
+	require.NoError(t, err)
+	textResult, ok := result.(*mcp.TextResult)
+	require.True(t, ok, "csv output must be a text result, got %T", result)
+	assert.Equal(t, "# total_count: 12\n"+
+		"ProductID,Name,Price\n"+
+		"1,\"Widget, large\",9.50\n"+
+		"2,\"Say \"\"hi\"\"\",\n", textResult.Text)
+	assert.Contains(t, textResult.Structured, `"ProductID":1`)
+
+	// $select decides the columns and their order
+	result, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{
+		"output_format": "csv", "$select": "Name,ProductID",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.(*mcp.TextResult).Text, "Name,ProductID\n\"Widget, large\",1\n")
+
+	// JSON stays the default
+	result, err = b.CallTool(context.Background(), "Products_search", map[string]interface{}{"search": "Widget"})
+	require.NoError(t, err)
+	assert.IsType(t, "", result)
+
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"output_format": "xml"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+}
+
+func TestMarkdownOutputFormat(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[
+			{"ProductID":1,"Name":"A|B","Price":"1.00","ReleaseDate":"2024-01-01"},
+			{"ProductID":2,"Name":"line\nbreak","Price":"2.00","ReleaseDate":"2024-01-01"},
+			{"ProductID":3,"Name":"C","Price":"3.00","ReleaseDate":"2024-01-01"}
+		]}}`))
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.OutputFormat = "markdown"
+		cfg.TableMaxColumns = 2
+		cfg.TableMaxRows = 2
+	})
+
+	// --output-format applies when the agent passes no output_format
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	textResult, ok := result.(*mcp.TextResult)
+	require.True(t, ok, "markdown output must be a text result, got %T", result)
+	assert.Equal(t, "_2 more columns omitted (Price, ReleaseDate), use $select to choose the columns_\n\n"+
+		"_showing 2 of 3 entities, use $top and $skip to page_\n\n"+
+		"| ProductID | Name |\n"+
+		"| --- | --- |\n"+
+		"| 1 | A\\|B |\n"+
+		"| 2 | line<br>break |\n", textResult.Text)
+
+	result, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"output_format": "json"})
+	require.NoError(t, err)
+	assert.IsType(t, "", result)
+}
+
+func TestColumnsOutputFormat(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"__count":"12","results":[
+			{"__metadata":{"type":"MOCK_SRV.Product"},"Name":"Widget","ProductID":1,"Price":"9.50"},
+			{"__metadata":{"type":"MOCK_SRV.Product"},"ProductID":2,"Name":"Gadget"}
+		]}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"output_format": "columns"})
+	require.NoError(t, err)
+	text, ok := result.(string)
+	require.True(t, ok, "columns output must be JSON text, got %T", result)
+	assert.NotContains(t, text, "__metadata")
+
+	var columnar struct {
+		Count   int64           `json:"@odata.count"`
+		Columns []string        `json:"columns"`
+		Rows    [][]interface{} `json:"rows"`
+		Value   interface{}     `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &columnar))
+	assert.Equal(t, int64(12), columnar.Count)
+	assert.Equal(t, []string{"ProductID", "Name", "Price"}, columnar.Columns)
+	assert.Equal(t, [][]interface{}{{float64(1), "Widget", "9.50"}, {float64(2), "Gadget", nil}}, columnar.Rows)
+	assert.Nil(t, columnar.Value, "entities are only sent as rows")
+}
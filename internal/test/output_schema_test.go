@@ -0,0 +1,42 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolOutputSchemas(t *testing.T) {
+	server := newMockODataService(t, nil)
+	b := newMockBridge(t, server, nil)
+
+	schemas := make(map[string]map[string]interface{})
+	for _, tool := range listTools(t, b) {
+		schemas[tool.Name] = tool.OutputSchema
+	}
+
+	filter := schemas["Products_filter"]
+	require.NotNil(t, filter)
+	value := filter["properties"].(map[string]interface{})["value"].(map[string]interface{})
+	assert.Equal(t, "array", value["type"])
+	item := value["items"].(map[string]interface{})
+	props := item["properties"].(map[string]interface{})
+	assert.Equal(t, "integer", props["ProductID"].(map[string]interface{})["type"])
+	assert.Equal(t, "string", props["Name"].(map[string]interface{})["type"])
+	assert.NotContains(t, props["Price"], "type", "decimals may be strings or numbers")
+
+	get := schemas["Products_get"]
+	require.NotNil(t, get)
+	assert.Equal(t, "object", get["properties"].(map[string]interface{})["value"].(map[string]interface{})["type"])
+
+	count := schemas["Products_count"]
+	require.NotNil(t, count)
+	assert.Contains(t, count["properties"], "count")
+
+	function := schemas["GetTopProducts"]
+	require.NotNil(t, function)
+	assert.Equal(t, "array", function["properties"].(map[string]interface{})["value"].(map[string]interface{})["type"])
+
+	assert.Nil(t, schemas["Products_delete"])
+}
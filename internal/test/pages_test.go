@@ -0,0 +1,156 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedProductsService serves a collection of products honoring $skip and $top, slowly
+// enough that concurrent page requests overlap
+type pagedProductsService struct {
+	total    int
+	failSkip string // $skip of a page answered with HTTP 500
+	mu       sync.Mutex
+	requests []string // $skip,$top of each request
+	queries  []url.Values
+	running  atomic.Int32
+	peak     atomic.Int32
+}
+
+func (s *pagedProductsService) handle(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	s.requests = append(s.requests, query.Get("$skip")+","+query.Get("$top"))
+	s.queries = append(s.queries, query)
+	s.mu.Unlock()
+
+	running := s.running.Add(1)
+	defer s.running.Add(-1)
+	for peak := s.peak.Load(); running > peak && !s.peak.CompareAndSwap(peak, running); peak = s.peak.Load() {
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	if s.failSkip != "" && query.Get("$skip") == s.failSkip {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	skip, _ := strconv.Atoi(query.Get("$skip"))
+	top, err := strconv.Atoi(query.Get("$top"))
+	if err != nil {
+		top = s.total
+	}
+	items := []map[string]interface{}{}
+	for i := skip; i < s.total && i < skip+top; i++ {
+		items = append(items, map[string]interface{}{"ProductID": i, "Name": "Product " + strconv.Itoa(i)})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"d": map[string]interface{}{"results": items, "__count": strconv.Itoa(s.total)},
+	})
+}
+
+// productIDs returns the ProductIDs of a list tool result
+func productIDs(t *testing.T, result interface{}) []int {
+	var response struct {
+		Value []struct {
+			ProductID int `json:"ProductID"`
+		} `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	ids := make([]int, len(response.Value))
+	for i, item := range response.Value {
+		ids[i] = item.ProductID
+	}
+	return ids
+}
+
+func TestLargeTopIsFetchedAsConcurrentPages(t *testing.T) {
+	service := &pagedProductsService{total: 100}
+	b := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.PageSize = 10
+		cfg.PageWorkers = 3
+	})
+
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(35), "$skip": float64(5)})
+	require.NoError(t, err)
+
+	ids := productIDs(t, result)
+	require.Len(t, ids, 35)
+	for i, id := range ids {
+		assert.Equal(t, 5+i, id, "pages are merged in order")
+	}
+	assert.ElementsMatch(t, []string{"5,10", "15,10", "25,10", "35,5"}, service.requests)
+	assert.Equal(t, int32(3), service.peak.Load(), "pages are fetched by --page-workers workers")
+
+	service.requests = nil
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(10)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{",10"}, service.requests, "a $top within one page is one request")
+}
+
+func TestPagedFetchStopsAtEndOfCollection(t *testing.T) {
+	service := &pagedProductsService{total: 25}
+	b := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.PageSize = 10
+		cfg.PageWorkers = 4
+	})
+
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(50)})
+	require.NoError(t, err)
+	assert.Len(t, productIDs(t, result), 25)
+}
+
+func TestPagedFetchFailsWhenAPageFails(t *testing.T) {
+	service := &pagedProductsService{total: 100, failSkip: "20"}
+	b := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.PageSize = 10
+		cfg.PageWorkers = 2
+	})
+
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(100)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	assert.Less(t, len(service.requests), 10, "the remaining pages are not requested")
+}
+
+func TestPagesAreOrderedByKeyAndCountedOnce(t *testing.T) {
+	service := &pagedProductsService{total: 100}
+	b := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.PageSize = 10
+		cfg.PageWorkers = 2
+		cfg.PaginationHints = true
+	})
+
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(30)})
+	require.NoError(t, err)
+	require.Len(t, service.queries, 3)
+	counted := 0
+	for _, query := range service.queries {
+		assert.Equal(t, "ProductID", query.Get("$orderby"), "pages without $orderby are ordered by the key")
+		if query.Get("$inlinecount") != "" {
+			counted++
+			assert.Equal(t, "0", query.Get("$skip"), "only the first page is counted")
+		}
+	}
+	assert.Equal(t, 1, counted)
+
+	service.queries = nil
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(30), "$orderby": "Name desc"})
+	require.NoError(t, err)
+	for _, query := range service.queries {
+		assert.Equal(t, "Name desc", query.Get("$orderby"))
+	}
+}
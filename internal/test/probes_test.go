@@ -0,0 +1,109 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getProbe requests a probe endpoint and returns its status code and status
+func getProbe(t *testing.T, url string) (int, map[string]string) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestProbesReportStartingUntilBridgeIsSet(t *testing.T) {
+	server := transport.NewHTTPServer(nil, &config.Config{})
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	code, body := getProbe(t, ts.URL+constants.LivenessEndpoint)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body["status"])
+
+	code, body = getProbe(t, ts.URL+constants.ReadinessEndpoint)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "starting", body["status"])
+
+	resp, _ := postMCP(t, ts.URL+constants.MCPEndpoint, "", "", map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "method": "initialize",
+		"params": map[string]interface{}{"protocolVersion": constants.MCPProtocolVersion, "capabilities": map[string]interface{}{}},
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "no sessions before the metadata is loaded")
+
+	server.SetBridge(newMockBridge(t, newMockODataService(t, nil), nil))
+	code, body = getProbe(t, ts.URL+constants.ReadinessEndpoint)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body["status"])
+	initializeHTTPSession(t, ts.URL+constants.MCPEndpoint, "")
+}
+
+func TestReadinessFailsWhenServiceRejectsCredentials(t *testing.T) {
+	b := newMockBridge(t, newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Logon failed", http.StatusUnauthorized)
+	}), func(cfg *config.Config) {
+		cfg.Username, cfg.Password = "DEVELOPER", "expired"
+	})
+	ts := httptest.NewServer(transport.NewHTTPServer(b, &config.Config{}).Handler())
+	defer ts.Close()
+
+	code, body := getProbe(t, ts.URL+constants.ReadinessEndpoint)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", body["status"])
+	assert.Contains(t, body["error"], "401")
+
+	code, _ = getProbe(t, ts.URL+constants.LivenessEndpoint)
+	assert.Equal(t, http.StatusOK, code, "the process stays alive")
+}
+
+func TestStartupTimeoutRetriesUnavailableService(t *testing.T) {
+	var attempts atomic.Int32
+	service := newMockODataService(t, nil)
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") && attempts.Add(1) == 1 {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		service.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer flaky.Close()
+
+	b := newMockBridge(t, flaky, func(cfg *config.Config) {
+		cfg.StartupTimeout = 10 * time.Second
+	})
+	assert.NotNil(t, b)
+	assert.Equal(t, int32(2), attempts.Load(), "the failed attempt is retried")
+
+	attempts.Store(0)
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer rejecting.Close()
+	_, err := bridge.NewODataMCPBridge(&config.Config{ServiceURL: rejecting.URL + "/", StartupTimeout: 10 * time.Second})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Load(), "rejected credentials are not retried")
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	started := time.Now()
+	_, err = bridge.NewODataMCPBridge(&config.Config{ServiceURL: unreachable.URL + "/", StartupTimeout: 700 * time.Millisecond})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service not available within --startup-timeout 700ms")
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestIDCorrelatesToolCallsAndBackendRequests(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	list := productListHandler(1)
+	b := newMockBridge(t, newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids = append(ids, r.Header.Get(constants.RequestIDHeader))
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "(") {
+			http.Error(w, `{"error":{"code":"404","message":{"value":"Product not found"}}}`, http.StatusNotFound)
+			return
+		}
+		list(w, r)
+	}), nil)
+	ctx := context.Background()
+
+	_, err := b.CallTool(ctx, "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Products_get", map[string]interface{}{"ProductID": float64(42)})
+	require.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, ids, 2)
+	for _, id := range ids {
+		assert.Regexp(t, uuidPattern, id)
+	}
+	assert.NotEqual(t, ids[0], ids[1], "every tool call gets its own ID")
+	assert.Contains(t, err.Error(), "Product not found")
+	assert.Contains(t, err.Error(), "(request ID: "+ids[1]+")", "the error names the ID sent to the service")
+}
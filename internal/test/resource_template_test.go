@@ -0,0 +1,54 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityResourceRead(t *testing.T) {
+	var requested []string
+	server := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"d": map[string]interface{}{"OrderID": "A'1", "Customer": "ACME"},
+		})
+	})
+	b := newMockBridge(t, server, nil)
+
+	contents, err := b.ReadResource(context.Background(), "odata://Products/42")
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	assert.Equal(t, "odata://Products/42", contents[0].URI)
+	assert.Equal(t, "application/json", contents[0].MimeType)
+	assert.Contains(t, contents[0].Text, "ACME")
+
+	_, err = b.ReadResource(context.Background(), "odata://Orders/'A''1'")
+	require.NoError(t, err)
+
+	_, err = b.ReadResource(context.Background(), "odata://Orders/OrderID='A1'")
+	require.NoError(t, err)
+
+	require.Len(t, requested, 3)
+	assert.Contains(t, requested[0], "Products(42)")
+	assert.Contains(t, requested[1], "Orders(")
+	assert.Contains(t, requested[2], "Orders(")
+}
+
+func TestEntityResourceReadUnknown(t *testing.T) {
+	server := newMockODataService(t, nil)
+	b := newMockBridge(t, server, nil)
+
+	for _, uri := range []string{"odata://Nope/1", "odata://Products", "http://example.com/Products/1"} {
+		_, err := b.ReadResource(context.Background(), uri)
+		require.Error(t, err, uri)
+		assert.True(t, errors.Is(err, mcp.ErrResourceNotFound), uri)
+	}
+}
@@ -0,0 +1,341 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// productListHandler serves count products with a padded name of roughly 100 bytes each
+func productListHandler(count int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items := make([]map[string]interface{}, count)
+		for i := range items {
+			items[i] = map[string]interface{}{"ProductID": i, "Name": strings.Repeat("x", 80)}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"d": map[string]interface{}{"results": items}})
+	}
+}
+
+func TestMaxResponseSizeTruncatesToolOutput(t *testing.T) {
+	service := newMockODataService(t, productListHandler(30))
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxResponseSize = 1000
+	})
+
+	for _, call := range []struct {
+		tool string
+		args map[string]interface{}
+	}{
+		{"Products_filter", map[string]interface{}{}},
+		{"Products_search", map[string]interface{}{"search": "x"}},
+	} {
+		result, err := b.CallTool(context.Background(), call.tool, call.args)
+		require.NoError(t, err, call.tool)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+		assert.Equal(t, true, response["truncated"], call.tool)
+		items := response["value"].([]interface{})
+		assert.NotEmpty(t, items, call.tool)
+		assert.Less(t, len(items), 30, call.tool)
+
+		data, _ := json.Marshal(items)
+		assert.LessOrEqual(t, len(data), 1000, call.tool)
+
+		meta := response["@odata.metadata"].(map[string]interface{})
+		assert.Contains(t, meta["warning"], "$select", call.tool)
+		assert.EqualValues(t, 30, meta["original_count"], call.tool)
+	}
+}
+
+func TestListResultsEncodeLikeMarshal(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		items := make([]map[string]interface{}, 20)
+		for i := range items {
+			items[i] = map[string]interface{}{"ProductID": i, "Name": fmt.Sprintf(`<"value":[%d]> & ü`, i)}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"d": map[string]interface{}{"__count": "20", "results": items}})
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxResponseSize = 500
+		cfg.PaginationHints = true
+	})
+
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(20)})
+	require.NoError(t, err)
+
+	// Entities are encoded one at a time; the result is still what json.Marshal produces
+	var response models.ODataResponse
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	data, err := json.Marshal(&response)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), result.(string))
+
+	items := response.Value.([]interface{})
+	assert.True(t, response.Truncated)
+	assert.Less(t, len(items), 20)
+	for i, item := range items {
+		assert.Equal(t, fmt.Sprintf(`<"value":[%d]> & ü`, i), item.(map[string]interface{})["Name"])
+	}
+}
+
+func TestConcurrentResponsesKeepTheirBodies(t *testing.T) {
+	// Each response echoes its $skip, with a padded name so that bodies differ in size
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		skip := r.URL.Query().Get("$skip")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"d":{"results":[{"ProductID":%s,"Name":"%s"}]}}`, skip, strings.Repeat("x", len(skip)*500))
+	})
+	b := newMockBridge(t, service, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$skip": float64(i)})
+			if !assert.NoError(t, err) {
+				return
+			}
+			var response map[string]interface{}
+			if assert.NoError(t, json.Unmarshal([]byte(result.(string)), &response)) {
+				item := response["value"].([]interface{})[0].(map[string]interface{})
+				assert.EqualValues(t, i, item["ProductID"])
+				assert.Len(t, item["Name"], len(fmt.Sprint(i))*500)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestMaxResponseSizeLimitsBodies(t *testing.T) {
+	service := newMockODataService(t, productListHandler(100))
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxResponseSize = 500
+	})
+
+	_, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("exceeds %d bytes", 2000))
+	assert.Contains(t, err.Error(), "$select")
+}
+
+func TestMaxItemsInjectsDefaultTop(t *testing.T) {
+	var tops []string
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		top := r.URL.Query().Get("$top")
+		tops = append(tops, top)
+		count := 20
+		if top != "" {
+			fmt.Sscanf(top, "%d", &count)
+		}
+		productListHandler(count)(w, r)
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxItems = 5
+	})
+
+	decode := func(result interface{}) map[string]interface{} {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+		return response
+	}
+
+	// Without $top the bridge asks for one more than --max-items entities and flags the cut-off
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	response := decode(result)
+	assert.Len(t, response["value"], 5)
+	assert.Equal(t, true, response["truncated"])
+	assert.Equal(t, "6", tops[len(tops)-1])
+
+	// An explicit $top is sent unchanged and oversized results are trimmed
+	result, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": 8.0})
+	require.NoError(t, err)
+	response = decode(result)
+	assert.Len(t, response["value"], 5)
+	assert.Equal(t, true, response["truncated"])
+	assert.Equal(t, "8", tops[len(tops)-1])
+
+	// Results within the limit are not flagged
+	result, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": 3.0})
+	require.NoError(t, err)
+	response = decode(result)
+	assert.Len(t, response["value"], 3)
+	assert.Nil(t, response["truncated"])
+}
+
+func TestDefaultTopDoesNotFlagExactlyFullPages(t *testing.T) {
+	service := newMockODataService(t, productListHandler(5))
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxItems = 5
+	})
+
+	// The service has exactly --max-items entities, so nothing was cut off
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	assert.Len(t, response["value"], 5)
+	assert.Nil(t, response["truncated"])
+}
+
+func TestDefaultTopAndHardTopBoundQueries(t *testing.T) {
+	var tops []string
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		top := r.URL.Query().Get("$top")
+		tops = append(tops, top)
+		count := 2000
+		if top != "" {
+			fmt.Sscanf(top, "%d", &count)
+		}
+		productListHandler(count)(w, r)
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.MaxItems = 0
+		cfg.DefaultTop = 50
+		cfg.HardTop = 200
+	})
+
+	decode := func(result interface{}) map[string]interface{} {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+		return response
+	}
+
+	// --default-top applies to queries without $top
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	response := decode(result)
+	assert.Equal(t, "51", tops[len(tops)-1])
+	assert.Len(t, response["value"], 50)
+	assert.Equal(t, true, response["truncated"])
+
+	// A $top above --hard-top is lowered to it and the cut-off is flagged
+	result, err = b.CallTool(context.Background(), "Products_search", map[string]interface{}{"search": "x", "$top": 5000.0})
+	require.NoError(t, err)
+	response = decode(result)
+	assert.Equal(t, "201", tops[len(tops)-1])
+	assert.Len(t, response["value"], 200)
+	assert.Equal(t, true, response["truncated"])
+	assert.Contains(t, response["@odata.metadata"].(map[string]interface{})["warning"], "$top is capped at 200")
+
+	// A $top within the cap is sent unchanged
+	result, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": 120.0})
+	require.NoError(t, err)
+	response = decode(result)
+	assert.Equal(t, "120", tops[len(tops)-1])
+	assert.Nil(t, response["truncated"])
+}
+
+func TestMaxUnfilteredRowsRejectsFullTableQueries(t *testing.T) {
+	service := &countingService{queries: make(map[string]int)}
+	server := newMockODataService(t, service.handle)
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxUnfilteredRows = 10
+		cfg.CountCacheTTL = time.Minute
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := b.CallTool(ctx, "Products_filter", map[string]interface{}{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "it has 42 entities, more than --max-unfiltered-rows 10")
+		assert.Contains(t, err.Error(), "$filter")
+	}
+	// The size is counted once; rejected queries are not sent
+	assert.Equal(t, 1, service.count(""))
+
+	_, err := b.CallTool(ctx, "Products_filter", map[string]interface{}{"$filter": "Price gt 10"})
+	require.NoError(t, err)
+	_, err = b.CallTool(ctx, "Products_filter", map[string]interface{}{"$top": float64(5)})
+	require.NoError(t, err)
+
+	// Small entity sets are queried in full
+	small := newMockBridge(t, server, func(cfg *config.Config) { cfg.MaxUnfilteredRows = 100 })
+	_, err = small.CallTool(ctx, "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+
+	// A default $top limits the query, so it is sent without counting
+	service = &countingService{queries: make(map[string]int)}
+	limited := newMockBridge(t, newMockODataService(t, service.handle), func(cfg *config.Config) {
+		cfg.MaxUnfilteredRows = 10
+		cfg.DefaultTop = 20
+	})
+	_, err = limited.CallTool(ctx, "Products_filter", map[string]interface{}{})
+	require.NoError(t, err, "a query limited by --default-top is not a full table read")
+	assert.Equal(t, 1, service.count(""), "only the query itself is sent")
+}
+
+func TestMaxUnfilteredRowsWarnsWhileADefaultTopApplies(t *testing.T) {
+	previous := slog.Default()
+	var logs bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	server := newMockODataService(t, nil)
+	newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxUnfilteredRows = 10
+		cfg.MaxItems = 0
+	})
+	assert.NotContains(t, logs.String(), "--max-unfiltered-rows has no effect")
+
+	// --max-items defaults to 100, which limits every query without $top
+	newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxUnfilteredRows = 10
+		cfg.MaxItems = 100
+	})
+	assert.Contains(t, logs.String(), "--max-unfiltered-rows has no effect")
+}
+
+func TestRawResponsesPassBodiesThrough(t *testing.T) {
+	const body = `{"d":{"results":[{"__metadata":{"uri":"Products(1)"},"ProductID":1,"Name":"Chai","ReleaseDate":"/Date(1700000000000)/"}]}}`
+	large := `{"d":{"results":[` + strings.Repeat(`{"ProductID":1,"Name":"Chai"},`, 40) + `{"ProductID":2,"Name":"Tea"}]}}`
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$top") == "41" {
+			w.Write([]byte(large))
+			return
+		}
+		w.Write([]byte(body))
+	})
+	b := newMockBridge(t, service, func(cfg *config.Config) {
+		cfg.RawResponses = true
+		cfg.MaxResponseSize = 1000
+	})
+
+	result, err := b.CallTool(context.Background(), "Products_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, body, result, "the body of the service is returned verbatim")
+
+	result, err = b.CallTool(context.Background(), "Products_get", map[string]interface{}{"ProductID": float64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, body, result)
+
+	// A body over --max-response-size is decoded and truncated
+	result, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(41)})
+	require.NoError(t, err)
+	var response models.ODataResponse
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &response))
+	assert.True(t, response.Truncated)
+
+	// CSV output needs the decoded entities
+	result, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"output_format": "csv"})
+	require.NoError(t, err)
+	assert.NotEqual(t, body, result)
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSAPMessagesInToolResults(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("sap-message", `{"code":"V1/555","message":"Order 42 created","severity":"success","target":"",`+
+				`"details":[{"code":"V1/154","message":"Order is blocked for credit","severity":"warning","target":"Customer"}]}`)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"d":{"OrderID":"42","Customer":"C1"}}`))
+		case http.MethodDelete:
+			w.Header().Set("sap-message", `<notification><code>V1/300</code><message>Order 42 deleted</message><severity>info</severity></notification>`)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`{"d":{"results":[]}}`))
+		}
+	})
+	b := newMockBridge(t, service, nil)
+
+	result, err := b.CallTool(context.Background(), "Orders_create", map[string]interface{}{"OrderID": "42", "Customer": "C1"})
+	require.NoError(t, err)
+	var created struct {
+		Messages []models.SAPMessage `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &created))
+	assert.Equal(t, []models.SAPMessage{
+		{Code: "V1/555", Message: "Order 42 created", Severity: "success"},
+		{Code: "V1/154", Message: "Order is blocked for credit", Severity: "warning", Target: "Customer"},
+	}, created.Messages)
+
+	result, err = b.CallTool(context.Background(), "Orders_delete", map[string]interface{}{"OrderID": "42"})
+	require.NoError(t, err)
+	var deleted struct {
+		Status   string              `json:"status"`
+		Messages []models.SAPMessage `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &deleted))
+	assert.Equal(t, "success", deleted.Status)
+	assert.Equal(t, []models.SAPMessage{{Code: "V1/300", Message: "Order 42 deleted", Severity: "info"}}, deleted.Messages)
+
+	// Responses without the header carry no messages
+	result, err = b.CallTool(context.Background(), "Orders_filter", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.NotContains(t, result, "messages")
+}
+
+func TestSAPErrorDetailsBecomeTypedMessages(t *testing.T) {
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"V1/010","message":{"lang":"en","value":"Order could not be created"},` +
+			`"innererror":{"transactionid":"4DD97CCD6B390040E00684E9F2C3E12B","errordetails":[` +
+			`{"code":"V1/011","message":"Customer C9 does not exist","propertyref":"","severity":"error","target":"Customer","transition":false},` +
+			`{"code":"V1/012","message":"Delivery date is in the past","propertyref":"DeliveryDate","severity":"warning","target":""},` +
+			`{"code":"/IWBEP/CX_MGW_BUSI_EXCEPTION","message":"","severity":"error","target":""}]}}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	_, err := b.CallTool(context.Background(), "Orders_create", map[string]interface{}{"OrderID": "42", "Customer": "C9"})
+	require.Error(t, err)
+
+	var statusErr *client.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	require.NotNil(t, statusErr.OData)
+	assert.Equal(t, "V1/010", statusErr.OData.Code)
+	assert.Equal(t, "Order could not be created", statusErr.OData.Message, "the v2 message object is read")
+	assert.Equal(t, []models.SAPMessage{
+		{Code: "V1/011", Message: "Customer C9 does not exist", Severity: "error", Target: "Customer"},
+		{Code: "V1/012", Message: "Delivery date is in the past", Severity: "warning", Target: "DeliveryDate"},
+	}, statusErr.OData.Messages)
+
+	assert.Contains(t, err.Error(), "HTTP 400")
+	assert.Contains(t, err.Error(), "[error] Customer C9 does not exist (target: Customer)")
+	assert.Contains(t, err.Error(), "[warning] Delivery date is in the past (target: DeliveryDate)")
+}
+
+func TestSAPErrorDetailsInToolErrorData(t *testing.T) {
+	server := mcp.NewServer("test", "1.0")
+	messages := []models.SAPMessage{{Code: "V1/011", Message: "Customer C9 does not exist", Severity: "error", Target: "Customer"}}
+	server.AddTool(&mcp.Tool{Name: "Orders_create", InputSchema: map[string]interface{}{"type": "object"}},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, fmt.Errorf("failed to create entity: %w", &client.StatusError{
+				StatusCode: http.StatusBadRequest,
+				Err:        errors.New(`HTTP 400: Order "42" could not be created`),
+				OData:      &models.ODataError{Code: "V1/010", Message: "Order could not be created", Messages: messages},
+			})
+		})
+
+	responses := runMCPSession(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"Orders_create","arguments":{}}}`)
+	require.Len(t, responses, 1)
+	var data struct {
+		Tool          string              `json:"tool"`
+		OriginalError string              `json:"original_error"`
+		Messages      []models.SAPMessage `json:"messages"`
+	}
+	rpcErr := responses[0]["error"].(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(rpcErr["data"].(string)), &data), "the error data is valid JSON")
+	assert.Equal(t, "Orders_create", data.Tool)
+	assert.Contains(t, data.OriginalError, `Order "42"`)
+	assert.Equal(t, messages, data.Messages)
+}
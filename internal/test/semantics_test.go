@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const semanticsMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="SALES_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Order">
+        <Key><PropertyRef Name="OrderID"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false"/>
+        <Property Name="ContactEmail" Type="Edm.String" sap:semantics="email"/>
+        <Property Name="ContactPhone" Type="Edm.String" sap:semantics="tel;type=work"/>
+        <Property Name="GrossAmount" Type="Edm.Decimal" Precision="15" Scale="2" sap:unit="Currency"/>
+        <Property Name="Currency" Type="Edm.String" sap:semantics="currency-code"/>
+      </EntityType>
+      <EntityContainer Name="SALES_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Orders" EntityType="SALES_SRV.Order" sap:label="Sales Orders"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestSemanticsDescriptions(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(semanticsMetadata), "http://example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "Currency", meta.EntityTypes["Order"].Properties[3].Unit)
+
+	service := newMockODataServiceWithMetadata(t, semanticsMetadata, nil)
+	b := newMockBridge(t, service, nil)
+
+	var properties map[string]interface{}
+	for _, tool := range listTools(t, b) {
+		if tool.Name == "Orders_create" {
+			properties = tool.InputSchema["properties"].(map[string]interface{})
+		}
+	}
+	require.NotNil(t, properties, "create tool not registered")
+	description := func(name string) interface{} {
+		return properties[name].(map[string]interface{})["description"]
+	}
+	assert.Equal(t, "Property: ContactEmail (email address)", description("ContactEmail"))
+	assert.Equal(t, "Property: ContactPhone (phone number)", description("ContactPhone"))
+	assert.Equal(t, "Property: GrossAmount (in the currency or unit given by Currency, up to 15 digits, 2 after the decimal point)", description("GrossAmount"))
+	assert.Equal(t, "Property: Currency (ISO currency code, e.g. EUR)", description("Currency"))
+}
+
+func TestTablesPairAmountsWithCurrency(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, semanticsMetadata, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[
+			{"OrderID":"1","GrossAmount":"9.50","Currency":"EUR"},
+			{"OrderID":"2","GrossAmount":"12.00"}
+		]}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	result, err := b.CallTool(context.Background(), "Orders_filter", map[string]interface{}{
+		"output_format": "markdown", "$select": "OrderID,GrossAmount,Currency",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "| OrderID | GrossAmount | Currency |\n"+
+		"| --- | --- | --- |\n"+
+		"| 1 | 9.50 EUR | EUR |\n"+
+		"| 2 | 12.00 |  |\n", result.(*mcp.TextResult).Text)
+
+	result, err = b.CallTool(context.Background(), "Orders_filter", map[string]interface{}{
+		"output_format": "csv", "$select": "OrderID,GrossAmount,Currency",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "OrderID,GrossAmount,Currency\n1,9.50 EUR,EUR\n2,12.00,\n", result.(*mcp.TextResult).Text)
+}
+
+func TestEntitySetLabelsInToolDescriptions(t *testing.T) {
+	service := newMockODataServiceWithMetadata(t, semanticsMetadata, nil)
+	b := newMockBridge(t, service, nil)
+
+	descriptions := make(map[string]string)
+	for _, tool := range listTools(t, b) {
+		descriptions[tool.Name] = tool.Description
+	}
+	assert.Equal(t, "List/filter Sales Orders (Orders) entities with OData query options", descriptions["Orders_filter"])
+	assert.Equal(t, "Create a new Sales Orders (Orders) entity", descriptions["Orders_create"])
+
+	// Unlabelled sets keep their technical name
+	b = newMockBridge(t, newMockODataService(t, nil), nil)
+	for _, tool := range listTools(t, b) {
+		if tool.Name == "Products_filter" {
+			assert.Equal(t, "List/filter Products entities with OData query options", tool.Description)
+		}
+	}
+
+	// OData v4 services label entity types and sets with Common.Label
+	meta, err := metadata.ParseMetadata([]byte(annotatedV4Metadata), "http://example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "Sales Orders", meta.EntitySets["SalesOrders"].Label)
+	assert.Equal(t, "Sales Order", meta.EntityTypes["SalesOrder"].Properties[0].Label)
+}
@@ -0,0 +1,215 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/client"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/logging"
+	"github.com/odata-mcp/go/internal/startup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startBridge creates a bridge for a service the way the bridge command does and wraps
+// the error as a metadata failure
+func startBridge(serviceURL string) error {
+	_, err := bridge.NewODataMCPBridge(&config.Config{ServiceURL: serviceURL})
+	return startup.Wrap(startup.CategoryMetadata, err)
+}
+
+func TestStartupErrorCategories(t *testing.T) {
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	assert.Equal(t, startup.CategoryAuth, startup.Classify(startBridge(unauthorized.URL)), "HTTP 401 on $metadata")
+	assert.Equal(t, startup.CategoryMetadata, startup.Classify(startBridge(broken.URL)), "HTTP 500 on $metadata")
+	assert.Equal(t, startup.CategoryNetwork, startup.Classify(startBridge(closedURL)), "connection refused")
+
+	configErr := startup.Wrap(startup.CategoryConfig, errors.New("cookie file not found: cookies.txt"))
+	assert.Equal(t, startup.CategoryConfig, startup.Classify(fmt.Errorf("wrapped: %w", configErr)))
+	assert.Equal(t, startup.CategoryInternal, startup.Classify(errors.New("boom")))
+	assert.Nil(t, startup.Wrap(startup.CategoryConfig, nil))
+}
+
+func TestStartupExitCodesAreDistinct(t *testing.T) {
+	seen := make(map[int]string)
+	for _, category := range []string{startup.CategoryInternal, startup.CategoryConfig, startup.CategoryAuth, startup.CategoryNetwork, startup.CategoryMetadata} {
+		code := startup.ExitCode(category)
+		assert.NotZero(t, code, category)
+		assert.NotContains(t, seen, code, "%s and %s share exit code %d", category, seen[code], code)
+		seen[code] = category
+	}
+	assert.Equal(t, 1, startup.ExitCode("unknown"))
+}
+
+func TestStartupErrorJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := startup.Wrap(startup.CategoryConfig, errors.New("invalid log level 'loud'"))
+	require.NoError(t, startup.WriteJSON(&out, err))
+
+	var report struct {
+		Error struct {
+			Category string `json:"category"`
+			Message  string `json:"message"`
+			ExitCode int    `json:"exit_code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, "config", report.Error.Category)
+	assert.Equal(t, "invalid log level 'loud'", report.Error.Message)
+	assert.Equal(t, startup.ExitCode(startup.CategoryConfig), report.Error.ExitCode)
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("\n")), "the error object is a single line")
+}
+
+func TestStartupProbesRunConcurrentlyWithMetadata(t *testing.T) {
+	var mu sync.Mutex
+	var tokenFetches, serviceDocs int
+	probed := make(chan struct{}, 2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/$metadata") {
+			// $metadata only answers once both probes reached the service
+			for i := 0; i < 2; i++ {
+				select {
+				case <-probed:
+				case <-time.After(5 * time.Second):
+					w.WriteHeader(http.StatusGatewayTimeout)
+					return
+				}
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(mockServiceMetadata))
+			return
+		}
+		mu.Lock()
+		if r.Header.Get("X-CSRF-Token") == "Fetch" {
+			tokenFetches++
+			w.Header().Set("X-CSRF-Token", "probe-token")
+			http.SetCookie(w, &http.Cookie{Name: "SAP_SESSIONID", Value: "abc"})
+		} else {
+			serviceDocs++
+		}
+		mu.Unlock()
+		probed <- struct{}{}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"EntitySets":["Products","Orders"]}}`))
+	}))
+	defer server.Close()
+
+	b := newMockBridge(t, server, func(cfg *config.Config) { cfg.StartupProbes = true })
+	assert.True(t, toolOperations(t, b)["Products:filter"])
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, tokenFetches)
+	assert.Equal(t, 1, serviceDocs)
+}
+
+func TestStartupProbesServiceDocumentReplacesBrokenMetadata(t *testing.T) {
+	var requests atomic.Int32
+	server := newMockODataServiceWithMetadata(t, "not metadata", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"EntitySets":[]}}`))
+	})
+
+	newMockBridge(t, server, func(cfg *config.Config) { cfg.StartupProbes = true })
+	// The token probe and the service document probe; the fallback reuses the probe
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestMetadataLargerThanBudgetFailsStartup(t *testing.T) {
+	// Without a Content-Length, the document is measured as it streams in
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		doc := largeSAPMetadata(200)
+		for len(doc) > 0 {
+			n := min(len(doc), 4096)
+			w.Write([]byte(doc[:n]))
+			w.(http.Flusher).Flush()
+			doc = doc[n:]
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := bridge.NewODataMCPBridge(&config.Config{ServiceURL: server.URL + "/", MaxMetadataSize: 64 * 1024})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, client.ErrMetadataTooLarge)
+	assert.Contains(t, err.Error(), "--metadata-file", "the error says how to get below the budget")
+
+	// A Content-Length over the budget fails before the body is read
+	small := newMockODataService(t, nil)
+	_, err = bridge.NewODataMCPBridge(&config.Config{ServiceURL: small.URL + "/", MaxMetadataSize: 100})
+	assert.ErrorIs(t, err, client.ErrMetadataTooLarge)
+	assert.Contains(t, err.Error(), fmt.Sprintf("%d bytes", len(mockServiceMetadata)))
+
+	b, err := bridge.NewODataMCPBridge(&config.Config{ServiceURL: server.URL + "/", MaxMetadataSize: 1024 * 1024})
+	require.NoError(t, err)
+	assert.True(t, toolOperations(t, b)["Set199:filter"], "a document within the budget is loaded")
+}
+
+func TestStartupSummaryIsLogged(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		logging.SetLevel(logging.DefaultLevel)
+	})
+
+	path := filepath.Join(t.TempDir(), "odata-mcp.log")
+	closeLog, err := logging.Configure("info", logging.FormatJSON, path)
+	require.NoError(t, err)
+
+	b := newMockBridge(t, newMockODataService(t, nil), nil)
+	session, err := b.NewSession("")
+	require.NoError(t, err)
+	listTools(t, session)
+	require.NoError(t, closeLog())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var summaries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		if record["msg"] == "Startup summary" {
+			summaries = append(summaries, record)
+		}
+	}
+
+	require.Len(t, summaries, 1, "the summary is logged once at startup; sessions reuse the metadata")
+	summary := summaries[0]
+	assert.Equal(t, float64(len(mockServiceMetadata)), summary["metadata_bytes"])
+	assert.Equal(t, float64(2), summary["entity_sets"])
+	assert.Equal(t, float64(2), summary["entity_types"])
+	assert.Equal(t, false, summary["metadata_cached"])
+	for _, key := range []string{"metadata_ms", "complex_types", "functions"} {
+		assert.Contains(t, summary, key)
+	}
+	assert.Greater(t, summary["rss_mb"], float64(0))
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolAnnotations(t *testing.T) {
+	server := newMockODataService(t, nil)
+	b := newMockBridge(t, server, nil)
+
+	annotations := make(map[string]*mcp.ToolAnnotations)
+	for _, tool := range listTools(t, b) {
+		annotations[tool.Name] = tool.Annotations
+	}
+
+	readOnly := []string{"odata_service_info", "Products_filter", "Products_count", "Products_search", "Products_get", "GetTopProducts"}
+	for _, name := range readOnly {
+		require.Contains(t, annotations, name)
+		require.NotNil(t, annotations[name].ReadOnlyHint, name)
+		assert.True(t, *annotations[name].ReadOnlyHint, name)
+	}
+
+	create := annotations["Products_create"]
+	require.NotNil(t, create)
+	assert.False(t, *create.ReadOnlyHint)
+	assert.False(t, *create.DestructiveHint)
+	assert.False(t, *create.IdempotentHint)
+
+	update := annotations["Products_update"]
+	require.NotNil(t, update)
+	assert.False(t, *update.ReadOnlyHint)
+	assert.True(t, *update.IdempotentHint)
+
+	del := annotations["Products_delete"]
+	require.NotNil(t, del)
+	assert.False(t, *del.ReadOnlyHint)
+	assert.True(t, *del.DestructiveHint)
+}
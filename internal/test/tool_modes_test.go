@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxToolsLazyRegistration(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxTools = 5
+	})
+
+	info, err := b.GetTraceInfo()
+	require.NoError(t, err)
+	names := make(map[string]bool)
+	for _, tool := range info.RegisteredTools {
+		names[tool.Name] = true
+	}
+	assert.Equal(t, map[string]bool{"odata_service_info": true, "bridge_stats": true, "list_entities": true, "enable_entity": true}, names)
+
+	result, err := b.CallTool(context.Background(), "list_entities", map[string]interface{}{"filter": "Prod*"})
+	require.NoError(t, err)
+	var listing struct {
+		EntitySets []struct {
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+		} `json:"entity_sets"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.(string)), &listing))
+	require.Len(t, listing.EntitySets, 1)
+	assert.Equal(t, "Products", listing.EntitySets[0].Name)
+	assert.False(t, listing.EntitySets[0].Enabled)
+
+	result, err = b.CallTool(context.Background(), "enable_entity", map[string]interface{}{"name": "Products"})
+	require.NoError(t, err)
+	assert.Contains(t, result.(string), "Products_filter")
+
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Products:filter"])
+	assert.True(t, ops["Products:get"])
+	assert.False(t, ops["Orders:filter"])
+
+	_, err = b.CallTool(context.Background(), "enable_entity", map[string]interface{}{"name": "Nope"})
+	assert.Error(t, err)
+}
+
+func TestMaxToolsNotExceeded(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxTools = 100
+	})
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Orders:filter"])
+	assert.True(t, ops["function:GetTopProducts"])
+}
+
+func TestCompactTools(t *testing.T) {
+	var requestedPath string
+	server := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"d": map[string]interface{}{"ProductID": 7, "Name": "Chai"},
+		})
+	})
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.CompactTools = true
+	})
+
+	info, err := b.GetTraceInfo()
+	require.NoError(t, err)
+	names := make(map[string]bool)
+	for _, tool := range info.RegisteredTools {
+		names[tool.Name] = true
+	}
+	for _, name := range []string{"odata_service_info", "bridge_stats", "query_entity", "count_entity", "search_entity", "get_entity", "create_entity", "update_entity", "delete_entity", "call_function"} {
+		assert.True(t, names[name], "expected compact tool %s", name)
+	}
+	assert.Len(t, names, 10)
+
+	args := map[string]interface{}{
+		"entity_set": "Products",
+		"key":        map[string]interface{}{"ProductID": float64(7)},
+	}
+	result, err := b.CallTool(context.Background(), "get_entity", args)
+	require.NoError(t, err)
+	assert.Contains(t, result.(string), "Chai")
+	assert.Contains(t, requestedPath, "Products(7)")
+	assert.Equal(t, "Products", args["entity_set"], "the caller's arguments are not modified")
+
+	_, err = b.CallTool(context.Background(), "get_entity", map[string]interface{}{"entity_set": "Unknown"})
+	assert.Error(t, err)
+}
+
+func TestCompactToolsRespectOperationRules(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.CompactTools = true
+		rules, err := config.ParseEntityOperationRules("Products:read")
+		require.NoError(t, err)
+		cfg.EntityOperationRules = rules
+	})
+
+	_, err := b.CallTool(context.Background(), "delete_entity", map[string]interface{}{
+		"entity_set": "Products",
+		"key":        map[string]interface{}{"ProductID": float64(1)},
+	})
+	assert.Error(t, err)
+}
+
+func TestEntitySetAndFunctionWithTheSameName(t *testing.T) {
+	metadata := strings.Replace(mockServiceMetadata, `FunctionImport Name="GetTopProducts"`, `FunctionImport Name="Orders"`, 1)
+	server := newMockODataServiceWithMetadata(t, metadata, nil)
+
+	ops := toolOperations(t, newMockBridge(t, server, nil))
+	assert.True(t, ops["Orders:filter"])
+	assert.True(t, ops["function:Orders"], "the function is enabled separately from the entity set")
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxTools = 5
+	})
+	result, err := b.CallTool(context.Background(), "enable_entity", map[string]interface{}{"name": "Orders"})
+	require.NoError(t, err)
+	assert.Contains(t, result.(string), "Orders_filter")
+	ops = toolOperations(t, b)
+	assert.True(t, ops["Orders:filter"])
+	assert.True(t, ops["function:Orders"])
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validToolName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+func TestSanitizeToolNamePart(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Products", "Products"},
+		{"Bestellpositionen_Größe", "Bestellpositionen_Groesse"},
+		{"Café.Menü", "Cafe_Menue"},
+		{"A_Product.Plant", "A_Product_Plant"},
+		{"Søren/Łódź", "Soren_Lodz"},
+		{"  spaced  name ", "spaced_name"},
+		{"dash-ok", "dash-ok"},
+		{"Заказы", "Zakazy"},
+		{"Ψηφία", "Psifia"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, utils.SanitizeToolNamePart(tt.input))
+		})
+	}
+}
+
+func TestSanitizeToolNamePartKeepsUntransliteratedNamesDistinct(t *testing.T) {
+	orders := utils.SanitizeToolNamePart("受注")
+	items := utils.SanitizeToolNamePart("受注明細")
+	assert.Regexp(t, `^[0-9a-f]{8}$`, orders)
+	assert.NotEqual(t, orders, items)
+	assert.Equal(t, orders, utils.SanitizeToolNamePart("受注"), "the hash is stable")
+	assert.Regexp(t, `^Header_[0-9a-f]{8}$`, utils.SanitizeToolNamePart("Header受注"))
+}
+
+func TestShortenToolName(t *testing.T) {
+	assert.Equal(t, "short", utils.ShortenToolName("short", 64))
+
+	long1 := "filter_" + strings.Repeat("VeryLongEntitySetName", 5) + "A"
+	long2 := "filter_" + strings.Repeat("VeryLongEntitySetName", 5) + "B"
+	short1 := utils.ShortenToolName(long1, 40)
+	short2 := utils.ShortenToolName(long2, 40)
+
+	assert.LessOrEqual(t, len(short1), 40)
+	assert.NotEqual(t, short1, short2, "truncated names must stay distinct")
+	assert.Equal(t, short1, utils.ShortenToolName(long1, 40), "shortening must be deterministic")
+}
+
+func TestFormatToolNameKeepsPostfix(t *testing.T) {
+	server := newMockODataService(t, nil)
+
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.NoPostfix = false
+		cfg.ToolPostfix = "mock"
+		cfg.ToolNameMaxLength = 22
+	})
+
+	info, err := b.GetTraceInfo()
+	require.NoError(t, err)
+	for _, tool := range info.RegisteredTools {
+		assert.Regexp(t, validToolName, tool.Name)
+		assert.LessOrEqual(t, len(tool.Name), 22, tool.Name)
+		assert.True(t, strings.HasSuffix(tool.Name, "_mock"), tool.Name)
+	}
+}
+
+func TestToolNameCollisionResolution(t *testing.T) {
+	metadata := `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="COLL_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Item">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="COLL_SRV_Entities">
+        <EntitySet Name="SalesÖrder" EntityType="COLL_SRV.Item"/>
+        <EntitySet Name="SalesOerder" EntityType="COLL_SRV.Item"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+	server := newMockODataServiceWithMetadata(t, metadata, nil)
+
+	b := newMockBridge(t, server, nil)
+	info, err := b.GetTraceInfo()
+	require.NoError(t, err)
+
+	byEntity := make(map[string]string)
+	for _, tool := range info.RegisteredTools {
+		if tool.Operation == "filter" {
+			byEntity[tool.EntitySet] = tool.Name
+		}
+	}
+	require.Len(t, byEntity, 2)
+	assert.Equal(t, "SalesOerder_filter", byEntity["SalesOerder"], "entity sets are registered in sorted order")
+	assert.Equal(t, "SalesOerder_filter_2", byEntity["SalesÖrder"])
+	assert.NotEmpty(t, info.NameCollisions)
+}
+
+func TestToolNameCollisionNamespacePrefix(t *testing.T) {
+	metadata := `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="ZMM" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Item">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="ID" Type="Edm.String" Nullable="false"/>
+      </EntityType>
+      <EntityContainer Name="ZMM_Entities">
+        <EntitySet Name="SalesOerder" EntityType="ZMM.Item"/>
+      </EntityContainer>
+    </Schema>
+    <Schema Namespace="ZSD" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityContainer Name="ZSD_Entities">
+        <EntitySet Name="SalesÖrder" EntityType="ZMM.Item"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+	server := newMockODataServiceWithMetadata(t, metadata, nil)
+
+	filterTools := func(b *bridge.ODataMCPBridge) map[string]string {
+		info, err := b.GetTraceInfo()
+		require.NoError(t, err)
+		byEntity := make(map[string]string)
+		for _, tool := range info.RegisteredTools {
+			if tool.Operation == "filter" {
+				byEntity[tool.EntitySet] = tool.Name
+			}
+		}
+		return byEntity
+	}
+
+	byEntity := filterTools(newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.ToolNameCollisions = constants.ToolNameCollisionsNamespace
+	}))
+	assert.Equal(t, "SalesOerder_filter", byEntity["SalesOerder"])
+	assert.Equal(t, "ZSD_SalesOerder_filter", byEntity["SalesÖrder"])
+
+	byEntity = filterTools(newMockBridge(t, server, nil))
+	assert.Equal(t, "SalesOerder_filter_2", byEntity["SalesÖrder"], "numeric suffixes stay the default")
+}
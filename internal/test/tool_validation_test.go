@@ -0,0 +1,113 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolArgumentValidation(t *testing.T) {
+	var requests atomic.Int32
+	service := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	})
+	b := newMockBridge(t, service, nil)
+
+	testCases := []struct {
+		name    string
+		tool    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"missing key", "Products_get", map[string]interface{}{}, "missing required argument 'ProductID'"},
+		{"wrong type", "Products_get", map[string]interface{}{"ProductID": "abc"}, "argument 'ProductID' must be an integer, got string \"abc\""},
+		{"fractional integer", "Products_filter", map[string]interface{}{"$top": 1.5}, "argument '$top' must be an integer"},
+		{"enum", "Products_update", map[string]interface{}{"ProductID": 1, "_method": "POST"}, "argument '_method' must be one of PUT, PATCH, MERGE"},
+		{"string expected", "Orders_get", map[string]interface{}{"OrderID": true}, "argument 'OrderID' must be a string, got boolean true"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := b.CallTool(context.Background(), tc.tool, tc.args)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+			assert.Contains(t, err.Error(), tc.message)
+		})
+	}
+	assert.Zero(t, requests.Load(), "invalid calls must not reach the service")
+}
+
+func TestToolsCallInvalidParams(t *testing.T) {
+	server := mcp.NewServer("test", "1.0")
+	var calls atomic.Int32
+	server.AddTool(&mcp.Tool{Name: "echo", InputSchema: map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"count": map[string]interface{}{"type": "integer"}},
+		"required":   []string{"count"},
+	}}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		calls.Add(1)
+		return "ok", nil
+	})
+
+	messages := runMCPSession(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"count":"many"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"count":"42"}}}`,
+	)
+
+	responses := make(map[float64]map[string]interface{})
+	for _, msg := range messages {
+		responses[msg["id"].(float64)] = msg
+	}
+	require.Len(t, responses, 3)
+
+	errObj, ok := responses[1]["error"].(map[string]interface{})
+	require.True(t, ok)
+	assert.EqualValues(t, -32602, errObj["code"])
+	assert.Contains(t, errObj["data"], "argument 'count' must be an integer")
+
+	errObj, ok = responses[2]["error"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, errObj["data"], "missing required argument 'count'")
+
+	// Numeric strings are accepted, as OData v2 serializes large numbers as strings
+	assert.NotNil(t, responses[3]["result"])
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestSchemaPatternsAreCompiledOnRegistration(t *testing.T) {
+	previous := slog.Default()
+	var logs bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	server := mcp.NewServer("test", "1.0")
+	server.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"code":  map[string]interface{}{"type": "string", "pattern": "^[0-9]*$"},
+			"label": map[string]interface{}{"type": "string", "pattern": "^[a-z+$"},
+		},
+	}}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	assert.Contains(t, logs.String(), "Invalid pattern in tool input schema is not checked")
+	assert.Contains(t, logs.String(), "tool=lookup")
+	assert.NotContains(t, logs.String(), "^[0-9]*$")
+
+	_, err := server.CallTool(context.Background(), "lookup", map[string]interface{}{"code": "12a"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArguments)
+	assert.Contains(t, err.Error(), "argument 'code' must match ^[0-9]*$")
+
+	_, err = server.CallTool(context.Background(), "lookup", map[string]interface{}{"code": "12", "label": "Any"})
+	assert.NoError(t, err, "invalid patterns are not checked")
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odata-mcp/go/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// otlpSpan is the part of an exported OTLP span the tests check
+type otlpSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Kind         int    `json:"kind"`
+	Attributes   []struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	} `json:"attributes"`
+	Status struct {
+		Code int `json:"code"`
+	} `json:"status"`
+}
+
+// attribute returns the value of a span attribute as exported
+func (s otlpSpan) attribute(key string) interface{} {
+	for _, attr := range s.Attributes {
+		if attr.Key == key {
+			for _, value := range attr.Value {
+				return value
+			}
+		}
+	}
+	return nil
+}
+
+// newOTLPCollector starts a collector recording the spans posted to /v1/traces
+func newOTLPCollector(t *testing.T) (*httptest.Server, func() []otlpSpan) {
+	var mu sync.Mutex
+	var spans []otlpSpan
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, tracing.TracesPath, r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var request struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []otlpSpan `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range request.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []otlpSpan {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]otlpSpan{}, spans...)
+	}
+}
+
+func TestTracingSpansToolCallsAndRequests(t *testing.T) {
+	var traceparents []string
+	var mu sync.Mutex
+	list := productListHandler(2)
+	b := newMockBridge(t, newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		mu.Unlock()
+		list(w, r)
+	}), nil)
+
+	collector, exported := newOTLPCollector(t)
+	shutdown, err := tracing.Setup(collector.URL, "odata-mcp-test")
+	require.NoError(t, err)
+
+	_, err = b.CallTool(context.Background(), "Products_filter", map[string]interface{}{"$top": float64(2)})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, shutdown(ctx))
+
+	var toolSpan, requestSpan *otlpSpan
+	spans := exported()
+	for i := range spans {
+		switch {
+		case spans[i].Name == "tools/call Products_filter":
+			toolSpan = &spans[i]
+		case spans[i].Name == "HTTP GET" && strings.Contains(spans[i].attribute("url.full").(string), "/Products"):
+			requestSpan = &spans[i]
+		}
+	}
+	require.NotNil(t, toolSpan)
+	require.NotNil(t, requestSpan)
+
+	assert.Equal(t, tracing.KindServer, toolSpan.Kind)
+	assert.Equal(t, "Products_filter", toolSpan.attribute("mcp.tool.name"))
+	assert.Empty(t, toolSpan.ParentSpanID)
+
+	assert.Equal(t, tracing.KindClient, requestSpan.Kind)
+	assert.Equal(t, toolSpan.TraceID, requestSpan.TraceID)
+	assert.Equal(t, toolSpan.SpanID, requestSpan.ParentSpanID)
+	assert.Equal(t, "200", requestSpan.attribute("http.response.status_code"))
+	assert.Equal(t, 1, requestSpan.Status.Code)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, traceparents)
+	assert.Equal(t, "00-"+toolSpan.TraceID+"-"+requestSpan.SpanID+"-01", traceparents[len(traceparents)-1],
+		"the service receives the trace context of the request span")
+}
+
+func TestTracingOffWithoutSetup(t *testing.T) {
+	ctx, span := tracing.Start(context.Background(), "tools/call x", tracing.KindServer)
+	assert.Nil(t, span)
+	span.SetAttribute("key", "value")
+	span.End()
+	assert.Empty(t, tracing.Traceparent(ctx))
+
+	_, err := tracing.Setup("localhost:4318", "odata-mcp")
+	assert.Error(t, err, "the endpoint must be a URL")
+}
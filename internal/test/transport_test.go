@@ -0,0 +1,56 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/odata-mcp/go/internal/config"
+)
+
+// connectionsForParallelCalls runs two rounds of parallel count calls and returns the
+// number of connections they were sent on
+func connectionsForParallelCalls(t *testing.T, idleConnsPerHost int) int {
+	var mu sync.Mutex
+	connections := make(map[string]bool)
+	server := newMockODataService(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections[r.RemoteAddr] = true
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"__count":"1","results":[]}}`))
+	})
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.MaxIdleConnsPerHost = idleConnsPerHost
+	})
+
+	for round := 0; round < 2; round++ {
+		var calls sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			calls.Add(1)
+			go func() {
+				defer calls.Done()
+				_, err := b.CallTool(context.Background(), "Products_count", map[string]interface{}{})
+				assert.NoError(t, err)
+			}()
+		}
+		calls.Wait()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return len(connections)
+}
+
+func TestIdleConnectionsPerHostAreReused(t *testing.T) {
+	// With the Go default of two idle connections per host, the second round opens new ones
+	assert.Greater(t, connectionsForParallelCalls(t, 2), 6)
+
+	// The six connections of the first round are kept for the second
+	require.LessOrEqual(t, connectionsForParallelCalls(t, 6), 6)
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const brokenMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata">
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="BROKEN_SRV" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Log">
+        <Property Name="Message" Type="Edm.String"/>
+        <NavigationProperty Name="Owner" Relationship="BROKEN_SRV.Log_Owner" FromRole="Log" ToRole="Owner"/>
+      </EntityType>
+      <EntityType Name="Special" BaseType="BROKEN_SRV.Log">
+        <Key><PropertyRef Name="ID"/></Key>
+        <Property Name="Code" Type="Edm.String"/>
+      </EntityType>
+      <EntityContainer Name="BROKEN_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Logs" EntityType="BROKEN_SRV.Log"/>
+        <EntitySet Name="Specials" EntityType="BROKEN_SRV.Special"/>
+        <EntitySet Name="Ghosts" EntityType="BROKEN_SRV.Ghost"/>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// issueKinds returns the "<kind>:<target>" pairs of a validation report
+func issueKinds(report *models.MetadataReport) map[string]bool {
+	kinds := make(map[string]bool)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind+":"+issue.Target] = true
+	}
+	return kinds
+}
+
+func TestValidateMetadata(t *testing.T) {
+	b := newMockBridge(t, newMockODataServiceWithMetadata(t, brokenMetadata, nil), nil)
+	report := b.ValidateMetadata()
+
+	kinds := issueKinds(report)
+	assert.True(t, kinds["unresolved_entity_type:Ghosts"])
+	assert.True(t, kinds["missing_key:Logs"])
+	assert.True(t, kinds["unresolved_key:Specials"])
+	assert.True(t, kinds["unresolved_navigation:Log.Owner"])
+	assert.True(t, kinds["unsupported:"], "entity type inheritance is reported")
+	assert.Equal(t, 2, report.Errors)
+	assert.Equal(t, 2, report.Warnings)
+
+	// A clean service has no issues
+	report = newMockBridge(t, newMockODataService(t, nil), nil).ValidateMetadata()
+	assert.Empty(t, report.Issues)
+	assert.Equal(t, 2, report.MetadataSummary.EntitySets)
+
+	// Filters that match nothing explain why only the service tools appear
+	report = newMockBridge(t, newMockODataService(t, nil), func(cfg *config.Config) {
+		cfg.AllowedEntities = []string{"Customers"}
+	}).ValidateMetadata()
+	assert.True(t, issueKinds(report)["filter_matches_nothing:--entities"])
+}
+
+func TestValidateMetadataParseError(t *testing.T) {
+	b := newMockBridge(t, newMockODataServiceWithMetadata(t, "<edmx:Edmx", nil), nil)
+	report := b.ValidateMetadata()
+
+	kinds := issueKinds(report)
+	require.True(t, kinds["parse_error:$metadata"], "issues: %v", report.Issues)
+	assert.True(t, kinds["no_entity_sets:"])
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// valueHelpMetadata is an SAP v2 service whose Plant and Currency properties have value
+// helps, declared with sap:value-list and Common.ValueList annotations
+const valueHelpMetadata = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="1.0" xmlns:edmx="http://schemas.microsoft.com/ado/2007/06/edmx" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:sap="http://www.sap.com/Protocols/SAPData">
+  <edmx:Reference Uri="./Vocabularies(TechnicalName='%2FIWBEP%2FVOC_COMMON',Version='0001',SAP__Origin='')" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+    <edmx:Include Namespace="com.sap.vocabularies.Common.v1" Alias="Common"/>
+  </edmx:Reference>
+  <edmx:DataServices m:DataServiceVersion="2.0">
+    <Schema Namespace="ZPURCHASE_SRV" xml:lang="en" xmlns="http://schemas.microsoft.com/ado/2008/09/edm">
+      <EntityType Name="Order" sap:content-version="1">
+        <Key><PropertyRef Name="OrderID"/></Key>
+        <Property Name="OrderID" Type="Edm.String" Nullable="false" MaxLength="10"/>
+        <Property Name="Plant" Type="Edm.String" MaxLength="4" sap:label="Plant" sap:value-list="standard"/>
+        <Property Name="Currency" Type="Edm.String" MaxLength="5" sap:value-list="fixed-values"/>
+        <Property Name="Vendor" Type="Edm.String" MaxLength="10" sap:value-list="standard"/>
+      </EntityType>
+      <EntityType Name="PlantVH" sap:content-version="1">
+        <Key><PropertyRef Name="Werks"/></Key>
+        <Property Name="Werks" Type="Edm.String" Nullable="false" MaxLength="4"/>
+        <Property Name="Name1" Type="Edm.String" MaxLength="30"/>
+      </EntityType>
+      <EntityType Name="CurrencyVH" sap:content-version="1">
+        <Key><PropertyRef Name="Waers"/></Key>
+        <Property Name="Waers" Type="Edm.String" Nullable="false" MaxLength="5"/>
+        <Property Name="Ltext" Type="Edm.String" MaxLength="40"/>
+      </EntityType>
+      <EntityContainer Name="ZPURCHASE_SRV_Entities" m:IsDefaultEntityContainer="true">
+        <EntitySet Name="Orders" EntityType="ZPURCHASE_SRV.Order"/>
+        <EntitySet Name="VL_SH_H_T001W" EntityType="ZPURCHASE_SRV.PlantVH" sap:creatable="false" sap:updatable="false" sap:deletable="false"/>
+        <EntitySet Name="VL_FV_WAERS" EntityType="ZPURCHASE_SRV.CurrencyVH" sap:creatable="false" sap:updatable="false" sap:deletable="false"/>
+      </EntityContainer>
+      <Annotations Target="ZPURCHASE_SRV.Order/Plant" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+        <Annotation Term="com.sap.vocabularies.Common.v1.ValueList">
+          <Record>
+            <PropertyValue Property="Label" String="Plants"/>
+            <PropertyValue Property="CollectionPath" String="VL_SH_H_T001W"/>
+            <PropertyValue Property="SearchSupported" Bool="true"/>
+            <PropertyValue Property="Parameters">
+              <Collection>
+                <Record Type="com.sap.vocabularies.Common.v1.ValueListParameterInOut">
+                  <PropertyValue Property="LocalDataProperty" PropertyPath="Plant"/>
+                  <PropertyValue Property="ValueListProperty" String="Werks"/>
+                </Record>
+                <Record Type="com.sap.vocabularies.Common.v1.ValueListParameterDisplayOnly">
+                  <PropertyValue Property="ValueListProperty" String="Name1"/>
+                </Record>
+              </Collection>
+            </PropertyValue>
+          </Record>
+        </Annotation>
+      </Annotations>
+      <Annotations Target="ZPURCHASE_SRV.Order/Currency" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+        <Annotation Term="Common.ValueList">
+          <Record>
+            <PropertyValue Property="CollectionPath" String="VL_FV_WAERS"/>
+            <PropertyValue Property="Parameters">
+              <Collection>
+                <Record Type="Common.ValueListParameterOut">
+                  <PropertyValue Property="LocalDataProperty" PropertyPath="Currency"/>
+                  <PropertyValue Property="ValueListProperty" String="Waers"/>
+                </Record>
+                <Record Type="Common.ValueListParameterDisplayOnly">
+                  <PropertyValue Property="ValueListProperty" String="Ltext"/>
+                </Record>
+              </Collection>
+            </PropertyValue>
+          </Record>
+        </Annotation>
+        <Annotation Term="Common.Label" String="Currency"/>
+      </Annotations>
+      <Annotations Target="ZPURCHASE_SRV.Order/Vendor" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+        <Annotation Term="Common.ValueList">
+          <Record>
+            <PropertyValue Property="CollectionRoot" String="/sap/opu/odata/sap/ZVENDOR_SRV"/>
+            <PropertyValue Property="CollectionPath" String="Vendors"/>
+          </Record>
+        </Annotation>
+      </Annotations>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestValueListAnnotationsAreParsed(t *testing.T) {
+	meta, err := metadata.ParseMetadata([]byte(valueHelpMetadata), "http://example.com/")
+	require.NoError(t, err)
+
+	order := meta.EntityTypes["Order"]
+	require.NotNil(t, order)
+	plant, currency, vendor := order.Properties[1], order.Properties[2], order.Properties[3]
+
+	assert.Equal(t, "standard", plant.ValueList)
+	require.NotNil(t, plant.ValueHelp)
+	assert.Equal(t, "VL_SH_H_T001W", plant.ValueHelp.CollectionPath)
+	assert.Equal(t, "Werks", plant.ValueHelp.ValueProperty)
+	assert.Equal(t, []string{"Werks", "Name1"}, plant.ValueHelp.Properties)
+	assert.Equal(t, "Plants", plant.ValueHelp.Label)
+	assert.True(t, plant.ValueHelp.SearchSupported)
+
+	require.NotNil(t, currency.ValueHelp, "the Common alias is resolved")
+	assert.Equal(t, "VL_FV_WAERS", currency.ValueHelp.CollectionPath)
+	assert.Equal(t, "Waers", currency.ValueHelp.ValueProperty)
+	assert.NotContains(t, currency.Annotations, "com.sap.vocabularies.Common.v1.Label", "other v2 annotations are not kept")
+
+	assert.Equal(t, "standard", vendor.ValueList)
+	assert.Nil(t, vendor.ValueHelp, "value helps of other services are not supported")
+}
+
+func TestValueHelpToolsQueryTheValueHelpEntitySet(t *testing.T) {
+	var mu sync.Mutex
+	var queries []*http.Request
+	server := newMockODataServiceWithMetadata(t, valueHelpMetadata, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[{"Werks":"1000","Name1":"Hamburg"},{"Werks":"1010","Name1":"Berlin"}]}}`))
+	})
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.ValueHelp = true
+	})
+
+	ops := toolOperations(t, b)
+	assert.True(t, ops["Orders:value_help"])
+	assert.False(t, ops["VL_SH_H_T001W:value_help"])
+
+	names := make(map[string]bool)
+	for _, tool := range listTools(t, b) {
+		names[tool.Name] = true
+		if tool.Name == "Orders_Plant_value_help" {
+			assert.Contains(t, tool.Description, "VL_SH_H_T001W")
+			assert.True(t, *tool.Annotations.ReadOnlyHint)
+			assert.Contains(t, tool.InputSchema["properties"], "search")
+		}
+	}
+	assert.True(t, names["Orders_Plant_value_help"])
+	assert.True(t, names["Orders_Currency_value_help"])
+	assert.False(t, names["Orders_Vendor_value_help"])
+
+	result, err := b.CallTool(context.Background(), "Orders_Plant_value_help", map[string]interface{}{"$filter": "startswith(Name1,'B')"})
+	require.NoError(t, err)
+	assert.Contains(t, result, "Berlin")
+
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0].URL.Path, "/VL_SH_H_T001W")
+	query, err := url.ParseQuery(queries[0].URL.RawQuery)
+	require.NoError(t, err)
+	assert.Equal(t, "Werks,Name1", query.Get("$select"))
+	assert.Equal(t, "Werks", query.Get("$orderby"))
+	assert.Equal(t, "startswith(Name1,'B')", query.Get("$filter"))
+}
+
+func TestValueHelpToolsAreOptInAndFollowTheEntityFilter(t *testing.T) {
+	server := newMockODataServiceWithMetadata(t, valueHelpMetadata, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"d":{"results":[]}}`))
+	})
+
+	// Without --value-help there are no value help tools
+	ops := toolOperations(t, newMockBridge(t, server, nil))
+	assert.False(t, ops["Orders:value_help"])
+
+	// Value helps whose entity set --entities excludes get no tool
+	b := newMockBridge(t, server, func(cfg *config.Config) {
+		cfg.ValueHelp = true
+		cfg.AllowedEntities = []string{"Orders", "VL_FV_WAERS"}
+	})
+	names := make(map[string]bool)
+	for _, tool := range listTools(t, b) {
+		names[tool.Name] = true
+	}
+	assert.True(t, names["Orders_Currency_value_help"])
+	assert.False(t, names["Orders_Plant_value_help"])
+}
@@ -0,0 +1,295 @@
+// Package tracing records spans of tool calls and OData requests and exports them to an
+// OpenTelemetry collector with OTLP/HTTP (JSON encoding). Outgoing OData requests carry
+// a W3C traceparent header, so traces continue into the SAP gateway. Without Setup,
+// spans are not recorded.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/odata-mcp/go/internal/logging"
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// Span kinds and status codes of the OTLP data model
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+
+	statusOK    = 1
+	statusError = 2
+)
+
+const (
+	// TracesPath is appended to the OTLP endpoint
+	TracesPath = "/v1/traces"
+
+	queueSize     = 2048
+	batchSize     = 512
+	flushInterval = 5 * time.Second
+)
+
+// logger logs the export of spans
+var logger = logging.Subsystem(logging.SubsystemTracing)
+
+// exporter is the active exporter; nil when tracing is off
+var exporter atomic.Pointer[Exporter]
+
+// Span is an operation of a trace. All methods are no-ops on a nil span.
+type Span struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     int
+	start    time.Time
+	mu       sync.Mutex
+	attrs    map[string]interface{}
+	status   int
+	message  string
+	ended    bool
+}
+
+type spanKey struct{}
+
+// Start begins a span as a child of the span in ctx, or of a new trace. It returns a
+// context carrying the span, or ctx and a nil span when tracing is off.
+func Start(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	if exporter.Load() == nil {
+		return ctx, nil
+	}
+	s := &Span{name: name, kind: kind, start: time.Now(), attrs: make(map[string]interface{})}
+	if parent := FromContext(ctx); parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		rand.Read(s.traceID[:])
+	}
+	rand.Read(s.spanID[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// FromContext returns the span of a context, or nil
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SetAttribute sets a string, bool or integer attribute
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs[key] = value
+	s.mu.Unlock()
+}
+
+// SetError marks the span as failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.status, s.message = statusError, redact.String(err.Error())
+	s.mu.Unlock()
+}
+
+// End ends the span and queues it for export
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	if s.status == 0 {
+		s.status = statusOK
+	}
+	span := s.otlp(time.Now())
+	s.mu.Unlock()
+
+	if e := exporter.Load(); e != nil {
+		e.enqueue(span)
+	}
+}
+
+// Traceparent returns the W3C traceparent header value of the span in ctx, or ""
+func Traceparent(ctx context.Context) string {
+	s := FromContext(ctx)
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(s.traceID[:]), hex.EncodeToString(s.spanID[:]))
+}
+
+// Exporter sends ended spans in batches to an OTLP/HTTP endpoint
+type Exporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+	queue       chan map[string]interface{}
+	flush       chan chan struct{}
+	done        chan struct{}
+}
+
+// Setup starts exporting spans to an OTLP/HTTP endpoint such as http://localhost:4318
+// (the path /v1/traces is appended). The returned function flushes the queued spans and
+// stops the exporter.
+func Setup(endpoint, serviceName string) (func(ctx context.Context) error, error) {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("invalid OTLP endpoint '%s': must be an http:// or https:// URL", endpoint)
+	}
+	e := &Exporter{
+		url:         strings.TrimSuffix(strings.TrimSuffix(endpoint, "/"), TracesPath) + TracesPath,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan map[string]interface{}, queueSize),
+		flush:       make(chan chan struct{}),
+		done:        make(chan struct{}),
+	}
+	exporter.Store(e)
+	go e.run()
+
+	return func(ctx context.Context) error {
+		exporter.CompareAndSwap(e, nil)
+		flushed := make(chan struct{})
+		select {
+		case e.flush <- flushed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case <-flushed:
+			close(e.done)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, nil
+}
+
+// enqueue queues a span for export, dropping it when the queue is full
+func (e *Exporter) enqueue(span map[string]interface{}) {
+	select {
+	case e.queue <- span:
+	default:
+		logger.Debug("Trace export queue full, dropping span", "span", span["name"])
+	}
+}
+
+// run exports batches when they are full, every flushInterval and on flush requests
+func (e *Exporter) run() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []map[string]interface{}
+	send := func() {
+		if len(batch) > 0 {
+			e.export(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case flushed := <-e.flush:
+			for len(e.queue) > 0 {
+				batch = append(batch, <-e.queue)
+			}
+			send()
+			close(flushed)
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// export posts spans as an OTLP ExportTraceServiceRequest
+func (e *Exporter) export(spans []map[string]interface{}) {
+	request := map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": otlpAttributes(map[string]interface{}{"service.name": e.serviceName}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": e.serviceName},
+				"spans": spans,
+			}},
+		}},
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		logger.Warn("Failed to encode spans", "error", err)
+		return
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		logger.Warn("Failed to export spans", "endpoint", e.url, "error", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		logger.Warn("Failed to export spans", "endpoint", e.url, "status", resp.StatusCode)
+	}
+}
+
+// otlp returns the OTLP JSON representation of an ended span
+func (s *Span) otlp(end time.Time) map[string]interface{} {
+	span := map[string]interface{}{
+		"traceId":           hex.EncodeToString(s.traceID[:]),
+		"spanId":            hex.EncodeToString(s.spanID[:]),
+		"name":              s.name,
+		"kind":              s.kind,
+		"startTimeUnixNano": strconv.FormatInt(s.start.UnixNano(), 10),
+		"endTimeUnixNano":   strconv.FormatInt(end.UnixNano(), 10),
+		"attributes":        otlpAttributes(s.attrs),
+		"status":            map[string]interface{}{"code": s.status, "message": s.message},
+	}
+	if s.parentID != [8]byte{} {
+		span["parentSpanId"] = hex.EncodeToString(s.parentID[:])
+	}
+	return span
+}
+
+// otlpAttributes converts attributes to OTLP key/value pairs
+func otlpAttributes(attrs map[string]interface{}) []interface{} {
+	result := make([]interface{}, 0, len(attrs))
+	for key, value := range attrs {
+		var v map[string]interface{}
+		switch value := value.(type) {
+		case bool:
+			v = map[string]interface{}{"boolValue": value}
+		case int:
+			v = map[string]interface{}{"intValue": strconv.Itoa(value)}
+		case int64:
+			v = map[string]interface{}{"intValue": strconv.FormatInt(value, 10)}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprint(value)}
+		}
+		result = append(result, map[string]interface{}{"key": key, "value": v})
+	}
+	return result
+}
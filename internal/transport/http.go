@@ -0,0 +1,527 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/config"
+	"github.com/odata-mcp/go/internal/constants"
+	"github.com/odata-mcp/go/internal/logging"
+	"github.com/odata-mcp/go/internal/metrics"
+)
+
+// eventBufferSize is the number of server messages kept for a session without an open
+// stream; a session with more waiting messages is closed
+const eventBufferSize = 100
+
+// errTooManySessions is returned for a new session when --max-sessions are open and busy
+var errTooManySessions = errors.New("too many open sessions")
+
+// logger logs the sessions of the transport
+var logger = logging.Subsystem(logging.SubsystemMCP)
+
+// HTTPServer serves MCP over Streamable HTTP. Every client session gets its own
+// bridge, so CSRF tokens, cookies, credentials and enabled tools never leak
+// from one client into another client's requests.
+type HTTPServer struct {
+	bridge   atomic.Pointer[bridge.ODataMCPBridge] // nil until the metadata is loaded
+	config   *config.Config
+	server   *http.Server
+	mu       sync.Mutex
+	sessions map[string]*session
+	check    serviceCheck // Last service check of the readiness probe
+}
+
+// session is one MCP client connection backed by its own bridge
+type session struct {
+	id            string
+	bridge        *bridge.ODataMCPBridge
+	authorization string // Authorization header the session was created with
+	input         *io.PipeWriter
+	inputMu       sync.Mutex
+	mu            sync.Mutex
+	pending       map[string]chan []byte // Responses awaited by POST requests, keyed by request id
+	events        chan []byte            // Notifications and server requests for the GET stream
+	streaming     bool
+	lastActive    atomic.Int64 // Unix nanoseconds of the last request of the client
+	done          chan struct{}
+}
+
+// NewHTTPServer creates an HTTP transport; b provides the metadata shared by all sessions.
+// If b is nil, the server answers probes but rejects new sessions until SetBridge.
+func NewHTTPServer(b *bridge.ODataMCPBridge, cfg *config.Config) *HTTPServer {
+	h := &HTTPServer{
+		config:   cfg,
+		sessions: make(map[string]*session),
+	}
+	if b != nil {
+		h.bridge.Store(b)
+	}
+	h.server = &http.Server{Addr: cfg.HTTPAddr, Handler: h.Handler()}
+	return h
+}
+
+// SetBridge provides the bridge with the loaded metadata once the server was started
+// without one, making it ready for sessions
+func (h *HTTPServer) SetBridge(b *bridge.ODataMCPBridge) {
+	h.bridge.Store(b)
+}
+
+// Handler returns the HTTP handler serving the MCP, metrics and probe endpoints
+func (h *HTTPServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(constants.MCPEndpoint, h.handleMCP)
+	mux.Handle(constants.MetricsEndpoint, metrics.Default.Handler())
+	mux.HandleFunc(constants.LivenessEndpoint, h.handleLiveness)
+	mux.HandleFunc(constants.ReadinessEndpoint, h.handleReadiness)
+	return mux
+}
+
+// ListenAndServe serves MCP on the configured address until Shutdown is called
+func (h *HTTPServer) ListenAndServe() error {
+	logger.Info("Serving MCP over HTTP", "url", "http://"+h.config.HTTPAddr+constants.MCPEndpoint)
+	if err := h.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown closes all sessions and stops the HTTP server
+func (h *HTTPServer) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	sessions := make([]*session, 0, len(h.sessions))
+	for _, s := range h.sessions {
+		sessions = append(sessions, s)
+	}
+	h.mu.Unlock()
+
+	for _, s := range sessions {
+		s.close()
+	}
+	return h.server.Shutdown(ctx)
+}
+
+// SessionCount returns the number of open sessions
+func (h *HTTPServer) SessionCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions)
+}
+
+func (h *HTTPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleStream(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// maxMessageSize returns the maximum size in bytes of request bodies (--max-message-size,
+// 10MB by default as on stdio)
+func (h *HTTPServer) maxMessageSize() int64 {
+	if h.config.MaxMessageSize > 0 {
+		return int64(h.config.MaxMessageSize)
+	}
+	return constants.DefaultMaxMessageSize
+}
+
+// handlePost forwards one JSON-RPC message to its session and returns the response to requests
+func (h *HTTPServer) handlePost(w http.ResponseWriter, r *http.Request) {
+	limit := h.maxMessageSize()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSONRPCError(w, http.StatusRequestEntityTooLarge, -32600,
+			fmt.Sprintf("Invalid request: message exceeds the maximum message size of %d bytes", limit))
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var msg struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		writeJSONRPCError(w, http.StatusBadRequest, -32700, "Parse error")
+		return
+	}
+
+	var s *session
+	if r.Header.Get(constants.MCPSessionIDHeader) == "" {
+		if msg.Method != "initialize" {
+			http.Error(w, "missing "+constants.MCPSessionIDHeader+" header", http.StatusBadRequest)
+			return
+		}
+		if h.bridge.Load() == nil {
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "the service metadata is not loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+		authorization := r.Header.Get("Authorization")
+		if h.config.PassThroughAuth && authorization == "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="odata-mcp"`)
+			http.Error(w, "authorization required", http.StatusUnauthorized)
+			return
+		}
+		if s, err = h.newSession(authorization); errors.Is(err, errTooManySessions) {
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("failed to create session: %v", err), http.StatusInternalServerError)
+			return
+		}
+	} else if s = h.lookupSession(w, r); s == nil {
+		return
+	}
+
+	w.Header().Set(constants.MCPSessionIDHeader, s.id)
+
+	// Notifications and responses to server requests have nothing to wait for
+	if msg.Method == "" || len(msg.ID) == 0 || string(msg.ID) == "null" {
+		if err := s.send(body); err != nil {
+			http.Error(w, "session closed", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	key := requestKey(msg.ID)
+	response := s.await(key)
+	if err := s.send(body); err != nil {
+		s.forget(key)
+		http.Error(w, "session closed", http.StatusNotFound)
+		return
+	}
+
+	select {
+	case data := <-response:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	case <-r.Context().Done():
+		// The client went away; cancel the request like an MCP client would
+		s.forget(key)
+		cancel, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "notifications/cancelled",
+			"params":  map[string]interface{}{"requestId": msg.ID, "reason": "HTTP request closed"},
+		})
+		s.send(cancel)
+	case <-s.done:
+		http.Error(w, "session closed", http.StatusNotFound)
+	}
+}
+
+// handleStream delivers notifications and server requests as server-sent events
+func (h *HTTPServer) handleStream(w http.ResponseWriter, r *http.Request) {
+	s := h.lookupSession(w, r)
+	if s == nil {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	if s.streaming {
+		s.mu.Unlock()
+		http.Error(w, "session already has an open stream", http.StatusConflict)
+		return
+	}
+	s.streaming = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.streaming = false
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set(constants.MCPSessionIDHeader, s.id)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case data := <-s.events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// handleDelete terminates a session at the client's request
+func (h *HTTPServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s := h.lookupSession(w, r)
+	if s == nil {
+		return
+	}
+	s.close()
+	<-s.done
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupSession finds the session named in the request, writing an error response if there is none
+func (h *HTTPServer) lookupSession(w http.ResponseWriter, r *http.Request) *session {
+	id := r.Header.Get(constants.MCPSessionIDHeader)
+	if id == "" {
+		http.Error(w, "missing "+constants.MCPSessionIDHeader+" header", http.StatusBadRequest)
+		return nil
+	}
+
+	h.mu.Lock()
+	s := h.sessions[id]
+	h.mu.Unlock()
+	if s == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return nil
+	}
+
+	// A session is bound to the credentials it was created with
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(s.authorization)) != 1 {
+		http.Error(w, "session belongs to a different user", http.StatusForbidden)
+		return nil
+	}
+	s.touch()
+	return s
+}
+
+// evictIdleSession makes room for a new session with --max-sessions: it closes the
+// session idle for the longest time, one without running requests or an open stream.
+// It returns errTooManySessions if all sessions are busy.
+func (h *HTTPServer) evictIdleSession() error {
+	h.mu.Lock()
+	if h.config.MaxSessions <= 0 || len(h.sessions) < h.config.MaxSessions {
+		h.mu.Unlock()
+		return nil
+	}
+	var idlest *session
+	for _, s := range h.sessions {
+		if !s.busy() && (idlest == nil || s.lastActive.Load() < idlest.lastActive.Load()) {
+			idlest = s
+		}
+	}
+	if idlest == nil {
+		h.mu.Unlock()
+		return errTooManySessions
+	}
+	// Removed right away, so that the slot is free before its server has exited
+	delete(h.sessions, idlest.id)
+	h.mu.Unlock()
+
+	logger.Debug("Closing idle HTTP session for a new one", "session", idlest.id, "max_sessions", h.config.MaxSessions)
+	idlest.close()
+	return nil
+}
+
+// newSession starts a bridge for a new client connection
+func (h *HTTPServer) newSession(authorization string) (*session, error) {
+	if err := h.evictIdleSession(); err != nil {
+		return nil, err
+	}
+	forwarded := ""
+	if h.config.PassThroughAuth {
+		forwarded = authorization
+	}
+	b, err := h.bridge.Load().NewSession(forwarded)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+	b.SetSessionID(id)
+
+	inputReader, inputWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+	s := &session{
+		id:            id,
+		bridge:        b,
+		authorization: authorization,
+		input:         inputWriter,
+		pending:       make(map[string]chan []byte),
+		events:        make(chan []byte, eventBufferSize),
+		done:          make(chan struct{}),
+	}
+	s.touch()
+
+	h.mu.Lock()
+	h.sessions[id] = s
+	h.mu.Unlock()
+
+	logger.Debug("HTTP session started", "session", id)
+
+	go s.dispatch(outputReader)
+	go func() {
+		err := b.Serve(inputReader, outputWriter)
+		outputWriter.Close()
+		inputReader.Close()
+
+		h.mu.Lock()
+		if h.sessions[id] == s {
+			delete(h.sessions, id)
+		}
+		h.mu.Unlock()
+		close(s.done)
+
+		logger.Debug("HTTP session ended", "session", id, "error", err)
+	}()
+
+	return s, nil
+}
+
+// send writes one message to the session's MCP server
+func (s *session) send(data []byte) error {
+	var line bytes.Buffer
+	if err := json.Compact(&line, data); err != nil {
+		return err
+	}
+	line.WriteByte('\n')
+
+	s.inputMu.Lock()
+	defer s.inputMu.Unlock()
+	_, err := s.input.Write(line.Bytes())
+	return err
+}
+
+// touch records client activity for choosing the session to close with --max-sessions
+func (s *session) touch() {
+	s.lastActive.Store(time.Now().UnixNano())
+}
+
+// busy reports whether the session has requests awaiting a response or an open stream
+func (s *session) busy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending) > 0 || s.streaming
+}
+
+// await registers interest in the response to a request
+func (s *session) await(key string) chan []byte {
+	ch := make(chan []byte, 1)
+	s.mu.Lock()
+	s.pending[key] = ch
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *session) forget(key string) {
+	s.mu.Lock()
+	delete(s.pending, key)
+	s.mu.Unlock()
+}
+
+// dispatch routes server output: responses to waiting POSTs, everything else to the stream.
+// Server messages are not limited here: the server enforces --max-message-size itself.
+func (s *session) dispatch(output *io.PipeReader) {
+	reader := bufio.NewReader(output)
+	overflowed := false
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// Without a reader, the server would block on its next message
+			output.CloseWithError(err)
+			if err != io.EOF {
+				logger.Warn("Closing HTTP session whose output cannot be read", "session", s.id, "error", err)
+				s.close()
+			}
+			return
+		}
+		data := bytes.TrimRight(line, "\r\n")
+		if len(data) == 0 {
+			continue
+		}
+
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.Unmarshal(data, &msg); err == nil && msg.Method == "" && len(msg.ID) > 0 {
+			key := requestKey(msg.ID)
+			s.mu.Lock()
+			ch := s.pending[key]
+			delete(s.pending, key)
+			s.mu.Unlock()
+			if ch != nil {
+				ch <- data
+				continue
+			}
+		}
+
+		// A client that does not read its stream would miss messages, so rather than
+		// dropping them or blocking the server, the session is closed
+		select {
+		case s.events <- data:
+		default:
+			if !overflowed {
+				overflowed = true
+				logger.Warn("Closing HTTP session whose event stream is not read", "session", s.id, "waiting", eventBufferSize)
+				s.close()
+			}
+		}
+	}
+}
+
+// close stops the session's bridge; the session is removed once its server has exited
+func (s *session) close() {
+	s.bridge.Stop()
+	s.input.Close()
+}
+
+// requestKey normalizes a JSON-RPC id so that e.g. 1 and 1.0 match the server's echo
+func requestKey(id json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(id, &v); err != nil {
+		return string(id)
+	}
+	return fmt.Sprintf("%T:%v", v, v)
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeJSONRPCError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error":   map[string]interface{}{"code": code, "message": message},
+	})
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/odata-mcp/go/internal/bridge"
+	"github.com/odata-mcp/go/internal/redact"
+)
+
+// Probes are answered from the last service check for this long, so that frequent
+// probes do not load the service
+const serviceCheckTTL = 10 * time.Second
+
+// serviceCheckTimeout limits a service check of the readiness probe
+const serviceCheckTimeout = 5 * time.Second
+
+// serviceCheck is the cached result of the last readiness check of the service
+type serviceCheck struct {
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// handleLiveness answers the liveness probe: the process is running and serves HTTP,
+// whether or not the service is available
+func (h *HTTPServer) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, http.StatusOK, "ok", nil)
+}
+
+// handleReadiness answers the readiness probe: the metadata is loaded and the service
+// is reachable and accepts the configured credentials
+func (h *HTTPServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	b := h.bridge.Load()
+	if b == nil {
+		writeProbe(w, http.StatusServiceUnavailable, "starting", nil)
+		return
+	}
+	if err := h.checkService(r.Context(), b); err != nil {
+		writeProbe(w, http.StatusServiceUnavailable, "unavailable", err)
+		return
+	}
+	writeProbe(w, http.StatusOK, "ready", nil)
+}
+
+// checkService returns the result of the last service check, checking the service again
+// when it is older than serviceCheckTTL
+func (h *HTTPServer) checkService(ctx context.Context, b *bridge.ODataMCPBridge) error {
+	h.check.mu.Lock()
+	defer h.check.mu.Unlock()
+
+	if time.Since(h.check.checked) < serviceCheckTTL {
+		return h.check.err
+	}
+	ctx, cancel := context.WithTimeout(ctx, serviceCheckTimeout)
+	defer cancel()
+	h.check.err = b.CheckService(ctx)
+	h.check.checked = time.Now()
+	return h.check.err
+}
+
+// writeProbe writes the status of a probe as {"status":"ready"}, with the error if the
+// probe failed
+func writeProbe(w http.ResponseWriter, code int, status string, err error) {
+	body := map[string]string{"status": status}
+	if err != nil {
+		body["error"] = redact.String(err.Error())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -61,39 +61,50 @@ func ParseODataLegacyDate(s string) (milliseconds int64, offset string, ok bool)
 	return ms, offset, true
 }
 
-// ConvertODataLegacyToISO converts OData legacy date to ISO 8601 format
+// ConvertODataLegacyToISO converts OData legacy date to ISO 8601 format.
+// A timezone offset (Edm.DateTimeOffset) is kept; plain dates are rendered in UTC.
 func ConvertODataLegacyToISO(legacy string) string {
-	ms, _, ok := ParseODataLegacyDate(legacy)
+	ms, offset, ok := ParseODataLegacyDate(legacy)
 	if !ok {
 		return legacy // Return as-is if not valid legacy format
 	}
 	
 	t := time.UnixMilli(ms).UTC()
+	if offset != "" {
+		hours, _ := strconv.Atoi(offset[1:3])
+		minutes, _ := strconv.Atoi(offset[3:5])
+		seconds := hours*3600 + minutes*60
+		if offset[0] == '-' {
+			seconds = -seconds
+		}
+		t = t.In(time.FixedZone("", seconds))
+	}
 	return t.Format(time.RFC3339)
 }
 
-// ConvertISOToODataLegacy converts ISO 8601 date to OData legacy format
-func ConvertISOToODataLegacy(iso string) string {
-	// Try various ISO formats
+// ParseISODateTime parses the ISO 8601 date and datetime forms accepted as tool input.
+// Values without a timezone are taken as UTC.
+func ParseISODateTime(iso string) (time.Time, bool) {
 	formats := []string{
 		time.RFC3339,
 		time.RFC3339Nano,
 		"2006-01-02T15:04:05",
-		"2006-01-02T15:04:05Z07:00",
+		"2006-01-02T15:04:05.999999999",
 		"2006-01-02",
 	}
 	
-	var t time.Time
-	var err error
-	
 	for _, format := range formats {
-		t, err = time.Parse(format, iso)
-		if err == nil {
-			break
+		if t, err := time.Parse(format, iso); err == nil {
+			return t, true
 		}
 	}
-	
-	if err != nil {
+	return time.Time{}, false
+}
+
+// ConvertISOToODataLegacy converts ISO 8601 date to OData legacy format
+func ConvertISOToODataLegacy(iso string) string {
+	t, ok := ParseISODateTime(iso)
+	if !ok {
 		return iso // Return as-is if not valid ISO format
 	}
 	
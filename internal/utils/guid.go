@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// guidLiteralRegex matches guid'...' literals holding a base64 encoded GUID
+var guidLiteralRegex = regexp.MustCompile(`guid'([A-Za-z0-9+/]{22}==)'`)
+
+// Base64ToGUID converts a base64 encoded 16-byte GUID, as returned by some SAP
+// OData v2 services for Edm.Guid values, to the canonical 8-4-4-4-12 form
+func Base64ToGUID(s string) (string, bool) {
+	if len(s) != 24 || !strings.HasSuffix(s, "==") {
+		return "", false
+	}
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(raw) != 16 {
+		return "", false
+	}
+	h := hex.EncodeToString(raw)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32], true
+}
+
+// NormalizeGUID returns the canonical form of a base64 encoded GUID and any other value unchanged
+func NormalizeGUID(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		if guid, ok := Base64ToGUID(s); ok {
+			return guid
+		}
+	}
+	return value
+}
+
+// NormalizeGUIDLiterals rewrites guid'<base64>' literals in a filter expression to canonical GUIDs
+func NormalizeGUIDLiterals(expr string) string {
+	return guidLiteralRegex.ReplaceAllStringFunc(expr, func(literal string) string {
+		guid, ok := Base64ToGUID(literal[5 : len(literal)-1])
+		if !ok {
+			return literal
+		}
+		return "guid'" + guid + "'"
+	})
+}
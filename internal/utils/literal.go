@@ -0,0 +1,18 @@
+package utils
+
+import "strings"
+
+// keyPredicateEscaper percent-encodes characters that would end the path segment of a
+// key predicate: the escape character itself, query and fragment markers, slashes and spaces
+var keyPredicateEscaper = strings.NewReplacer("%", "%25", "#", "%23", "?", "%3F", "/", "%2F", " ", "%20")
+
+// QuoteODataString returns s as an OData string literal, doubling embedded single
+// quotes as the OData ABNF requires (O'Brien becomes 'O''Brien')
+func QuoteODataString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// EscapeKeyPredicate makes a key predicate safe to embed in a URL path
+func EscapeKeyPredicate(predicate string) string {
+	return keyPredicateEscaper.Replace(predicate)
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations covers letters that do not decompose into an ASCII base letter
+var transliterations = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue", 'Ä': "Ae", 'Ö': "Oe", 'Ü': "Ue",
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O", 'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D",
+	'þ': "th", 'Þ': "Th", 'ð': "d", 'Ð': "D", 'ı': "i",
+
+	// Cyrillic (Russian, Ukrainian, Belarusian)
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "E", 'Ж': "Zh",
+	'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M", 'Н': "N", 'О': "O",
+	'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U", 'Ф': "F", 'Х': "Kh", 'Ц': "Ts",
+	'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch", 'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu",
+	'Я': "Ya", 'І': "I", 'Ї': "Yi", 'Є': "Ye", 'Ґ': "G", 'Ў': "U",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	'Α': "A", 'Β': "V", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z", 'Η': "I", 'Θ': "Th",
+	'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M", 'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P",
+	'Ρ': "R", 'Σ': "S", 'Τ': "T", 'Υ': "Y", 'Φ': "F", 'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O",
+}
+
+// SanitizeToolNamePart transliterates a name to ASCII and replaces every character
+// not allowed in MCP tool names ([a-zA-Z0-9_-]) with an underscore. Names with letters
+// that cannot be transliterated (e.g. CJK) end with a hash of the original name, so they
+// stay distinct and never become empty.
+func SanitizeToolNamePart(name string) string {
+	var sb strings.Builder
+	lost := false
+	for _, r := range name {
+		if t, ok := transliterations[r]; ok {
+			sb.WriteString(t)
+			continue
+		}
+		for _, d := range norm.NFD.String(string(r)) {
+			switch {
+			case unicode.Is(unicode.Mn, d):
+				// Drop combining marks left over from decomposition (é -> e)
+			case transliterations[d] != "":
+				// Accented letters of other scripts (ί -> ι -> i)
+				sb.WriteString(transliterations[d])
+			case d < unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d) || d == '_' || d == '-'):
+				sb.WriteRune(d)
+			default:
+				if unicode.IsLetter(d) || unicode.IsDigit(d) {
+					lost = true
+				}
+				sb.WriteRune('_')
+			}
+		}
+	}
+	if lost {
+		sb.WriteString("_" + nameHash(name))
+	}
+
+	// Collapse runs of underscores produced by replaced characters
+	result := sb.String()
+	for strings.Contains(result, "__") {
+		result = strings.ReplaceAll(result, "__", "_")
+	}
+	return strings.Trim(result, "_")
+}
+
+// ShortenToolName truncates a name to maxLength characters. Truncated names end
+// with a short hash of the full name so that different long names stay distinct.
+func ShortenToolName(name string, maxLength int) string {
+	if maxLength <= 0 || len(name) <= maxLength {
+		return name
+	}
+
+	suffix := "_" + nameHash(name)
+
+	if maxLength <= len(suffix) {
+		return suffix[len(suffix)-maxLength:]
+	}
+	return strings.TrimRight(name[:maxLength-len(suffix)], "_-") + suffix
+}
+
+// nameHash returns a short stable hash of a name
+func nameHash(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// ContainsName reports whether a list of names contains name, ignoring the spaces
+// around the names of a split comma-separated list
+func ContainsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.TrimSpace(n) == name {
+			return true
+		}
+	}
+	return false
+}
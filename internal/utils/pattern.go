@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IsRegexPattern reports whether a filter pattern is a regular expression rather
+// than a wildcard pattern. Regular expressions are anchored with ^ or $, or
+// enclosed in slashes (/.../).
+func IsRegexPattern(pattern string) bool {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return true
+	}
+	return strings.HasPrefix(pattern, "^") || strings.HasSuffix(pattern, "$")
+}
+
+// CompilePattern compiles a filter pattern. Wildcard patterns support * (any
+// run of characters) and ? (a single character) anywhere in the pattern and
+// must match the whole name; regular expressions are used as written.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	var expr string
+	switch {
+	case len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+		expr = pattern[1 : len(pattern)-1]
+	case IsRegexPattern(pattern):
+		expr = pattern
+	default:
+		var sb strings.Builder
+		sb.WriteString("^")
+		for _, r := range pattern {
+			switch r {
+			case '*':
+				sb.WriteString(".*")
+			case '?':
+				sb.WriteString(".")
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		sb.WriteString("$")
+		expr = sb.String()
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// Patterns holds compiled filter patterns. It is built once per configuration and only
+// read afterwards, so it is safe for concurrent use without locking.
+type Patterns struct {
+	compiled map[string]*regexp.Regexp
+}
+
+// NewPatterns compiles filter patterns, failing on the first invalid one
+func NewPatterns(patterns ...string) (*Patterns, error) {
+	p := &Patterns{compiled: make(map[string]*regexp.Regexp, len(patterns))}
+	for _, pattern := range patterns {
+		re, err := CompilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		p.compiled[pattern] = re
+	}
+	return p, nil
+}
+
+// Match checks if a name matches a wildcard or regex filter pattern. Patterns that were
+// not compiled in advance (e.g. a list_entities filter) are compiled for this call.
+// Invalid patterns never match. A nil Patterns compiles every pattern.
+func (p *Patterns) Match(name, pattern string) bool {
+	if pattern == name {
+		return true
+	}
+	var re *regexp.Regexp
+	if p != nil {
+		re = p.compiled[pattern]
+	}
+	if re == nil {
+		var err error
+		if re, err = CompilePattern(pattern); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(name)
+}
+
+// MatchPattern checks if a name matches a wildcard or regex filter pattern.
+// Invalid patterns never match.
+func MatchPattern(name, pattern string) bool {
+	return (*Patterns)(nil).Match(name, pattern)
+}
+
+// SplitPatterns splits a comma-separated pattern list. Commas inside parentheses,
+// brackets and braces of a regular expression ("^A_(Product|Plant){1,2}") do not split,
+// and "\," is a literal comma anywhere. Items are trimmed and empty items dropped.
+func SplitPatterns(list string) []string {
+	var result []string
+	var sb strings.Builder
+	depth := 0
+	flush := func() {
+		if item := strings.TrimSpace(sb.String()); item != "" {
+			result = append(result, item)
+		}
+		sb.Reset()
+	}
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+		switch {
+		case c == '\\' && i+1 < len(list) && list[i+1] == ',':
+			sb.WriteByte(',')
+			i++
+			continue
+		case c == '\\' && i+1 < len(list):
+			// Keep other escapes, including escaped brackets, as written
+			sb.WriteByte(c)
+			sb.WriteByte(list[i+1])
+			i++
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case (c == ')' || c == ']' || c == '}') && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			flush()
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	flush()
+	return result
+}